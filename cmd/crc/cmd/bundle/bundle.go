@@ -15,5 +15,9 @@ func GetBundleCmd(config *config.Config) *cobra.Command {
 		},
 	}
 	bundleCmd.AddCommand(getGenerateCmd(config))
+	bundleCmd.AddCommand(getListCmd())
+	bundleCmd.AddCommand(getDownloadCmd(config))
+	bundleCmd.AddCommand(getVerifyCmd(config))
+	bundleCmd.AddCommand(getPruneCmd())
 	return bundleCmd
 }
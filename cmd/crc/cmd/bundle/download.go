@@ -0,0 +1,50 @@
+package bundle
+
+import (
+	"context"
+
+	"github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/spf13/cobra"
+)
+
+func getDownloadCmd(cfg *config.Config) *cobra.Command {
+	var presetName string
+	downloadCmd := &cobra.Command{
+		Use:   "download",
+		Short: "Download the default bundle",
+		Long:  "Download and extract the default bundle of a preset in the cache, without running 'crc setup'",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			preset := config.GetPreset(cfg)
+			if presetName != "" {
+				var err error
+				if preset, err = crcPreset.ParsePresetE(presetName); err != nil {
+					return err
+				}
+			}
+			return runDownload(preset, config.GetBundleMirrors(cfg))
+		},
+	}
+	downloadCmd.Flags().StringVarP(&presetName, "preset", "p", "", "Preset of the bundle to download, the 'preset' setting is used by default")
+	return downloadCmd
+}
+
+func runDownload(preset crcPreset.Preset, bundleMirrors []string) error {
+	bundlePath := constants.GetDefaultBundlePath(preset)
+	logging.Infof("Downloading bundle: %s...", bundlePath)
+	bundlePath, err := bundle.Download(context.Background(), preset, bundlePath, bundleMirrors)
+	if err != nil {
+		return err
+	}
+	logging.Infof("Uncompressing %s", bundlePath)
+	bundleInfo, err := bundle.Extract(context.Background(), bundlePath)
+	if err != nil {
+		return err
+	}
+	logging.Infof("Bundle %s is ready to be used", bundleInfo.GetBundleName())
+	return nil
+}
@@ -2,7 +2,6 @@ package bundle
 
 import (
 	"github.com/crc-org/crc/v2/pkg/crc/config"
-	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/machine"
 	"github.com/spf13/cobra"
@@ -22,8 +21,8 @@ func getGenerateCmd(config *config.Config) *cobra.Command {
 	return generateCmd
 }
 
-func runGenerate(config *config.Config, forceStop bool) error {
-	client := machine.NewClient(constants.DefaultName, logging.IsDebug(), config)
+func runGenerate(cfg *config.Config, forceStop bool) error {
+	client := machine.NewClient(config.GetInstanceName(cfg), logging.IsDebug(), cfg)
 
 	return client.GenerateBundle(forceStop)
 }
@@ -0,0 +1,107 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+const jsonFormat = "json"
+
+func getListCmd() *cobra.Command {
+	var outputFormat string
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the bundles in the cache",
+		Long:  "List the bundles extracted in the cache, their size and the instances using them",
+		Args:  cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runList(os.Stdout, outputFormat)
+		},
+	}
+	addOutputFormatFlag(listCmd, &outputFormat)
+	return listCmd
+}
+
+func addOutputFormatFlag(cmd *cobra.Command, outputFormat *string) {
+	cmd.Flags().StringVarP(outputFormat, "output", "o", "", "Output format. One of: json")
+}
+
+type bundleListItem struct {
+	Name    string           `json:"name"`
+	Version string           `json:"version"`
+	Preset  crcPreset.Preset `json:"preset"`
+	Size    int64            `json:"size"`
+	InUseBy []string         `json:"inUseBy,omitempty"`
+}
+
+type bundleList struct {
+	Bundles []bundleListItem `json:"bundles"`
+}
+
+func runList(writer io.Writer, outputFormat string) error {
+	bundles, err := bundle.List()
+	if err != nil {
+		return err
+	}
+	inUse, err := machine.BundlesInUse()
+	if err != nil {
+		return err
+	}
+	list := bundleList{
+		Bundles: []bundleListItem{},
+	}
+	for _, b := range bundles {
+		size, err := bundle.Size(b.GetBundleName())
+		if err != nil {
+			return err
+		}
+		list.Bundles = append(list.Bundles, bundleListItem{
+			Name:    b.GetBundleName(),
+			Version: b.GetVersion(),
+			Preset:  b.GetBundleType(),
+			Size:    size,
+			InUseBy: inUse[b.GetBundleName()],
+		})
+	}
+	return render(&list, writer, outputFormat)
+}
+
+func (l *bundleList) prettyPrintTo(writer io.Writer) error {
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tPRESET\tSIZE\tIN USE BY")
+	for _, b := range l.Bundles {
+		inUseBy := "-"
+		if len(b.InUseBy) > 0 {
+			inUseBy = strings.Join(b.InUseBy, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", b.Name, b.Version, b.Preset, units.HumanSize(float64(b.Size)), inUseBy)
+	}
+	return w.Flush()
+}
+
+type prettyPrintable interface {
+	prettyPrintTo(writer io.Writer) error
+}
+
+func render(obj prettyPrintable, writer io.Writer, outputFormat string) error {
+	switch outputFormat {
+	case jsonFormat:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(obj)
+	case "":
+		return obj.prettyPrintTo(writer)
+	default:
+		return fmt.Errorf("invalid format: %s", outputFormat)
+	}
+}
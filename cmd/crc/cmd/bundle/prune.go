@@ -0,0 +1,53 @@
+package bundle
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/spf13/cobra"
+)
+
+func getPruneCmd() *cobra.Command {
+	var keep int
+	pruneCmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove old bundles from the cache",
+		Long: `Remove old bundles from the cache
+The bundles with the most recent OpenShift versions are kept, as well as the
+bundles used by existing instances.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if keep < 0 {
+				return fmt.Errorf("--keep cannot be negative")
+			}
+			return runPrune(os.Stdout, keep)
+		},
+	}
+	pruneCmd.Flags().IntVar(&keep, "keep", 1, "Number of bundles to keep")
+	return pruneCmd
+}
+
+func runPrune(writer io.Writer, keep int) error {
+	inUse, err := machine.BundlesInUse()
+	if err != nil {
+		return err
+	}
+	var inUseNames []string
+	for name := range inUse {
+		inUseNames = append(inUseNames, name)
+	}
+	removed, err := bundle.Prune(keep, inUseNames)
+	for _, name := range removed {
+		fmt.Fprintf(writer, "Removed bundle %s\n", name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(removed) == 0 {
+		_, err = fmt.Fprintln(writer, "No bundle to remove")
+	}
+	return err
+}
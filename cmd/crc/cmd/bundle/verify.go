@@ -0,0 +1,62 @@
+package bundle
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+	"github.com/spf13/cobra"
+)
+
+func getVerifyCmd(cfg *config.Config) *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify [BUNDLE]",
+		Short: "Verify the integrity of a bundle in the cache",
+		Long: `Verify the integrity of a bundle in the cache
+The signature of the bundle archive is checked when the archive is still in
+the cache, then the size and sha256sum of each extracted file are checked
+against the bundle metadata. The bundle of the 'bundle' setting is verified
+by default.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return runVerify(args[0], config.GetBundleMirrors(cfg))
+			}
+			bundleName, err := bundle.GetBundleNameFromURI(cfg.Get(config.Bundle).AsString())
+			if err != nil {
+				return err
+			}
+			return runVerify(bundleName, config.GetBundleMirrors(cfg))
+		},
+	}
+	return verifyCmd
+}
+
+func runVerify(bundleName string, mirrors []string) error {
+	bundleInfo, err := bundle.Get(bundleName)
+	if err != nil {
+		return err
+	}
+
+	archivePath := bundle.ArchivePath(bundleName)
+	if crcos.FileExists(archivePath) {
+		logging.Infof("Verifying the signature of %s", filepath.Base(archivePath))
+		if err := bundle.VerifySignature(archivePath, mirrors); err != nil {
+			return fmt.Errorf("cannot verify the signature of %s: %w", filepath.Base(archivePath), err)
+		}
+	} else {
+		logging.Warnf("%s is not in the cache, skipping the signature verification", filepath.Base(archivePath))
+	}
+
+	logging.Infof("Verifying the files of %s", bundleInfo.GetBundleName())
+	if err := bundleInfo.VerifyChecksums(); err != nil {
+		return errors.Join(fmt.Errorf("bundle %s is corrupted", bundleInfo.GetBundleName()), err)
+	}
+	_, err = fmt.Fprintf(os.Stdout, "Bundle %s is valid\n", bundleInfo.GetBundleName())
+	return err
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+	"go.podman.io/common/pkg/strongunits"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+)
+
+var cacheUnusedFor string
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	for _, cmd := range cacheCmd.Commands() {
+		addOutputFormatFlag(cmd)
+	}
+	cachePruneCmd.Flags().StringVar(&cacheUnusedFor, "unused-for", "", "Only remove the content which was not used for this duration, like 168h")
+	rootCmd.AddCommand(cacheCmd)
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache SUBCOMMAND [flags]",
+	Short: "Manage the registry cache of the daemon",
+	Long: fmt.Sprintf(`Manage the registry cache of the daemon
+When the '%s' setting is true, the cluster pulls the images of the registries
+of the '%s' setting through a cache run by 'crc daemon'. The cached layers
+and manifests are kept when the instance is deleted. The registry cache
+requires the user network mode.`, crcConfig.RegistryCache, crcConfig.RegistryCacheRegistries),
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Display the usage of the registry cache",
+	Long:  "Display the size of the registry cache and its hits and misses since the daemon started",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runCacheStats(os.Stdout, daemonclient.New(), outputFormat)
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove the content of the registry cache",
+	Long:  "Remove the cached layers and manifests, only those which were not used for --unused-for when it is set",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runCachePrune(os.Stdout, daemonclient.New(), cacheUnusedFor, outputFormat)
+	},
+}
+
+func runCacheStats(writer io.Writer, client *daemonclient.Client, outputFormat string) error {
+	result, err := client.APIClient.RegistryCache()
+	if err != nil {
+		return render(&cacheStats{Error: daemonError(err)}, writer, outputFormat)
+	}
+	return render(&cacheStats{
+		Success:    true,
+		Enabled:    result.Enabled,
+		Registries: result.Registries,
+		Blobs:      result.Blobs,
+		Manifests:  result.Manifests,
+		Size:       result.Size,
+		MaxSize:    result.MaxSize,
+		Hits:       result.Hits,
+		Misses:     result.Misses,
+	}, writer, outputFormat)
+}
+
+func runCachePrune(writer io.Writer, client *daemonclient.Client, unusedFor, outputFormat string) error {
+	result, err := client.APIClient.PruneRegistryCache(unusedFor)
+	if err != nil {
+		return render(&cachePruneResult{Error: daemonError(err)}, writer, outputFormat)
+	}
+	return render(&cachePruneResult{
+		Success: true,
+		Removed: result.Removed,
+		Freed:   result.Freed,
+	}, writer, outputFormat)
+}
+
+func daemonError(err error) *crcErrors.SerializableError {
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		return crcErrors.ToSerializableError(crcErrors.DaemonNotRunning)
+	}
+	return crcErrors.ToSerializableError(err)
+}
+
+type cacheStats struct {
+	Success    bool                         `json:"success"`
+	Error      *crcErrors.SerializableError `json:"error,omitempty"`
+	Enabled    bool                         `json:"enabled"`
+	Registries []string                     `json:"registries,omitempty"`
+	Blobs      int                          `json:"blobs"`
+	Manifests  int                          `json:"manifests"`
+	Size       strongunits.B                `json:"size"`
+	MaxSize    strongunits.B                `json:"maxSize"`
+	Hits       int64                        `json:"hits"`
+	Misses     int64                        `json:"misses"`
+}
+
+func (s *cacheStats) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 1, ' ', 0)
+
+	type line struct {
+		left, right string
+	}
+	enabled := "Disabled"
+	if s.Enabled {
+		enabled = "Enabled"
+	}
+	lines := []line{
+		{"Registry Cache", enabled},
+		{"Registries", valueOrDash(strings.Join(s.Registries, ", "))},
+		{"Layers", fmt.Sprintf("%d", s.Blobs)},
+		{"Manifests", fmt.Sprintf("%d", s.Manifests)},
+		{"Usage", fmt.Sprintf("%s of %s",
+			units.HumanSize(float64(s.Size)),
+			units.HumanSize(float64(s.MaxSize)))},
+		{"Hits", fmt.Sprintf("%d", s.Hits)},
+		{"Misses", fmt.Sprintf("%d", s.Misses)},
+	}
+	for _, line := range lines {
+		if err := printLine(w, line.left, line.right); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+type cachePruneResult struct {
+	Success bool                         `json:"success"`
+	Error   *crcErrors.SerializableError `json:"error,omitempty"`
+	Removed int                          `json:"removed"`
+	Freed   strongunits.B                `json:"freed"`
+}
+
+func (s *cachePruneResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	_, err := fmt.Fprintf(writer, "Removed %d layers and manifests, freed %s\n", s.Removed, units.HumanSize(float64(s.Freed)))
+	return err
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	apiClient "github.com/crc-org/crc/v2/pkg/crc/api/client"
+	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	"github.com/crc-org/crc/v2/pkg/crc/registrycache"
+	mocks "github.com/crc-org/crc/v2/test/mocks/api"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func setUpCacheClient(t *testing.T) *mocks.Client {
+	client := mocks.NewClient(t)
+	client.On("RegistryCache").Return(apiClient.RegistryCacheResult{
+		Enabled:    true,
+		Registries: []string{"quay.io", "docker.io"},
+		Stats: registrycache.Stats{
+			Blobs:     12,
+			Manifests: 3,
+			Size:      2_000_000_000,
+			MaxSize:   20_000_000_000,
+			Hits:      40,
+			Misses:    15,
+		},
+	}, nil)
+	return client
+}
+
+func TestCacheStatsPlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runCacheStats(out, &daemonclient.Client{APIClient: setUpCacheClient(t)}, ""))
+	assert.Equal(t, `Registry Cache: Enabled
+Registries:     quay.io, docker.io
+Layers:         12
+Manifests:      3
+Usage:          2GB of 20GB
+Hits:           40
+Misses:         15
+`, out.String())
+}
+
+func TestCacheStatsJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runCacheStats(out, &daemonclient.Client{APIClient: setUpCacheClient(t)}, jsonFormat))
+	assert.JSONEq(t, `{
+  "success": true,
+  "enabled": true,
+  "registries": ["quay.io", "docker.io"],
+  "blobs": 12,
+  "manifests": 3,
+  "size": 2000000000,
+  "maxSize": 20000000000,
+  "hits": 40,
+  "misses": 15
+}`, out.String())
+}
+
+func TestCachePrunePlain(t *testing.T) {
+	client := mocks.NewClient(t)
+	client.On("PruneRegistryCache", "168h").Return(registrycache.PruneResult{Removed: 4, Freed: 300_000_000}, nil)
+
+	out := new(bytes.Buffer)
+	assert.NoError(t, runCachePrune(out, &daemonclient.Client{APIClient: client}, "168h", ""))
+	assert.Equal(t, "Removed 4 layers and manifests, freed 300MB\n", out.String())
+}
+
+func TestCachePruneJSONError(t *testing.T) {
+	client := mocks.NewClient(t)
+	client.On("PruneRegistryCache", "").Return(registrycache.PruneResult{}, errors.New("broken"))
+
+	out := new(bytes.Buffer)
+	assert.NoError(t, runCachePrune(out, &daemonclient.Client{APIClient: client}, "", jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "broken", "removed": 0, "freed": 0}`, out.String())
+}
@@ -11,6 +11,7 @@ import (
 )
 
 func init() {
+	cleanupCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only list the changes which would be made to the host, without making them")
 	addOutputFormatFlag(cleanupCmd)
 	rootCmd.AddCommand(cleanupCmd)
 }
@@ -25,6 +26,13 @@ var cleanupCmd = &cobra.Command{
 }
 
 func runCleanup() error {
+	if dryRun {
+		return render(&dryRunResult{
+			Success: true,
+			Actions: preflight.PlanCleanUpHost(),
+			command: "crc cleanup",
+		}, os.Stdout, outputFormat)
+	}
 	err := preflight.CleanUpHost()
 	return render(&cleanupResult{
 		Success: err == nil,
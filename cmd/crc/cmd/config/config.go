@@ -66,5 +66,8 @@ Properties: ` + "\n\n" + configurableFields(config),
 	configCmd.AddCommand(configSetCmd(config))
 	configCmd.AddCommand(configUnsetCmd(config))
 	configCmd.AddCommand(configViewCmd(config))
+	configCmd.AddCommand(configApplyCmd(config))
+	configCmd.AddCommand(configDiffCmd(config))
+	configCmd.AddCommand(configExportCmd(config))
 	return configCmd
 }
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	jsonFormat = "json"
+	yamlFormat = "yaml"
+)
+
+var (
+	diffOutputFormat   string
+	exportOutputFormat string
+)
+
+func configApplyCmd(config *config.Config) *cobra.Command {
+	return &cobra.Command{
+		Use:   "apply FILE",
+		Short: "Apply the settings of a profile file",
+		Long: `Applies the settings of a YAML or JSON profile file to the crc configuration.
+The settings are validated as with 'crc config set'. If one of them is invalid,
+the configuration is left untouched.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runConfigApply(config, args[0], os.Stdout)
+		},
+	}
+}
+
+func configDiffCmd(config *config.Config) *cobra.Command {
+	configDiffCmd := &cobra.Command{
+		Use:   "diff FILE",
+		Short: "Show the settings a profile file would change",
+		Long:  `Shows the crc configuration properties which would be changed by applying a YAML or JSON profile file.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runConfigDiff(config, args[0], diffOutputFormat, os.Stdout)
+		},
+	}
+	configDiffCmd.Flags().StringVarP(&diffOutputFormat, "output", "o", "", "Output format (json)")
+	return configDiffCmd
+}
+
+func configExportCmd(config *config.Config) *cobra.Command {
+	configExportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the crc configuration as a profile",
+		Long: `Exports the crc configuration properties which don't have their default value
+as a profile which can be used with 'crc config apply' or 'crc start --config-file'.
+Secrets and passwords are not exported.`,
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return runConfigExport(config, exportOutputFormat, os.Stdout)
+		},
+	}
+	configExportCmd.Flags().StringVarP(&exportOutputFormat, "output", "o", yamlFormat, "Output format (yaml or json)")
+	return configExportCmd
+}
+
+func runConfigApply(cfg *config.Config, path string, writer io.Writer) error {
+	profile, err := config.LoadProfile(path)
+	if err != nil {
+		return err
+	}
+	changes, err := cfg.ApplyProfile(profile)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		_, err := fmt.Fprintln(writer, "The configuration already matches the profile")
+		return err
+	}
+	for _, change := range changes {
+		if _, err := fmt.Fprintf(writer, "%s: %v -> %v\n", change.Key, change.Current, change.Desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runConfigDiff(cfg *config.Config, path, outputFormat string, writer io.Writer) error {
+	profile, err := config.LoadProfile(path)
+	if err != nil {
+		return err
+	}
+	changes, err := cfg.DiffProfile(profile)
+	if err != nil {
+		return err
+	}
+	switch outputFormat {
+	case jsonFormat:
+		return json.NewEncoder(writer).Encode(changes)
+	case "":
+		if len(changes) == 0 {
+			_, err := fmt.Fprintln(writer, "The configuration already matches the profile")
+			return err
+		}
+		w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(w, "PROPERTY\tCURRENT\tPROFILE")
+		for _, change := range changes {
+			fmt.Fprintf(w, "%s\t%v\t%v\n", change.Key, change.Current, change.Desired)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("invalid output format: %s", outputFormat)
+	}
+}
+
+func runConfigExport(cfg *config.Config, outputFormat string, writer io.Writer) error {
+	profile := cfg.ExportProfile()
+	switch outputFormat {
+	case jsonFormat:
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(profile)
+	case yamlFormat:
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		defer encoder.Close()
+		return encoder.Encode(profile)
+	default:
+		return fmt.Errorf("invalid output format: %s", outputFormat)
+	}
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/crc-org/crc/v2/pkg/os/terminal"
+	"github.com/spf13/cobra"
+)
+
+const (
+	vmPathPrefix   = "vm:"
+	hostPathPrefix = "host:"
+)
+
+var cpPrivileged bool
+
+func init() {
+	cpCmd.Flags().BoolVar(&cpPrivileged, "privileged", false, "Use root access to read or write the files in the CRC instance")
+	rootCmd.AddCommand(cpCmd)
+}
+
+var cpCmd = &cobra.Command{
+	Use:   "cp SOURCE DESTINATION",
+	Short: "Copy files between the host and the CRC instance",
+	Long: `Copy a file or a directory between the host and the CRC instance.
+The path in the instance is prefixed with 'vm:', the path on the host can be
+prefixed with 'host:'. Relative paths in the instance are relative to the
+home directory of the 'core' user. Directories are copied recursively, and
+the permissions and modification times of the files are preserved.
+
+Examples:
+  crc cp ./manifests vm:/home/core/
+  crc cp vm:/var/log/crio.log host:crio.log --privileged`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runCp(args[0], args[1], cpPrivileged)
+	},
+}
+
+// parseCopyPath returns the path of 'arg' without its prefix, and whether
+// it is in the VM
+func parseCopyPath(arg string) (string, bool) {
+	if vmPath, ok := strings.CutPrefix(arg, vmPathPrefix); ok {
+		if vmPath == "" {
+			vmPath = "."
+		}
+		return vmPath, true
+	}
+	return strings.TrimPrefix(arg, hostPathPrefix), false
+}
+
+func runCp(srcArg, destArg string, privileged bool) error {
+	src, srcInVM := parseCopyPath(srcArg)
+	dest, destInVM := parseCopyPath(destArg)
+	if srcInVM == destInVM {
+		return errors.New("one of the paths must be in the CRC instance, and be prefixed with 'vm:'")
+	}
+
+	runner, err := createSSHRunner()
+	if err != nil {
+		return err
+	}
+	defer runner.Close()
+
+	options := ssh.CopyOptions{Privileged: privileged}
+	if terminal.IsShowTerminalOutput() {
+		var size int64
+		if srcInVM {
+			size, err = runner.SizeInVM(src, options)
+		} else {
+			size, err = hostSize(src)
+		}
+		if err != nil {
+			logging.Debugf("Cannot get the size of %s, the progress will not be displayed: %v", src, err)
+		} else {
+			bar := pb.Start64(size)
+			bar.Set(pb.Bytes, true)
+			defer bar.Finish()
+			options.Progress = func(n int64) {
+				bar.Add64(n)
+			}
+		}
+	}
+
+	if srcInVM {
+		return runner.CopyFromVM(src, dest, options)
+	}
+	return runner.CopyToVM(src, dest, options)
+}
+
+// hostSize returns the size of the regular files in 'file'
+func hostSize(file string) (int64, error) {
+	var size int64
+	err := filepath.Walk(file, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
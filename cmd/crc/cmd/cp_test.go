@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCopyPath(t *testing.T) {
+	for _, test := range []struct {
+		arg  string
+		path string
+		inVM bool
+	}{
+		{arg: "vm:/home/core/file", path: "/home/core/file", inVM: true},
+		{arg: "vm:", path: ".", inVM: true},
+		{arg: "host:file", path: "file"},
+		{arg: "/tmp/file", path: "/tmp/file"},
+		{arg: `C:\Users\crc\file`, path: `C:\Users\crc\file`},
+	} {
+		path, inVM := parseCopyPath(test.arg)
+		assert.Equal(t, test.path, path, test.arg)
+		assert.Equal(t, test.inVM, inVM, test.arg)
+	}
+}
+
+func TestCpNeedsOnePathInVM(t *testing.T) {
+	assert.EqualError(t, runCp("a", "host:b", false), "one of the paths must be in the CRC instance, and be prefixed with 'vm:'")
+	assert.EqualError(t, runCp("vm:a", "vm:b", false), "one of the paths must be in the CRC instance, and be prefixed with 'vm:'")
+}
+
+func TestHostSize(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a"), []byte("hello"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "b"), []byte("world!"), 0o600))
+
+	size, err := hostSize(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), size)
+}
@@ -5,11 +5,13 @@ import (
 	"net/http"
 )
 
-// CustomResponseWriter wraps the standard http.ResponseWriter and captures the response body
+// CustomResponseWriter wraps the standard http.ResponseWriter and captures the response body.
+// The body of the streamed responses, which are flushed, is not captured.
 type CustomResponseWriter struct {
 	http.ResponseWriter
 	statusCode int
 	body       *bytes.Buffer
+	streamed   bool
 }
 
 // NewCustomResponseWriter creates a new CustomResponseWriter
@@ -29,14 +31,29 @@ func (rw *CustomResponseWriter) WriteHeader(statusCode int) {
 
 // Write captures the response body and logs it
 func (rw *CustomResponseWriter) Write(p []byte) (int, error) {
-	bufferLen, err := rw.body.Write(p)
-	if err != nil {
-		return bufferLen, err
+	if !rw.streamed {
+		bufferLen, err := rw.body.Write(p)
+		if err != nil {
+			return bufferLen, err
+		}
 	}
 
 	return rw.ResponseWriter.Write(p)
 }
 
+// Flush sends the buffered data to the client, and stops the capture of the
+// response body as it is streamed
+func (rw *CustomResponseWriter) Flush() {
+	rw.streamed = true
+	rw.body.Reset()
+	_ = http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for http.ResponseController
+func (rw *CustomResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // interceptResponseBodyMiddleware injects the custom bodyConsumer function (received as second argument) into
 // http.HandleFunc logic that allows users to intercept response body as per their requirements (e.g. logging)
 // and returns updated http.Handler
@@ -49,3 +49,34 @@ func TestLogResponseBodyMiddlewareCapturesResponseAsExpected(t *testing.T) {
 	assert.Equal(t, "Testing!", responseBody.String())
 	assert.Equal(t, "Testing!", interceptedResponseBody)
 }
+
+func TestLogResponseBodyMiddlewareDoesNotCaptureStreamedResponse(t *testing.T) {
+	// Given
+	interceptedResponseBody := "not called"
+	responseBodyConsumer := func(_ int, buffer *bytes.Buffer, _ *http.Request) {
+		interceptedResponseBody = buffer.String()
+	}
+	streamHandler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		for _, frame := range []string{"frame 1\n", "frame 2\n"} {
+			_, _ = fmt.Fprint(w, frame)
+			if err := http.NewResponseController(w).Flush(); err != nil {
+				t.Error(err)
+			}
+		}
+	})
+	server := httptest.NewServer(interceptResponseBodyMiddleware(streamHandler, responseBodyConsumer))
+	defer server.Close()
+	// When
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Then
+	responseBody := new(bytes.Buffer)
+	if _, err := responseBody.ReadFrom(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "frame 1\nframe 2\n", responseBody.String())
+	assert.Equal(t, "", interceptedResponseBody)
+}
@@ -12,6 +12,7 @@ import (
 	"os/signal"
 	"regexp"
 	"runtime"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -21,10 +22,14 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/api"
 	"github.com/crc-org/crc/v2/pkg/crc/api/client"
 	"github.com/crc-org/crc/v2/pkg/crc/api/events"
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/metrics"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	"github.com/crc-org/crc/v2/pkg/crc/registrycache"
 	"github.com/crc-org/crc/v2/pkg/fileserver/fs9p"
 	"github.com/crc-org/machine/libmachine/drivers"
 	"github.com/docker/go-units"
@@ -32,6 +37,7 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"go.podman.io/common/pkg/strongunits"
 )
 
 var (
@@ -145,6 +151,13 @@ func createNewVirtualNetworkConfig(providedConfig *crcConfig.Config) types.Confi
 		}
 		virtualNetworkConfig.NAT[hostVirtualIP] = "127.0.0.1"
 	}
+	records, err := network.ParseDNSRecords(providedConfig.Get(crcConfig.DNSRecords).AsString())
+	if err != nil {
+		logging.Warnf("Ignoring the DNS records of the '%s' setting: %v", crcConfig.DNSRecords, err)
+	}
+	for _, record := range records {
+		virtualNetworkConfig.DNS = network.AddDNSRecord(virtualNetworkConfig.DNS, record)
+	}
 	return virtualNetworkConfig
 }
 
@@ -155,6 +168,7 @@ func run(configuration *types.Configuration) error {
 	}
 
 	errCh := make(chan error)
+	registryCache := newRegistryCache(config)
 
 	listener, err := httpListener()
 	if err != nil {
@@ -167,9 +181,11 @@ func run(configuration *types.Configuration) error {
 		}
 		mux := http.NewServeMux()
 		mux.Handle("/network/", interceptResponseBodyMiddleware(http.StripPrefix("/network", vn.Mux()), logResponseBodyConditionally))
-		machineClient := newMachine()
-		mux.Handle("/api/", interceptResponseBodyMiddleware(http.StripPrefix("/api", api.NewMux(config, machineClient, logging.Memory, segmentClient)), logResponseBodyConditionally))
-		mux.Handle("/events", interceptResponseBodyMiddleware(http.StripPrefix("/events", events.NewEventServer(machineClient)), logResponseBodyConditionally))
+		machineClient := newCurrentInstanceMachine()
+		eventServer := events.NewEventServer(machineClient)
+		mux.Handle("/api/", interceptResponseBodyMiddleware(http.StripPrefix("/api", api.NewMux(config, machineClient, logging.Memory, segmentClient, eventServer, registryCache)), logResponseBodyConditionally))
+		mux.Handle("/events", interceptResponseBodyMiddleware(http.StripPrefix("/events", eventServer), logResponseBodyConditionally))
+		mux.Handle("/metrics", metrics.NewHandler(machineClient, vn))
 		s := &http.Server{
 			Handler:           handlers.LoggingHandler(os.Stderr, mux),
 			ReadHeaderTimeout: 10 * time.Second,
@@ -211,6 +227,8 @@ func run(configuration *types.Configuration) error {
 		}
 	}()
 
+	go serveRegistryCache(vn, net.JoinHostPort(configuration.GatewayIP, strconv.Itoa(constants.RegistryCachePort)), registryCache, errCh)
+
 	go func() {
 		var oldCancel context.CancelFunc
 		for {
@@ -331,6 +349,57 @@ func run(configuration *types.Configuration) error {
 	}
 }
 
+// registryCacheRetryInterval is the interval at which the daemon checks if
+// the registry cache was enabled, or retries to listen after a failure
+const registryCacheRetryInterval = 10 * time.Second
+
+// serveRegistryCache serves 'registryCache' on 'address' of the virtual
+// network, which the cluster pulls the images through when the
+// 'registry-cache' setting is enabled. The setting can be enabled while the
+// daemon runs, so nothing listens on 'address' until it is. The cache is
+// optional, a failure to listen is logged and does not stop the daemon.
+func serveRegistryCache(vn *virtualnetwork.VirtualNetwork, address string, registryCache *registrycache.Cache, errCh chan<- error) {
+	var listener net.Listener
+	for {
+		if registryCache.Enabled() {
+			var err error
+			listener, err = vn.Listen("tcp", address)
+			if err == nil {
+				break
+			}
+			logging.Errorf("Cannot listen on %s for the registry cache: %v", address, err)
+		}
+		time.Sleep(registryCacheRetryInterval)
+	}
+	// no write timeout, the image layers can be large
+	s := &http.Server{
+		Handler:           handlers.LoggingHandler(os.Stderr, registryCache),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err := s.Serve(listener); err != nil {
+		errCh <- errors.Wrap(err, "registry cache http.Serve failed")
+	}
+}
+
+// newRegistryCache returns the registry cache of the daemon, it reads the
+// configuration for each request so that 'crc config set' changes are
+// applied without restarting the daemon
+func newRegistryCache(cfg *crcConfig.Config) *registrycache.Cache {
+	return registrycache.New(registrycache.Options{
+		Dir: constants.RegistryCacheDir,
+		Enabled: func() bool {
+			return cfg.Get(crcConfig.RegistryCache).AsBool()
+		},
+		Registries: func() []string {
+			return crcConfig.GetRegistryCacheRegistries(cfg)
+		},
+		MaxSize: func() strongunits.B {
+			return strongunits.GiB(cfg.Get(crcConfig.RegistryCacheSize).AsUInt()).ToBytes()
+		},
+		PullSecret: cluster.NewNonInteractivePullSecretLoader(cfg, "").Value,
+	})
+}
+
 type HostsFileEditor interface {
 	Add(ip string, hostnames ...string) error
 	Remove(hostnames ...string) error
@@ -156,6 +156,26 @@ func TestCreateNewVirtualNetworkConfig_WhenHostNetworkConfigSet_ThenSetNAT(t *te
 	assert.Equal(t, "127.0.0.1", virtualNetworkConfig.NAT["192.168.127.254"])
 }
 
+func TestCreateNewVirtualNetworkConfig_WhenDNSRecordsSet_ThenAddZones(t *testing.T) {
+	// Given
+	testCrcConfig := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(testCrcConfig)
+	_, err := testCrcConfig.Set(crcConfig.DNSRecords, "db.crc.testing=192.168.127.3,*.apps.mycompany.test")
+	assert.NoError(t, err)
+
+	// When
+	virtualNetworkConfig := createNewVirtualNetworkConfig(testCrcConfig)
+
+	// Then
+	assert.Len(t, virtualNetworkConfig.DNS, 5)
+	assert.Equal(t, "crc.testing.", virtualNetworkConfig.DNS[1].Name)
+	assert.Equal(t, "db", virtualNetworkConfig.DNS[1].Records[0].Name)
+	assert.Equal(t, net.ParseIP("192.168.127.3"), virtualNetworkConfig.DNS[1].Records[0].IP)
+	assert.Equal(t, "host", virtualNetworkConfig.DNS[1].Records[1].Name)
+	assert.Equal(t, "apps.mycompany.test.", virtualNetworkConfig.DNS[4].Name)
+	assert.Equal(t, net.ParseIP("192.168.127.2"), virtualNetworkConfig.DNS[4].DefaultIP)
+}
+
 type fakeHostsFileEditor struct {
 	addCalled    bool
 	removeCalled bool
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
@@ -58,6 +59,16 @@ func deleteMachine(client machine.Client, clearCache bool, cacheDir string, inte
 		return false, errors.New("non-interactive deletion requires --force")
 	}
 
+	if snapshots, err := client.ListSnapshots(); err != nil {
+		logging.Debugf("Cannot list the snapshots of the instance: %v", err)
+	} else if len(snapshots) > 0 {
+		names := make([]string, 0, len(snapshots))
+		for _, snapshot := range snapshots {
+			names = append(names, snapshot.Name)
+		}
+		logging.Warnf("Deleting the instance also deletes its %d snapshot(s): %s", len(snapshots), strings.Join(names, ", "))
+	}
+
 	yes := input.PromptUserForYesOrNo("Do you want to delete the instance",
 		force)
 	if yes {
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	"github.com/crc-org/crc/v2/pkg/crc/diagnose"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/spf13/cobra"
+)
+
+var diagnoseOutputFile string
+
+func init() {
+	diagnoseCmd.Flags().StringVar(&diagnoseOutputFile, "output-file", "", "File to write the tarball to (default: crc-diagnose-<timestamp>.tar.gz)")
+	rootCmd.AddCommand(diagnoseCmd)
+}
+
+var diagnoseCmd = &cobra.Command{
+	Use:   "diagnose",
+	Short: "Collect diagnostic information for bug reports",
+	Long: `Collect the crc logs, the configuration, the preflight checks results,
+the bundle metadata, the VM journal and the state of the cluster operators into
+a single tarball. The pull secret and the passwords are masked, but please
+review the content of the tarball before sharing it.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		collector := diagnose.NewCollector(config, newMachine())
+		collector.DaemonLogs = daemonLogs(daemonclient.New())
+		return runDiagnose(os.Stdout, collector, diagnoseOutputFile)
+	},
+}
+
+// daemonLogs returns the logs kept in memory by the daemon, if it is running
+func daemonLogs(client *daemonclient.Client) func() []string {
+	return func() []string {
+		logs, err := client.APIClient.Logs()
+		if err != nil {
+			logging.Debugf("Cannot get the daemon logs: %v", err)
+			return nil
+		}
+		return logs.Messages
+	}
+}
+
+func runDiagnose(writer io.Writer, collector *diagnose.Collector, outputFile string) error {
+	if outputFile == "" {
+		outputFile = fmt.Sprintf("crc-diagnose-%s.tar.gz", time.Now().Format("20060102-150405"))
+	}
+	f, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err := collector.Collect(context.Background(), f); err != nil {
+		f.Close()
+		os.Remove(outputFile)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(writer, "Diagnostic information written to %s\n", outputFile)
+	return nil
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/crc-org/crc/v2/pkg/crc/preflight"
+)
+
+var dryRun bool
+
+// dryRunResult is the output of 'crc setup --dry-run' and
+// 'crc cleanup --dry-run'
+type dryRunResult struct {
+	Success bool                      `json:"success"`
+	Actions []preflight.PlannedAction `json:"actions"`
+
+	command string
+}
+
+func (s *dryRunResult) prettyPrintTo(writer io.Writer) error {
+	if len(s.Actions) == 0 {
+		_, err := fmt.Fprintf(writer, "'%s' would not change anything on this host\n", s.command)
+		return err
+	}
+	fmt.Fprintf(writer, "'%s' would make the following changes:\n", s.command)
+	for _, action := range s.Actions {
+		fmt.Fprintf(writer, "%s\n", action.Description)
+		switch {
+		case action.Manual:
+			fmt.Fprintln(writer, "  must be fixed manually")
+		case action.Changes == nil:
+			fmt.Fprintln(writer, "  changes are not described")
+		}
+		for _, change := range action.Changes {
+			sudo := ""
+			if change.Privileged {
+				sudo = " (sudo)"
+			}
+			fmt.Fprintf(writer, "  %s %s %s%s\n", change.Action, change.Kind, change.Target, sudo)
+		}
+	}
+	return nil
+}
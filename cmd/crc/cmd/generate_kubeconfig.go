@@ -35,7 +35,7 @@ func runGenerateKubeconfig() error {
 		return fmt.Errorf("the CRC instance is not running, cannot retrieve kubeconfig")
 	}
 
-	data, err := os.ReadFile(constants.KubeconfigFilePath)
+	data, err := os.ReadFile(constants.GetKubeconfigFilePath(client.GetName()))
 	if err != nil {
 		return fmt.Errorf("Error reading kubeconfig: %v", err)
 	}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+)
+
+var (
+	imageStreamTag string
+	imageNamespace string
+)
+
+func init() {
+	imageLoadCmd.Flags().StringVar(&imageStreamTag, "imagestream", "", "Also push the image to the internal registry as this image stream tag, NAME[:TAG]")
+	imageLoadCmd.Flags().StringVarP(&imageNamespace, "namespace", "n", "default", "Namespace of the image stream")
+	imageCmd.AddCommand(imageLoadCmd)
+	rootCmd.AddCommand(imageCmd)
+}
+
+var imageCmd = &cobra.Command{
+	Use:   "image SUBCOMMAND [flags]",
+	Short: "Manage the images of the CRC instance",
+	Long:  "Manage the images of the container storage of the CRC instance",
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var imageLoadCmd = &cobra.Command{
+	Use:   "load IMAGE|ARCHIVE",
+	Short: "Load an image in the CRC instance",
+	Long: `Load an image in the container storage of the CRC instance, which is used
+by CRI-O, so that the pods can run it without pulling it. The argument is
+either an image archive, in the docker or OCI format, or an image of the
+host which is exported with 'podman save'. The archive is streamed over SSH.
+With --imagestream, the image is also pushed to the internal registry of
+OpenShift, as a tag of an image stream of the --namespace namespace.
+
+Examples:
+  crc image load localhost/myapp:dev
+  crc image load ./myapp.tar
+  crc image load localhost/myapp:dev --imagestream myapp:dev --namespace myproject`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runImageLoad(args[0], imageStreamTag, imageNamespace)
+	},
+}
+
+func runImageLoad(source, imageStreamTag, namespace string) error {
+	var imageStream, tag string
+	if imageStreamTag != "" {
+		if crcConfig.GetPreset(config) == crcPreset.Microshift {
+			return fmt.Errorf("--imagestream is not supported with the %s preset, it has no internal registry", crcPreset.Microshift)
+		}
+		var err error
+		if imageStream, tag, err = cluster.ParseImageStreamTag(imageStreamTag); err != nil {
+			return err
+		}
+	}
+
+	runner, err := createSSHRunner()
+	if err != nil {
+		return err
+	}
+	defer runner.Close()
+
+	archive, wait, err := openImageArchive(source)
+	if err != nil {
+		return err
+	}
+	logging.Infof("Loading %s in the CRC instance...", source)
+	images, err := cluster.LoadImage(runner, archive)
+	// closing the archive stops the export when the load failed
+	archive.Close()
+	if err := errors.Join(err, wait()); err != nil {
+		return err
+	}
+	fmt.Printf("Loaded image: %s\n", strings.Join(images, ", "))
+
+	if imageStream == "" {
+		return nil
+	}
+	logging.Infof("Pushing %s to the internal registry...", images[0])
+	imageStreamTag, err = cluster.PushImageToImageStream(oc.UseOCWithSSH(runner), runner, images[0], namespace, imageStream, tag)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pushed image stream tag: %s\n", imageStreamTag)
+	return nil
+}
+
+// openImageArchive returns a reader of the image archive 'source', which is
+// either a file or an image exported from the host with 'podman save'. The
+// returned function waits for the export and must be called once the archive
+// is read.
+func openImageArchive(source string) (io.ReadCloser, func() error, error) {
+	if info, err := os.Stat(source); err == nil && info.Mode().IsRegular() {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, nil, err
+		}
+		return file, func() error { return nil }, nil
+	}
+
+	podman, err := exec.LookPath("podman")
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s is not a file, and podman is needed to export it from the host: %w", source, err)
+	}
+	// #nosec G204
+	cmd := exec.Command(podman, "save", "--format", "oci-archive", source)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	wait := func() error {
+		if err := cmd.Wait(); err != nil {
+			return fmt.Errorf("Failed to export %s with podman: %s: %w", source, strings.TrimSpace(stderr.String()), err)
+		}
+		return nil
+	}
+	return stdout, wait, nil
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	instanceCmd.AddCommand(instanceListCmd)
+	instanceCmd.AddCommand(instanceCreateCmd)
+	instanceCmd.AddCommand(instanceSwitchCmd)
+	addOutputFormatFlag(instanceListCmd)
+	instanceCreateCmd.Flags().Bool("switch", false, "Make the new instance the current one")
+	rootCmd.AddCommand(instanceCmd)
+}
+
+var instanceCmd = &cobra.Command{
+	Use:   "instance SUBCOMMAND [flags]",
+	Short: "Manage CRC instances",
+	Long: `Manage CRC instances
+The current instance is the one the 'start', 'stop', 'status', 'console' and
+'delete' commands act on. Only one instance can be running at a time.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var instanceListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the CRC instances",
+	Long:  "List the CRC instances and their state",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		instances, err := listInstances(crcConfig.GetInstanceName(config), machine.GetInstanceState)
+		if err != nil {
+			return err
+		}
+		return render(instances, os.Stdout, outputFormat)
+	},
+}
+
+var instanceCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create a new CRC instance",
+	Long: `Create a new CRC instance
+Its virtual machine is created on the first 'crc start' after switching to it.
+The preset, bundle, cpus, memory, disk-size, persistent-volume-size and ingress
+ports settings are stored separately for each instance.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := machine.CreateInstance(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("Instance '%s' created\n", args[0])
+		switchToNew, err := cmd.Flags().GetBool("switch")
+		if err != nil || !switchToNew {
+			return err
+		}
+		return runInstanceSwitch(os.Stdout, config, daemonclient.New(), args[0])
+	},
+}
+
+var instanceSwitchCmd = &cobra.Command{
+	Use:   "switch NAME",
+	Short: "Change the current CRC instance",
+	Long: `Change the CRC instance the crc commands act on
+The current instance cannot be changed while it is running and served by the
+daemon, stop it first.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runInstanceSwitch(os.Stdout, config, daemonclient.New(), args[0])
+	},
+}
+
+func runInstanceSwitch(writer io.Writer, config *crcConfig.Config, client *daemonclient.Client, name string) error {
+	if err := checkDaemonNotServingOtherInstance(client, name); err != nil {
+		return err
+	}
+	if _, err := config.Set(crcConfig.Instance, name); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "Switched to instance '%s'\n", name)
+	return err
+}
+
+// checkDaemonNotServingOtherInstance refuses to switch away from the running
+// instance the daemon serves. The daemon follows the 'instance' setting, so
+// its API would act on the new current instance while the virtual machine of
+// the other one keeps using its network.
+func checkDaemonNotServingOtherInstance(client *daemonclient.Client, name string) error {
+	instances, err := client.APIClient.Instances()
+	if err != nil {
+		logging.Debugf("Cannot list the instances of the daemon, assuming it is not running: %v", err)
+		return nil
+	}
+	for _, served := range instances.Instances {
+		if served.Current && served.Name != name && served.State == state.Running {
+			return fmt.Errorf("the daemon is serving the running instance '%s', stop it with 'crc stop' before switching to '%s'", served.Name, name)
+		}
+	}
+	return nil
+}
+
+type instance struct {
+	Name    string      `json:"name"`
+	Current bool        `json:"current"`
+	Created bool        `json:"created"`
+	State   state.State `json:"state,omitempty"`
+}
+
+type instanceList struct {
+	Instances []instance `json:"instances"`
+}
+
+func listInstances(current string, getState func(name string) (state.State, bool, error)) (*instanceList, error) {
+	names, err := machine.ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	list := &instanceList{
+		Instances: []instance{},
+	}
+	for _, name := range names {
+		vmState, created, err := getState(name)
+		if err != nil {
+			vmState = state.Error
+		}
+		if !created {
+			vmState = ""
+		}
+		list.Instances = append(list.Instances, instance{
+			Name:    name,
+			Current: name == current,
+			Created: created,
+			State:   vmState,
+		})
+	}
+	return list, nil
+}
+
+func (l *instanceList) prettyPrintTo(writer io.Writer) error {
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tSTATE")
+	for _, instance := range l.Instances {
+		current := ""
+		if instance.Current {
+			current = "*"
+		}
+		vmState := string(instance.State)
+		if !instance.Created {
+			vmState = "Does not exist"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", current, instance.Name, vmState)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	apiClient "github.com/crc-org/crc/v2/pkg/crc/api/client"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	mocks "github.com/crc-org/crc/v2/test/mocks/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setUpInstances(t *testing.T) {
+	instancesDir := constants.CrcInstancesDir
+	constants.CrcInstancesDir = t.TempDir()
+	t.Cleanup(func() {
+		constants.CrcInstancesDir = instancesDir
+	})
+	require.NoError(t, machine.CreateInstance("microshift"))
+}
+
+func fakeInstanceState(name string) (state.State, bool, error) {
+	if name == constants.DefaultName {
+		return state.Running, true, nil
+	}
+	return "", false, nil
+}
+
+func TestInstanceListPlain(t *testing.T) {
+	setUpInstances(t)
+
+	instances, err := listInstances("microshift", fakeInstanceState)
+	require.NoError(t, err)
+	out := new(bytes.Buffer)
+	assert.NoError(t, render(instances, out, ""))
+	assert.Equal(t, `CURRENT   NAME         STATE
+          crc          Running
+*         microshift   Does not exist
+`, out.String())
+}
+
+func TestInstanceListJSON(t *testing.T) {
+	setUpInstances(t)
+
+	instances, err := listInstances(constants.DefaultName, fakeInstanceState)
+	require.NoError(t, err)
+	out := new(bytes.Buffer)
+	assert.NoError(t, render(instances, out, jsonFormat))
+	assert.JSONEq(t, `{"instances": [
+		{"name": "crc", "current": true, "created": true, "state": "Running"},
+		{"name": "microshift", "current": false, "created": false}
+	]}`, out.String())
+}
+
+func TestInstanceCreateExisting(t *testing.T) {
+	setUpInstances(t)
+
+	assert.EqualError(t, machine.CreateInstance("microshift"), "instance 'microshift' already exists")
+	assert.EqualError(t, machine.CreateInstance(constants.DefaultName), "instance 'crc' already exists")
+	assert.Error(t, machine.CreateInstance("Invalid_Name"))
+}
+
+func TestInstanceSwitchWhileTheDaemonServesARunningInstance(t *testing.T) {
+	setUpInstances(t)
+	config := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(config)
+
+	client := mocks.NewClient(t)
+	client.On("Instances").Return(apiClient.InstancesResult{Instances: []apiClient.InstanceResult{
+		{Name: constants.DefaultName, Current: true, Created: true, State: state.Running},
+		{Name: "microshift"},
+	}}, nil)
+	out := new(bytes.Buffer)
+	assert.EqualError(t, runInstanceSwitch(out, config, &daemonclient.Client{APIClient: client}, "microshift"),
+		"the daemon is serving the running instance 'crc', stop it with 'crc stop' before switching to 'microshift'")
+	assert.Equal(t, constants.DefaultName, crcConfig.GetInstanceName(config))
+	assert.NoError(t, runInstanceSwitch(out, config, &daemonclient.Client{APIClient: client}, constants.DefaultName))
+}
+
+func TestInstanceSwitchWithoutDaemon(t *testing.T) {
+	setUpInstances(t)
+	config := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(config)
+
+	client := mocks.NewClient(t)
+	client.On("Instances").Return(apiClient.InstancesResult{}, errors.New("connection refused"))
+	out := new(bytes.Buffer)
+	assert.NoError(t, runInstanceSwitch(out, config, &daemonclient.Client{APIClient: client}, "microshift"))
+	assert.Equal(t, "Switched to instance 'microshift'\n", out.String())
+	assert.Equal(t, "microshift", crcConfig.GetInstanceName(config))
+}
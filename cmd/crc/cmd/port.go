@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+)
+
+var portProtocol string
+
+func init() {
+	portCmd.AddCommand(portExposeCmd)
+	portCmd.AddCommand(portUnexposeCmd)
+	portCmd.AddCommand(portListCmd)
+	for _, cmd := range portCmd.Commands() {
+		addOutputFormatFlag(cmd)
+	}
+	for _, cmd := range []*cobra.Command{portExposeCmd, portUnexposeCmd} {
+		cmd.Flags().StringVar(&portProtocol, "protocol", "tcp", "Protocol of the forwarded port (tcp or udp)")
+	}
+	rootCmd.AddCommand(portCmd)
+}
+
+var portCmd = &cobra.Command{
+	Use:   "port SUBCOMMAND [flags]",
+	Short: "Manage the port forwards of the instance",
+	Long: `Manage the port forwards of the instance
+A port forward makes a port of the instance, such as a NodePort service, or of
+another address reachable from the instance network, available on the host.
+The port forwards are applied each time the instance starts. They require the
+'user' network mode.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var portExposeCmd = &cobra.Command{
+	Use:   "expose [HOST_IP:]HOST_PORT [IP:]PORT",
+	Short: "Forward a host port to the instance",
+	Long: `Forward a host port to a port of the instance, or to IP:PORT in the instance network.
+The host port listens on 127.0.0.1 unless HOST_IP is given.
+
+Examples:
+  crc port expose 8080 30080
+  crc port expose 0.0.0.0:5432 192.168.127.2:5432`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runPortExpose(os.Stdout, newMachine(), types.PortForward{
+			Protocol: portProtocol,
+			Local:    args[0],
+			Remote:   args[1],
+		}, outputFormat)
+	},
+}
+
+var portUnexposeCmd = &cobra.Command{
+	Use:   "unexpose [HOST_IP:]HOST_PORT",
+	Short: "Remove a port forward of the instance",
+	Long:  "Remove the forward of a host port to the instance",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runPortUnexpose(os.Stdout, newMachine(), portProtocol, args[0], outputFormat)
+	},
+}
+
+var portListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the port forwards of the instance",
+	Long:  "List the port forwards of the instance, and whether they are currently applied",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runPortList(os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+func runPortExpose(writer io.Writer, client machine.Client, forward types.PortForward, outputFormat string) error {
+	err := checkIfMachineMissing(client)
+	var exposed *types.PortForward
+	if err == nil {
+		exposed, err = client.ExposePort(forward)
+	}
+	result := &portResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		action:  "Exposed",
+	}
+	if exposed != nil {
+		result.PortForward = *exposed
+	}
+	return render(result, writer, outputFormat)
+}
+
+func runPortUnexpose(writer io.Writer, client machine.Client, protocol, local, outputFormat string) error {
+	err := checkIfMachineMissing(client)
+	if err == nil {
+		err = client.UnexposePort(protocol, local)
+	}
+	return render(&portResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		PortForward: types.PortForward{
+			Protocol: protocol,
+			Local:    local,
+		},
+		action: "Unexposed",
+	}, writer, outputFormat)
+}
+
+func runPortList(writer io.Writer, client machine.Client, outputFormat string) error {
+	if err := checkIfMachineMissing(client); err != nil {
+		return err
+	}
+	forwards, err := client.ListPortForwards()
+	if err != nil {
+		return err
+	}
+	return render(&portList{Ports: forwards}, writer, outputFormat)
+}
+
+type portResult struct {
+	Success bool                         `json:"success"`
+	Error   *crcErrors.SerializableError `json:"error,omitempty"`
+	types.PortForward
+	action string
+}
+
+func (s *portResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if s.Remote == "" {
+		_, err := fmt.Fprintf(writer, "%s %s/%s\n", s.action, s.Protocol, s.Local)
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "%s %s/%s -> %s\n", s.action, s.Protocol, s.Local, s.Remote)
+	return err
+}
+
+type portList struct {
+	Ports []types.PortForwardInfo `json:"ports"`
+}
+
+func (s *portList) prettyPrintTo(writer io.Writer) error {
+	if len(s.Ports) == 0 {
+		_, err := fmt.Fprintln(writer, "No port forwards")
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "PROTOCOL\tHOST\tINSTANCE\tACTIVE")
+	for _, port := range s.Ports {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", port.Protocol, port.Local, port.Remote, port.Active)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortExposePlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPortExpose(out, fakemachine.NewClient(), fakemachine.DummyPortForward, ""))
+	assert.Equal(t, "Exposed tcp/127.0.0.1:5432 -> 192.168.127.2:30432\n", out.String())
+}
+
+func TestPortExposeJSONError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPortExpose(out, fakemachine.NewFailingClient(), types.PortForward{Local: "8080", Remote: "80"}, jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "port expose failed", "protocol": "", "local": "", "remote": ""}`, out.String())
+}
+
+func TestPortUnexposePlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPortUnexpose(out, fakemachine.NewClient(), "tcp", "5432", ""))
+	assert.Equal(t, "Unexposed tcp/5432\n", out.String())
+}
+
+func TestPortListPlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPortList(out, fakemachine.NewClient(), ""))
+	assert.Equal(t, `PROTOCOL   HOST             INSTANCE              ACTIVE
+tcp        127.0.0.1:5432   192.168.127.2:30432   true
+`, out.String())
+}
+
+func TestPortListJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runPortList(out, fakemachine.NewClient(), jsonFormat))
+	assert.JSONEq(t, `{"ports": [{"protocol": "tcp", "local": "127.0.0.1:5432", "remote": "192.168.127.2:30432", "active": true}]}`, out.String())
+}
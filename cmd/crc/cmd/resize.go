@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.podman.io/common/pkg/strongunits"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+)
+
+var (
+	resizeCPUs     uint
+	resizeMemory   uint
+	resizeDiskSize uint
+)
+
+func init() {
+	resizeCmd.Flags().UintVarP(&resizeCPUs, crcConfig.CPUs, "c", 0, "Number of CPU cores to allocate to the instance")
+	resizeCmd.Flags().UintVarP(&resizeMemory, crcConfig.Memory, "m", 0, "MiB of memory to allocate to the instance")
+	resizeCmd.Flags().UintVarP(&resizeDiskSize, crcConfig.DiskSize, "d", 0, "Total size in GiB of the disk used by the instance, it can only grow")
+	addOutputFormatFlag(resizeCmd)
+	rootCmd.AddCommand(resizeCmd)
+}
+
+var resizeCmd = &cobra.Command{
+	Use:   "resize",
+	Short: "Change the CPUs, memory or disk size of the instance",
+	Long: `Change the CPUs, memory or disk size of the instance without deleting it
+A running instance is stopped to apply the changes, and started again. The
+'cpus', 'memory' and 'disk-size' settings are updated accordingly.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		resizeConfig := types.ResizeConfig{
+			CPUs:     resizeCPUs,
+			Memory:   strongunits.MiB(resizeMemory),
+			DiskSize: strongunits.GiB(resizeDiskSize),
+		}
+		return runResize(cmd.Context(), os.Stdout, newMachine(), resizeConfig, outputFormat)
+	},
+}
+
+func runResize(ctx context.Context, writer io.Writer, client machine.Client, resizeConfig types.ResizeConfig, outputFormat string) error {
+	if resizeConfig == (types.ResizeConfig{}) {
+		return fmt.Errorf("At least one of --%s, --%s or --%s must be set", crcConfig.CPUs, crcConfig.Memory, crcConfig.DiskSize)
+	}
+	err := checkIfMachineMissing(client)
+	var result *types.ResizeResult
+	if err == nil {
+		result, err = client.Resize(resizeConfig)
+	}
+	restarted := false
+	if err == nil && result.RestartRequired {
+		// starting the instance also grows its root filesystem to the new
+		// disk size
+		_, err = runStart(ctx)
+		restarted = err == nil
+	}
+	return render(toResizeResult(result, restarted, err), writer, outputFormat)
+}
+
+func toResizeResult(result *types.ResizeResult, restarted bool, err error) *resizeResult {
+	res := &resizeResult{
+		Success:   err == nil,
+		Error:     crcErrors.ToSerializableError(err),
+		Restarted: restarted,
+	}
+	if result != nil {
+		res.CPUs = result.CPUs
+		res.Memory = result.Memory
+		res.DiskSize = result.DiskSize
+		res.Changed = result.Changed
+		res.restartRequired = result.RestartRequired
+	}
+	return res
+}
+
+type resizeResult struct {
+	Success   bool                         `json:"success"`
+	Error     *crcErrors.SerializableError `json:"error,omitempty"`
+	CPUs      uint                         `json:"cpus,omitempty"`
+	Memory    strongunits.MiB              `json:"memory,omitempty"`
+	DiskSize  strongunits.GiB              `json:"diskSize,omitempty"`
+	Changed   []string                     `json:"changed,omitempty"`
+	Restarted bool                         `json:"restarted"`
+
+	restartRequired bool
+}
+
+func (s *resizeResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		if s.restartRequired {
+			return fmt.Errorf("The instance was resized but could not be started again: %w", s.Error)
+		}
+		return s.Error
+	}
+	resources := fmt.Sprintf("%d CPUs, %d MiB of memory and a %d GiB disk", s.CPUs, s.Memory, s.DiskSize)
+	if len(s.Changed) == 0 {
+		_, err := fmt.Fprintf(writer, "No change, the instance has %s\n", resources)
+		return err
+	}
+	if _, err := fmt.Fprintf(writer, "Changed %s, the instance has %s\n", strings.Join(s.Changed, ", "), resources); err != nil {
+		return err
+	}
+	if s.Restarted {
+		_, err := fmt.Fprintf(writer, "The instance was restarted to apply the changes\n")
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "The changes are applied on the next 'crc start'\n")
+	return err
+}
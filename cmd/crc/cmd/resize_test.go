@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizePlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runResize(context.Background(), out, fakemachine.NewClient(), types.ResizeConfig{CPUs: 6, Memory: 12288, DiskSize: 50}, ""))
+	assert.Equal(t, `Changed cpus, memory, disk-size, the instance has 6 CPUs, 12288 MiB of memory and a 50 GiB disk
+The changes are applied on the next 'crc start'
+`, out.String())
+}
+
+func TestResizeJSONError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runResize(context.Background(), out, fakemachine.NewFailingClient(), types.ResizeConfig{CPUs: 6}, jsonFormat))
+	assert.JSONEq(t, `{"success": false, "error": "resize failed", "restarted": false}`, out.String())
+}
+
+func TestResizeWithoutFlags(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.EqualError(t, runResize(context.Background(), out, fakemachine.NewClient(), types.ResizeConfig{}, ""),
+		"At least one of --cpus, --memory or --disk-size must be set")
+}
@@ -44,7 +44,7 @@ var rootCmd = &cobra.Command{
 
 var (
 	globalForce   bool
-	viper         *crcConfig.ViperStorage
+	viper         *crcConfig.InstanceStorage
 	config        *crcConfig.Config
 	segmentClient *segment.Client
 )
@@ -151,11 +151,12 @@ func setProxyDefaults() error {
 	return nil
 }
 
-func newConfig() (*crcConfig.Config, *crcConfig.ViperStorage, error) {
-	viper, err := crcConfig.NewViperStorage(constants.ConfigPath, constants.CrcEnvPrefix)
+func newConfig() (*crcConfig.Config, *crcConfig.InstanceStorage, error) {
+	globalViper, err := crcConfig.NewViperStorage(constants.ConfigPath, constants.CrcEnvPrefix)
 	if err != nil {
 		return nil, nil, err
 	}
+	viper := crcConfig.NewInstanceStorage(globalViper, constants.CrcEnvPrefix)
 	cfg := crcConfig.New(viper, crcConfig.NewSecretStorage())
 	crcConfig.RegisterSettings(cfg)
 	preflight.RegisterSettings(cfg)
@@ -163,7 +164,15 @@ func newConfig() (*crcConfig.Config, *crcConfig.ViperStorage, error) {
 }
 
 func newMachine() machine.Client {
-	return machine.NewSynchronizedMachine(machine.NewClient(constants.DefaultName, logging.IsDebug(), config))
+	return machine.NewSynchronizedMachine(machine.NewClient(crcConfig.GetInstanceName(config), logging.IsDebug(), config))
+}
+
+// newCurrentInstanceMachine returns a machine client which follows the
+// changes of the current instance, for use by long running commands
+func newCurrentInstanceMachine() machine.Client {
+	return machine.NewCurrentInstance(config, func(name string) machine.Client {
+		return machine.NewSynchronizedMachine(machine.NewClient(name, logging.IsDebug(), config))
+	})
 }
 
 func addForceFlag(cmd *cobra.Command) {
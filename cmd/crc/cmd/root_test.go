@@ -23,25 +23,60 @@ func TestCrcManPageGenerator_WhenInvoked_GeneratesManPagesForAllCrcSubCommands(t
 		manPagesFiles = append(manPagesFiles, manPage.Name())
 	}
 	assert.ElementsMatch(t, []string{
+		"crc-bundle-download.1",
 		"crc-bundle-generate.1",
+		"crc-bundle-list.1",
+		"crc-bundle-prune.1",
+		"crc-bundle-verify.1",
 		"crc-bundle.1",
+		"crc-cache-prune.1",
+		"crc-cache-stats.1",
+		"crc-cache.1",
 		"crc-cleanup.1",
+		"crc-config-apply.1",
+		"crc-config-diff.1",
+		"crc-config-export.1",
 		"crc-config-get.1",
 		"crc-config-set.1",
 		"crc-config-unset.1",
 		"crc-config-view.1",
 		"crc-config.1",
 		"crc-console.1",
+		"crc-cp.1",
 		"crc-delete.1",
+		"crc-diagnose.1",
 		"crc-generate-kubeconfig.1",
+		"crc-image-load.1",
+		"crc-image.1",
+		"crc-instance-create.1",
+		"crc-instance-list.1",
+		"crc-instance-switch.1",
+		"crc-instance.1",
 		"crc-ip.1",
 		"crc-oc-env.1",
 		"crc-podman-env.1",
+		"crc-port-expose.1",
+		"crc-port-list.1",
+		"crc-port-unexpose.1",
+		"crc-port.1",
+		"crc-resize.1",
 		"crc-setup.1",
+		"crc-snapshot-create.1",
+		"crc-snapshot-delete.1",
+		"crc-snapshot-list.1",
+		"crc-snapshot-restore.1",
+		"crc-snapshot.1",
+		"crc-ssh.1",
 		"crc-start.1",
 		"crc-status.1",
 		"crc-stop.1",
 		"crc-version.1",
+		"crc-volume-attach.1",
+		"crc-volume-create.1",
+		"crc-volume-delete.1",
+		"crc-volume-detach.1",
+		"crc-volume-list.1",
+		"crc-volume.1",
 		"crc.1",
 	}, manPagesFiles)
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"text/tabwriter"
+	"time"
 
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
@@ -27,6 +29,7 @@ func init() {
 	setupCmd.Flags().StringP(crcConfig.Bundle, "b", constants.GetDefaultBundlePath(crcConfig.GetPreset(config)), crcConfig.BundleHelpMsg(config))
 	setupCmd.Flags().BoolVar(&checkOnly, "check-only", false, "Only run the preflight checks, don't try to fix any misconfiguration")
 	setupCmd.Flags().BoolVar(&forceShowProgressbars, "show-progressbars", false, "Always show the progress bars for download and extraction")
+	setupCmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only list the changes which would be made to the host, without making them")
 	addOutputFormatFlag(setupCmd)
 	rootCmd.AddCommand(setupCmd)
 }
@@ -44,6 +47,14 @@ var setupCmd = &cobra.Command{
 }
 
 func runSetup(_ []string) error {
+	if dryRun {
+		return render(&dryRunResult{
+			Success: true,
+			Actions: preflight.PlanSetupHost(config),
+			command: "crc setup",
+		}, os.Stdout, outputFormat)
+	}
+
 	if config.Get(crcConfig.ConsentTelemetry).AsString() == "" {
 		fmt.Println("CRC is constantly improving and we would like to know more about usage (more details at https://developers.redhat.com/article/tool-data-collection)")
 		fmt.Println("Your preference can be changed manually if desired using 'crc config set consent-telemetry <yes/no>'")
@@ -66,26 +77,53 @@ func runSetup(_ []string) error {
 
 	// set global variable to force terminal output
 	crcTerminal.ForceShowOutput = forceShowProgressbars
-	err := preflight.SetupHost(config, checkOnly)
-	if err != nil && checkOnly {
+	if checkOnly {
+		return runSetupCheckOnly(os.Stdout, preflight.CheckHost(config), outputFormat)
+	}
+	err := preflight.SetupHost(config, false)
+
+	return render(&setupResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+	}, os.Stdout, outputFormat)
+}
+
+// runSetupCheckOnly renders the report of the preflight checks, it fails
+// with preflightFailedExitCode when one of them failed
+func runSetupCheckOnly(writer io.Writer, checks []preflight.CheckResult, outputFormat string) error {
+	var failed int
+	for _, check := range checks {
+		if check.Result == preflight.CheckFailed {
+			failed++
+		}
+	}
+	var err error
+	if failed > 0 {
 		err = exec.CodeExitError{
-			Err:  err,
+			Err:  fmt.Errorf("%d of %d preflight checks failed, run 'crc setup' to fix them", failed, len(checks)),
 			Code: preflightFailedExitCode,
 		}
 	}
-
 	return render(&setupResult{
 		Success: err == nil,
 		Error:   crcErrors.ToSerializableError(err),
-	}, os.Stdout, outputFormat)
+		Checks:  checks,
+	}, writer, outputFormat)
 }
 
 type setupResult struct {
 	Success bool                         `json:"success"`
 	Error   *crcErrors.SerializableError `json:"error,omitempty"`
+	// Checks is the report of the preflight checks with --check-only
+	Checks []preflight.CheckResult `json:"checks,omitempty"`
 }
 
 func (s *setupResult) prettyPrintTo(writer io.Writer) error {
+	if s.Checks != nil {
+		if err := printChecks(writer, s.Checks); err != nil {
+			return err
+		}
+	}
 	if s.Error != nil {
 		return s.Error
 	}
@@ -93,3 +131,21 @@ func (s *setupResult) prettyPrintTo(writer io.Writer) error {
 		"Use 'crc start' to start the instance")
 	return err
 }
+
+func printChecks(writer io.Writer, checks []preflight.CheckResult) error {
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CHECK\tRESULT\tFIX AVAILABLE\tDURATION\tDETAILS")
+	for _, check := range checks {
+		details := check.Error
+		if check.SkippedByConfig {
+			details = fmt.Sprintf("skipped by 'skip-%s' setting", check.ID)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\n",
+			check.Description,
+			check.Result,
+			check.FixAvailable,
+			time.Duration(check.DurationMs)*time.Millisecond,
+			details)
+	}
+	return w.Flush()
+}
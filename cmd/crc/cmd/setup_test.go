@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/preflight"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,3 +44,82 @@ func TestSetupRenderActionJSONFailure(t *testing.T) {
 	}, out, jsonFormat))
 	assert.JSONEq(t, `{"success": false, "error": "broken"}`, out.String())
 }
+
+var testCheckResults = []preflight.CheckResult{
+	{ID: "check-root-user", Description: "Checking if running as non-root", Result: preflight.CheckPassed, DurationMs: 1},
+	{ID: "check-ram", Description: "Checking minimum RAM requirements", Result: preflight.CheckSkipped, SkippedByConfig: true},
+	{Description: "Checking if libvirt is installed", Labels: map[string]string{"os": "linux"}, Result: preflight.CheckFailed, Error: "libvirt is not installed", FixAvailable: true, DurationMs: 1500},
+}
+
+func TestSetupCheckOnlyPlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	err := runSetupCheckOnly(out, testCheckResults, "")
+	assert.EqualError(t, err, "1 of 3 preflight checks failed, run 'crc setup' to fix them")
+	assert.Equal(t, "CHECK                               RESULT    FIX AVAILABLE   DURATION   DETAILS\n"+
+		"Checking if running as non-root     passed    false           1ms        \n"+
+		"Checking minimum RAM requirements   skipped   false           0s         skipped by 'skip-check-ram' setting\n"+
+		"Checking if libvirt is installed    failed    true            1.5s       libvirt is not installed\n", out.String())
+}
+
+func TestSetupCheckOnlyJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSetupCheckOnly(out, testCheckResults, jsonFormat))
+	assert.JSONEq(t, `{
+  "success": false,
+  "error": "1 of 3 preflight checks failed, run 'crc setup' to fix them",
+  "checks": [
+    {"id": "check-root-user", "description": "Checking if running as non-root", "result": "passed", "fixAvailable": false, "durationMs": 1},
+    {"id": "check-ram", "description": "Checking minimum RAM requirements", "result": "skipped", "fixAvailable": false, "skippedByConfig": true, "durationMs": 0},
+    {"description": "Checking if libvirt is installed", "labels": {"os": "linux"}, "result": "failed", "error": "libvirt is not installed", "fixAvailable": true, "durationMs": 1500}
+  ]
+}`, out.String())
+}
+
+func TestSetupCheckOnlySuccess(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSetupCheckOnly(out, testCheckResults[:2], jsonFormat))
+	assert.Contains(t, out.String(), `"success": true`)
+}
+
+func TestSetupDryRunPlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, render(&dryRunResult{
+		Success: true,
+		Actions: []preflight.PlannedAction{
+			{ID: "check-network-manager-config", Description: "Writing Network Manager config for crc", Changes: []preflight.Change{
+				{Kind: preflight.FileChange, Target: "/etc/NetworkManager/conf.d/crc-nm-dnsmasq.conf", Action: "write", Privileged: true},
+				{Kind: preflight.UnitChange, Target: "NetworkManager", Action: "reload", Privileged: true},
+			}},
+			{ID: "check-ram", Description: "crc requires at least 10.5GB to run", Manual: true},
+			{ID: "check-bundle-extracted", Description: "Getting bundle for the CRC executable"},
+		},
+		command: "crc setup",
+	}, out, ""))
+	assert.Equal(t, `'crc setup' would make the following changes:
+Writing Network Manager config for crc
+  write file /etc/NetworkManager/conf.d/crc-nm-dnsmasq.conf (sudo)
+  reload systemd-unit NetworkManager (sudo)
+crc requires at least 10.5GB to run
+  must be fixed manually
+Getting bundle for the CRC executable
+  changes are not described
+`, out.String())
+}
+
+func TestSetupDryRunJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, render(&dryRunResult{
+		Success: true,
+		Actions: []preflight.PlannedAction{
+			{Description: "Removing crc libvirt storage pool", Changes: []preflight.Change{
+				{Kind: preflight.LibvirtChange, Target: "pool/crc", Action: "destroy"},
+			}},
+		},
+		command: "crc cleanup",
+	}, out, jsonFormat))
+	assert.JSONEq(t, `{"success": true, "actions": [{"description": "Removing crc libvirt storage pool", "changes": [{"kind": "libvirt", "target": "pool/crc", "action": "destroy"}]}]}`, out.String())
+
+	out.Reset()
+	assert.NoError(t, render(&dryRunResult{Success: true, command: "crc setup"}, out, ""))
+	assert.Equal(t, "'crc setup' would not change anything on this host\n", out.String())
+}
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+	"go.podman.io/common/pkg/strongunits"
+
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/input"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+)
+
+func init() {
+	snapshotCmd.AddCommand(snapshotCreateCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+	for _, cmd := range snapshotCmd.Commands() {
+		addOutputFormatFlag(cmd)
+	}
+	addForceFlag(snapshotRestoreCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot SUBCOMMAND [flags]",
+	Short: "Manage snapshots of the instance",
+	Long: `Manage snapshots of the instance
+A snapshot saves the disk of the instance virtual machine, together with its
+kubeconfig and passwords. The instance must be stopped to create or restore a
+snapshot.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create a snapshot of the instance",
+	Long:  "Create a snapshot of the stopped instance",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runSnapshotCreate(os.Stdout, newMachine(), args[0], outputFormat)
+	},
+}
+
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the snapshots of the instance",
+	Long:  "List the snapshots of the instance",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runSnapshotList(os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore NAME",
+	Short: "Restore a snapshot of the instance",
+	Long:  "Restore a snapshot of the stopped instance, the changes made since the snapshot was created are lost",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runSnapshotRestore(os.Stdout, newMachine(), args[0], outputFormat != jsonFormat, globalForce, outputFormat)
+	},
+}
+
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a snapshot of the instance",
+	Long:  "Delete a snapshot of the instance",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runSnapshotDelete(os.Stdout, newMachine(), args[0], outputFormat)
+	},
+}
+
+func runSnapshotCreate(writer io.Writer, client machine.Client, name, outputFormat string) error {
+	err := checkIfMachineMissing(client)
+	if err == nil {
+		_, err = client.CreateSnapshot(name)
+	}
+	return render(&snapshotResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		Name:    name,
+		action:  "Created",
+	}, writer, outputFormat)
+}
+
+func runSnapshotList(writer io.Writer, client machine.Client, outputFormat string) error {
+	if err := checkIfMachineMissing(client); err != nil {
+		return err
+	}
+	snapshots, err := client.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	list := &snapshotList{
+		Snapshots: []snapshot{},
+	}
+	for _, info := range snapshots {
+		list.Snapshots = append(list.Snapshots, snapshot{
+			Name:         info.Name,
+			CreationTime: info.CreationTime,
+			BundleName:   info.BundleName,
+			DiskSize:     info.DiskSize,
+		})
+	}
+	return render(list, writer, outputFormat)
+}
+
+func restoreSnapshot(client machine.Client, name string, interactive, force bool) (bool, error) {
+	if err := checkIfMachineMissing(client); err != nil {
+		return false, err
+	}
+	if !interactive && !force {
+		return false, errors.New("non-interactive snapshot restore requires --force")
+	}
+	yes := input.PromptUserForYesOrNo(fmt.Sprintf("Do you want to restore snapshot '%s', the changes made to the instance since its creation will be lost", name), force)
+	if !yes {
+		return false, nil
+	}
+	return true, client.RestoreSnapshot(name)
+}
+
+func runSnapshotRestore(writer io.Writer, client machine.Client, name string, interactive, force bool, outputFormat string) error {
+	restored, err := restoreSnapshot(client, name, interactive, force)
+	return render(&snapshotResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		Name:    name,
+		action:  "Restored",
+		skipped: !restored,
+	}, writer, outputFormat)
+}
+
+func runSnapshotDelete(writer io.Writer, client machine.Client, name, outputFormat string) error {
+	err := client.DeleteSnapshot(name)
+	return render(&snapshotResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		Name:    name,
+		action:  "Deleted",
+	}, writer, outputFormat)
+}
+
+type snapshotResult struct {
+	Success bool                         `json:"success"`
+	Error   *crcErrors.SerializableError `json:"error,omitempty"`
+	Name    string                       `json:"name"`
+	action  string
+	skipped bool
+}
+
+func (s *snapshotResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if s.skipped {
+		return nil
+	}
+	_, err := fmt.Fprintf(writer, "%s snapshot '%s'\n", s.action, s.Name)
+	return err
+}
+
+type snapshot struct {
+	Name         string        `json:"name"`
+	CreationTime time.Time     `json:"creationTime"`
+	BundleName   string        `json:"bundleName"`
+	DiskSize     strongunits.B `json:"diskSize"`
+}
+
+type snapshotList struct {
+	Snapshots []snapshot `json:"snapshots"`
+}
+
+func (s *snapshotList) prettyPrintTo(writer io.Writer) error {
+	if len(s.Snapshots) == 0 {
+		_, err := fmt.Fprintln(writer, "No snapshots")
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCREATED\tSIZE\tBUNDLE")
+	for _, snapshot := range s.Snapshots {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			snapshot.Name,
+			snapshot.CreationTime.Format("2006-01-02 15:04:05"),
+			units.HumanSize(float64(snapshot.DiskSize)),
+			snapshot.BundleName)
+	}
+	return w.Flush()
+}
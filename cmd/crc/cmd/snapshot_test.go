@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotCreatePlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotCreate(out, fakemachine.NewClient(), "clean", ""))
+	assert.Equal(t, "Created snapshot 'clean'\n", out.String())
+}
+
+func TestSnapshotCreateJSONError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotCreate(out, fakemachine.NewFailingClient(), "clean", jsonFormat))
+	assert.JSONEq(t, `{"success": false, "name": "clean", "error": "snapshot failed"}`, out.String())
+}
+
+func TestSnapshotListPlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotList(out, fakemachine.NewClient(), ""))
+	assert.Equal(t, `NAME    CREATED               SIZE   BUNDLE
+clean   2024-01-02 03:04:05   1GB    crc_libvirt_4.15.0_amd64.crcbundle
+`, out.String())
+}
+
+func TestSnapshotListJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotList(out, fakemachine.NewClient(), jsonFormat))
+	assert.JSONEq(t, `{"snapshots": [{"name": "clean", "creationTime": "2024-01-02T03:04:05Z", "bundleName": "crc_libvirt_4.15.0_amd64.crcbundle", "diskSize": 1000000000}]}`, out.String())
+}
+
+func TestSnapshotRestoreNonInteractive(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotRestore(out, fakemachine.NewClient(), "clean", false, false, jsonFormat))
+	assert.JSONEq(t, `{"success": false, "name": "clean", "error": "non-interactive snapshot restore requires --force"}`, out.String())
+}
+
+func TestSnapshotRestoreForce(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runSnapshotRestore(out, fakemachine.NewClient(), "clean", true, true, ""))
+	assert.Equal(t, "Restored snapshot 'clean'\n", out.String())
+}
+
+func TestSnapshotDeletePlainError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.EqualError(t, runSnapshotDelete(out, fakemachine.NewFailingClient(), "clean", ""), "snapshot delete failed")
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"k8s.io/client-go/util/exec"
+)
+
+var sshTTY bool
+
+func init() {
+	sshCmd.Flags().BoolVarP(&sshTTY, "tty", "t", false, "Allocate a pseudo-terminal for the command")
+	rootCmd.AddCommand(sshCmd)
+}
+
+var sshCmd = &cobra.Command{
+	Use:   "ssh [-- command [args...]]",
+	Short: "Open a shell or run a command in the CRC instance",
+	Long: `Open an interactive shell in the CRC instance, or run the given command in it.
+The standard input is forwarded to the command, and crc exits with the exit
+status of the command.`,
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runSSH(args, sshTTY)
+	},
+}
+
+func runSSH(args []string, tty bool) error {
+	runner, err := createSSHRunner()
+	if err != nil {
+		return err
+	}
+	defer runner.Close()
+
+	streams := ssh.Streams{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	var terminal *ssh.Terminal
+	stdinFd, isTerminal := terminalFd(os.Stdin)
+	if isTerminal && (tty || len(args) == 0) {
+		terminal, err = setupTerminal(stdinFd)
+		if err != nil {
+			return err
+		}
+		state, err := term.MakeRaw(stdinFd)
+		if err != nil {
+			return fmt.Errorf("Failed to set the terminal in raw mode: %w", err)
+		}
+		defer func() {
+			if err := term.Restore(stdinFd, state); err != nil {
+				logging.Debugf("Failed to restore the terminal: %v", err)
+			}
+		}()
+	} else if tty {
+		logging.Warn("Pseudo-terminal will not be allocated because stdin is not a terminal")
+	}
+
+	err = runner.Exec(ssh.QuoteCommand(args), streams, terminal)
+	if status, ok := ssh.ExitStatus(err); ok {
+		return exec.CodeExitError{
+			Err:  fmt.Errorf("command exited with status %d", status),
+			Code: status,
+		}
+	}
+	return err
+}
+
+// createSSHRunner returns a runner connected to the running CRC instance
+func createSSHRunner() (*ssh.Runner, error) {
+	client := newMachine()
+	if err := checkIfMachineMissing(client); err != nil {
+		return nil, err
+	}
+
+	running, err := client.IsRunning()
+	if err != nil {
+		return nil, err
+	}
+	if !running {
+		return nil, fmt.Errorf("the CRC instance is not running, cannot connect to it")
+	}
+
+	connectionDetails, err := client.ConnectionDetails()
+	if err != nil {
+		return nil, err
+	}
+	return ssh.CreateRunner(connectionDetails.IP, connectionDetails.SSHPort, connectionDetails.SSHKeys...)
+}
+
+func terminalFd(file *os.File) (int, bool) {
+	fd := file.Fd()
+	if fd > math.MaxInt {
+		return 0, false
+	}
+	return int(fd), term.IsTerminal(int(fd))
+}
+
+func setupTerminal(fd int) (*ssh.Terminal, error) {
+	width, height, err := term.GetSize(fd)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get the terminal size: %w", err)
+	}
+	termName := os.Getenv("TERM")
+	if termName == "" {
+		termName = "xterm-256color"
+	}
+	return &ssh.Terminal{
+		Term:   termName,
+		Size:   ssh.TerminalSize{Width: width, Height: height},
+		Resize: watchTerminalSize(fd),
+	}, nil
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"golang.org/x/term"
+)
+
+// watchTerminalSize sends the new size of the terminal each time it is
+// resized, until crc exits
+func watchTerminalSize(fd int) <-chan ssh.TerminalSize {
+	sizes := make(chan ssh.TerminalSize, 1)
+	sigwinch := make(chan os.Signal, 1)
+	signal.Notify(sigwinch, syscall.SIGWINCH)
+	go func() {
+		for range sigwinch {
+			width, height, err := term.GetSize(fd)
+			if err != nil {
+				continue
+			}
+			select {
+			case sizes <- ssh.TerminalSize{Width: width, Height: height}:
+			default:
+			}
+		}
+	}()
+	return sizes
+}
@@ -0,0 +1,11 @@
+package cmd
+
+import (
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+// watchTerminalSize returns nil as there is no SIGWINCH on Windows, the
+// pseudo-terminal keeps the size it had when the session started
+func watchTerminalSize(_ int) <-chan ssh.TerminalSize {
+	return nil
+}
@@ -44,8 +44,29 @@ func init() {
 	flagSet.UintP(crcConfig.DiskSize, "d", constants.DefaultDiskSize, "Total size in GiB of the disk used by the instance")
 	flagSet.StringP(crcConfig.NameServer, "n", "", "IPv4 address of nameserver to use for the instance")
 	flagSet.Bool(crcConfig.DisableUpdateCheck, false, "Don't check for update")
+	flagSet.String(crcConfig.AppsDomain, "", "Custom domain under which the routes are also served")
 
 	startCmd.Flags().AddFlagSet(flagSet)
+	startCmd.Flags().StringVar(&startConfigFile, "config-file", "", "Profile file (YAML or JSON) applied to the configuration before starting the instance")
+}
+
+var startConfigFile string
+
+// applyStartProfile applies the settings of the profile given with
+// --config-file. Command line flags still take precedence over them.
+func applyStartProfile(config *crcConfig.Config, path string) error {
+	profile, err := crcConfig.LoadProfile(path)
+	if err != nil {
+		return err
+	}
+	changes, err := config.ApplyProfile(profile)
+	if err != nil {
+		return fmt.Errorf("Cannot apply profile %s: %w", path, err)
+	}
+	for _, change := range changes {
+		logging.Infof("Setting %s to %v from profile %s", change.Key, change.Desired, path)
+	}
+	return nil
 }
 
 var startCmd = &cobra.Command{
@@ -53,6 +74,11 @@ var startCmd = &cobra.Command{
 	Short: "Start the instance",
 	Long:  "Start the instance",
 	RunE: func(cmd *cobra.Command, _ []string) error {
+		if startConfigFile != "" {
+			if err := applyStartProfile(config, startConfigFile); err != nil {
+				return err
+			}
+		}
 		if err := viper.BindFlagSet(cmd.Flags()); err != nil {
 			return err
 		}
@@ -82,12 +108,23 @@ func runStart(ctx context.Context) (*types.StartResult, error) {
 		IngressHTTPPort:   config.Get(crcConfig.IngressHTTPPort).AsUInt(),
 		IngressHTTPSPort:  config.Get(crcConfig.IngressHTTPSPort).AsUInt(),
 		EnableSharedDirs:  config.Get(crcConfig.EnableSharedDirs).AsBool(),
+		SharedDirs:        crcConfig.GetSharedDirs(config),
 
 		EmergencyLogin: config.Get(crcConfig.EmergencyLogin).AsBool(),
 
 		PersistentVolumeSize: config.Get(crcConfig.PersistentVolumeSize).AsInt(),
 
-		EnableBundleQuayFallback: config.Get(crcConfig.EnableBundleQuayFallback).AsBool(),
+		BundleMirrors: crcConfig.GetBundleMirrors(config),
+
+		ProvisionFile: config.Get(crcConfig.ProvisionFile).AsString(),
+
+		AppsDomain: config.Get(crcConfig.AppsDomain).AsString(),
+		CustomCertificates: types.CustomCertificates{
+			CACertFile: config.Get(crcConfig.CustomCACertFile).AsString(),
+			CAKeyFile:  config.Get(crcConfig.CustomCAKeyFile).AsString(),
+			CertFile:   config.Get(crcConfig.CustomCertFile).AsString(),
+			KeyFile:    config.Get(crcConfig.CustomKeyFile).AsString(),
+		},
 	}
 
 	client := newMachine()
@@ -314,7 +351,7 @@ func writeMicroShiftTemplatedMessage(writer io.Writer) error {
 	return parsed.Execute(writer, &templateVariables{
 		EvalCommandLine:   shell.GenerateUsageHint(userShell, "crc oc-env"),
 		CommandLinePrefix: commandLinePrefix(userShell),
-		KubeConfigPath:    constants.KubeconfigFilePath,
+		KubeConfigPath:    constants.GetKubeconfigFilePath(crcConfig.GetInstanceName(config)),
 	})
 }
 
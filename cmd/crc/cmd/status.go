@@ -17,6 +17,7 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
 	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/docker/go-units"
@@ -57,6 +58,7 @@ type status struct {
 	PersistentVolumeUse  strongunits.B                `json:"persistentVolumeUsage,omitempty"`
 	PersistentVolumeSize strongunits.B                `json:"persistentVolumeSize,omitempty"`
 	Preset               preset.Preset                `json:"preset"`
+	SharedDirs           []machineConfig.SharedDir    `json:"sharedDirs,omitempty"`
 }
 
 func runStatus(writer io.Writer, client *daemonclient.Client, cacheDir, outputFormat string, watch bool) error {
@@ -182,6 +184,7 @@ func getStatus(client *daemonclient.Client, cacheDir string) *status {
 		PersistentVolumeSize: clusterStatus.PersistentVolumeSize,
 		CacheDir:             cacheDir,
 		Preset:               clusterStatus.Preset,
+		SharedDirs:           clusterStatus.SharedDirs,
 	}
 }
 
@@ -219,6 +222,16 @@ func (s *status) prettyPrintTo(writer io.Writer) error {
 			units.HumanSize(float64(s.PersistentVolumeUse)),
 			units.HumanSize(float64(s.PersistentVolumeSize)))})
 	}
+	for _, dir := range s.SharedDirs {
+		mount := fmt.Sprintf("%s -> %s", dir.Source, dir.Target)
+		if dir.ReadOnly {
+			mount += " (read-only)"
+		}
+		if dir.Owner != "" {
+			mount += fmt.Sprintf(" (owner %s)", dir.Owner)
+		}
+		lines = append(lines, line{"Shared Directory", mount})
+	}
 	lines = append(lines,
 		line{"Cache Usage", units.HumanSize(float64(s.CacheUsage))},
 		line{"Cache Directory", s.CacheDir})
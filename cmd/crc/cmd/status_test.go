@@ -12,6 +12,7 @@ import (
 
 	apiClient "github.com/crc-org/crc/v2/pkg/crc/api/client"
 	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
@@ -101,6 +102,41 @@ Cache Directory: %s
 	assert.Equal(t, fmt.Sprintf(expected, cacheDir), out.String())
 }
 
+func TestPlainStatusWithSharedDirs(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	client := mocks.NewClient(t)
+	client.On("Status").Return(apiClient.ClusterStatusResult{
+		CrcStatus:        string(state.Running),
+		OpenshiftStatus:  string(types.OpenshiftRunning),
+		OpenshiftVersion: "4.5.1",
+		DiskUse:          10_000_000_000,
+		DiskSize:         20_000_000_000,
+		Preset:           preset.OpenShift,
+		SharedDirs: []machineConfig.SharedDir{
+			{Source: "/srv/data", Target: "/mnt/data", ReadOnly: true},
+			{Source: "/srv/src", Target: "/srv/src"},
+			{Source: "/srv/home", Target: "/home/core/host", Owner: "1000:1000"},
+		},
+	}, nil)
+
+	out := new(bytes.Buffer)
+	assert.NoError(t, runStatus(out, &daemonclient.Client{
+		APIClient: client,
+	}, cacheDir, "", false))
+
+	expected := `CRC VM:           Running
+OpenShift:        Running (v4.5.1)
+Disk Usage:       10GB of 20GB (Inside the CRC VM)
+Shared Directory: /srv/data -> /mnt/data (read-only)
+Shared Directory: /srv/src -> /srv/src
+Shared Directory: /srv/home -> /home/core/host (owner 1000:1000)
+Cache Usage:      0B
+Cache Directory:  %s
+`
+	assert.Equal(t, fmt.Sprintf(expected, cacheDir), out.String())
+}
+
 func TestJsonStatus(t *testing.T) {
 	cacheDir := t.TempDir()
 
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.podman.io/common/pkg/strongunits"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+)
+
+var volumeSize uint
+
+func init() {
+	volumeCmd.AddCommand(volumeCreateCmd)
+	volumeCmd.AddCommand(volumeDeleteCmd)
+	volumeCmd.AddCommand(volumeAttachCmd)
+	volumeCmd.AddCommand(volumeDetachCmd)
+	volumeCmd.AddCommand(volumeListCmd)
+	for _, cmd := range volumeCmd.Commands() {
+		addOutputFormatFlag(cmd)
+	}
+	volumeCreateCmd.Flags().UintVar(&volumeSize, "size", 10, "Size in GiB of the volume")
+	rootCmd.AddCommand(volumeCmd)
+}
+
+var volumeCmd = &cobra.Command{
+	Use:   "volume SUBCOMMAND [flags]",
+	Short: "Manage additional disks of the instance",
+	Long: fmt.Sprintf(`Manage additional disks of the instance
+A volume is a disk which is kept when the instance is deleted. Once attached
+to an instance, it is available in the cluster as a local PersistentVolume
+with the '%s' storage class. Volumes are only supported with libvirt.`, cluster.VolumeStorageClass),
+	Run: func(cmd *cobra.Command, _ []string) {
+		_ = cmd.Help()
+	},
+}
+
+var volumeCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create a volume",
+	Long:  "Create a volume, it is formatted when it is first attached",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runVolumeCreate(os.Stdout, newMachine(), args[0], strongunits.GiB(volumeSize), outputFormat)
+	},
+}
+
+var volumeDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a volume",
+	Long:  "Delete a detached volume and its data",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runVolumeAction(os.Stdout, newMachine().DeleteVolume, args[0], "Deleted", outputFormat)
+	},
+}
+
+var volumeAttachCmd = &cobra.Command{
+	Use:   "attach NAME",
+	Short: "Attach a volume to the instance",
+	Long:  "Attach a volume to the instance and expose it as a PersistentVolume in the cluster",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runVolumeAttach(os.Stdout, newMachine(), args[0], outputFormat)
+	},
+}
+
+var volumeDetachCmd = &cobra.Command{
+	Use:   "detach NAME",
+	Short: "Detach a volume from the instance",
+	Long:  "Detach a volume from the running instance, its PersistentVolume must not be bound to a claim",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		return runVolumeAction(os.Stdout, newMachine().DetachVolume, args[0], "Detached", outputFormat)
+	},
+}
+
+var volumeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the volumes",
+	Long:  "List the volumes and the instances they are attached to",
+	Args:  cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		return runVolumeList(os.Stdout, newMachine(), outputFormat)
+	},
+}
+
+func runVolumeCreate(writer io.Writer, client machine.Client, name string, size strongunits.GiB, outputFormat string) error {
+	_, err := client.CreateVolume(name, size)
+	return render(&volumeResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		Name:    name,
+		action:  "Created",
+	}, writer, outputFormat)
+}
+
+func runVolumeAttach(writer io.Writer, client machine.Client, name, outputFormat string) error {
+	err := checkIfMachineMissing(client)
+	var volume *types.VolumeInfo
+	if err == nil {
+		volume, err = client.AttachVolume(name)
+	}
+	result := &volumeResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		Name:    name,
+		action:  "Attached",
+	}
+	if volume != nil {
+		result.PersistentVolume = volume.PersistentVolume
+	}
+	return render(result, writer, outputFormat)
+}
+
+func runVolumeAction(writer io.Writer, action func(name string) error, name, actionName, outputFormat string) error {
+	err := action(name)
+	return render(&volumeResult{
+		Success: err == nil,
+		Error:   crcErrors.ToSerializableError(err),
+		Name:    name,
+		action:  actionName,
+	}, writer, outputFormat)
+}
+
+func runVolumeList(writer io.Writer, client machine.Client, outputFormat string) error {
+	volumes, err := client.ListVolumes()
+	if err != nil {
+		return err
+	}
+	list := &volumeList{
+		Volumes: []volume{},
+	}
+	for _, info := range volumes {
+		list.Volumes = append(list.Volumes, volume{
+			Name:             info.Name,
+			Size:             info.Size,
+			Instance:         info.Instance,
+			PersistentVolume: info.PersistentVolume,
+		})
+	}
+	return render(list, writer, outputFormat)
+}
+
+type volumeResult struct {
+	Success          bool                         `json:"success"`
+	Error            *crcErrors.SerializableError `json:"error,omitempty"`
+	Name             string                       `json:"name"`
+	PersistentVolume string                       `json:"persistentVolume,omitempty"`
+	action           string
+}
+
+func (s *volumeResult) prettyPrintTo(writer io.Writer) error {
+	if s.Error != nil {
+		return s.Error
+	}
+	if s.PersistentVolume != "" {
+		_, err := fmt.Fprintf(writer, "%s volume '%s', it is available as PersistentVolume %s with the '%s' storage class\n",
+			s.action, s.Name, s.PersistentVolume, cluster.VolumeStorageClass)
+		return err
+	}
+	_, err := fmt.Fprintf(writer, "%s volume '%s'\n", s.action, s.Name)
+	return err
+}
+
+type volume struct {
+	Name             string          `json:"name"`
+	Size             strongunits.GiB `json:"size"`
+	Instance         string          `json:"instance,omitempty"`
+	PersistentVolume string          `json:"persistentVolume,omitempty"`
+}
+
+type volumeList struct {
+	Volumes []volume `json:"volumes"`
+}
+
+func (s *volumeList) prettyPrintTo(writer io.Writer) error {
+	if len(s.Volumes) == 0 {
+		_, err := fmt.Fprintln(writer, "No volumes")
+		return err
+	}
+	w := tabwriter.NewWriter(writer, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSIZE\tINSTANCE\tPERSISTENT VOLUME")
+	for _, volume := range s.Volumes {
+		fmt.Fprintf(w, "%s\t%dGiB\t%s\t%s\n", volume.Name, volume.Size, valueOrDash(volume.Instance), valueOrDash(volume.PersistentVolume))
+	}
+	return w.Flush()
+}
+
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVolumeCreatePlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runVolumeCreate(out, fakemachine.NewClient(), "data", 10, ""))
+	assert.Equal(t, "Created volume 'data'\n", out.String())
+}
+
+func TestVolumeCreateJSONError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runVolumeCreate(out, fakemachine.NewFailingClient(), "data", 10, jsonFormat))
+	assert.JSONEq(t, `{"success": false, "name": "data", "error": "volume create failed"}`, out.String())
+}
+
+func TestVolumeAttachPlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runVolumeAttach(out, fakemachine.NewClient(), "data", ""))
+	assert.Equal(t, "Attached volume 'data', it is available as PersistentVolume crc-volume-data with the 'crc-volume' storage class\n", out.String())
+}
+
+func TestVolumeAttachJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runVolumeAttach(out, fakemachine.NewClient(), "data", jsonFormat))
+	assert.JSONEq(t, `{"success": true, "name": "data", "persistentVolume": "crc-volume-data"}`, out.String())
+}
+
+func TestVolumeDetachPlainError(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.EqualError(t, runVolumeAction(out, fakemachine.NewFailingClient().DetachVolume, "data", "Detached", ""), "volume detach failed")
+}
+
+func TestVolumeListPlain(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runVolumeList(out, fakemachine.NewClient(), ""))
+	assert.Equal(t, `NAME   SIZE    INSTANCE   PERSISTENT VOLUME
+data   10GiB   -          -
+`, out.String())
+}
+
+func TestVolumeListJSON(t *testing.T) {
+	out := new(bytes.Buffer)
+	assert.NoError(t, runVolumeList(out, fakemachine.NewClient(), jsonFormat))
+	assert.JSONEq(t, `{"volumes": [{"name": "data", "size": 10}]}`, out.String())
+}
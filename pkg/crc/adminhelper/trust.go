@@ -0,0 +1,11 @@
+package adminhelper
+
+import "errors"
+
+// trustedCAFileName is the name of the file holding the cluster CAs in the
+// trust store of the host
+const trustedCAFileName = "crc-cluster-ca.crt"
+
+// ErrTrustStoreUnsupported is returned when crc cannot manage the trust
+// store of the host
+var ErrTrustStoreUnsupported = errors.New("installing the cluster CA in the host trust store is only supported on Linux")
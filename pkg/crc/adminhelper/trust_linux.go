@@ -0,0 +1,80 @@
+package adminhelper
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+)
+
+type trustStore struct {
+	anchorsDir    string
+	updateCommand string
+}
+
+// trustStores are the layouts of the host trust store, for the Fedora/RHEL
+// and the Debian/Ubuntu distribution families
+var trustStores = []trustStore{
+	{anchorsDir: "/etc/pki/ca-trust/source/anchors", updateCommand: "update-ca-trust"},
+	{anchorsDir: "/usr/local/share/ca-certificates", updateCommand: "update-ca-certificates"},
+}
+
+func hostTrustStore() (trustStore, error) {
+	for _, store := range trustStores {
+		if _, err := exec.LookPath(store.updateCommand); err != nil {
+			continue
+		}
+		if crcos.FileExists(store.anchorsDir) {
+			return store, nil
+		}
+	}
+	return trustStore{}, ErrTrustStoreUnsupported
+}
+
+// TrustedCA returns the path of the cluster CAs in the trust store of the
+// host, and the command updating the trust store
+func TrustedCA() (string, string, error) {
+	store, err := hostTrustStore()
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(store.anchorsDir, trustedCAFileName), store.updateCommand, nil
+}
+
+// AddToTrustStore installs the pem certificates 'caPEM' in the trust store
+// of the host, replacing the ones installed before
+func AddToTrustStore(caPEM []byte) error {
+	store, err := hostTrustStore()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(store.anchorsDir, trustedCAFileName)
+	if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, caPEM) {
+		return nil
+	}
+	if err := crcos.WriteToFileAsRoot("Installing the cluster CA in the host trust store", string(caPEM), path, 0644); err != nil {
+		return err
+	}
+	_, _, err = crcos.RunPrivileged("Updating the host trust store", store.updateCommand)
+	return err
+}
+
+// RemoveFromTrustStore removes the certificates installed by
+// AddToTrustStore, if any
+func RemoveFromTrustStore() error {
+	store, err := hostTrustStore()
+	if err != nil {
+		return nil
+	}
+	path := filepath.Join(store.anchorsDir, trustedCAFileName)
+	if !crcos.FileExists(path) {
+		return nil
+	}
+	if err := crcos.RemoveFileAsRoot("Removing the cluster CA from the host trust store", path); err != nil {
+		return err
+	}
+	_, _, err = crcos.RunPrivileged("Updating the host trust store", store.updateCommand)
+	return err
+}
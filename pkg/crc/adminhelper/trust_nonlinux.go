@@ -0,0 +1,7 @@
+//go:build !linux
+
+package adminhelper
+
+func AddToTrustStore(_ []byte) error {
+	return ErrTrustStoreUnsupported
+}
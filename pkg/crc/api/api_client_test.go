@@ -1,20 +1,28 @@
 package api
 
 import (
+	"bytes"
+	gocontext "context"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"go.podman.io/common/pkg/strongunits"
 
 	apiClient "github.com/crc-org/crc/v2/pkg/crc/api/client"
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
 	"github.com/crc-org/crc/v2/pkg/crc/version"
 	"github.com/stretchr/testify/assert"
 )
@@ -33,7 +41,7 @@ func newTestClient() *testClient {
 	fakeMachine := fakemachine.NewClient()
 	config := setupNewInMemoryConfig()
 
-	ts := httptest.NewServer(NewMux(config, fakeMachine, &mockLogger{}, &mockTelemetry{}))
+	ts := httptest.NewServer(NewMux(config, fakeMachine, &mockLogger{}, &mockTelemetry{}, nil, nil))
 
 	return &testClient{
 		apiClient.New(http.DefaultClient, ts.URL),
@@ -59,6 +67,25 @@ func TestVersion(t *testing.T) {
 	)
 }
 
+func TestVersionOfADaemonWithoutV1Routes(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = io.WriteString(w, `{"CrcVersion":"2.40.0","CommitSha":"abcdef","OpenshiftVersion":"4.16.7"}`)
+	}))
+	defer ts.Close()
+
+	vr, err := apiClient.New(http.DefaultClient, ts.URL).Version()
+	assert.NoError(t, err)
+	assert.Equal(t, apiClient.VersionResult{
+		CrcVersion:       "2.40.0",
+		CommitSha:        "abcdef",
+		OpenshiftVersion: "4.16.7",
+	}, vr)
+}
+
 func TestStatus(t *testing.T) {
 	client := newTestClient()
 	defer client.Close()
@@ -106,6 +133,52 @@ func TestStart(t *testing.T) {
 	)
 }
 
+func TestStartLongerThanTheClientTimeout(t *testing.T) {
+	fakeMachine := fakemachine.NewClient()
+	fakeMachine.StartDuration = time.Second
+	ts := httptest.NewServer(NewMux(setupNewInMemoryConfig(), fakeMachine, &mockLogger{}, &mockTelemetry{}, nil, nil))
+	defer ts.Close()
+
+	// each poll of the start operation must be shorter than the timeout
+	client := apiClient.New(&http.Client{Timeout: 300 * time.Millisecond}, ts.URL)
+	startResult, err := client.Start(apiClient.StartConfig{})
+	assert.NoError(t, err)
+	assert.True(t, startResult.KubeletStarted)
+}
+
+func TestStartFailure(t *testing.T) {
+	fakeMachine := fakemachine.NewClient()
+	fakeMachine.Failing = true
+	config := setupNewInMemoryConfig()
+
+	ts := httptest.NewServer(NewMux(config, fakeMachine, &mockLogger{}, &mockTelemetry{}, nil, nil))
+	defer ts.Close()
+
+	client := apiClient.New(http.DefaultClient, ts.URL)
+	_, err := client.Start(apiClient.StartConfig{})
+	var opErr *apiClient.OperationError
+	assert.ErrorAs(t, err, &opErr)
+	assert.Equal(t, apiClient.OperationStart, opErr.Type)
+	assert.Equal(t, apiClient.ErrorCodeInternal, opErr.Code)
+	assert.EqualError(t, err, "Failed to start")
+
+	operations, err := client.Operations()
+	assert.NoError(t, err)
+	assert.Len(t, operations.Operations, 1)
+	assert.Equal(t, apiClient.OperationFailed, operations.Operations[0].State)
+}
+
+func TestDownloadBundle(t *testing.T) {
+	client := newTestClient()
+	defer client.Close()
+	bundlePath := filepath.Join(t.TempDir(), "crc.crcbundle")
+	assert.NoError(t, os.WriteFile(bundlePath, []byte{}, 0600))
+
+	result, err := client.DownloadBundle(bundlePath)
+	assert.NoError(t, err)
+	assert.Equal(t, apiClient.BundleDownloadResult{Path: bundlePath}, result)
+}
+
 func TestSetup(t *testing.T) {
 	client := newTestClient()
 	defer client.Close()
@@ -257,7 +330,7 @@ func TestTelemetry(t *testing.T) {
 	config := setupNewInMemoryConfig()
 
 	telemetry := &mockTelemetry{}
-	ts := httptest.NewServer(NewMux(config, fakeMachine, &mockLogger{}, telemetry))
+	ts := httptest.NewServer(NewMux(config, fakeMachine, &mockLogger{}, telemetry, nil, nil))
 	defer ts.Close()
 
 	client := apiClient.New(http.DefaultClient, ts.URL)
@@ -274,7 +347,7 @@ func TestPullSecret(t *testing.T) {
 	fakeMachine := fakemachine.NewClient()
 	config := setupNewInMemoryConfig()
 
-	ts := httptest.NewServer(NewMux(config, fakeMachine, &mockLogger{}, &mockTelemetry{}))
+	ts := httptest.NewServer(NewMux(config, fakeMachine, &mockLogger{}, &mockTelemetry{}, nil, nil))
 	defer ts.Close()
 
 	client := apiClient.New(http.DefaultClient, ts.URL)
@@ -294,3 +367,96 @@ func TestPullSecret(t *testing.T) {
 
 	assert.Error(t, client.SetPullSecret("{}")) // invalid
 }
+
+func TestExec(t *testing.T) {
+	handler := NewHandler(setupNewInMemoryConfig(), fakemachine.NewClient(), &mockLogger{}, &mockTelemetry{}, nil, nil)
+	handler.exec = func(command string, streams ssh.Streams) (int, error) {
+		_, _ = io.Copy(streams.Stdout, streams.Stdin)
+		_, _ = fmt.Fprintf(streams.Stderr, "ran %s", command)
+		return 2, nil
+	}
+	ts := httptest.NewServer(newServerWithRoutes(handler).Handler())
+	defer ts.Close()
+	client := apiClient.New(http.DefaultClient, ts.URL)
+
+	var stdout, stderr bytes.Buffer
+	exitCode, err := client.Exec(apiClient.ExecRequest{Command: []string{"cat", "my file"}, Stdin: []byte("hello")}, &stdout, &stderr)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, exitCode)
+	assert.Equal(t, "hello", stdout.String())
+	assert.Equal(t, "ran cat 'my file'", stderr.String())
+
+	handler.exec = func(_ string, _ ssh.Streams) (int, error) {
+		return -1, errors.New("connection refused")
+	}
+	_, err = client.Exec(apiClient.ExecRequest{Command: []string{"uptime"}}, &stdout, &stderr)
+	assert.EqualError(t, err, "connection refused")
+
+	// the output is streamed without the timeout of the client
+	handler.exec = func(_ string, streams ssh.Streams) (int, error) {
+		time.Sleep(time.Second)
+		_, _ = io.WriteString(streams.Stdout, "done")
+		return 0, nil
+	}
+	stdout.Reset()
+	client = apiClient.New(&http.Client{Timeout: 300 * time.Millisecond}, ts.URL)
+	exitCode, err = client.Exec(apiClient.ExecRequest{Command: []string{"sleep", "1"}}, &stdout, &stderr)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+	assert.Equal(t, "done", stdout.String())
+}
+
+func TestDiagnoseLongerThanTheClientTimeout(t *testing.T) {
+	handler := NewHandler(setupNewInMemoryConfig(), fakemachine.NewClient(), &mockLogger{}, &mockTelemetry{}, nil, nil)
+	handler.diagnose = func(ctx gocontext.Context, w io.Writer) error {
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		_, err := io.WriteString(w, "diagnose tarball")
+		return err
+	}
+	ts := httptest.NewServer(newServerWithRoutes(handler).Handler())
+	defer ts.Close()
+
+	// the tarball is streamed without the timeout of the client
+	client := apiClient.New(&http.Client{Timeout: 300 * time.Millisecond}, ts.URL)
+	var tarball bytes.Buffer
+	assert.NoError(t, client.Diagnose(&tarball))
+	assert.Equal(t, "diagnose tarball", tarball.String())
+
+	handler.diagnose = func(_ gocontext.Context, _ io.Writer) error {
+		return errors.New("cannot write tarball")
+	}
+	assert.ErrorContains(t, client.Diagnose(&tarball), "cannot write tarball")
+}
+
+func TestSharedDirs(t *testing.T) {
+	client := newTestClient()
+	defer client.Close()
+	dir := t.TempDir()
+
+	added, err := client.AddSharedDir(machineConfig.SharedDir{Source: dir, Target: "/mnt/data/", ReadOnly: true})
+	assert.NoError(t, err)
+	expected := machineConfig.SharedDir{Source: dir, Target: "/mnt/data", ReadOnly: true}
+	assert.Equal(t, apiClient.SharedDirResult{SharedDir: expected, Applied: true}, added)
+	assert.Equal(t, fmt.Sprintf("%s=/mnt/data;ro", dir), client.config.Get(crcConfig.SharedDirs).AsString())
+
+	list, err := client.ListSharedDirs()
+	assert.NoError(t, err)
+	assert.Equal(t, []machineConfig.SharedDir{expected}, list.SharedDirs)
+
+	_, err = client.AddSharedDir(machineConfig.SharedDir{Source: filepath.Join(dir, "missing")})
+	var httpErr *apiClient.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, apiClient.ErrorCodeInvalidRequest, httpErr.Code)
+
+	removed, err := client.RemoveSharedDir("/mnt/data")
+	assert.NoError(t, err)
+	assert.Equal(t, apiClient.SharedDirResult{SharedDir: expected, Applied: true}, removed)
+	assert.Equal(t, "", client.config.Get(crcConfig.SharedDirs).AsString())
+
+	_, err = client.RemoveSharedDir("/mnt/data")
+	assert.EqualError(t, err, "shared directory not found: /mnt/data")
+}
@@ -3,13 +3,15 @@ package api
 import (
 	"net/http"
 
+	"github.com/crc-org/crc/v2/pkg/crc/api/events"
 	"github.com/crc-org/crc/v2/pkg/crc/cluster"
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/registrycache"
 )
 
-func NewMux(config *crcConfig.Config, machine machine.Client, logger Logger, telemetry Telemetry) http.Handler {
-	handler := NewHandler(config, machine, logger, telemetry)
+func NewMux(config *crcConfig.Config, machine machine.Client, logger Logger, telemetry Telemetry, eventServer *events.EventServer, registryCache *registrycache.Cache) http.Handler {
+	handler := NewHandler(config, machine, logger, telemetry, eventServer, registryCache)
 
 	server := newServerWithRoutes(handler)
 
@@ -40,6 +42,13 @@ func newServerWithRoutes(handler *Handler) *server {
 	server.POST("/config", handler.SetConfig)
 	server.DELETE("/config", handler.UnsetConfig)
 
+	server.GET("/instances", handler.Instances)
+
+	server.GET("/snapshots", handler.ListSnapshots)
+	server.POST("/snapshots", handler.CreateSnapshot)
+	server.DELETE("/snapshots", handler.DeleteSnapshot)
+	server.POST("/snapshots/restore", handler.RestoreSnapshot)
+
 	server.GET("/logs", handler.Logs)
 
 	server.GET("/telemetry", handler.UploadTelemetry)
@@ -48,9 +57,81 @@ func newServerWithRoutes(handler *Handler) *server {
 	server.GET("/pull-secret", getPullSecret(handler.Config))
 	server.POST("/pull-secret", setPullSecret())
 
+	addV1Routes(server, handler)
+
 	return server
 }
 
+const v1Prefix = "/v1"
+
+// addV1Routes registers the routes of the versioned API. Unlike the routes
+// above, they only accept the HTTP method matching the action, and report
+// errors with a JSON error object. The long-running actions return an
+// operation which can be queried with /v1/operations/{id}. They are
+// described in openapi.json.
+func addV1Routes(server *server, handler *Handler) {
+	server.GET(v1Prefix+"/openapi.json", getOpenAPIDocument)
+
+	server.GET(v1Prefix+"/version", handler.GetVersion)
+	server.GET(v1Prefix+"/status", handler.Status)
+
+	server.POST(v1Prefix+"/start", handler.AsyncStart)
+	server.POST(v1Prefix+"/stop", handler.AsyncStop)
+	server.POST(v1Prefix+"/poweroff", handler.PowerOff)
+	server.POST(v1Prefix+"/resize", handler.AsyncResize)
+	server.DELETE(v1Prefix+"/instance", handler.AsyncDelete)
+
+	server.POST(v1Prefix+"/bundles/download", handler.DownloadBundle)
+
+	server.GET(v1Prefix+"/operations", handler.ListOperations)
+	server.GET(v1Prefix+"/operations/", handler.GetOperation)
+	server.DELETE(v1Prefix+"/operations/", handler.CancelOperation)
+
+	server.GET(v1Prefix+"/webconsoleurl", handler.GetWebconsoleInfo)
+
+	server.GET(v1Prefix+"/config", handler.GetConfig)
+	server.POST(v1Prefix+"/config", handler.SetConfig)
+	server.DELETE(v1Prefix+"/config", handler.UnsetConfig)
+
+	server.GET(v1Prefix+"/instances", handler.Instances)
+
+	server.GET(v1Prefix+"/snapshots", handler.ListSnapshots)
+	server.POST(v1Prefix+"/snapshots", handler.CreateSnapshot)
+	server.DELETE(v1Prefix+"/snapshots", handler.DeleteSnapshot)
+	server.POST(v1Prefix+"/snapshots/restore", handler.RestoreSnapshot)
+
+	server.GET(v1Prefix+"/ports", handler.ListPorts)
+	server.POST(v1Prefix+"/ports", handler.ExposePort)
+	server.DELETE(v1Prefix+"/ports", handler.UnexposePort)
+
+	server.GET(v1Prefix+"/volumes", handler.ListVolumes)
+	server.POST(v1Prefix+"/volumes", handler.CreateVolume)
+	server.DELETE(v1Prefix+"/volumes", handler.DeleteVolume)
+	server.POST(v1Prefix+"/volumes/attach", handler.AttachVolume)
+	server.POST(v1Prefix+"/volumes/detach", handler.DetachVolume)
+
+	server.GET(v1Prefix+"/shared-dirs", handler.ListSharedDirs)
+	server.POST(v1Prefix+"/shared-dirs", handler.AddSharedDir)
+	server.DELETE(v1Prefix+"/shared-dirs", handler.RemoveSharedDir)
+
+	server.GET(v1Prefix+"/dns/records", handler.ListDNSRecords)
+	server.POST(v1Prefix+"/dns/records", handler.AddDNSRecord)
+	server.DELETE(v1Prefix+"/dns/records", handler.RemoveDNSRecord)
+
+	server.GET(v1Prefix+"/registry-cache", handler.RegistryCacheStats)
+	server.POST(v1Prefix+"/registry-cache/prune", handler.PruneRegistryCache)
+
+	server.GET(v1Prefix+"/logs", handler.Logs)
+	server.GET(v1Prefix+"/diagnose", handler.Diagnose)
+	server.GET(v1Prefix+"/preflight", handler.Preflight)
+	server.POST(v1Prefix+"/exec", handler.Exec)
+
+	server.POST(v1Prefix+"/telemetry", handler.UploadTelemetry)
+
+	server.GET(v1Prefix+"/pull-secret", getPullSecret(handler.Config))
+	server.POST(v1Prefix+"/pull-secret", setPullSecret())
+}
+
 func setPullSecret() func(c *context) error {
 	return func(c *context) error {
 		if err := cluster.StoreInKeyring(string(c.requestBody)); err != nil {
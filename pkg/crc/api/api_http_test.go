@@ -1,8 +1,12 @@
 package api
 
 import (
+	gocontext "context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/http/httputil"
@@ -10,19 +14,26 @@ import (
 	"strings"
 	"testing"
 
+	gvtypes "github.com/containers/gvisor-tap-vsock/pkg/types"
+	apiClient "github.com/crc-org/crc/v2/pkg/crc/api/client"
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/fakemachine"
+	"github.com/crc-org/crc/v2/pkg/crc/preflight"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/registrycache"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
 	"github.com/crc-org/crc/v2/pkg/crc/version"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.podman.io/common/pkg/strongunits"
 )
 
 type mockServer struct {
 	*server
-	client *fakemachine.Client
-	config crcConfig.Storage
+	client  *fakemachine.Client
+	config  crcConfig.Storage
+	handler *Handler
 }
 
 func createDummyPullSecret(t *testing.T) string {
@@ -47,15 +58,87 @@ func newMockServer(pullSecretPath string) *mockServer {
 	config := setupNewInMemoryConfig()
 	_, _ = config.Set(crcConfig.PullSecretFile, pullSecretPath)
 
-	handler := NewHandler(config, fakeMachine, &mockLogger{}, &mockTelemetry{})
+	handler := NewHandler(config, fakeMachine, &mockLogger{}, &mockTelemetry{}, nil, nil)
 
 	return &mockServer{
-		server: newServerWithRoutes(handler),
-		client: fakeMachine,
-		config: config,
+		server:  newServerWithRoutes(handler),
+		client:  fakeMachine,
+		config:  config,
+		handler: handler,
 	}
 }
 
+// resetOperations replaces the operation manager with one generating
+// predictable IDs and times
+func resetOperations(_ *testing.T, server *mockServer) {
+	server.handler.operations = newTestOperationManager()
+}
+
+func fakeDiagnose(err error) func(*testing.T, *mockServer) {
+	return func(_ *testing.T, server *mockServer) {
+		server.handler.diagnose = func(_ gocontext.Context, w io.Writer) error {
+			if err != nil {
+				return err
+			}
+			_, err := io.WriteString(w, "diagnose tarball")
+			return err
+		}
+	}
+}
+
+func fakeExec(exitCode int, err error) func(*testing.T, *mockServer) {
+	return func(t *testing.T, server *mockServer) {
+		server.handler.exec = func(command string, streams ssh.Streams) (int, error) {
+			if err != nil {
+				return -1, err
+			}
+			stdin, readErr := io.ReadAll(streams.Stdin)
+			require.NoError(t, readErr)
+			_, _ = fmt.Fprintf(streams.Stdout, "%s: %s", command, stdin)
+			_, _ = io.WriteString(streams.Stderr, "warning")
+			return exitCode, nil
+		}
+	}
+}
+
+func fakeDNS(_ *testing.T, server *mockServer) {
+	zones := []gvtypes.Zone{{Name: "crc.testing.", Records: []gvtypes.Record{{Name: "api", IP: net.ParseIP("192.168.127.2")}}}}
+	server.handler.dnsZones = func() ([]gvtypes.Zone, error) {
+		return zones, nil
+	}
+	server.handler.addDNSZone = func(zone *gvtypes.Zone) error {
+		zones = append(zones, *zone)
+		return nil
+	}
+}
+
+func fakeRegistryCache(t *testing.T, server *mockServer) {
+	server.handler.registryCache = registrycache.New(registrycache.Options{
+		Dir:        t.TempDir(),
+		Enabled:    func() bool { return false },
+		Registries: func() []string { return nil },
+		MaxSize:    func() strongunits.B { return strongunits.GiB(20).ToBytes() },
+	})
+}
+
+func fakeCheckHost(_ *testing.T, server *mockServer) {
+	server.handler.checkHost = func(_ crcConfig.Storage) []preflight.CheckResult {
+		return []preflight.CheckResult{
+			{ID: "check-ram", Description: "Checking minimum RAM requirements", Result: preflight.CheckSkipped, SkippedByConfig: true},
+			{Description: "Checking if libvirt is installed", Labels: map[string]string{"os": "linux"}, Result: preflight.CheckFailed, Error: "libvirt is not installed", FixAvailable: true, DurationMs: 12},
+		}
+	}
+}
+
+func useTemporaryInstancesDir(t *testing.T, _ *mockServer) {
+	instancesDir, machineBaseDir := constants.CrcInstancesDir, constants.MachineBaseDir
+	constants.CrcInstancesDir = t.TempDir()
+	constants.MachineBaseDir = t.TempDir()
+	t.Cleanup(func() {
+		constants.CrcInstancesDir, constants.MachineBaseDir = instancesDir, machineBaseDir
+	})
+}
+
 func sendRequest(handler http.Handler, request *request) *http.Response {
 	url := fmt.Sprintf("/%s", request.resource)
 	var data io.Reader
@@ -142,6 +225,15 @@ func jSon(data string) response {
 	}
 }
 
+func acceptedJSON(data string) response {
+	return response{
+		statusCode: 202,
+		protoMajor: 1,
+		protoMinor: 1,
+		body:       data,
+	}
+}
+
 func empty() response {
 	return response{
 		statusCode: 200,
@@ -158,6 +250,11 @@ func httpError(statusCode int) response {
 	}
 }
 
+func v1Error(statusCode int, code, message string) response {
+	body, _ := json.Marshal(apiClient.ErrorResponse{Error: apiClient.Error{Code: code, Message: message}})
+	return httpError(statusCode).withBody(string(body) + "\n")
+}
+
 func (resp response) withBody(body string) response {
 	resp.body = body
 	return resp
@@ -302,6 +399,53 @@ var testCases = []testCase{
 		response: jSon(`{"Configs":{"cpus":4}}`),
 	},
 
+	// instances
+	{
+		preTestFunc: useTemporaryInstancesDir,
+		request:     get("instances"),
+		response:    jSon(`{"Instances":[{"Name":"crc","Current":true,"Created":false}]}`),
+	},
+
+	// snapshots
+	{
+		request:  get("snapshots"),
+		response: jSon(`{"Snapshots":[{"Name":"clean","CreationTime":"2024-01-02T03:04:05Z","BundleName":"crc_libvirt_4.15.0_amd64.crcbundle","DiskSize":1000000000}]}`),
+	},
+	{
+		request: post("snapshots").withBody(`{"name":"before-upgrade"}`),
+		response: response{
+			statusCode: 201,
+			protoMajor: 1,
+			protoMinor: 1,
+			body:       `{"Name":"before-upgrade","CreationTime":"2024-01-02T03:04:05Z","BundleName":"crc_libvirt_4.15.0_amd64.crcbundle","DiskSize":1000000000}`,
+		},
+	},
+	{
+		request:  deleteRequest("snapshots").withBody(`{"name":"clean"}`),
+		response: empty(),
+	},
+	{
+		request:  post("snapshots/restore").withBody(`{"name":"clean"}`),
+		response: empty(),
+	},
+
+	// snapshots with failure
+	{
+		request:     get("snapshots"),
+		failRequest: true,
+		response:    httpError(500).withBody("snapshot list failed\n"),
+	},
+	{
+		request:     post("snapshots").withBody(`{"name":"clean"}`),
+		failRequest: true,
+		response:    httpError(500).withBody("snapshot failed\n"),
+	},
+	{
+		request:     post("snapshots/restore").withBody(`{"name":"clean"}`),
+		failRequest: true,
+		response:    httpError(500).withBody("snapshot restore failed\n"),
+	},
+
 	// logs
 	{
 		request:  get("logs"),
@@ -357,6 +501,313 @@ var testCases = []testCase{
 		response:    httpError(500).withBody("empty pull secret\n"),
 	},
 
+	// v1 routes
+	{
+		request:  get("v1/openapi.json"),
+		response: jSon(string(openAPIDocument)),
+	},
+	{
+		request:  get("v1/version"),
+		response: jSon(fmt.Sprintf(`{"CrcVersion":"%s","CommitSha":"%s","OpenshiftVersion":"%s","MicroshiftVersion":"%s"}`, version.GetCRCVersion(), version.GetCommitSha(), version.GetBundleVersion(preset.OpenShift), version.GetBundleVersion(preset.Microshift))),
+	},
+	{
+		request:  get("v1/status"),
+		response: jSon(`{"CrcStatus":"Running","OpenshiftStatus":"Running","OpenshiftVersion":"4.5.1","DiskUse":10000000000,"DiskSize":20000000000,"RAMUse":1000,"RAMSize":2000,"Preset":"openshift"}`),
+	},
+	{
+		request:     get("v1/status"),
+		failRequest: true,
+		response:    v1Error(500, "internal_error", "broken"),
+	},
+	{
+		preTestFunc: resetOperations,
+		request:     post("v1/start"),
+		response:    acceptedJSON(`{"ID":"1","Type":"start","State":"running","StartTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:  get("v1/operations/1?wait=1m"),
+		response: jSon(`{"ID":"1","Type":"start","State":"succeeded","Result":{"Status":"","ClusterConfig":{"ClusterType":"openshift","ClusterCACert":"MIIDODCCAiCgAwIBAgIIRVfCKNUa1wIwDQYJ","KubeConfig":"/tmp/kubeconfig","KubeAdminPass":"foobar","DeveloperPass":"foobar","ClusterAPI":"https://foo.testing:6443","WebConsoleURL":"https://console.foo.testing:6443","ProxyConfig":null},"KubeletStarted":true},"StartTime":"2024-01-02T03:04:05Z","EndTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:     post("v1/start"),
+		failRequest: true,
+		response:    acceptedJSON(`{"ID":"2","Type":"start","State":"running","StartTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:     get("v1/operations/2?wait=1m"),
+		failRequest: true,
+		response:    jSon(`{"ID":"2","Type":"start","State":"failed","Error":{"code":"internal_error","message":"Failed to start"},"StartTime":"2024-01-02T03:04:05Z","EndTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:  post("v1/start").withBody("xx"),
+		response: v1Error(400, "invalid_request", "invalid character 'x' looking for beginning of value"),
+	},
+	{
+		request:  post("v1/stop"),
+		response: acceptedJSON(`{"ID":"3","Type":"stop","State":"running","StartTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:  post("v1/poweroff"),
+		response: empty(),
+	},
+	{
+		request:  deleteRequest("v1/instance"),
+		response: acceptedJSON(`{"ID":"4","Type":"delete","State":"running","StartTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:  get("v1/operations/4?wait=1m"),
+		response: jSon(`{"ID":"4","Type":"delete","State":"succeeded","StartTime":"2024-01-02T03:04:05Z","EndTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:  deleteRequest("v1/operations/4"),
+		response: v1Error(409, "not_cancellable", "operation 4 is already succeeded"),
+	},
+	{
+		request:  post("v1/bundles/download").withBody(`{"bundle":"/nonexistent/crc.crcbundle"}`),
+		response: acceptedJSON(`{"ID":"5","Type":"bundle-download","State":"running","StartTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:  get("v1/operations/5?wait=1m"),
+		response: jSon(`{"ID":"5","Type":"bundle-download","State":"succeeded","Result":{"Path":"/nonexistent/crc.crcbundle"},"StartTime":"2024-01-02T03:04:05Z","EndTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:  post("v1/resize").withBody(`{"cpus":6,"memory":12288,"diskSize":50}`),
+		response: acceptedJSON(`{"ID":"6","Type":"resize","State":"running","StartTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:  get("v1/operations/6?wait=1m"),
+		response: jSon(`{"ID":"6","Type":"resize","State":"succeeded","Result":{"CPUs":6,"Memory":12288,"DiskSize":50,"Changed":["cpus","memory","disk-size"],"RestartRequired":false},"StartTime":"2024-01-02T03:04:05Z","EndTime":"2024-01-02T03:04:05Z"}`),
+	},
+	{
+		request:  post("v1/resize"),
+		response: v1Error(400, "invalid_request", "unexpected end of JSON input"),
+	},
+	{
+		preTestFunc: resetOperations,
+		request:     get("v1/operations"),
+		response:    jSon(`{"Operations":[]}`),
+	},
+	{
+		request:  get("v1/operations/unknown"),
+		response: v1Error(404, "not_found", "operation not found"),
+	},
+	{
+		request:  get("v1/operations/"),
+		response: v1Error(404, "not_found", "operation not found"),
+	},
+	{
+		request:  get("v1/webconsoleurl"),
+		response: jSon(`{"ClusterConfig":{"ClusterType":"openshift","ClusterCACert":"MIIDODCCAiCgAwIBAgIIRVfCKNUa1wIwDQYJ","KubeConfig":"/tmp/kubeconfig","KubeAdminPass":"foobar","DeveloperPass":"foobar","ClusterAPI":"https://foo.testing:6443","WebConsoleURL":"https://console.foo.testing:6443","ProxyConfig":null},"State":"Running"}`),
+	},
+	{
+		request:  get("v1/config?cpus"),
+		response: jSon(`{"Configs":{"cpus":4}}`),
+	},
+	{
+		request:  post("v1/config").withBody(`{"properties":{"cpus":1}}`),
+		response: v1Error(400, "invalid_request", "Value '1' for configuration property 'cpus' is invalid, reason: requires CPUs >= 4"),
+	},
+	{
+		request:  deleteRequest("v1/config"),
+		response: v1Error(400, "invalid_request", "unexpected end of JSON input"),
+	},
+	{
+		preTestFunc: useTemporaryInstancesDir,
+		request:     get("v1/instances"),
+		response:    jSon(`{"Instances":[{"Name":"crc","Current":true,"Created":false}]}`),
+	},
+	{
+		request:  get("v1/snapshots"),
+		response: jSon(`{"Snapshots":[{"Name":"clean","CreationTime":"2024-01-02T03:04:05Z","BundleName":"crc_libvirt_4.15.0_amd64.crcbundle","DiskSize":1000000000}]}`),
+	},
+	{
+		request: post("v1/snapshots").withBody(`{"name":"before-upgrade"}`),
+		response: response{
+			statusCode: 201,
+			protoMajor: 1,
+			protoMinor: 1,
+			body:       `{"Name":"before-upgrade","CreationTime":"2024-01-02T03:04:05Z","BundleName":"crc_libvirt_4.15.0_amd64.crcbundle","DiskSize":1000000000}`,
+		},
+	},
+	{
+		request:  deleteRequest("v1/snapshots").withBody(`{"name":"clean"}`),
+		response: empty(),
+	},
+	{
+		request:  post("v1/snapshots/restore").withBody(`{"name":"clean"}`),
+		response: empty(),
+	},
+	{
+		request:  get("v1/ports"),
+		response: jSon(`{"Ports":[{"protocol":"tcp","local":"127.0.0.1:5432","remote":"192.168.127.2:30432","active":true}]}`),
+	},
+	{
+		request: post("v1/ports").withBody(`{"protocol":"tcp","local":"127.0.0.1:8080","remote":"192.168.127.2:30080"}`),
+		response: response{
+			statusCode: 201,
+			protoMajor: 1,
+			protoMinor: 1,
+			body:       `{"protocol":"tcp","local":"127.0.0.1:8080","remote":"192.168.127.2:30080"}`,
+		},
+	},
+	{
+		request:  deleteRequest("v1/ports").withBody(`{"protocol":"tcp","local":"127.0.0.1:8080"}`),
+		response: empty(),
+	},
+	{
+		request:  post("v1/ports"),
+		response: v1Error(400, "invalid_request", "unexpected end of JSON input"),
+	},
+	{
+		request:  get("v1/volumes"),
+		response: jSon(`{"Volumes":[{"Name":"data","Size":10}]}`),
+	},
+	{
+		request: post("v1/volumes").withBody(`{"name":"db","size":5}`),
+		response: response{
+			statusCode: 201,
+			protoMajor: 1,
+			protoMinor: 1,
+			body:       `{"Name":"db","Size":5}`,
+		},
+	},
+	{
+		request:  post("v1/volumes/attach").withBody(`{"name":"data"}`),
+		response: jSon(`{"Name":"data","Size":10,"Instance":"crc","Device":"vdb","PersistentVolume":"crc-volume-data"}`),
+	},
+	{
+		request:  post("v1/volumes/detach").withBody(`{"name":"data"}`),
+		response: empty(),
+	},
+	{
+		request:  deleteRequest("v1/volumes").withBody(`{"name":"data"}`),
+		response: empty(),
+	},
+	{
+		request:  get("v1/shared-dirs"),
+		response: jSon(`{"SharedDirs":[]}`),
+	},
+	{
+		request:  post("v1/shared-dirs").withBody(`{"source":"relative"}`),
+		response: v1Error(400, "invalid_request", "invalid shared directory: 'relative' is not an absolute path"),
+	},
+	{
+		request:  deleteRequest("v1/shared-dirs").withBody(`{"target":"/mnt/data"}`),
+		response: v1Error(404, "not_found", "shared directory not found: /mnt/data"),
+	},
+	{
+		request:  get("v1/dns/records"),
+		response: jSon(`{"Records":[]}`),
+	},
+	{
+		preTestFunc: fakeDNS,
+		request:     post("v1/dns/records").withBody(`{"hostname":"DB.mycompany.test"}`),
+		response: response{
+			statusCode: 201,
+			protoMajor: 1,
+			protoMinor: 1,
+			body:       `{"hostname":"db.mycompany.test","ip":"192.168.127.2"}`,
+		},
+	},
+	{
+		request:  get("v1/dns/records"),
+		response: jSon(`{"Records":[{"hostname":"db.mycompany.test","ip":"192.168.127.2"}]}`),
+	},
+	{
+		request:  post("v1/dns/records").withBody(`{"hostname":"db","ip":"192.168.127.3"}`),
+		response: v1Error(400, "invalid_request", "invalid DNS record: 'db' is not a fully qualified domain name"),
+	},
+	{
+		request:  deleteRequest("v1/dns/records").withBody(`{"hostname":"db.mycompany.test"}`),
+		response: jSon(`{"restartRequired":true}`),
+	},
+	{
+		request:  deleteRequest("v1/dns/records").withBody(`{"hostname":"db.mycompany.test"}`),
+		response: v1Error(404, "not_found", "DNS record not found: db.mycompany.test"),
+	},
+	{
+		request:  get("v1/registry-cache"),
+		response: v1Error(500, "internal_error", "the registry cache is not running"),
+	},
+	{
+		preTestFunc: fakeRegistryCache,
+		request:     get("v1/registry-cache"),
+		response:    jSon(`{"Enabled":false,"Registries":[],"Blobs":0,"Manifests":0,"Size":0,"MaxSize":21474836480,"Hits":0,"Misses":0}`),
+	},
+	{
+		preTestFunc: fakeRegistryCache,
+		request:     post("v1/registry-cache/prune").withBody(`{"unusedFor":"bad"}`),
+		response:    v1Error(400, "invalid_request", "invalid unused duration: bad"),
+	},
+	{
+		preTestFunc: fakeRegistryCache,
+		request:     post("v1/registry-cache/prune"),
+		response:    jSon(`{"Removed":0,"Freed":0}`),
+	},
+	{
+		request:  get("v1/logs"),
+		response: jSon(`{"Messages":["message 1","message 2","message 3"]}`),
+	},
+	{
+		preTestFunc: fakeDiagnose(nil),
+		request:     get("v1/diagnose"),
+		response:    jSon("diagnose tarball"),
+	},
+	{
+		preTestFunc: fakeDiagnose(errors.New("cannot write tarball")),
+		request:     get("v1/diagnose"),
+		response:    v1Error(500, "internal_error", "cannot write tarball"),
+	},
+	{
+		preTestFunc: fakeCheckHost,
+		request:     get("v1/preflight"),
+		response:    jSon(`{"Checks":[{"id":"check-ram","description":"Checking minimum RAM requirements","result":"skipped","fixAvailable":false,"skippedByConfig":true,"durationMs":0},{"description":"Checking if libvirt is installed","labels":{"os":"linux"},"result":"failed","error":"libvirt is not installed","fixAvailable":true,"durationMs":12}]}`),
+	},
+	{
+		preTestFunc: fakeExec(0, nil),
+		request:     post("v1/exec").withBody(`{"command":["cat","/etc/hostname"],"stdin":"aGVsbG8="}`),
+		response: jSon(`{"stream":"stdout","data":"Y2F0IC9ldGMvaG9zdG5hbWU6IGhlbGxv"}` + "\n" +
+			`{"stream":"stderr","data":"d2FybmluZw=="}` + "\n" +
+			`{"exitCode":0}` + "\n"),
+	},
+	{
+		preTestFunc: fakeExec(3, nil),
+		request:     post("v1/exec").withBody(`{"command":["false"]}`),
+		response: jSon(`{"stream":"stdout","data":"ZmFsc2U6IA=="}` + "\n" +
+			`{"stream":"stderr","data":"d2FybmluZw=="}` + "\n" +
+			`{"exitCode":3}` + "\n"),
+	},
+	{
+		preTestFunc: fakeExec(0, errors.New("connection refused")),
+		request:     post("v1/exec").withBody(`{"command":["uptime"]}`),
+		response:    jSon(`{"error":"connection refused"}` + "\n"),
+	},
+	{
+		request:  post("v1/exec").withBody(`{"command":[]}`),
+		response: v1Error(400, "invalid_request", "command is required"),
+	},
+	{
+		request:  post("v1/telemetry"),
+		response: v1Error(400, "invalid_request", "unexpected end of JSON input"),
+	},
+	{
+		// the pull secret was removed by the pull-secret failure tests
+		request:  get("v1/pull-secret"),
+		response: httpError(404),
+	},
+	{
+		request:  post("v1/pull-secret"),
+		response: v1Error(500, "internal_error", "empty pull secret"),
+	},
+	{
+		request:  get("v1/notfound"),
+		response: v1Error(404, "not_found", "Not Found"),
+	},
+	{
+		request:  get("v1/stop"),
+		response: v1Error(405, "method_not_allowed", "Method Not Allowed"),
+	},
+
 	// not found
 	{
 		request:  get("notfound"),
@@ -486,17 +937,20 @@ func TestRequests(t *testing.T) {
 func TestRoutes(t *testing.T) {
 	// this checks that we have test cases for all routes registered with the `api` entrypoint
 
+	server := newMockServer("")
 	var routes = map[string][]string{}
 	for _, testCase := range testCases {
 		// Add leading '/', remove trailing '?....'
 		pattern := fmt.Sprintf("/%s", strings.SplitN(testCase.request.resource, "?", 2)[0])
+		if match, ok := server.match(pattern); ok {
+			pattern = match
+		}
 		if _, ok := routes[pattern]; !ok {
 			routes[pattern] = []string{}
 		}
 		routes[pattern] = append(routes[pattern], testCase.request.httpMethod)
 	}
 
-	server := newMockServer("")
 	for pattern, methodMap := range server.routes {
 		assert.Contains(t, routes, pattern)
 		for method := range methodMap {
@@ -8,6 +8,13 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
+
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	"github.com/crc-org/crc/v2/pkg/crc/registrycache"
+	"go.podman.io/common/pkg/strongunits"
 )
 
 type Client interface {
@@ -23,6 +30,36 @@ type Client interface {
 	Telemetry(action string) error
 	IsPullSecretDefined() (bool, error)
 	SetPullSecret(data string) error
+	Instances() (InstancesResult, error)
+	CreateSnapshot(name string) (types.SnapshotInfo, error)
+	ListSnapshots() (SnapshotsResult, error)
+	RestoreSnapshot(name string) error
+	DeleteSnapshot(name string) error
+	DownloadBundle(bundle string) (BundleDownloadResult, error)
+	Operations() (OperationsResult, error)
+	Operation(id string) (OperationResult, error)
+	CancelOperation(id string) (OperationResult, error)
+	Logs() (LogsResult, error)
+	Preflight() (PreflightResult, error)
+	Diagnose(w io.Writer) error
+	Exec(req ExecRequest, stdout, stderr io.Writer) (int, error)
+	ExposePort(forward types.PortForward) (types.PortForward, error)
+	UnexposePort(protocol, local string) error
+	ListPorts() (PortsResult, error)
+	AddDNSRecord(record network.DNSRecord) (network.DNSRecord, error)
+	RemoveDNSRecord(hostname string) (RemoveDNSRecordResult, error)
+	ListDNSRecords() (DNSRecordsResult, error)
+	CreateVolume(name string, size strongunits.GiB) (types.VolumeInfo, error)
+	DeleteVolume(name string) error
+	AttachVolume(name string) (types.VolumeInfo, error)
+	DetachVolume(name string) error
+	ListVolumes() (VolumesResult, error)
+	AddSharedDir(dir machineConfig.SharedDir) (SharedDirResult, error)
+	RemoveSharedDir(target string) (SharedDirResult, error)
+	ListSharedDirs() (SharedDirsResult, error)
+	RegistryCache() (RegistryCacheResult, error)
+	PruneRegistryCache(unusedFor string) (registrycache.PruneResult, error)
+	Resize(req ResizeRequest) (ResizeResult, error)
 }
 
 type HTTPError struct {
@@ -30,12 +67,47 @@ type HTTPError struct {
 	Method     string
 	StatusCode int
 	Body       string
+	// Code and Message are set from the error object returned by the
+	// /v1 routes
+	Code    string
+	Message string
 }
 
 func (err *HTTPError) Error() string {
+	if err.Message != "" {
+		return err.Message
+	}
 	return err.Body
 }
 
+func newHTTPError(url, method string, statusCode int, body []byte) *HTTPError {
+	httpErr := &HTTPError{
+		URL:        url,
+		Method:     method,
+		StatusCode: statusCode,
+		Body:       string(body),
+	}
+	var errResponse ErrorResponse
+	if err := json.Unmarshal(body, &errResponse); err == nil {
+		httpErr.Code = errResponse.Error.Code
+		httpErr.Message = errResponse.Error.Message
+	}
+	return httpErr
+}
+
+// OperationError is returned when a long-running operation failed or was
+// cancelled
+type OperationError struct {
+	ID      string
+	Type    string
+	Code    string
+	Message string
+}
+
+func (err *OperationError) Error() string {
+	return err.Message
+}
+
 type client struct {
 	client *http.Client
 	base   string
@@ -48,6 +120,9 @@ func New(httpClient *http.Client, baseURL string) Client {
 	}
 }
 
+// Version uses the unversioned route, which the daemons of the releases
+// without the /v1 routes also serve, so that a version mismatch with such a
+// daemon is detected and reported
 func (c *client) Version() (VersionResult, error) {
 	var vr = VersionResult{}
 	body, err := c.sendGetRequest("/version")
@@ -63,7 +138,7 @@ func (c *client) Version() (VersionResult, error) {
 
 func (c *client) Status() (ClusterStatusResult, error) {
 	var sr = ClusterStatusResult{}
-	body, err := c.sendGetRequest("/status")
+	body, err := c.sendGetRequest("/v1/status")
 	if err != nil {
 		return sr, err
 	}
@@ -83,30 +158,45 @@ func (c *client) Start(config StartConfig) (StartResult, error) {
 			return sr, fmt.Errorf("Failed to encode data to JSON: %w", err)
 		}
 	}
-	body, err := c.sendPostRequest("/start", data)
+	body, err := c.sendPostRequest("/v1/start", data)
 	if err != nil {
 		return sr, err
 	}
-	err = json.Unmarshal(body, &sr)
+	return sr, c.waitForOperation(body, &sr)
+}
+
+func (c *client) Stop() error {
+	body, err := c.sendPostRequest("/v1/stop", nil)
 	if err != nil {
-		return sr, err
+		return err
 	}
-	return sr, nil
+	return c.waitForOperation(body, nil)
 }
 
-func (c *client) Stop() error {
-	_, err := c.sendGetRequest("/stop")
-	return err
+func (c *client) Resize(req ResizeRequest) (ResizeResult, error) {
+	var rr = ResizeResult{}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return rr, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/v1/resize", bytes.NewReader(data))
+	if err != nil {
+		return rr, err
+	}
+	return rr, c.waitForOperation(body, &rr)
 }
 
 func (c *client) Delete() error {
-	_, err := c.sendGetRequest("/delete")
-	return err
+	body, err := c.sendDeleteRequest("/v1/instance", nil)
+	if err != nil {
+		return err
+	}
+	return c.waitForOperation(body, nil)
 }
 
 func (c *client) WebconsoleURL() (*ConsoleResult, error) {
 	var cr = ConsoleResult{}
-	body, err := c.sendGetRequest("/webconsoleurl")
+	body, err := c.sendGetRequest("/v1/webconsoleurl")
 	if err != nil {
 		return &cr, err
 	}
@@ -124,7 +214,7 @@ func (c *client) GetConfig(configs []string) (GetConfigResult, error) {
 		escapeConfigs = append(escapeConfigs, url.QueryEscape(v))
 	}
 	queryString := strings.Join(escapeConfigs, "&")
-	body, err := c.sendGetRequest(fmt.Sprintf("/config?%s", queryString))
+	body, err := c.sendGetRequest(fmt.Sprintf("/v1/config?%s", queryString))
 	if err != nil {
 		return gcr, err
 	}
@@ -147,7 +237,7 @@ func (c *client) SetConfig(configs SetConfigRequest) (SetOrUnsetConfigResult, er
 		return scr, fmt.Errorf("Failed to encode data to JSON: %w", err)
 	}
 
-	body, err := c.sendPostRequest("/config", data)
+	body, err := c.sendPostRequest("/v1/config", data)
 	if err != nil {
 		return scr, err
 	}
@@ -169,7 +259,7 @@ func (c *client) UnsetConfig(configs []string) (SetOrUnsetConfigResult, error) {
 	if err := json.NewEncoder(data).Encode(cfg); err != nil {
 		return ucr, fmt.Errorf("Failed to encode data to JSON: %w", err)
 	}
-	body, err := c.sendDeleteRequest("/config", data)
+	body, err := c.sendDeleteRequest("/v1/config", data)
 	if err != nil {
 		return ucr, err
 	}
@@ -188,13 +278,13 @@ func (c *client) Telemetry(action string) error {
 		return fmt.Errorf("Failed to encode data to JSON: %w", err)
 	}
 
-	_, err = c.sendPostRequest("/telemetry", bytes.NewReader(data))
+	_, err = c.sendPostRequest("/v1/telemetry", bytes.NewReader(data))
 
 	return err
 }
 
 func (c *client) IsPullSecretDefined() (bool, error) {
-	res, err := c.client.Get(fmt.Sprintf("%s%s", c.base, "/pull-secret"))
+	res, err := c.client.Get(fmt.Sprintf("%s%s", c.base, "/v1/pull-secret"))
 	if err != nil {
 		return false, err
 	}
@@ -203,35 +293,507 @@ func (c *client) IsPullSecretDefined() (bool, error) {
 }
 
 func (c *client) SetPullSecret(data string) error {
-	_, err := c.sendPostRequest("/pull-secret", bytes.NewReader([]byte(data)))
+	_, err := c.sendPostRequest("/v1/pull-secret", bytes.NewReader([]byte(data)))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (c *client) sendGetRequest(url string) ([]byte, error) {
-	res, err := c.client.Get(fmt.Sprintf("%s%s", c.base, url))
+func (c *client) Instances() (InstancesResult, error) {
+	var ir = InstancesResult{}
+	body, err := c.sendGetRequest("/v1/instances")
 	if err != nil {
-		return nil, err
+		return ir, err
 	}
-	defer res.Body.Close()
+	err = json.Unmarshal(body, &ir)
+	if err != nil {
+		return ir, err
+	}
+	return ir, nil
+}
 
-	body, err := io.ReadAll(res.Body)
+func (c *client) CreateSnapshot(name string) (types.SnapshotInfo, error) {
+	var si = types.SnapshotInfo{}
+	data, err := json.Marshal(SnapshotRequest{
+		Name: name,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("Unknown error reading response: %w", err)
+		return si, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/v1/snapshots", bytes.NewReader(data))
+	if err != nil {
+		return si, err
+	}
+	err = json.Unmarshal(body, &si)
+	if err != nil {
+		return si, err
+	}
+	return si, nil
+}
+
+func (c *client) ListSnapshots() (SnapshotsResult, error) {
+	var sr = SnapshotsResult{}
+	body, err := c.sendGetRequest("/v1/snapshots")
+	if err != nil {
+		return sr, err
+	}
+	err = json.Unmarshal(body, &sr)
+	if err != nil {
+		return sr, err
+	}
+	return sr, nil
+}
+
+func (c *client) RestoreSnapshot(name string) error {
+	data, err := json.Marshal(SnapshotRequest{
+		Name: name,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	_, err = c.sendPostRequest("/v1/snapshots/restore", bytes.NewReader(data))
+	return err
+}
+
+func (c *client) DeleteSnapshot(name string) error {
+	data, err := json.Marshal(SnapshotRequest{
+		Name: name,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	_, err = c.sendDeleteRequest("/v1/snapshots", bytes.NewReader(data))
+	return err
+}
+
+func (c *client) ExposePort(forward types.PortForward) (types.PortForward, error) {
+	var pf = types.PortForward{}
+	data, err := json.Marshal(forward)
+	if err != nil {
+		return pf, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/v1/ports", bytes.NewReader(data))
+	if err != nil {
+		return pf, err
+	}
+	err = json.Unmarshal(body, &pf)
+	if err != nil {
+		return pf, err
+	}
+	return pf, nil
+}
+
+func (c *client) UnexposePort(protocol, local string) error {
+	data, err := json.Marshal(UnexposePortRequest{
+		Protocol: protocol,
+		Local:    local,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	_, err = c.sendDeleteRequest("/v1/ports", bytes.NewReader(data))
+	return err
+}
+
+func (c *client) ListPorts() (PortsResult, error) {
+	var pr = PortsResult{}
+	body, err := c.sendGetRequest("/v1/ports")
+	if err != nil {
+		return pr, err
+	}
+	err = json.Unmarshal(body, &pr)
+	if err != nil {
+		return pr, err
+	}
+	return pr, nil
+}
+
+func (c *client) AddDNSRecord(record network.DNSRecord) (network.DNSRecord, error) {
+	var dr = network.DNSRecord{}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return dr, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/v1/dns/records", bytes.NewReader(data))
+	if err != nil {
+		return dr, err
+	}
+	err = json.Unmarshal(body, &dr)
+	if err != nil {
+		return dr, err
+	}
+	return dr, nil
+}
+
+func (c *client) RemoveDNSRecord(hostname string) (RemoveDNSRecordResult, error) {
+	var rr = RemoveDNSRecordResult{}
+	data, err := json.Marshal(RemoveDNSRecordRequest{
+		Hostname: hostname,
+	})
+	if err != nil {
+		return rr, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendDeleteRequest("/v1/dns/records", bytes.NewReader(data))
+	if err != nil {
+		return rr, err
+	}
+	err = json.Unmarshal(body, &rr)
+	if err != nil {
+		return rr, err
+	}
+	return rr, nil
+}
+
+func (c *client) ListDNSRecords() (DNSRecordsResult, error) {
+	var dr = DNSRecordsResult{}
+	body, err := c.sendGetRequest("/v1/dns/records")
+	if err != nil {
+		return dr, err
+	}
+	err = json.Unmarshal(body, &dr)
+	if err != nil {
+		return dr, err
+	}
+	return dr, nil
+}
+
+func (c *client) RegistryCache() (RegistryCacheResult, error) {
+	var rr = RegistryCacheResult{}
+	body, err := c.sendGetRequest("/v1/registry-cache")
+	if err != nil {
+		return rr, err
+	}
+	err = json.Unmarshal(body, &rr)
+	if err != nil {
+		return rr, err
+	}
+	return rr, nil
+}
+
+func (c *client) PruneRegistryCache(unusedFor string) (registrycache.PruneResult, error) {
+	var pr = registrycache.PruneResult{}
+	data, err := json.Marshal(PruneRegistryCacheRequest{
+		UnusedFor: unusedFor,
+	})
+	if err != nil {
+		return pr, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/v1/registry-cache/prune", bytes.NewReader(data))
+	if err != nil {
+		return pr, err
+	}
+	err = json.Unmarshal(body, &pr)
+	if err != nil {
+		return pr, err
+	}
+	return pr, nil
+}
+
+func (c *client) CreateVolume(name string, size strongunits.GiB) (types.VolumeInfo, error) {
+	var vi = types.VolumeInfo{}
+	data, err := json.Marshal(CreateVolumeRequest{
+		Name: name,
+		Size: size,
+	})
+	if err != nil {
+		return vi, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/v1/volumes", bytes.NewReader(data))
+	if err != nil {
+		return vi, err
+	}
+	err = json.Unmarshal(body, &vi)
+	if err != nil {
+		return vi, err
+	}
+	return vi, nil
+}
+
+func (c *client) DeleteVolume(name string) error {
+	data, err := json.Marshal(VolumeRequest{
+		Name: name,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	_, err = c.sendDeleteRequest("/v1/volumes", bytes.NewReader(data))
+	return err
+}
+
+func (c *client) AttachVolume(name string) (types.VolumeInfo, error) {
+	var vi = types.VolumeInfo{}
+	data, err := json.Marshal(VolumeRequest{
+		Name: name,
+	})
+	if err != nil {
+		return vi, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/v1/volumes/attach", bytes.NewReader(data))
+	if err != nil {
+		return vi, err
+	}
+	err = json.Unmarshal(body, &vi)
+	if err != nil {
+		return vi, err
+	}
+	return vi, nil
+}
+
+func (c *client) DetachVolume(name string) error {
+	data, err := json.Marshal(VolumeRequest{
+		Name: name,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	_, err = c.sendPostRequest("/v1/volumes/detach", bytes.NewReader(data))
+	return err
+}
+
+func (c *client) ListVolumes() (VolumesResult, error) {
+	var vr = VolumesResult{}
+	body, err := c.sendGetRequest("/v1/volumes")
+	if err != nil {
+		return vr, err
+	}
+	err = json.Unmarshal(body, &vr)
+	if err != nil {
+		return vr, err
+	}
+	return vr, nil
+}
+
+func (c *client) AddSharedDir(dir machineConfig.SharedDir) (SharedDirResult, error) {
+	var sr = SharedDirResult{}
+	data, err := json.Marshal(dir)
+	if err != nil {
+		return sr, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/v1/shared-dirs", bytes.NewReader(data))
+	if err != nil {
+		return sr, err
+	}
+	err = json.Unmarshal(body, &sr)
+	if err != nil {
+		return sr, err
+	}
+	return sr, nil
+}
+
+func (c *client) RemoveSharedDir(target string) (SharedDirResult, error) {
+	var sr = SharedDirResult{}
+	data, err := json.Marshal(RemoveSharedDirRequest{
+		Target: target,
+	})
+	if err != nil {
+		return sr, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendDeleteRequest("/v1/shared-dirs", bytes.NewReader(data))
+	if err != nil {
+		return sr, err
+	}
+	err = json.Unmarshal(body, &sr)
+	if err != nil {
+		return sr, err
+	}
+	return sr, nil
+}
+
+func (c *client) ListSharedDirs() (SharedDirsResult, error) {
+	var sr = SharedDirsResult{}
+	body, err := c.sendGetRequest("/v1/shared-dirs")
+	if err != nil {
+		return sr, err
+	}
+	err = json.Unmarshal(body, &sr)
+	if err != nil {
+		return sr, err
+	}
+	return sr, nil
+}
+
+func (c *client) DownloadBundle(bundle string) (BundleDownloadResult, error) {
+	var br = BundleDownloadResult{}
+	data, err := json.Marshal(BundleDownloadRequest{
+		Bundle: bundle,
+	})
+	if err != nil {
+		return br, fmt.Errorf("Failed to encode data to JSON: %w", err)
+	}
+	body, err := c.sendPostRequest("/v1/bundles/download", bytes.NewReader(data))
+	if err != nil {
+		return br, err
+	}
+	return br, c.waitForOperation(body, &br)
+}
+
+func (c *client) Operations() (OperationsResult, error) {
+	var or = OperationsResult{}
+	body, err := c.sendGetRequest("/v1/operations")
+	if err != nil {
+		return or, err
+	}
+	err = json.Unmarshal(body, &or)
+	if err != nil {
+		return or, err
+	}
+	return or, nil
+}
+
+func (c *client) Operation(id string) (OperationResult, error) {
+	var or = OperationResult{}
+	body, err := c.sendGetRequest("/v1/operations/" + url.PathEscape(id))
+	if err != nil {
+		return or, err
 	}
+	err = json.Unmarshal(body, &or)
+	if err != nil {
+		return or, err
+	}
+	return or, nil
+}
+
+func (c *client) CancelOperation(id string) (OperationResult, error) {
+	var or = OperationResult{}
+	body, err := c.sendDeleteRequest("/v1/operations/"+url.PathEscape(id), nil)
+	if err != nil {
+		return or, err
+	}
+	err = json.Unmarshal(body, &or)
+	if err != nil {
+		return or, err
+	}
+	return or, nil
+}
+
+func (c *client) Logs() (LogsResult, error) {
+	var lr = LogsResult{}
+	body, err := c.sendGetRequest("/v1/logs")
+	if err != nil {
+		return lr, err
+	}
+	err = json.Unmarshal(body, &lr)
+	if err != nil {
+		return lr, err
+	}
+	return lr, nil
+}
+
+func (c *client) Preflight() (PreflightResult, error) {
+	var pr = PreflightResult{}
+	body, err := c.sendGetRequest("/v1/preflight")
+	if err != nil {
+		return pr, err
+	}
+	err = json.Unmarshal(body, &pr)
+	if err != nil {
+		return pr, err
+	}
+	return pr, nil
+}
+
+// Diagnose writes to 'w' the gzipped tarball created by 'crc diagnose', as it
+// is streamed by the daemon
+func (c *client) Diagnose(w io.Writer) error {
+	body, err := c.openStream("/v1/diagnose", http.MethodGet, nil)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	_, err = io.Copy(w, body)
+	return err
+}
 
-	if res.StatusCode != http.StatusOK {
-		return nil, &HTTPError{
-			URL:        url,
-			Method:     "GET",
-			StatusCode: res.StatusCode,
-			Body:       string(body),
+// Exec runs a command in the VM, copies its output to 'stdout' and 'stderr'
+// as it is streamed by the daemon, and returns its exit code
+func (c *client) Exec(req ExecRequest, stdout, stderr io.Writer) (int, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return -1, err
+	}
+	body, err := c.openStream("/v1/exec", http.MethodPost, bytes.NewReader(data))
+	if err != nil {
+		return -1, err
+	}
+	defer body.Close()
+
+	decoder := json.NewDecoder(body)
+	for {
+		var frame ExecFrame
+		if err := decoder.Decode(&frame); err != nil {
+			if err == io.EOF {
+				return -1, fmt.Errorf("The exec stream ended before the command exited")
+			}
+			return -1, err
+		}
+		switch {
+		case frame.Error != "":
+			return -1, fmt.Errorf("%s", frame.Error)
+		case frame.ExitCode != nil:
+			return *frame.ExitCode, nil
+		case frame.Stream == ExecStreamStdout:
+			if _, err := stdout.Write(frame.Data); err != nil {
+				return -1, err
+			}
+		case frame.Stream == ExecStreamStderr:
+			if _, err := stderr.Write(frame.Data); err != nil {
+				return -1, err
+			}
 		}
 	}
+}
 
-	return body, nil
+// defaultOperationWait is how long each request polling a running operation
+// waits for it to finish. It must be well below the timeout of the HTTP
+// client, otherwise the polls of the long operations time out.
+const defaultOperationWait = 10 * time.Second
+
+// operationWait returns how long the operation polls wait, a third of the
+// timeout of the HTTP client when it is shorter than defaultOperationWait
+func (c *client) operationWait() time.Duration {
+	if timeout := c.client.Timeout; timeout > 0 && timeout/3 < defaultOperationWait {
+		return timeout / 3
+	}
+	return defaultOperationWait
+}
+
+// waitForOperation polls the operation described by 'body' until it is
+// finished, and decodes its result into 'result' when it is not nil
+func (c *client) waitForOperation(body []byte, result interface{}) error {
+	var op OperationResult
+	if err := json.Unmarshal(body, &op); err != nil {
+		return err
+	}
+	for !op.Finished() {
+		body, err := c.sendGetRequest(fmt.Sprintf("/v1/operations/%s?wait=%s", url.PathEscape(op.ID), c.operationWait()))
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(body, &op); err != nil {
+			return err
+		}
+	}
+	if op.State != OperationSucceeded {
+		opErr := &OperationError{
+			ID:      op.ID,
+			Type:    op.Type,
+			Message: fmt.Sprintf("%s operation %s", op.Type, op.State),
+		}
+		if op.Error != nil {
+			opErr.Code = op.Error.Code
+			opErr.Message = op.Error.Message
+		}
+		return opErr
+	}
+	if result == nil || len(op.Result) == 0 {
+		return nil
+	}
+	return json.Unmarshal(op.Result, result)
+}
+
+func (c *client) sendGetRequest(url string) ([]byte, error) {
+	return c.sendRequest(url, http.MethodGet, nil)
 }
 
 func (c *client) sendPostRequest(url string, data io.Reader) ([]byte, error) {
@@ -243,32 +805,54 @@ func (c *client) sendDeleteRequest(url string, data io.Reader) ([]byte, error) {
 }
 
 func (c *client) sendRequest(url string, method string, data io.Reader) ([]byte, error) {
-	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.base, url), data)
+	resBody, err := c.openRequest(url, method, data)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/json")
+	defer resBody.Close()
 
-	res, err := c.client.Do(req) //nolint:gosec // G704: SSRF vulnerability check not needed as the base URL is unix socket
+	body, err := io.ReadAll(resBody)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Unknown error reading response: %w", err)
 	}
-	defer res.Body.Close()
+	return body, nil
+}
 
-	switch method {
-	case http.MethodPost:
-		if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-			return nil, fmt.Errorf("Error occurred sending POST request to : %s : %d", url, res.StatusCode)
-		}
-	case http.MethodDelete, http.MethodGet:
-		if res.StatusCode != http.StatusOK {
-			return nil, fmt.Errorf("Error occurred sending %s request to : %s : %d", method, url, res.StatusCode)
-		}
+// openRequest sends a request and returns the body of the response, which
+// must be closed by the caller, when it succeeded
+func (c *client) openRequest(url string, method string, data io.Reader) (io.ReadCloser, error) {
+	return c.do(c.client, url, method, data)
+}
+
+// openStream is like openRequest, but the request is not bound to the
+// timeout of the client, as a streamed response can last longer
+func (c *client) openStream(url string, method string, data io.Reader) (io.ReadCloser, error) {
+	streamClient := *c.client
+	streamClient.Timeout = 0
+	return c.do(&streamClient, url, method, data)
+}
+
+func (c *client) do(httpClient *http.Client, url string, method string, data io.Reader) (io.ReadCloser, error) {
+	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.base, url), data)
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
 	}
 
-	body, err := io.ReadAll(res.Body)
+	res, err := httpClient.Do(req) //nolint:gosec // G704: SSRF vulnerability check not needed as the base URL is unix socket
 	if err != nil {
-		return nil, fmt.Errorf("Unknown error reading response: %w", err)
+		return nil, err
 	}
-	return body, nil
+
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated && res.StatusCode != http.StatusAccepted {
+		defer res.Body.Close()
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Unknown error reading response: %w", err)
+		}
+		return nil, newHTTPError(url, method, res.StatusCode, body)
+	}
+	return res.Body, nil
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/progress"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/r3labs/sse/v2"
 )
@@ -34,3 +35,31 @@ func (c *SSEClient) Status(statusCallback func(*types.ClusterLoadResult)) error
 
 	return err
 }
+
+func (c *SSEClient) StartProgress(progressCallback func(*progress.Event)) error {
+	err := c.client.Subscribe("start-progress", func(msg *sse.Event) {
+		event := &progress.Event{}
+		err := json.Unmarshal(msg.Data, event)
+		if err != nil {
+			logging.Errorf("Could not parse start progress event: %s", err)
+			return
+		}
+		progressCallback(event)
+	})
+
+	return err
+}
+
+func (c *SSEClient) Operations(operationCallback func(*OperationResult)) error {
+	err := c.client.Subscribe("operations", func(msg *sse.Event) {
+		operation := &OperationResult{}
+		err := json.Unmarshal(msg.Data, operation)
+		if err != nil {
+			logging.Errorf("Could not parse operation event: %s", err)
+			return
+		}
+		operationCallback(operation)
+	})
+
+	return err
+}
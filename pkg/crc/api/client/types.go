@@ -1,9 +1,15 @@
 package client
 
 import (
+	"encoding/json"
+	"time"
+
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/registrycache"
 	"go.podman.io/common/pkg/strongunits"
 )
 
@@ -31,6 +37,127 @@ type ClusterStatusResult struct {
 	PersistentVolumeUse  strongunits.B `json:"PersistentVolumeUse,omitempty"`
 	PersistentVolumeSize strongunits.B `json:"PersistentVolumeSize,omitempty"`
 	Preset               preset.Preset
+	SharedDirs           []machineConfig.SharedDir `json:"SharedDirs,omitempty"`
+}
+
+type InstanceResult struct {
+	Name    string
+	Current bool
+	Created bool
+	State   state.State `json:"State,omitempty"`
+}
+
+type InstancesResult struct {
+	Instances []InstanceResult
+}
+
+type SnapshotRequest struct {
+	Name string `json:"name"`
+}
+
+type SnapshotsResult struct {
+	Snapshots []types.SnapshotInfo
+}
+
+type PortsResult struct {
+	Ports []types.PortForwardInfo
+}
+
+// ResizeRequest holds the resources requested for the instance, and the
+// pull secret file used when it is started again
+type ResizeRequest struct {
+	types.ResizeConfig
+	PullSecretFile string `json:"pullSecretFile,omitempty"`
+}
+
+// ResizeResult is the result of the resize operation, StartResult is set
+// when the instance was started again after the change
+type ResizeResult struct {
+	types.ResizeResult
+	StartResult *StartResult `json:"StartResult,omitempty"`
+}
+
+type UnexposePortRequest struct {
+	Protocol string `json:"protocol"`
+	Local    string `json:"local"`
+}
+
+type VolumesResult struct {
+	Volumes []types.VolumeInfo
+}
+
+type CreateVolumeRequest struct {
+	Name string          `json:"name"`
+	Size strongunits.GiB `json:"size"`
+}
+
+type VolumeRequest struct {
+	Name string `json:"name"`
+}
+
+type SharedDirsResult struct {
+	SharedDirs []machineConfig.SharedDir
+}
+
+// SharedDirResult is the directory added to or removed from the shared-dirs
+// setting, Applied is false when the instance is only changed when it starts
+type SharedDirResult struct {
+	machineConfig.SharedDir
+	Applied bool `json:"applied"`
+}
+
+type RemoveSharedDirRequest struct {
+	Target string `json:"target"`
+}
+
+// RegistryCacheResult describes the registry cache of the daemon, Enabled
+// is the value of the registry-cache setting
+type RegistryCacheResult struct {
+	Enabled    bool
+	Registries []string
+	registrycache.Stats
+}
+
+// PruneRegistryCacheRequest removes the content of the registry cache which
+// was not used for UnusedFor, a duration like 168h, all of it when it is
+// empty
+type PruneRegistryCacheRequest struct {
+	UnusedFor string `json:"unusedFor,omitempty"`
+}
+
+type DNSRecordsResult struct {
+	Records []network.DNSRecord
+}
+
+type RemoveDNSRecordRequest struct {
+	Hostname string `json:"hostname"`
+}
+
+// RemoveDNSRecordResult tells if the removed hostname keeps resolving in the
+// user-mode network until the daemon restarts
+type RemoveDNSRecordResult struct {
+	RestartRequired bool `json:"restartRequired"`
+}
+
+// PreflightCheck is the result of a preflight check, see
+// preflight.CheckResult
+type PreflightCheck struct {
+	ID              string            `json:"id,omitempty"`
+	Description     string            `json:"description"`
+	Labels          map[string]string `json:"labels,omitempty"`
+	Result          string            `json:"result"`
+	Error           string            `json:"error,omitempty"`
+	FixAvailable    bool              `json:"fixAvailable"`
+	SkippedByConfig bool              `json:"skippedByConfig,omitempty"`
+	DurationMs      int64             `json:"durationMs"`
+}
+
+type PreflightResult struct {
+	Checks []PreflightCheck
+}
+
+type LogsResult struct {
+	Messages []string
 }
 
 type ConsoleResult struct {
@@ -66,3 +193,109 @@ type TelemetryRequest struct {
 	Source string `json:"source"`
 	Status string `json:"status"`
 }
+
+// ExecRequest is the body of the /v1/exec requests
+type ExecRequest struct {
+	// Command is the command to run and its arguments, they are not
+	// interpreted by a shell
+	Command []string `json:"command"`
+	// Stdin is sent to the standard input of the command
+	Stdin []byte `json:"stdin,omitempty"`
+}
+
+const (
+	ExecStreamStdout = "stdout"
+	ExecStreamStderr = "stderr"
+)
+
+// ExecFrame is a line of the newline-delimited JSON stream returned by
+// /v1/exec. The frames with a Stream carry the output of the command, the
+// last frame has its ExitCode, or an Error when it could not be run.
+type ExecFrame struct {
+	Stream   string `json:"stream,omitempty"`
+	Data     []byte `json:"data,omitempty"`
+	ExitCode *int   `json:"exitCode,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Error codes of the errors returned by the /v1 routes
+const (
+	ErrorCodeInvalidRequest   = "invalid_request"
+	ErrorCodeNotFound         = "not_found"
+	ErrorCodeMethodNotAllowed = "method_not_allowed"
+	ErrorCodeVMNotExist       = "vm_not_exist"
+	ErrorCodeVMNotRunning     = "vm_not_running"
+	ErrorCodeBusy             = "busy"
+	ErrorCodeConflict         = "conflict"
+	ErrorCodePreflightFailed  = "preflight_failed"
+	ErrorCodeNotCancellable   = "not_cancellable"
+	ErrorCodeCancelled        = "cancelled"
+	ErrorCodeInternal         = "internal_error"
+)
+
+// Error is the error object returned by the /v1 routes
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ErrorResponse is the body of the responses of the /v1 routes which failed
+type ErrorResponse struct {
+	Error Error `json:"error"`
+}
+
+// Types of the long-running operations started by the /v1 routes
+const (
+	OperationStart          = "start"
+	OperationStop           = "stop"
+	OperationDelete         = "delete"
+	OperationBundleDownload = "bundle-download"
+	OperationResize         = "resize"
+)
+
+type OperationState string
+
+const (
+	OperationRunning   OperationState = "running"
+	OperationSucceeded OperationState = "succeeded"
+	OperationFailed    OperationState = "failed"
+	OperationCancelled OperationState = "cancelled"
+)
+
+type OperationProgress struct {
+	Message string
+	// Current and Total are set when the amount of work is known, for
+	// example the number of bytes of a download
+	Current int64 `json:"Current,omitempty"`
+	Total   int64 `json:"Total,omitempty"`
+}
+
+// OperationResult describes a long-running operation. Result is set when
+// the operation succeeded, and its content depends on the type of the
+// operation. Error is set when it failed or was cancelled.
+type OperationResult struct {
+	ID        string
+	Type      string
+	State     OperationState
+	Progress  *OperationProgress `json:"Progress,omitempty"`
+	Result    json.RawMessage    `json:"Result,omitempty"`
+	Error     *Error             `json:"Error,omitempty"`
+	StartTime time.Time
+	EndTime   *time.Time `json:"EndTime,omitempty"`
+}
+
+func (op *OperationResult) Finished() bool {
+	return op.State != OperationRunning
+}
+
+type OperationsResult struct {
+	Operations []OperationResult
+}
+
+type BundleDownloadRequest struct {
+	Bundle string `json:"bundle"`
+}
+
+type BundleDownloadResult struct {
+	Path string
+}
@@ -0,0 +1,68 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	apiClient "github.com/crc-org/crc/v2/pkg/crc/api/client"
+	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+)
+
+// requestError is returned by the handlers when the request is invalid
+type requestError struct {
+	err error
+}
+
+func (e *requestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *requestError) Unwrap() error {
+	return e.err
+}
+
+// errVMNotRunning is returned by the handlers which need a running VM
+var errVMNotRunning = errors.New("the CRC instance is not running")
+
+// errRegistryCacheNotRunning is returned by the registry-cache routes when
+// the API is not served by the daemon
+var errRegistryCacheNotRunning = errors.New("the registry cache is not running")
+
+// classifyError returns the HTTP status code and the error code used to
+// report 'err' on the /v1 routes
+func classifyError(err error) (int, string) {
+	var reqErr *requestError
+	var preflightErr *crcErrors.PreflightError
+	var notCancellableErr *errOperationNotCancellable
+	switch {
+	case errors.As(err, &reqErr):
+		return http.StatusBadRequest, apiClient.ErrorCodeInvalidRequest
+	case errors.Is(err, crcErrors.VMNotExist):
+		return http.StatusNotFound, apiClient.ErrorCodeVMNotExist
+	case errors.Is(err, errVMNotRunning):
+		return http.StatusConflict, apiClient.ErrorCodeVMNotRunning
+	case errors.Is(err, machine.ErrInvalidPortForward), errors.Is(err, network.ErrInvalidDNSRecord), errors.Is(err, machine.ErrInvalidVolume),
+		errors.Is(err, machineConfig.ErrInvalidSharedDir):
+		return http.StatusBadRequest, apiClient.ErrorCodeInvalidRequest
+	case errors.Is(err, machine.ErrPortForwardNotFound), errors.Is(err, network.ErrDNSRecordNotFound), errors.Is(err, machine.ErrVolumeNotFound),
+		errors.Is(err, machineConfig.ErrSharedDirNotFound):
+		return http.StatusNotFound, apiClient.ErrorCodeNotFound
+	case errors.Is(err, machine.ErrPortForwardExists), errors.Is(err, machine.ErrPortForwardingUnsupported),
+		errors.Is(err, network.ErrDNSRecordsUnsupported),
+		errors.Is(err, machine.ErrVolumeExists), errors.Is(err, machine.ErrVolumeAttached):
+		return http.StatusConflict, apiClient.ErrorCodeConflict
+	case errors.Is(err, machine.ErrBusy), errors.Is(err, machine.ErrStoppingOrDeleting):
+		return http.StatusConflict, apiClient.ErrorCodeBusy
+	case errors.As(err, &preflightErr):
+		return http.StatusPreconditionFailed, apiClient.ErrorCodePreflightFailed
+	case errors.Is(err, errOperationNotFound):
+		return http.StatusNotFound, apiClient.ErrorCodeNotFound
+	case errors.As(err, &notCancellableErr):
+		return http.StatusConflict, apiClient.ErrorCodeNotCancellable
+	default:
+		return http.StatusInternalServerError, apiClient.ErrorCodeInternal
+	}
+}
@@ -56,6 +56,8 @@ func NewEventServer(machine machine.Client) *EventServer {
 
 	sseServer.CreateStream(LOGS)
 	sseServer.CreateStream(STATUS)
+	sseServer.CreateStream(OPERATIONS)
+	sseServer.CreateStream(START_PROGRESS)
 	return eventServer
 }
 
@@ -63,12 +65,20 @@ func (es *EventServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	es.sseServer.ServeHTTP(w, r)
 }
 
+// Publisher returns an EventPublisher sending events to the subscribers of
+// 'streamID'
+func (es *EventServer) Publisher(streamID string) EventPublisher {
+	return newEventPublisher(streamID, es.sseServer)
+}
+
 func createEventStream(server *EventServer, streamID string) EventStream {
 	switch streamID {
 	case LOGS:
 		return newLogsStream(server)
 	case STATUS:
 		return newStatusStream(server)
+	case OPERATIONS, START_PROGRESS:
+		return &publishedStream{}
 	}
 	return nil
 }
@@ -44,3 +44,11 @@ func (es *eventStream) RemoveSubscriber(subscriber *sse.Subscriber) {
 		es.producer.Stop()
 	}
 }
+
+// publishedStream is used for the streams whose events are published by
+// other components when they happen, independently of the subscribers
+type publishedStream struct{}
+
+func (*publishedStream) AddSubscriber(_ *sse.Subscriber) {}
+
+func (*publishedStream) RemoveSubscriber(_ *sse.Subscriber) {}
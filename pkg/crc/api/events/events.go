@@ -3,8 +3,10 @@ package events
 import "github.com/r3labs/sse/v2"
 
 const (
-	LOGS   = "logs"   // Logs event channel, contains daemon logs
-	STATUS = "status" // status event channel, contains VM load info
+	LOGS           = "logs"           // Logs event channel, contains daemon logs
+	STATUS         = "status"         // status event channel, contains VM load info
+	OPERATIONS     = "operations"     // operations event channel, contains the state changes of the API operations
+	START_PROGRESS = "start-progress" // start progress event channel, contains the phases of the instance start
 )
 
 type EventPublisher interface {
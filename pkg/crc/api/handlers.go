@@ -1,27 +1,66 @@
 package api
 
 import (
+	"bytes"
 	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
+	gvtypes "github.com/containers/gvisor-tap-vsock/pkg/types"
 	"go.podman.io/common/pkg/strongunits"
 
 	"github.com/crc-org/crc/v2/pkg/crc/api/client"
+	"github.com/crc-org/crc/v2/pkg/crc/api/events"
 	"github.com/crc-org/crc/v2/pkg/crc/cluster"
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	"github.com/crc-org/crc/v2/pkg/crc/diagnose"
 	"github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/progress"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/preflight"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/registrycache"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
 	"github.com/crc-org/crc/v2/pkg/crc/version"
+	"github.com/crc-org/crc/v2/pkg/download"
+	"github.com/r3labs/sse/v2"
 )
 
 type Handler struct {
-	Logger    Logger
-	Client    machine.Client
-	Config    *crcConfig.Config
-	Telemetry Telemetry
+	Logger        Logger
+	Client        machine.Client
+	Config        *crcConfig.Config
+	Telemetry     Telemetry
+	operations    *operationManager
+	startProgress events.EventPublisher
+	// diagnose writes the 'crc diagnose' tarball
+	diagnose func(ctx gocontext.Context, w io.Writer) error
+	// checkHost runs the preflight checks of 'crc setup'
+	checkHost func(config crcConfig.Storage) []preflight.CheckResult
+	// registryCache is the registry cache of the daemon, it is nil when the
+	// handler is not run by the daemon
+	registryCache *registrycache.Cache
+	// exec runs a command in the VM and returns its exit code
+	exec func(command string, streams ssh.Streams) (int, error)
+	// dnsZones lists the zones of the user-mode network DNS server
+	dnsZones func() ([]gvtypes.Zone, error)
+	// addDNSZone adds a zone to the user-mode network DNS server, or
+	// merges it with the existing zone with the same name
+	addDNSZone func(zone *gvtypes.Zone) error
 }
 
 type Logger interface {
@@ -32,23 +71,162 @@ type Telemetry interface {
 	UploadAction(action, source, status string) error
 }
 
-type loggerResult struct {
-	Messages []string
-}
-
 func (h *Handler) Logs(c *context) error {
-	return c.JSON(http.StatusOK, &loggerResult{
+	return c.JSON(http.StatusOK, &client.LogsResult{
 		Messages: h.Logger.Messages(),
 	})
 }
 
-func NewHandler(config *crcConfig.Config, machine machine.Client, logger Logger, telemetry Telemetry) *Handler {
-	return &Handler{
-		Client:    machine,
-		Config:    config,
-		Logger:    logger,
-		Telemetry: telemetry,
+// Preflight runs all the preflight checks of 'crc setup' without fixing
+// the failed ones
+func (h *Handler) Preflight(c *context) error {
+	var res client.PreflightResult
+	for _, check := range h.checkHost(h.Config) {
+		res.Checks = append(res.Checks, client.PreflightCheck{
+			ID:              check.ID,
+			Description:     check.Description,
+			Labels:          check.Labels,
+			Result:          string(check.Result),
+			Error:           check.Error,
+			FixAvailable:    check.FixAvailable,
+			SkippedByConfig: check.SkippedByConfig,
+			DurationMs:      check.DurationMs,
+		})
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// Diagnose streams the redacted tarball created by 'crc diagnose'. The
+// collection stops when the client goes away.
+func (h *Handler) Diagnose(c *context) error {
+	c.headers["Content-Disposition"] = `attachment; filename="crc-diagnose.tar.gz"`
+	return c.Stream(http.StatusOK, "application/gzip", func(w io.Writer) error {
+		return h.diagnose(c.ctx, w)
+	})
+}
+
+// Exec runs a command in the VM and streams its output as newline-delimited
+// JSON frames, the last one holding its exit code
+func (h *Handler) Exec(c *context) error {
+	var req client.ExecRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if len(req.Command) == 0 {
+		return &requestError{err: fmt.Errorf("command is required")}
+	}
+	exists, err := h.Client.Exists()
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.VMNotExist
+	}
+	running, err := h.Client.IsRunning()
+	if err != nil {
+		return err
+	}
+	if !running {
+		return errVMNotRunning
+	}
+
+	return c.Stream(http.StatusOK, "application/x-ndjson", func(w io.Writer) error {
+		frames := &execFrameWriter{encoder: json.NewEncoder(w)}
+		exitCode, err := h.exec(ssh.QuoteCommand(req.Command), ssh.Streams{
+			Stdin:  bytes.NewReader(req.Stdin),
+			Stdout: frames.output(client.ExecStreamStdout),
+			Stderr: frames.output(client.ExecStreamStderr),
+		})
+		if err != nil {
+			return frames.write(client.ExecFrame{Error: err.Error()})
+		}
+		return frames.write(client.ExecFrame{ExitCode: &exitCode})
+	})
+}
+
+func (h *Handler) execInVM(command string, streams ssh.Streams) (int, error) {
+	connectionDetails, err := h.Client.ConnectionDetails()
+	if err != nil {
+		return -1, err
+	}
+	runner, err := ssh.CreateRunner(connectionDetails.IP, connectionDetails.SSHPort, connectionDetails.SSHKeys...)
+	if err != nil {
+		return -1, err
 	}
+	defer runner.Close()
+	err = runner.Exec(command, streams, nil)
+	if status, ok := ssh.ExitStatus(err); ok {
+		return status, nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+// execFrameWriter serializes the frames written concurrently for the
+// standard output and error of the commands run by Exec
+type execFrameWriter struct {
+	lock    sync.Mutex
+	encoder *json.Encoder
+}
+
+func (fw *execFrameWriter) write(frame client.ExecFrame) error {
+	fw.lock.Lock()
+	defer fw.lock.Unlock()
+	return fw.encoder.Encode(frame)
+}
+
+func (fw *execFrameWriter) output(stream string) io.Writer {
+	return execOutputWriter(func(p []byte) (int, error) {
+		data := make([]byte, len(p))
+		copy(data, p)
+		if err := fw.write(client.ExecFrame{Stream: stream, Data: data}); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	})
+}
+
+type execOutputWriter func(p []byte) (int, error)
+
+func (w execOutputWriter) Write(p []byte) (int, error) {
+	return w(p)
+}
+
+// NewHandler returns the handler of the API routes. The state changes of the
+// long-running operations and the start progress are published on the
+// streams of 'eventServer' when it is not nil. The registry-cache routes
+// report the content of 'registryCache'.
+func NewHandler(config *crcConfig.Config, machine machine.Client, logger Logger, telemetry Telemetry, eventServer *events.EventServer, registryCache *registrycache.Cache) *Handler {
+	var operationsPublisher, startProgressPublisher events.EventPublisher
+	if eventServer != nil {
+		operationsPublisher = eventServer.Publisher(events.OPERATIONS)
+		startProgressPublisher = eventServer.Publisher(events.START_PROGRESS)
+	}
+	collector := diagnose.NewCollector(config, machine)
+	if logger != nil {
+		collector.DaemonLogs = logger.Messages
+	}
+	handler := &Handler{
+		Client:        machine,
+		Config:        config,
+		Logger:        logger,
+		Telemetry:     telemetry,
+		operations:    newOperationManager(operationsPublisher),
+		startProgress: startProgressPublisher,
+		diagnose:      collector.Collect,
+		checkHost:     preflight.CheckHost,
+		registryCache: registryCache,
+		dnsZones: func() ([]gvtypes.Zone, error) {
+			return daemonclient.New().NetworkClient.ListDNS()
+		},
+		addDNSZone: func(zone *gvtypes.Zone) error {
+			return daemonclient.New().NetworkClient.AddDNS(zone)
+		},
+	}
+	handler.exec = handler.execInVM
+	return handler
 }
 
 func (h *Handler) Status(c *context) error {
@@ -57,7 +235,7 @@ func (h *Handler) Status(c *context) error {
 		return err
 	}
 	if !exists {
-		return c.String(http.StatusInternalServerError, string(errors.VMNotExist))
+		return errors.VMNotExist
 	}
 
 	res, err := h.Client.Status()
@@ -75,6 +253,7 @@ func (h *Handler) Status(c *context) error {
 		PersistentVolumeUse:  res.PersistentVolumeUse,
 		PersistentVolumeSize: res.PersistentVolumeSize,
 		Preset:               res.Preset,
+		SharedDirs:           res.SharedDirs,
 	})
 }
 
@@ -97,45 +276,104 @@ func (h *Handler) PowerOff(c *context) error {
 }
 
 func (h *Handler) Start(c *context) error {
+	startConfig, err := h.startConfig(c)
+	if err != nil {
+		return err
+	}
+	res, err := h.start(gocontext.Background(), startConfig, nil)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// AsyncStart starts the instance in the background. The preflight checks
+// are run before returning so that their failure is reported immediately.
+func (h *Handler) AsyncStart(c *context) error {
+	startConfig, err := h.startConfig(c)
+	if err != nil {
+		return err
+	}
+	return accepted(c, h.operations.run(client.OperationStart, true, func(ctx gocontext.Context, report progressFunc) (interface{}, error) {
+		return h.start(ctx, startConfig, report)
+	}))
+}
+
+func (h *Handler) startConfig(c *context) (types.StartConfig, error) {
 	crcConfig.UpdateDefaults(h.Config)
 	var parsedArgs client.StartConfig
 	if len(c.requestBody) > 0 {
 		if err := c.Bind(&parsedArgs); err != nil {
-			return err
+			return types.StartConfig{}, err
 		}
 	}
 	if err := preflight.StartPreflightChecks(h.Config); err != nil {
-		return err
+		return types.StartConfig{}, err
 	}
+	return getStartConfig(h.Config, parsedArgs), nil
+}
 
-	startConfig := getStartConfig(h.Config, parsedArgs)
-	res, err := h.Client.Start(gocontext.Background(), startConfig)
+// start starts the instance, publishing its progress on the start-progress
+// event stream, and reporting it with 'report' when it is not nil
+func (h *Handler) start(ctx gocontext.Context, startConfig types.StartConfig, report progressFunc) (*client.StartResult, error) {
+	ctx = progress.NewContext(ctx, func(event progress.Event) {
+		h.publishStartProgress(event)
+		if report != nil {
+			report(client.OperationProgress{
+				Message: event.Message,
+				Current: int64(event.Percent),
+				Total:   100,
+			})
+		}
+	})
+	res, err := h.Client.Start(ctx, startConfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return c.JSON(http.StatusOK, client.StartResult{
+	return &client.StartResult{
 		Status:         string(res.Status),
 		ClusterConfig:  res.ClusterConfig,
 		KubeletStarted: res.KubeletStarted,
-	})
+	}, nil
+}
+
+func (h *Handler) publishStartProgress(event progress.Event) {
+	if h.startProgress == nil {
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		logging.Errorf("unexpected error during start progress object to JSON conversion: %v", err)
+		return
+	}
+	h.startProgress.Publish(&sse.Event{Event: []byte(events.START_PROGRESS), Data: data})
 }
 
 func getStartConfig(cfg crcConfig.Storage, args client.StartConfig) types.StartConfig {
 	return types.StartConfig{
-		BundlePath:               cfg.Get(crcConfig.Bundle).AsString(),
-		Memory:                   strongunits.MiB(cfg.Get(crcConfig.Memory).AsUInt()),
-		DiskSize:                 strongunits.GiB(cfg.Get(crcConfig.DiskSize).AsUInt()),
-		CPUs:                     cfg.Get(crcConfig.CPUs).AsUInt(),
-		NameServer:               cfg.Get(crcConfig.NameServer).AsString(),
-		PullSecret:               cluster.NewNonInteractivePullSecretLoader(cfg, args.PullSecretFile),
-		KubeAdminPassword:        cfg.Get(crcConfig.KubeAdminPassword).AsString(),
-		DeveloperPassword:        cfg.Get(crcConfig.DeveloperPassword).AsString(),
-		IngressHTTPPort:          cfg.Get(crcConfig.IngressHTTPPort).AsUInt(),
-		IngressHTTPSPort:         cfg.Get(crcConfig.IngressHTTPSPort).AsUInt(),
-		Preset:                   crcConfig.GetPreset(cfg),
-		EnableSharedDirs:         cfg.Get(crcConfig.EnableSharedDirs).AsBool(),
-		EmergencyLogin:           cfg.Get(crcConfig.EmergencyLogin).AsBool(),
-		EnableBundleQuayFallback: cfg.Get(crcConfig.EnableBundleQuayFallback).AsBool(),
+		BundlePath:        cfg.Get(crcConfig.Bundle).AsString(),
+		Memory:            strongunits.MiB(cfg.Get(crcConfig.Memory).AsUInt()),
+		DiskSize:          strongunits.GiB(cfg.Get(crcConfig.DiskSize).AsUInt()),
+		CPUs:              cfg.Get(crcConfig.CPUs).AsUInt(),
+		NameServer:        cfg.Get(crcConfig.NameServer).AsString(),
+		PullSecret:        cluster.NewNonInteractivePullSecretLoader(cfg, args.PullSecretFile),
+		KubeAdminPassword: cfg.Get(crcConfig.KubeAdminPassword).AsString(),
+		DeveloperPassword: cfg.Get(crcConfig.DeveloperPassword).AsString(),
+		IngressHTTPPort:   cfg.Get(crcConfig.IngressHTTPPort).AsUInt(),
+		IngressHTTPSPort:  cfg.Get(crcConfig.IngressHTTPSPort).AsUInt(),
+		Preset:            crcConfig.GetPreset(cfg),
+		EnableSharedDirs:  cfg.Get(crcConfig.EnableSharedDirs).AsBool(),
+		SharedDirs:        crcConfig.GetSharedDirs(cfg),
+		EmergencyLogin:    cfg.Get(crcConfig.EmergencyLogin).AsBool(),
+		BundleMirrors:     crcConfig.GetBundleMirrors(cfg),
+		ProvisionFile:     cfg.Get(crcConfig.ProvisionFile).AsString(),
+		AppsDomain:        cfg.Get(crcConfig.AppsDomain).AsString(),
+		CustomCertificates: types.CustomCertificates{
+			CACertFile: cfg.Get(crcConfig.CustomCACertFile).AsString(),
+			CAKeyFile:  cfg.Get(crcConfig.CustomCAKeyFile).AsString(),
+			CertFile:   cfg.Get(crcConfig.CustomCertFile).AsString(),
+			KeyFile:    cfg.Get(crcConfig.CustomKeyFile).AsString(),
+		},
 	}
 }
 
@@ -156,6 +394,134 @@ func (h *Handler) Delete(c *context) error {
 	return c.Code(http.StatusOK)
 }
 
+func (h *Handler) AsyncStop(c *context) error {
+	return accepted(c, h.operations.run(client.OperationStop, false, func(_ gocontext.Context, _ progressFunc) (interface{}, error) {
+		_, err := h.Client.Stop()
+		return nil, err
+	}))
+}
+
+func (h *Handler) AsyncDelete(c *context) error {
+	return accepted(c, h.operations.run(client.OperationDelete, false, func(_ gocontext.Context, _ progressFunc) (interface{}, error) {
+		return nil, h.Client.Delete()
+	}))
+}
+
+// AsyncResize changes the resources of the instance in the background. It
+// is started again when it was stopped to apply the change.
+func (h *Handler) AsyncResize(c *context) error {
+	var req client.ResizeRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	return accepted(c, h.operations.run(client.OperationResize, false, func(ctx gocontext.Context, report progressFunc) (interface{}, error) {
+		res, err := h.Client.Resize(req.ResizeConfig)
+		if err != nil {
+			return nil, err
+		}
+		result := &client.ResizeResult{ResizeResult: *res}
+		if !res.RestartRequired {
+			return result, nil
+		}
+		crcConfig.UpdateDefaults(h.Config)
+		if err := preflight.StartPreflightChecks(h.Config); err != nil {
+			return nil, err
+		}
+		startConfig := getStartConfig(h.Config, client.StartConfig{PullSecretFile: req.PullSecretFile})
+		if result.StartResult, err = h.start(ctx, startConfig, report); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}))
+}
+
+func (h *Handler) DownloadBundle(c *context) error {
+	crcConfig.UpdateDefaults(h.Config)
+	var req client.BundleDownloadRequest
+	if len(c.requestBody) > 0 {
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+	}
+	bundleURI := req.Bundle
+	if bundleURI == "" {
+		bundleURI = h.Config.Get(crcConfig.Bundle).AsString()
+	}
+	bundlePreset := crcConfig.GetPreset(h.Config)
+	bundleMirrors := crcConfig.GetBundleMirrors(h.Config)
+
+	return accepted(c, h.operations.run(client.OperationBundleDownload, true, func(ctx gocontext.Context, report progressFunc) (interface{}, error) {
+		if _, err := os.Stat(bundleURI); err == nil {
+			return &client.BundleDownloadResult{Path: bundleURI}, nil
+		}
+		ctx = download.WithProgress(ctx, func(current, total int64) {
+			report(client.OperationProgress{
+				Message: "Downloading bundle",
+				Current: current,
+				Total:   total,
+			})
+		})
+		path, err := bundle.Download(ctx, bundlePreset, bundleURI, bundleMirrors)
+		if err != nil {
+			return nil, err
+		}
+		return &client.BundleDownloadResult{Path: path}, nil
+	}))
+}
+
+func (h *Handler) ListOperations(c *context) error {
+	return c.JSON(http.StatusOK, client.OperationsResult{
+		Operations: h.operations.list(),
+	})
+}
+
+// maxOperationWait bounds the time a GET /v1/operations/{id} request is
+// delayed, the client is expected to send a new request after it
+const maxOperationWait = time.Minute
+
+// GetOperation returns the state of an operation. With the 'wait' query
+// parameter, for instance '?wait=30s', the response is delayed until the
+// operation is finished or the duration elapsed.
+func (h *Handler) GetOperation(c *context) error {
+	id := operationID(c)
+	op, err := h.operations.get(id)
+	if err != nil {
+		return err
+	}
+	if wait := c.url.Query().Get("wait"); wait != "" && !op.Finished() {
+		timeout, err := time.ParseDuration(wait)
+		if err != nil {
+			return &requestError{err: fmt.Errorf("invalid wait duration: %w", err)}
+		}
+		if timeout > maxOperationWait {
+			timeout = maxOperationWait
+		}
+		if op, err = h.operations.wait(id, timeout); err != nil {
+			return err
+		}
+	}
+	return c.JSON(http.StatusOK, op)
+}
+
+func (h *Handler) CancelOperation(c *context) error {
+	op, err := h.operations.cancel(operationID(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, op)
+}
+
+func operationID(c *context) string {
+	return strings.TrimPrefix(c.url.Path, v1Prefix+"/operations/")
+}
+
+// accepted is the response of the requests which started a long-running
+// operation. The Location header is relative to the request URL.
+func accepted(c *context, op client.OperationResult) error {
+	c.headers["Location"] = "operations/" + op.ID
+	return c.JSON(http.StatusAccepted, op)
+}
+
 func (h *Handler) GetWebconsoleInfo(c *context) error {
 	if err := machine.CheckIfMachineMissing(h.Client); err != nil {
 		// In case of machine doesn't exist then consoleResult error
@@ -173,6 +539,355 @@ func (h *Handler) GetWebconsoleInfo(c *context) error {
 	})
 }
 
+func (h *Handler) Instances(c *context) error {
+	names, err := machine.ListInstances()
+	if err != nil {
+		return err
+	}
+	current := crcConfig.GetInstanceName(h.Config)
+	instances := []client.InstanceResult{}
+	for _, name := range names {
+		vmState, created, err := machine.GetInstanceState(name)
+		if err != nil {
+			vmState = state.Error
+		}
+		if !created {
+			vmState = ""
+		}
+		instances = append(instances, client.InstanceResult{
+			Name:    name,
+			Current: name == current,
+			Created: created,
+			State:   vmState,
+		})
+	}
+	return c.JSON(http.StatusOK, client.InstancesResult{
+		Instances: instances,
+	})
+}
+
+func (h *Handler) ListSnapshots(c *context) error {
+	snapshots, err := h.Client.ListSnapshots()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, client.SnapshotsResult{
+		Snapshots: snapshots,
+	})
+}
+
+func (h *Handler) CreateSnapshot(c *context) error {
+	var req client.SnapshotRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	snapshot, err := h.Client.CreateSnapshot(req.Name)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, snapshot)
+}
+
+func (h *Handler) RestoreSnapshot(c *context) error {
+	var req client.SnapshotRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := h.Client.RestoreSnapshot(req.Name); err != nil {
+		return err
+	}
+	return c.Code(http.StatusOK)
+}
+
+func (h *Handler) DeleteSnapshot(c *context) error {
+	var req client.SnapshotRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := h.Client.DeleteSnapshot(req.Name); err != nil {
+		return err
+	}
+	return c.Code(http.StatusOK)
+}
+
+func (h *Handler) ListPorts(c *context) error {
+	ports, err := h.Client.ListPortForwards()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, client.PortsResult{
+		Ports: ports,
+	})
+}
+
+func (h *Handler) ExposePort(c *context) error {
+	var req types.PortForward
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	forward, err := h.Client.ExposePort(req)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, forward)
+}
+
+func (h *Handler) UnexposePort(c *context) error {
+	var req client.UnexposePortRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := h.Client.UnexposePort(req.Protocol, req.Local); err != nil {
+		return err
+	}
+	return c.Code(http.StatusOK)
+}
+
+func (h *Handler) ListVolumes(c *context) error {
+	volumes, err := h.Client.ListVolumes()
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, client.VolumesResult{
+		Volumes: volumes,
+	})
+}
+
+func (h *Handler) CreateVolume(c *context) error {
+	var req client.CreateVolumeRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	volume, err := h.Client.CreateVolume(req.Name, req.Size)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, volume)
+}
+
+func (h *Handler) DeleteVolume(c *context) error {
+	var req client.VolumeRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := h.Client.DeleteVolume(req.Name); err != nil {
+		return err
+	}
+	return c.Code(http.StatusOK)
+}
+
+func (h *Handler) AttachVolume(c *context) error {
+	var req client.VolumeRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	volume, err := h.Client.AttachVolume(req.Name)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, volume)
+}
+
+func (h *Handler) DetachVolume(c *context) error {
+	var req client.VolumeRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if err := h.Client.DetachVolume(req.Name); err != nil {
+		return err
+	}
+	return c.Code(http.StatusOK)
+}
+
+func (h *Handler) dnsRecords() ([]network.DNSRecord, error) {
+	return network.ParseDNSRecords(h.Config.Get(crcConfig.DNSRecords).AsString())
+}
+
+func (h *Handler) setDNSRecords(records []network.DNSRecord) error {
+	if len(records) == 0 {
+		_, err := h.Config.Unset(crcConfig.DNSRecords)
+		return err
+	}
+	_, err := h.Config.Set(crcConfig.DNSRecords, network.FormatDNSRecords(records))
+	return err
+}
+
+func (h *Handler) ListDNSRecords(c *context) error {
+	records, err := h.dnsRecords()
+	if err != nil {
+		return err
+	}
+	if records == nil {
+		records = []network.DNSRecord{}
+	}
+	return c.JSON(http.StatusOK, client.DNSRecordsResult{
+		Records: records,
+	})
+}
+
+// AddDNSRecord adds the record to the DNS server of the user-mode network
+// and to the dns-records setting, so that it is also resolved after the
+// daemon restarts
+func (h *Handler) AddDNSRecord(c *context) error {
+	var req network.DNSRecord
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	if crcConfig.GetNetworkMode(h.Config) != network.UserNetworkingMode {
+		return network.ErrDNSRecordsUnsupported
+	}
+	record, err := network.NormalizeDNSRecord(req)
+	if err != nil {
+		return err
+	}
+	records, err := h.dnsRecords()
+	if err != nil {
+		return err
+	}
+	zones, err := h.dnsZones()
+	if err != nil {
+		return err
+	}
+	zone := network.DNSRecordZone(zones, record)
+	if err := h.addDNSZone(&zone); err != nil {
+		return err
+	}
+	if err := h.setDNSRecords(network.SetDNSRecord(records, record)); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, record)
+}
+
+// RemoveDNSRecord removes the record from the dns-records setting. The DNS
+// server of the user-mode network cannot remove records, so when it still
+// resolves the hostname, it keeps resolving until the daemon restarts, which
+// the response reports.
+func (h *Handler) RemoveDNSRecord(c *context) error {
+	var req client.RemoveDNSRecordRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	record, err := network.NormalizeDNSRecord(network.DNSRecord{Hostname: req.Hostname})
+	if err != nil {
+		return err
+	}
+	records, err := h.dnsRecords()
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(records, func(existing network.DNSRecord) bool {
+		return existing.Hostname == record.Hostname
+	})
+	if index == -1 {
+		return fmt.Errorf("%w: %s", network.ErrDNSRecordNotFound, record.Hostname)
+	}
+	removed := records[index]
+	if err := h.setDNSRecords(slices.Delete(records, index, index+1)); err != nil {
+		return err
+	}
+	var result client.RemoveDNSRecordResult
+	if crcConfig.GetNetworkMode(h.Config) == network.UserNetworkingMode {
+		zones, err := h.dnsZones()
+		if err != nil {
+			return err
+		}
+		result.RestartRequired = network.ResolvesDNSRecord(zones, removed)
+	}
+	if result.RestartRequired {
+		logging.Warnf("%s keeps resolving in the user-mode network until the daemon restarts", record.Hostname)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) setSharedDirs(dirs []machineConfig.SharedDir) error {
+	if len(dirs) == 0 {
+		_, err := h.Config.Unset(crcConfig.SharedDirs)
+		return err
+	}
+	_, err := h.Config.Set(crcConfig.SharedDirs, machineConfig.FormatSharedDirs(dirs))
+	return err
+}
+
+func (h *Handler) ListSharedDirs(c *context) error {
+	dirs := crcConfig.GetSharedDirs(h.Config)
+	if dirs == nil {
+		dirs = []machineConfig.SharedDir{}
+	}
+	return c.JSON(http.StatusOK, client.SharedDirsResult{
+		SharedDirs: dirs,
+	})
+}
+
+// AddSharedDir adds the directory to the shared-dirs setting, and mounts it
+// right away when the instance is running and its driver supports it
+func (h *Handler) AddSharedDir(c *context) error {
+	var req machineConfig.SharedDir
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	dir, err := machineConfig.NormalizeSharedDir(req)
+	if err != nil {
+		return err
+	}
+	applied, err := h.applySharedDirChange(func() (bool, error) {
+		return h.Client.MountSharedDir(dir)
+	})
+	if err != nil {
+		return err
+	}
+	if err := h.setSharedDirs(machineConfig.SetSharedDir(crcConfig.GetSharedDirs(h.Config), dir)); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, client.SharedDirResult{
+		SharedDir: dir,
+		Applied:   applied,
+	})
+}
+
+// RemoveSharedDir removes the directory from the shared-dirs setting, and
+// unmounts it right away when the instance is running and its driver
+// supports it
+func (h *Handler) RemoveSharedDir(c *context) error {
+	var req client.RemoveSharedDirRequest
+	if err := c.Bind(&req); err != nil {
+		return err
+	}
+	dirs := crcConfig.GetSharedDirs(h.Config)
+	index := slices.IndexFunc(dirs, func(existing machineConfig.SharedDir) bool {
+		return existing.Target == req.Target
+	})
+	if index == -1 {
+		return fmt.Errorf("%w: %s", machineConfig.ErrSharedDirNotFound, req.Target)
+	}
+	dir := dirs[index]
+	applied, err := h.applySharedDirChange(func() (bool, error) {
+		return h.Client.UnmountSharedDir(dir.Target)
+	})
+	if err != nil {
+		return err
+	}
+	if err := h.setSharedDirs(slices.Delete(dirs, index, index+1)); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, client.SharedDirResult{
+		SharedDir: dir,
+		Applied:   applied,
+	})
+}
+
+// applySharedDirChange runs 'change' on the instance, when there is no
+// instance the change is applied when it is created
+func (h *Handler) applySharedDirChange(change func() (bool, error)) (bool, error) {
+	exists, err := h.Client.Exists()
+	if err != nil {
+		return false, err
+	}
+	if !exists {
+		return false, nil
+	}
+	return change()
+}
+
 func (h *Handler) SetConfig(c *context) error {
 	var req client.SetConfigRequest
 	if err := c.Bind(&req); err != nil {
@@ -195,7 +910,7 @@ func (h *Handler) SetConfig(c *context) error {
 		successProps = append(successProps, k)
 	}
 	if len(multiError.Errors) != 0 {
-		return multiError
+		return &requestError{err: multiError}
 	}
 	return c.JSON(http.StatusOK, client.SetOrUnsetConfigResult{
 		Properties: successProps,
@@ -223,7 +938,7 @@ func (h *Handler) UnsetConfig(c *context) error {
 		successProps = append(successProps, key)
 	}
 	if len(multiError.Errors) != 0 {
-		return multiError
+		return &requestError{err: multiError}
 	}
 	return c.JSON(http.StatusOK, client.SetOrUnsetConfigResult{
 		Properties: successProps,
@@ -282,3 +997,49 @@ func (h *Handler) UploadTelemetry(c *context) error {
 	}
 	return c.Code(http.StatusOK)
 }
+
+// RegistryCacheStats reports the content of the registry cache
+func (h *Handler) RegistryCacheStats(c *context) error {
+	if h.registryCache == nil {
+		return errRegistryCacheNotRunning
+	}
+	stats, err := h.registryCache.Stats()
+	if err != nil {
+		return err
+	}
+	registries := h.registryCache.Registries()
+	if registries == nil {
+		registries = []string{}
+	}
+	return c.JSON(http.StatusOK, client.RegistryCacheResult{
+		Enabled:    h.registryCache.Enabled(),
+		Registries: registries,
+		Stats:      *stats,
+	})
+}
+
+// PruneRegistryCache removes the content of the registry cache which was
+// not used for the requested duration, all of it by default
+func (h *Handler) PruneRegistryCache(c *context) error {
+	if h.registryCache == nil {
+		return errRegistryCacheNotRunning
+	}
+	var req client.PruneRegistryCacheRequest
+	if len(c.requestBody) > 0 {
+		if err := c.Bind(&req); err != nil {
+			return err
+		}
+	}
+	var unusedFor time.Duration
+	if req.UnusedFor != "" {
+		var err error
+		if unusedFor, err = time.ParseDuration(req.UnusedFor); err != nil || unusedFor < 0 {
+			return &requestError{err: fmt.Errorf("invalid unused duration: %s", req.UnusedFor)}
+		}
+	}
+	result, err := h.registryCache.Prune(unusedFor)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, result)
+}
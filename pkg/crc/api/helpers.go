@@ -1,16 +1,24 @@
 package api
 
 import (
+	gocontext "context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
 	"sync"
 
+	apiClient "github.com/crc-org/crc/v2/pkg/crc/api/client"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
 )
 
 type context struct {
+	// ctx is the context of the request, it is canceled when the client
+	// goes away
+	ctx         gocontext.Context
 	method      string
 	requestBody []byte
 	url         *url.URL
@@ -18,10 +26,16 @@ type context struct {
 	code         int
 	headers      map[string]string
 	responseBody []byte
+
+	writer   http.ResponseWriter
+	streamed bool
 }
 
 func (c *context) Bind(r interface{}) error {
-	return json.Unmarshal(c.requestBody, r)
+	if err := json.Unmarshal(c.requestBody, r); err != nil {
+		return &requestError{err: err}
+	}
+	return nil
 }
 
 func (c *context) JSON(code int, r interface{}) error {
@@ -39,6 +53,63 @@ func (c *context) String(code int, r string) error {
 	return nil
 }
 
+func (c *context) Blob(code int, contentType string, b []byte) error {
+	c.code = code
+	c.responseBody = b
+	c.headers["Content-Type"] = contentType
+	return nil
+}
+
+// Stream sends the output of 'stream' as it is written, instead of
+// buffering the response body. The headers are sent with the first write, so
+// an error returned by 'stream' before it wrote anything is reported like the
+// errors of the other handlers. The later errors cannot be reported to the
+// client anymore, they are only logged.
+func (c *context) Stream(code int, contentType string, stream func(w io.Writer) error) error {
+	c.headers["Content-Type"] = contentType
+	fw := &flushWriter{w: c.writer, writeHeader: func() {
+		for k, v := range c.headers {
+			c.writer.Header().Set(k, v)
+		}
+		c.writer.WriteHeader(code)
+	}}
+	err := stream(fw)
+	if !fw.wroteHeader {
+		if err != nil {
+			return err
+		}
+		fw.writeHeader()
+	}
+	c.streamed = true
+	if err != nil {
+		logging.Error("Failed to stream response: ", err)
+	}
+	return nil
+}
+
+// flushWriter flushes the response after each write so that the client
+// receives the streamed data without delay
+type flushWriter struct {
+	w           http.ResponseWriter
+	writeHeader func()
+	wroteHeader bool
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	if !fw.wroteHeader {
+		fw.wroteHeader = true
+		fw.writeHeader()
+	}
+	n, err := fw.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	if err := http.NewResponseController(fw.w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return n, err
+	}
+	return n, nil
+}
+
 func (c *context) Code(code int) error {
 	c.code = code
 	return nil
@@ -82,36 +153,76 @@ func (s *server) DELETE(pattern string, handler func(c *context) error) {
 	s.routes[pattern][http.MethodDelete] = handler
 }
 
+// match returns the pattern of the route serving 'path'. Like with
+// http.ServeMux, a pattern ending with a slash matches all the paths it
+// prefixes, and the longest pattern wins.
+func (s *server) match(path string) (string, bool) {
+	if _, ok := s.routes[path]; ok {
+		return path, true
+	}
+	match := ""
+	for pattern := range s.routes {
+		if strings.HasSuffix(pattern, "/") && strings.HasPrefix(path, pattern) && len(pattern) > len(match) {
+			match = pattern
+		}
+	}
+	return match, match != ""
+}
+
 func (s *server) Handler() http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the /v1 routes report errors with a JSON error object, the
+		// other ones are kept unchanged for compatibility
+		v1 := strings.HasPrefix(r.URL.Path, v1Prefix+"/")
+		writeError := writePlainError
+		if v1 {
+			writeError = writeJSONError
+		}
+
 		s.routesLock.RLock()
-		route, ok := s.routes[r.URL.Path]
+		pattern, ok := s.match(r.URL.Path)
+		route := s.routes[pattern]
 		if !ok {
 			s.routesLock.RUnlock()
-			http.Error(w, "Not Found", http.StatusNotFound)
+			writeError(w, http.StatusNotFound, apiClient.ErrorCodeNotFound, "Not Found")
 			return
 		}
 		handler, ok := route[r.Method]
 		if !ok {
 			s.routesLock.RUnlock()
-			http.Error(w, "Not Found", http.StatusNotFound)
+			if v1 {
+				w.Header().Set("Allow", allowedMethods(route))
+				writeError(w, http.StatusMethodNotAllowed, apiClient.ErrorCodeMethodNotAllowed, "Method Not Allowed")
+				return
+			}
+			writeError(w, http.StatusNotFound, apiClient.ErrorCodeNotFound, "Not Found")
 			return
 		}
 		s.routesLock.RUnlock()
 
 		requestBody, err := io.ReadAll(r.Body)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, http.StatusInternalServerError, apiClient.ErrorCodeInternal, err.Error())
 			return
 		}
 		c := &context{
+			ctx:         r.Context(),
 			method:      r.Method,
 			requestBody: requestBody,
 			headers:     make(map[string]string),
 			url:         r.URL,
+			writer:      w,
+		}
+		err = handler(c)
+		if c.streamed {
+			return
 		}
-		if err := handler(c); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if err != nil {
+			statusCode, code := http.StatusInternalServerError, apiClient.ErrorCodeInternal
+			if v1 {
+				statusCode, code = classifyError(err)
+			}
+			writeError(w, statusCode, code, err.Error())
 			return
 		}
 
@@ -125,3 +236,30 @@ func (s *server) Handler() http.Handler {
 		}
 	})
 }
+
+func allowedMethods(route map[string]func(*context) error) string {
+	methods := make([]string, 0, len(route))
+	for method := range route {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
+func writePlainError(w http.ResponseWriter, statusCode int, _, message string) {
+	http.Error(w, message, statusCode)
+}
+
+func writeJSONError(w http.ResponseWriter, statusCode int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(apiClient.ErrorResponse{
+		Error: apiClient.Error{
+			Code:    code,
+			Message: message,
+		},
+	}); err != nil {
+		logging.Error("Failed to send response: ", err)
+	}
+}
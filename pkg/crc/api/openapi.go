@@ -0,0 +1,19 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPIDocument describes the /v1 routes. It must be updated when they
+// change, TestOpenAPIDocument checks they are all documented.
+//
+//go:embed openapi.json
+var openAPIDocument []byte
+
+func getOpenAPIDocument(c *context) error {
+	c.code = http.StatusOK
+	c.responseBody = openAPIDocument
+	c.headers["Content-Type"] = "application/json"
+	return nil
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	var document struct {
+		OpenAPI string                            `json:"openapi"`
+		Paths   map[string]map[string]interface{} `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(openAPIDocument, &document))
+	assert.Equal(t, "3.0.3", document.OpenAPI)
+
+	server := newMockServer("")
+	documented := 0
+	for pattern, methodMap := range server.routes {
+		path, ok := strings.CutPrefix(pattern, v1Prefix)
+		if !ok {
+			continue
+		}
+		if strings.HasSuffix(path, "/") {
+			// the routes matching a path prefix take an ID
+			path += "{id}"
+		}
+		require.Contains(t, document.Paths, path, "%s is missing from the OpenAPI document", pattern)
+		for method := range methodMap {
+			assert.Contains(t, document.Paths[path], strings.ToLower(method), "%s %s is missing from the OpenAPI document", method, pattern)
+			documented++
+		}
+	}
+
+	operations := 0
+	for _, methods := range document.Paths {
+		operations += len(methods)
+	}
+	assert.Equal(t, documented, operations, "the OpenAPI document describes routes which are not registered")
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	gocontext "context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/api/client"
+	"github.com/crc-org/crc/v2/pkg/crc/api/events"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/r3labs/sse/v2"
+)
+
+// maxFinishedOperations is the number of finished operations which are kept
+// so that their result can still be queried
+const maxFinishedOperations = 50
+
+var errOperationNotFound = errors.New("operation not found")
+
+type errOperationNotCancellable struct {
+	reason string
+}
+
+func (err *errOperationNotCancellable) Error() string {
+	return err.reason
+}
+
+type progressFunc func(progress client.OperationProgress)
+
+type operationFunc func(ctx gocontext.Context, progress progressFunc) (interface{}, error)
+
+type operation struct {
+	client.OperationResult
+	cancel      gocontext.CancelFunc
+	cancellable bool
+	done        chan struct{}
+}
+
+// operationManager runs the long-running actions of the /v1 routes in the
+// background. Their state is kept in memory, and each change is published
+// on the operations event stream.
+type operationManager struct {
+	lock       sync.Mutex
+	operations map[string]*operation
+	// ids are the IDs of the operations in creation order
+	ids       []string
+	publisher events.EventPublisher
+
+	newID func() string
+	now   func() time.Time
+}
+
+func newOperationManager(publisher events.EventPublisher) *operationManager {
+	return &operationManager{
+		operations: map[string]*operation{},
+		publisher:  publisher,
+		newID:      randomOperationID,
+		now:        time.Now,
+	}
+}
+
+func randomOperationID() string {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		panic(fmt.Sprintf("cannot generate operation ID: %v", err))
+	}
+	return hex.EncodeToString(id)
+}
+
+// run starts 'fn' in the background and returns the state of the new
+// operation. When 'cancellable' is false, 'fn' does not stop when its
+// context is cancelled, and cancel() refuses to cancel the operation.
+func (m *operationManager) run(operationType string, cancellable bool, fn operationFunc) client.OperationResult {
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	op := &operation{
+		OperationResult: client.OperationResult{
+			ID:        m.newID(),
+			Type:      operationType,
+			State:     client.OperationRunning,
+			StartTime: m.now(),
+		},
+		cancel:      cancel,
+		cancellable: cancellable,
+		done:        make(chan struct{}),
+	}
+
+	m.lock.Lock()
+	m.operations[op.ID] = op
+	m.ids = append(m.ids, op.ID)
+	result := op.OperationResult
+	m.lock.Unlock()
+	m.publish(result)
+
+	go func() {
+		defer cancel()
+		res, err := fn(ctx, func(progress client.OperationProgress) {
+			m.update(op, func() {
+				op.Progress = &progress
+			})
+		})
+		m.finish(op, ctx, res, err)
+	}()
+
+	return result
+}
+
+func (m *operationManager) finish(op *operation, ctx gocontext.Context, res interface{}, err error) {
+	var result json.RawMessage
+	if err == nil && res != nil {
+		result, err = json.Marshal(res)
+	}
+	m.update(op, func() {
+		endTime := m.now()
+		op.EndTime = &endTime
+		switch {
+		case err == nil:
+			op.State = client.OperationSucceeded
+			op.Result = result
+		case ctx.Err() != nil:
+			op.State = client.OperationCancelled
+			op.Error = &client.Error{
+				Code:    client.ErrorCodeCancelled,
+				Message: err.Error(),
+			}
+		default:
+			_, code := classifyError(err)
+			op.State = client.OperationFailed
+			op.Error = &client.Error{
+				Code:    code,
+				Message: err.Error(),
+			}
+		}
+		m.prune()
+	})
+	close(op.done)
+	if err != nil {
+		logging.Debugf("Operation %s (%s) failed: %v", op.ID, op.Type, err)
+	}
+}
+
+// update applies 'change' to the operation and publishes its new state
+func (m *operationManager) update(op *operation, change func()) {
+	m.lock.Lock()
+	change()
+	result := op.OperationResult
+	m.lock.Unlock()
+	m.publish(result)
+}
+
+func (m *operationManager) publish(result client.OperationResult) {
+	if m.publisher == nil {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		logging.Errorf("unexpected error during operation object to JSON conversion: %v", err)
+		return
+	}
+	m.publisher.Publish(&sse.Event{Event: []byte("operation"), Data: data})
+}
+
+// prune forgets the oldest finished operations. It must be called with the
+// lock held.
+func (m *operationManager) prune() {
+	finished := 0
+	for _, id := range m.ids {
+		if m.operations[id].Finished() {
+			finished++
+		}
+	}
+	ids := m.ids[:0]
+	for _, id := range m.ids {
+		if finished > maxFinishedOperations && m.operations[id].Finished() {
+			delete(m.operations, id)
+			finished--
+			continue
+		}
+		ids = append(ids, id)
+	}
+	m.ids = ids
+}
+
+func (m *operationManager) list() []client.OperationResult {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	operations := []client.OperationResult{}
+	for _, id := range m.ids {
+		operations = append(operations, m.operations[id].OperationResult)
+	}
+	return operations
+}
+
+func (m *operationManager) get(id string) (client.OperationResult, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	op, ok := m.operations[id]
+	if !ok {
+		return client.OperationResult{}, errOperationNotFound
+	}
+	return op.OperationResult, nil
+}
+
+// wait returns the state of the operation once it is finished, or after
+// 'timeout' if it is still running
+func (m *operationManager) wait(id string, timeout time.Duration) (client.OperationResult, error) {
+	m.lock.Lock()
+	op, ok := m.operations[id]
+	m.lock.Unlock()
+	if !ok {
+		return client.OperationResult{}, errOperationNotFound
+	}
+	select {
+	case <-op.done:
+	case <-time.After(timeout):
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return op.OperationResult, nil
+}
+
+// cancel requests the cancellation of a running operation. The operation
+// is in the cancelled state once 'fn' returned.
+func (m *operationManager) cancel(id string) (client.OperationResult, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	op, ok := m.operations[id]
+	if !ok {
+		return client.OperationResult{}, errOperationNotFound
+	}
+	if op.Finished() {
+		return client.OperationResult{}, &errOperationNotCancellable{reason: fmt.Sprintf("operation %s is already %s", id, op.State)}
+	}
+	if !op.cancellable {
+		return client.OperationResult{}, &errOperationNotCancellable{reason: fmt.Sprintf("%s operations cannot be cancelled", op.Type)}
+	}
+	op.cancel()
+	return op.OperationResult, nil
+}
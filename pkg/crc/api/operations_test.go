@@ -0,0 +1,146 @@
+package api
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/api/client"
+	"github.com/r3labs/sse/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOperationManager() *operationManager {
+	manager := newOperationManager(nil)
+	id := 0
+	manager.newID = func() string {
+		id++
+		return strconv.Itoa(id)
+	}
+	manager.now = func() time.Time {
+		return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	}
+	return manager
+}
+
+type recordingPublisher struct {
+	events chan *sse.Event
+}
+
+func (p *recordingPublisher) Publish(event *sse.Event) {
+	p.events <- event
+}
+
+func (p *recordingPublisher) next(t *testing.T) client.OperationResult {
+	var op client.OperationResult
+	event := <-p.events
+	assert.Equal(t, "operation", string(event.Event))
+	require.NoError(t, json.Unmarshal(event.Data, &op))
+	return op
+}
+
+func TestOperationSucceeded(t *testing.T) {
+	publisher := &recordingPublisher{events: make(chan *sse.Event, 10)}
+	manager := newTestOperationManager()
+	manager.publisher = publisher
+
+	op := manager.run(client.OperationBundleDownload, true, func(_ gocontext.Context, progress progressFunc) (interface{}, error) {
+		progress(client.OperationProgress{Message: "Downloading bundle", Current: 1, Total: 2})
+		return &client.BundleDownloadResult{Path: "/bundle"}, nil
+	})
+	assert.Equal(t, "1", op.ID)
+	assert.Equal(t, client.OperationRunning, op.State)
+
+	op, err := manager.wait(op.ID, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, client.OperationSucceeded, op.State)
+	assert.JSONEq(t, `{"Path":"/bundle"}`, string(op.Result))
+	assert.Nil(t, op.Error)
+	assert.NotNil(t, op.EndTime)
+
+	assert.Equal(t, client.OperationRunning, publisher.next(t).State)
+	progress := publisher.next(t)
+	assert.Equal(t, &client.OperationProgress{Message: "Downloading bundle", Current: 1, Total: 2}, progress.Progress)
+	assert.Equal(t, client.OperationSucceeded, publisher.next(t).State)
+}
+
+func TestOperationFailed(t *testing.T) {
+	manager := newTestOperationManager()
+	op := manager.run(client.OperationStop, false, func(_ gocontext.Context, _ progressFunc) (interface{}, error) {
+		return nil, errors.New("stop failed")
+	})
+	op, err := manager.wait(op.ID, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, client.OperationFailed, op.State)
+	assert.Equal(t, &client.Error{Code: client.ErrorCodeInternal, Message: "stop failed"}, op.Error)
+	assert.Empty(t, op.Result)
+}
+
+func TestOperationCancelled(t *testing.T) {
+	manager := newTestOperationManager()
+	started := make(chan struct{})
+	op := manager.run(client.OperationStart, true, func(ctx gocontext.Context, _ progressFunc) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	<-started
+
+	cancelled, err := manager.cancel(op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, op.ID, cancelled.ID)
+
+	op, err = manager.wait(op.ID, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, client.OperationCancelled, op.State)
+	assert.Equal(t, client.ErrorCodeCancelled, op.Error.Code)
+
+	_, err = manager.cancel(op.ID)
+	assert.EqualError(t, err, "operation 1 is already cancelled")
+}
+
+func TestOperationNotCancellable(t *testing.T) {
+	manager := newTestOperationManager()
+	release := make(chan struct{})
+	op := manager.run(client.OperationDelete, false, func(_ gocontext.Context, _ progressFunc) (interface{}, error) {
+		<-release
+		return nil, nil
+	})
+	_, err := manager.cancel(op.ID)
+	assert.EqualError(t, err, "delete operations cannot be cancelled")
+
+	close(release)
+	op, err = manager.wait(op.ID, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, client.OperationSucceeded, op.State)
+}
+
+func TestOperationNotFound(t *testing.T) {
+	manager := newTestOperationManager()
+	_, err := manager.get("unknown")
+	assert.ErrorIs(t, err, errOperationNotFound)
+	_, err = manager.wait("unknown", time.Minute)
+	assert.ErrorIs(t, err, errOperationNotFound)
+	_, err = manager.cancel("unknown")
+	assert.ErrorIs(t, err, errOperationNotFound)
+}
+
+func TestOperationsPruned(t *testing.T) {
+	manager := newTestOperationManager()
+	for i := 0; i < maxFinishedOperations+5; i++ {
+		op := manager.run(client.OperationStop, false, func(_ gocontext.Context, _ progressFunc) (interface{}, error) {
+			return nil, nil
+		})
+		_, err := manager.wait(op.ID, time.Minute)
+		require.NoError(t, err)
+	}
+	operations := manager.list()
+	assert.Len(t, operations, maxFinishedOperations)
+	assert.Equal(t, "6", operations[0].ID)
+	_, err := manager.get("5")
+	assert.ErrorIs(t, err, errOperationNotFound)
+}
@@ -0,0 +1,83 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+const (
+	apiCertSecretName = "crc-api-cert"
+	configNamespace   = "openshift-config"
+)
+
+// EnsureAPIServerCertificateInTheCluster makes the API server present
+// 'certPEM' for 'hostname'. It is applied again when 'force' is true, after
+// the certificates of the cluster were renewed.
+//
+// The certificate replaces the named certificates of the API server, the
+// kube-apiserver pods are rolled out once it changes.
+func EnsureAPIServerCertificateInTheCluster(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner, hostname string, certPEM, keyPEM []byte, force bool) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "apiserver"); err != nil {
+		return err
+	}
+	currentNames, err := getAPIServerNamedCertificates(ocConfig)
+	if err != nil {
+		return err
+	}
+	currentCert, err := getTLSSecretCertificate(ocConfig, apiCertSecretName, configNamespace)
+	if err != nil {
+		return err
+	}
+	if !force && currentNames == apiCertSecretName && currentCert == strings.TrimSpace(string(certPEM)) {
+		return nil
+	}
+
+	logging.Infof("Updating the serving certificate of the API server for %s...", hostname)
+	if err := applyTLSSecret(ocConfig, sshRunner, apiCertSecretName, configNamespace, certPEM, keyPEM); err != nil {
+		return err
+	}
+	cmdArgs := []string{"patch", "apiserver", "cluster", "-p",
+		fmt.Sprintf(`'{"spec":{"servingCerts":{"namedCertificates":[{"names":["%s"],"servingCertificate":{"name":"%s"}}]}}}'`, hostname, apiCertSecretName),
+		"--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to update the API server certificate %v: %s", err, stderr)
+	}
+	return nil
+}
+
+// RemoveAPIServerCertificateFromCluster reverts the changes of
+// EnsureAPIServerCertificateInTheCluster, if any
+func RemoveAPIServerCertificateFromCluster(ctx context.Context, ocConfig oc.Config) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "apiserver"); err != nil {
+		return err
+	}
+	currentNames, err := getAPIServerNamedCertificates(ocConfig)
+	if err != nil || currentNames != apiCertSecretName {
+		return err
+	}
+
+	logging.Info("Restoring the serving certificate of the API server...")
+	cmdArgs := []string{"patch", "apiserver", "cluster", "-p",
+		`'{"spec":{"servingCerts":{"namedCertificates":null}}}'`, "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to restore the API server certificate %v: %s", err, stderr)
+	}
+	if _, stderr, err := ocConfig.RunOcCommand("delete", "secret", apiCertSecretName, "-n", configNamespace, "--ignore-not-found"); err != nil {
+		return fmt.Errorf("Failed to delete the API server certificate %v: %s", err, stderr)
+	}
+	return nil
+}
+
+func getAPIServerNamedCertificates(ocConfig oc.Config) (string, error) {
+	stdout, stderr, err := ocConfig.RunOcCommand("get", "apiserver", "cluster",
+		"-o", `jsonpath="{.spec.servingCerts.namedCertificates[*].servingCertificate.name}"`)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get the API server configuration %v: %s", err, stderr)
+	}
+	return strings.TrimSpace(stdout), nil
+}
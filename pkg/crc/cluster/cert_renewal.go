@@ -84,6 +84,14 @@ func ApproveCSRAndWaitForCertsRenewal(ctx context.Context, sshRunner *ssh.Runner
 	return nil
 }
 
+// CertsRenewed returns true when ApproveCSRAndWaitForCertsRenewal renewed
+// some of the expired certificates 'certsExpired' returned by
+// CheckCertsValidity. The custom certificates of the cluster are then
+// applied again.
+func CertsRenewed(certsExpired map[string]bool) bool {
+	return certsExpired[KubeletClientCert] || certsExpired[KubeletServerCert] || certsExpired[AggregatorClientCert]
+}
+
 func waitForCertRenewal(sshRunner *ssh.Runner, cert string) func() error {
 	return func() error {
 		expired, err := checkCertValidity(sshRunner, cert)
@@ -33,6 +33,7 @@ type Status struct {
 	progressing []string
 	degraded    []string
 	unavailable []string
+	total       int
 }
 
 const maxNames = 5
@@ -71,6 +72,12 @@ func joinWithLimit(names []string, maxNames int) string {
 	return ret
 }
 
+// Counts returns the number of operators which were checked, and how many of
+// them are available, progressing and degraded
+func (status *Status) Counts() (total, available, progressing, degraded int) {
+	return status.total, status.total - len(status.unavailable), len(status.progressing), len(status.degraded)
+}
+
 func (status *Status) IsReady() bool {
 	return status.Available && !status.Progressing && !status.Degraded && !status.Disabled
 }
@@ -99,6 +106,7 @@ func getStatus(ctx context.Context, lister operatorLister, selector []string) (*
 			continue
 		}
 		found = true
+		cs.total++
 		for _, con := range c.Status.Conditions {
 			switch con.Type {
 			case openshiftapi.OperatorAvailable:
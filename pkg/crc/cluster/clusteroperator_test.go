@@ -15,11 +15,13 @@ import (
 var (
 	available = &Status{
 		Available: true,
+		total:     3,
 	}
 	progressing = &Status{
 		Available:   true,
 		Progressing: true,
 		progressing: []string{"authentication"},
+		total:       3,
 	}
 )
 
@@ -35,6 +37,13 @@ func TestGetClusterOperatorsStatusProgressing(t *testing.T) {
 	assert.Equal(t, progressing, status)
 }
 
+func TestClusterOperatorsStatusCounts(t *testing.T) {
+	status, err := getStatus(context.Background(), lister("co-progressing.json"), []string{})
+	assert.NoError(t, err)
+	total, available, progressing, degraded := status.Counts()
+	assert.Equal(t, []int{3, 3, 1, 0}, []int{total, available, progressing, degraded})
+}
+
 type mockLister struct {
 	file string
 }
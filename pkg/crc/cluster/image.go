@@ -0,0 +1,131 @@
+package cluster
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+// InternalRegistryHost is the address of the internal registry of OpenShift,
+// the nodes resolve it through /etc/hosts
+const InternalRegistryHost = "image-registry.openshift-image-registry.svc:5000"
+
+const imagePushAuthFile = "/tmp/crc-image-push-auth.json"
+
+var (
+	namespaceRegexp   = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+	imageStreamRegexp = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*$`)
+	imageTagRegexp    = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+)
+
+// LoadImage loads the image archive read from 'archive', in the docker or
+// OCI format, in the container storage of the VM which is used by CRI-O. It
+// returns the names of the loaded images.
+func LoadImage(sshRunner *ssh.Runner, archive io.Reader) ([]string, error) {
+	var stdout, stderr bytes.Buffer
+	if err := sshRunner.Exec("sudo podman load", ssh.Streams{Stdin: archive, Stdout: &stdout, Stderr: &stderr}, nil); err != nil {
+		return nil, fmt.Errorf("Failed to load the image: %s: %w", strings.TrimSpace(stderr.String()), err)
+	}
+	images := parseLoadedImages(stdout.String())
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no image was loaded: %s", strings.TrimSpace(stdout.String()))
+	}
+	return images, nil
+}
+
+// parseLoadedImages returns the images of the 'podman load' output, like
+// Loaded image: quay.io/crcont/app:latest
+func parseLoadedImages(output string) []string {
+	var images []string
+	for _, line := range strings.Split(output, "\n") {
+		_, loaded, ok := strings.Cut(line, "Loaded image")
+		if !ok {
+			continue
+		}
+		_, loaded, ok = strings.Cut(loaded, ":")
+		if !ok {
+			continue
+		}
+		for _, image := range strings.Split(loaded, ",") {
+			if image = strings.TrimSpace(image); image != "" {
+				images = append(images, image)
+			}
+		}
+	}
+	return images
+}
+
+// ParseImageStreamTag parses the NAME[:TAG] value of an image stream tag,
+// the tag defaults to latest
+func ParseImageStreamTag(value string) (string, string, error) {
+	name, tag, hasTag := strings.Cut(value, ":")
+	if !hasTag {
+		tag = "latest"
+	}
+	if !imageStreamRegexp.MatchString(name) || len(name) > 253 {
+		return "", "", fmt.Errorf("'%s' is not a valid image stream name", name)
+	}
+	if !imageTagRegexp.MatchString(tag) {
+		return "", "", fmt.Errorf("'%s' is not a valid image tag", tag)
+	}
+	return name, tag, nil
+}
+
+// PushImageToImageStream pushes the image 'image' of the container storage
+// of the VM to the internal registry, as the tag 'tag' of the image stream
+// 'imageStream' of 'namespace'. The image stream is created if needed. The
+// push uses a token of the builder service account of the namespace, which
+// is allowed to update its image streams.
+func PushImageToImageStream(ocConfig oc.Config, sshRunner *ssh.Runner, image, namespace, imageStream, tag string) (string, error) {
+	if !namespaceRegexp.MatchString(namespace) {
+		return "", fmt.Errorf("'%s' is not a valid namespace", namespace)
+	}
+	if _, stderr, err := ocConfig.RunOcCommand("get", "namespace", namespace); err != nil {
+		return "", fmt.Errorf("Cannot find namespace %s: %s", namespace, strings.TrimSpace(stderr))
+	}
+	token, stderr, err := ocConfig.RunOcCommandPrivate("create", "token", "builder", "-n", namespace)
+	if err != nil {
+		return "", fmt.Errorf("Cannot get a token of the builder service account of %s: %s", namespace, strings.TrimSpace(stderr))
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", errors.New("the token of the builder service account is empty")
+	}
+
+	authFile, err := json.Marshal(map[string]interface{}{
+		"auths": map[string]interface{}{
+			InternalRegistryHost: map[string]string{
+				"auth": base64.StdEncoding.EncodeToString([]byte("builder:" + token)),
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := sshRunner.CopyDataPrivileged(authFile, imagePushAuthFile, 0600); err != nil {
+		return "", err
+	}
+	defer func() {
+		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Removing %s", imagePushAuthFile), "rm", "-f", imagePushAuthFile); err != nil {
+			logging.Debugf("Failed to remove %s: %v", imagePushAuthFile, err)
+		}
+	}()
+
+	destination := fmt.Sprintf("%s/%s/%s:%s", InternalRegistryHost, namespace, imageStream, tag)
+	// the certificate of the internal registry is signed by the service CA,
+	// which is not trusted by podman
+	if _, stderr, err := sshRunner.RunPrivileged(fmt.Sprintf("Pushing %s to the internal registry", image),
+		ssh.QuoteCommand([]string{"podman", "push", "--tls-verify=false", "--authfile", imagePushAuthFile, image, destination})); err != nil {
+		return "", fmt.Errorf("Failed to push %s to %s: %s", image, destination, strings.TrimSpace(stderr))
+	}
+	return fmt.Sprintf("%s/%s:%s", namespace, imageStream, tag), nil
+}
@@ -0,0 +1,30 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLoadedImages(t *testing.T) {
+	assert.Equal(t, []string{"localhost/myapp:dev"}, parseLoadedImages("Getting image source signatures\nCopying blob 5f70bf18a086 done\nWriting manifest to image destination\nLoaded image: localhost/myapp:dev\n"))
+	assert.Equal(t, []string{"quay.io/crcont/app:latest", "quay.io/crcont/app:v1"}, parseLoadedImages("Loaded image(s): quay.io/crcont/app:latest,quay.io/crcont/app:v1\n"))
+	assert.Empty(t, parseLoadedImages("Error: payload does not match any of the supported image formats\n"))
+}
+
+func TestParseImageStreamTag(t *testing.T) {
+	name, tag, err := ParseImageStreamTag("myapp")
+	assert.NoError(t, err)
+	assert.Equal(t, "myapp", name)
+	assert.Equal(t, "latest", tag)
+
+	name, tag, err = ParseImageStreamTag("my-app:v1.2")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-app", name)
+	assert.Equal(t, "v1.2", tag)
+
+	_, _, err = ParseImageStreamTag("MyApp")
+	assert.EqualError(t, err, "'MyApp' is not a valid image stream name")
+	_, _, err = ParseImageStreamTag("myapp:")
+	assert.EqualError(t, err, "'' is not a valid image tag")
+}
@@ -0,0 +1,356 @@
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+)
+
+const (
+	ingressCertSecretName = "crc-ingress-cert"
+	ingressNamespace      = "openshift-ingress"
+	appsDomainAnnotation  = "crc.dev/apps-domain"
+	ingressCAAnnotation   = "crc.dev/ingress-ca"
+	proxyTrustedCAName    = "user-ca-bundle"
+	trustedCABundleKey    = "ca-bundle.crt"
+)
+
+// EnsureAppsDomainInTheCluster configures the cluster ingress to also serve
+// the routes under 'appsDomain'. The ingress configuration is annotated with
+// it so that RemoveAppsDomainFromCluster only reverts the domain crc set.
+func EnsureAppsDomainInTheCluster(ctx context.Context, ocConfig oc.Config, appsDomain string) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "ingresses.config.openshift.io"); err != nil {
+		return err
+	}
+	currentDomain, _, err := getAppsDomain(ocConfig)
+	if err != nil || currentDomain == appsDomain {
+		return err
+	}
+
+	logging.Infof("Configuring the cluster ingress for the %s domain...", appsDomain)
+	patch := fmt.Sprintf(`'{"metadata":{"annotations":{"%s":"%s"}},"spec":{"appsDomain":"%s"}}'`, appsDomainAnnotation, appsDomain, appsDomain)
+	cmdArgs := []string{"patch", "ingresses.config.openshift.io", "cluster", "-p", patch, "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to update the apps domain %v: %s", err, stderr)
+	}
+	return nil
+}
+
+// RemoveAppsDomainFromCluster reverts the changes of
+// EnsureAppsDomainInTheCluster, if any. An apps domain changed by hand since
+// then is kept.
+func RemoveAppsDomainFromCluster(ctx context.Context, ocConfig oc.Config) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "ingresses.config.openshift.io"); err != nil {
+		return err
+	}
+	currentDomain, crcDomain, err := getAppsDomain(ocConfig)
+	if err != nil || crcDomain == "" {
+		return err
+	}
+
+	patch := fmt.Sprintf(`'{"metadata":{"annotations":{"%s":null}}}'`, appsDomainAnnotation)
+	if currentDomain == crcDomain {
+		logging.Infof("Removing the %s domain from the cluster ingress...", currentDomain)
+		patch = fmt.Sprintf(`'{"metadata":{"annotations":{"%s":null}},"spec":{"appsDomain":null}}'`, appsDomainAnnotation)
+	}
+	cmdArgs := []string{"patch", "ingresses.config.openshift.io", "cluster", "-p", patch, "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to remove the apps domain %v: %s", err, stderr)
+	}
+	return nil
+}
+
+// getAppsDomain returns the apps domain of the cluster ingress and the one
+// set by EnsureAppsDomainInTheCluster
+func getAppsDomain(ocConfig oc.Config) (string, string, error) {
+	stdout, stderr, err := ocConfig.RunOcCommand("get", "ingresses.config.openshift.io", "cluster", "-o",
+		fmt.Sprintf(`jsonpath="{.spec.appsDomain}/{.metadata.annotations.%s}"`, strings.ReplaceAll(appsDomainAnnotation, ".", `\.`)))
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to get the ingress configuration %v: %s", err, stderr)
+	}
+	currentDomain, crcDomain, _ := strings.Cut(strings.TrimSpace(stdout), "/")
+	return currentDomain, crcDomain, nil
+}
+
+// EnsureIngressCertificateInTheCluster makes 'certPEM' the default
+// certificate of the cluster ingress. It is applied again when 'force' is
+// true, after the certificates of the cluster were renewed.
+func EnsureIngressCertificateInTheCluster(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner, certPEM, keyPEM []byte, force bool) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "ingresscontroller"); err != nil {
+		return err
+	}
+	currentName, err := getIngressDefaultCertificate(ocConfig)
+	if err != nil {
+		return err
+	}
+	currentCert, err := getTLSSecretCertificate(ocConfig, ingressCertSecretName, ingressNamespace)
+	if err != nil {
+		return err
+	}
+	if !force && currentName == ingressCertSecretName && currentCert == strings.TrimSpace(string(certPEM)) {
+		return nil
+	}
+
+	logging.Info("Updating the default certificate of the cluster ingress...")
+	if err := applyTLSSecret(ocConfig, sshRunner, ingressCertSecretName, ingressNamespace, certPEM, keyPEM); err != nil {
+		return err
+	}
+	cmdArgs := []string{"patch", "ingresscontroller", "default", "-n", "openshift-ingress-operator", "-p",
+		fmt.Sprintf(`'{"spec":{"defaultCertificate":{"name":"%s"}}}'`, ingressCertSecretName), "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to update the ingress default certificate %v: %s", err, stderr)
+	}
+	return nil
+}
+
+// RemoveIngressCertificateFromCluster reverts the changes of
+// EnsureIngressCertificateInTheCluster, if any
+func RemoveIngressCertificateFromCluster(ctx context.Context, ocConfig oc.Config) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "ingresscontroller"); err != nil {
+		return err
+	}
+	currentName, err := getIngressDefaultCertificate(ocConfig)
+	if err != nil || currentName != ingressCertSecretName {
+		return err
+	}
+
+	logging.Info("Restoring the default certificate of the cluster ingress...")
+	cmdArgs := []string{"patch", "ingresscontroller", "default", "-n", "openshift-ingress-operator", "-p",
+		`'{"spec":{"defaultCertificate":null}}'`, "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to restore the ingress default certificate %v: %s", err, stderr)
+	}
+	if _, stderr, err := ocConfig.RunOcCommand("delete", "secret", ingressCertSecretName, "-n", ingressNamespace, "--ignore-not-found"); err != nil {
+		return fmt.Errorf("Failed to delete the ingress certificate %v: %s", err, stderr)
+	}
+	return nil
+}
+
+func getIngressDefaultCertificate(ocConfig oc.Config) (string, error) {
+	stdout, stderr, err := ocConfig.RunOcCommand("get", "ingresscontroller", "default", "-n", "openshift-ingress-operator",
+		"-o", `jsonpath="{.spec.defaultCertificate.name}"`)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get the ingress controller %v: %s", err, stderr)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// getTLSSecretCertificate returns the pem certificate of the TLS secret
+// 'name', or an empty string when it does not exist
+func getTLSSecretCertificate(ocConfig oc.Config, name, namespace string) (string, error) {
+	stdout, stderr, err := ocConfig.RunOcCommandPrivate("get", "secret", name, "-n", namespace,
+		"--ignore-not-found", "-o", `jsonpath="{.data.tls\.crt}"`)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get secret %s %v: %s", name, err, stderr)
+	}
+	cert, err := base64.StdEncoding.DecodeString(strings.TrimSpace(stdout))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(cert)), nil
+}
+
+// applyTLSSecret creates or updates the TLS secret 'name' with the pem
+// certificate and key
+func applyTLSSecret(ocConfig oc.Config, sshRunner *ssh.Runner, name, namespace string, certPEM, keyPEM []byte) error {
+	return applyResource(ocConfig, sshRunner, name, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "kubernetes.io/tls",
+		"metadata": map[string]string{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"data": map[string][]byte{
+			"tls.crt": certPEM,
+			"tls.key": keyPEM,
+		},
+	})
+}
+
+// applyResource creates or updates the cluster resource 'name' described by
+// 'resource', its manifest is copied to the VM since it may hold secrets
+func applyResource(ocConfig oc.Config, sshRunner *ssh.Runner, name string, resource map[string]interface{}) error {
+	manifest, err := json.Marshal(resource)
+	if err != nil {
+		return err
+	}
+	manifestFileName := fmt.Sprintf("/tmp/%s.json", name)
+	if err := sshRunner.CopyDataPrivileged(manifest, manifestFileName, 0600); err != nil {
+		return err
+	}
+	defer func() {
+		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Removing %s", manifestFileName), "rm", "-f", manifestFileName); err != nil {
+			logging.Debugf("Failed to remove %s: %v", manifestFileName, err)
+		}
+	}()
+	if _, stderr, err := ocConfig.RunOcCommandPrivate("apply", "-f", manifestFileName); err != nil {
+		return fmt.Errorf("Failed to update %s %s %v: %s", resource["kind"], name, err, stderr)
+	}
+	return nil
+}
+
+// EnsureIngressCAInTheProxyTrustedCA adds 'caPEM' to the trusted CA bundle of
+// the cluster proxy, so that the cluster components, like the console and the
+// OAuth server, trust the routes served with the ingress certificate it
+// signs. The bundle is annotated with it so that it can be removed again.
+func EnsureIngressCAInTheProxyTrustedCA(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner, caPEM []byte) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "proxy"); err != nil {
+		return err
+	}
+	name, err := getProxyTrustedCAName(ocConfig)
+	if err != nil {
+		return err
+	}
+	trustedCAName := name
+	if trustedCAName == "" {
+		trustedCAName = proxyTrustedCAName
+	}
+	bundle, crcCA, err := getTrustedCABundle(ocConfig, trustedCAName)
+	if err != nil {
+		return err
+	}
+	ca := strings.TrimSpace(string(caPEM))
+	if containsCertificate(bundle, ca) && (crcCA == "" || crcCA == ca) {
+		return nil
+	}
+
+	logging.Info("Adding the ingress CA to the trusted CA bundle of the cluster...")
+	bundle = withoutCertificate(bundle, crcCA)
+	if !containsCertificate(bundle, ca) {
+		bundle = strings.TrimSpace(bundle+"\n"+ca) + "\n"
+	}
+	if err := applyTrustedCABundle(ocConfig, sshRunner, trustedCAName, bundle, ca); err != nil {
+		return err
+	}
+	if name != "" {
+		return nil
+	}
+	cmdArgs := []string{"patch", "proxy", "cluster", "-p",
+		fmt.Sprintf(`'{"spec":{"trustedCA":{"name":"%s"}}}'`, trustedCAName), "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to update the trusted CA of the proxy %v: %s", err, stderr)
+	}
+	return nil
+}
+
+// RemoveIngressCAFromProxyTrustedCA reverts the changes of
+// EnsureIngressCAInTheProxyTrustedCA, if any. The trusted CA of the proxy is
+// unset when the bundle only held the ingress CA.
+func RemoveIngressCAFromProxyTrustedCA(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "proxy"); err != nil {
+		return err
+	}
+	name, err := getProxyTrustedCAName(ocConfig)
+	if err != nil || name == "" {
+		return err
+	}
+	bundle, crcCA, err := getTrustedCABundle(ocConfig, name)
+	if err != nil || crcCA == "" {
+		return err
+	}
+
+	logging.Info("Removing the ingress CA from the trusted CA bundle of the cluster...")
+	bundle = strings.TrimSpace(withoutCertificate(bundle, crcCA))
+	if bundle != "" {
+		return applyTrustedCABundle(ocConfig, sshRunner, name, bundle+"\n", "")
+	}
+	cmdArgs := []string{"patch", "proxy", "cluster", "-p", `'{"spec":{"trustedCA":{"name":""}}}'`, "--type", "merge"}
+	if _, stderr, err := ocConfig.RunOcCommand(cmdArgs...); err != nil {
+		return fmt.Errorf("Failed to update the trusted CA of the proxy %v: %s", err, stderr)
+	}
+	if _, stderr, err := ocConfig.RunOcCommand("delete", "configmap", name, "-n", "openshift-config", "--ignore-not-found"); err != nil {
+		return fmt.Errorf("Failed to delete the trusted CA bundle %v: %s", err, stderr)
+	}
+	return nil
+}
+
+func getProxyTrustedCAName(ocConfig oc.Config) (string, error) {
+	stdout, stderr, err := ocConfig.RunOcCommand("get", "proxy", "cluster", "-o", `jsonpath="{.spec.trustedCA.name}"`)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get the proxy configuration %v: %s", err, stderr)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// getTrustedCABundle returns the CA bundle of the config map 'name' and the
+// CA added to it by EnsureIngressCAInTheProxyTrustedCA
+func getTrustedCABundle(ocConfig oc.Config, name string) (string, string, error) {
+	stdout, stderr, err := ocConfig.RunOcCommand("get", "configmap", name, "-n", "openshift-config", "--ignore-not-found", "-o", "json")
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to get the trusted CA bundle %v: %s", err, stderr)
+	}
+	if strings.TrimSpace(stdout) == "" {
+		return "", "", nil
+	}
+	var configMap struct {
+		Metadata struct {
+			Annotations map[string]string `json:"annotations"`
+		} `json:"metadata"`
+		Data map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &configMap); err != nil {
+		return "", "", err
+	}
+	return configMap.Data[trustedCABundleKey], configMap.Metadata.Annotations[ingressCAAnnotation], nil
+}
+
+// applyTrustedCABundle creates or updates the config map 'name' holding the
+// trusted CA bundle of the proxy, 'ca' is the CA crc added to it
+func applyTrustedCABundle(ocConfig oc.Config, sshRunner *ssh.Runner, name, bundle, ca string) error {
+	metadata := map[string]interface{}{
+		"name":      name,
+		"namespace": "openshift-config",
+	}
+	if ca != "" {
+		metadata["annotations"] = map[string]string{ingressCAAnnotation: ca}
+	}
+	return applyResource(ocConfig, sshRunner, name, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   metadata,
+		"data": map[string]string{
+			trustedCABundleKey: bundle,
+		},
+	})
+}
+
+func containsCertificate(bundle, certPEM string) bool {
+	return withoutCertificate(bundle, certPEM) != bundle
+}
+
+// withoutCertificate returns the pem 'bundle' without the certificate
+// 'certPEM'
+func withoutCertificate(bundle, certPEM string) string {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return bundle
+	}
+	var kept []byte
+	found := false
+	rest := []byte(bundle)
+	for {
+		var current *pem.Block
+		current, rest = pem.Decode(rest)
+		if current == nil {
+			break
+		}
+		if current.Type == block.Type && bytes.Equal(current.Bytes, block.Bytes) {
+			found = true
+			continue
+		}
+		kept = append(kept, pem.EncodeToMemory(current)...)
+	}
+	if !found {
+		return bundle
+	}
+	return string(kept)
+}
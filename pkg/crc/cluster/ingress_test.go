@@ -0,0 +1,25 @@
+package cluster
+
+import (
+	"testing"
+
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithoutCertificate(t *testing.T) {
+	_, proxyCA, err := crctls.GetSelfSignedIngressCA()
+	require.NoError(t, err)
+	_, ingressCA, err := crctls.GetSelfSignedIngressCA()
+	require.NoError(t, err)
+	proxyPEM, ingressPEM := string(crctls.CertToPem(proxyCA)), string(crctls.CertToPem(ingressCA))
+
+	bundle := proxyPEM + "\n" + ingressPEM
+	assert.True(t, containsCertificate(bundle, ingressPEM))
+	assert.Equal(t, proxyPEM, withoutCertificate(bundle, ingressPEM))
+	assert.False(t, containsCertificate(proxyPEM, ingressPEM))
+	assert.Equal(t, proxyPEM, withoutCertificate(proxyPEM, ingressPEM))
+	assert.Equal(t, "", withoutCertificate(ingressPEM, ingressPEM))
+	assert.Equal(t, bundle, withoutCertificate(bundle, ""))
+}
@@ -17,7 +17,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// GenerateUserPassword creates and put updated password to ~/.crc/machine/crc/ directory
+// GenerateUserPassword creates and put updated password to the ~/.crc/machines/<instance>/ directory
 func GenerateUserPassword(passwordFile string, user string) error {
 	logging.Infof("Generating new password for the %s user", user)
 	password, err := GenerateRandomPasswordHash(23)
@@ -27,9 +27,9 @@ func GenerateUserPassword(passwordFile string, user string) error {
 	return os.WriteFile(passwordFile, []byte(password), 0600)
 }
 
-// UpdateUserPasswords updates the htpasswd secret
-func UpdateUserPasswords(ctx context.Context, ocConfig oc.Config, newKubeAdminPassword string, newDeveloperPassword string) error {
-	credentials, err := resolveUserPasswords(newKubeAdminPassword, newDeveloperPassword, constants.GetKubeAdminPasswordPath(), constants.GetDeveloperPasswordPath())
+// UpdateUserPasswords updates the htpasswd secret with the passwords of the instance 'instanceName'
+func UpdateUserPasswords(ctx context.Context, ocConfig oc.Config, instanceName string, newKubeAdminPassword string, newDeveloperPassword string) error {
+	credentials, err := resolveUserPasswords(newKubeAdminPassword, newDeveloperPassword, constants.GetKubeAdminPasswordPath(instanceName), constants.GetDeveloperPasswordPath(instanceName))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,61 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/crc-org/crc/v2/pkg/crc/systemd"
+)
+
+const registryCacheConfPath = "/etc/containers/registries.conf.d/999-crc-registry-cache.conf"
+
+// ConfigureRegistryCache makes CRI-O pull the images of 'registries' through
+// the registry cache of the daemon at 'location', and removes this
+// configuration when 'registries' is empty. A registries.conf drop-in file
+// is used rather than an ImageDigestMirrorSet since the machine config
+// operator reboots the node to apply it. When the cache cannot be reached,
+// the images are pulled from the registries.
+func ConfigureRegistryCache(sshRunner *ssh.Runner, location string, registries []string) error {
+	conf := registryCacheConf(location, registries)
+	current, _, err := sshRunner.RunPrivileged("Reading the registry cache configuration", "cat", registryCacheConfPath)
+	if err != nil {
+		current = ""
+	}
+	if current == conf {
+		return nil
+	}
+	if conf == "" {
+		logging.Info("Disabling the registry cache...")
+		if _, _, err := sshRunner.RunPrivileged("Removing the registry cache configuration", "rm", "-f", registryCacheConfPath); err != nil {
+			return err
+		}
+	} else {
+		logging.Info("Configuring the registry cache...")
+		if err := sshRunner.CopyDataPrivileged([]byte(conf), registryCacheConfPath, 0644); err != nil {
+			return err
+		}
+	}
+	return systemd.NewInstanceSystemdCommander(sshRunner).Reload("crio")
+}
+
+func registryCacheConf(location string, registries []string) string {
+	if len(registries) == 0 {
+		return ""
+	}
+	var conf strings.Builder
+	conf.WriteString("# Generated by crc, the images are pulled through the registry cache of the daemon\n")
+	for _, registry := range registries {
+		fmt.Fprintf(&conf, `
+[[registry]]
+prefix = "%[1]s"
+location = "%[1]s"
+
+[[registry.mirror]]
+location = "%[2]s/%[1]s"
+insecure = true
+`, registry, location)
+	}
+	return conf.String()
+}
@@ -0,0 +1,29 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryCacheConf(t *testing.T) {
+	assert.Equal(t, "", registryCacheConf("192.168.127.1:5000", nil))
+	assert.Equal(t, `# Generated by crc, the images are pulled through the registry cache of the daemon
+
+[[registry]]
+prefix = "quay.io"
+location = "quay.io"
+
+[[registry.mirror]]
+location = "192.168.127.1:5000/quay.io"
+insecure = true
+
+[[registry]]
+prefix = "docker.io"
+location = "docker.io"
+
+[[registry.mirror]]
+location = "192.168.127.1:5000/docker.io"
+insecure = true
+`, registryCacheConf("192.168.127.1:5000", []string{"quay.io", "docker.io"}))
+}
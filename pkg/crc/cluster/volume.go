@@ -0,0 +1,158 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	crcerrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"go.podman.io/common/pkg/strongunits"
+)
+
+const (
+	// VolumeStorageClass is the storage class of the PersistentVolumes of
+	// the volumes, the claims must request it to be bound to them
+	VolumeStorageClass = "crc-volume"
+	volumesMountDir    = "/var/mnt/crc-volumes"
+)
+
+// VolumePersistentVolumeName returns the name of the PersistentVolume
+// exposing the volume 'name'
+func VolumePersistentVolumeName(name string) string {
+	return fmt.Sprintf("crc-volume-%s", name)
+}
+
+// EnsureVolumeInTheCluster mounts the disk of the volume 'name' in the VM,
+// it is formatted the first time, and makes it available as a local
+// PersistentVolume. This must be done each time the VM starts since the
+// mount is not persistent.
+func EnsureVolumeInTheCluster(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner, name string, size strongunits.GiB) error {
+	// the serial of the disk is the name of the volume
+	device := fmt.Sprintf("/dev/disk/by-id/virtio-%s", name)
+	waitForDevice := func() error {
+		if _, _, err := sshRunner.Run("test", "-b", device); err != nil {
+			return &crcerrors.RetriableError{Err: fmt.Errorf("%s not found", device)}
+		}
+		return nil
+	}
+	if err := crcerrors.Retry(ctx, 30*time.Second, waitForDevice, time.Second); err != nil {
+		return err
+	}
+
+	mountPoint := path.Join(volumesMountDir, name)
+	if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Checking the filesystem of %s", device), "blkid", device); err != nil {
+		logging.Infof("Formatting volume %s...", name)
+		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Formatting %s", device), "mkfs.xfs", "-L", name, device); err != nil {
+			return err
+		}
+	}
+	if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Creating %s", mountPoint), "mkdir", "-p", mountPoint); err != nil {
+		return err
+	}
+	if _, _, err := sshRunner.Run("mountpoint", "-q", mountPoint); err != nil {
+		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Mounting %s", mountPoint), "mount", "-o", "context=\"system_u:object_r:container_file_t:s0\"", device, mountPoint); err != nil {
+			return err
+		}
+		// the pods run with random uids
+		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Making %s writable", mountPoint), "chmod", "0777", mountPoint); err != nil {
+			return err
+		}
+	}
+
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "pv"); err != nil {
+		return err
+	}
+	pvName := VolumePersistentVolumeName(name)
+	return applyResource(ocConfig, sshRunner, pvName, map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "PersistentVolume",
+		"metadata": map[string]interface{}{
+			"name": pvName,
+			"labels": map[string]string{
+				"crc.dev/volume": name,
+			},
+		},
+		"spec": map[string]interface{}{
+			"capacity": map[string]string{
+				"storage": fmt.Sprintf("%dGi", size),
+			},
+			"accessModes":                   []string{"ReadWriteOnce"},
+			"persistentVolumeReclaimPolicy": "Retain",
+			"storageClassName":              VolumeStorageClass,
+			"local": map[string]string{
+				"path": mountPoint,
+			},
+			"nodeAffinity": map[string]interface{}{
+				"required": map[string]interface{}{
+					"nodeSelectorTerms": []interface{}{
+						map[string]interface{}{
+							"matchExpressions": []interface{}{
+								map[string]string{
+									"key":      "kubernetes.io/hostname",
+									"operator": "Exists",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+// VolumesInTheCluster returns the names of the volumes which have a
+// PersistentVolume in the cluster
+func VolumesInTheCluster(ctx context.Context, ocConfig oc.Config) ([]string, error) {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "pv"); err != nil {
+		return nil, err
+	}
+	stdout, stderr, err := ocConfig.RunOcCommand("get", "pv", "-l", "crc.dev/volume", "-o", "name")
+	if err != nil {
+		return nil, fmt.Errorf("Failed to list the PersistentVolumes of the volumes %v: %s", err, stderr)
+	}
+	var names []string
+	for _, line := range strings.Fields(stdout) {
+		if name, ok := strings.CutPrefix(line, "persistentvolume/"+VolumePersistentVolumeName("")); ok {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// RemoveVolumeFromCluster deletes the PersistentVolume of the volume 'name'
+// and unmounts its disk, so that it can be detached from the VM. This fails
+// when the PersistentVolume is bound to a claim.
+func RemoveVolumeFromCluster(ctx context.Context, ocConfig oc.Config, sshRunner *ssh.Runner, name string) error {
+	if err := WaitForOpenshiftResource(ctx, ocConfig, "pv"); err != nil {
+		return err
+	}
+	pvName := VolumePersistentVolumeName(name)
+	stdout, stderr, err := ocConfig.RunOcCommand("get", "pv", pvName, "--ignore-not-found",
+		"-o", `jsonpath="{.status.phase} {.spec.claimRef.namespace}/{.spec.claimRef.name}"`)
+	if err != nil {
+		return fmt.Errorf("Failed to get PersistentVolume %s %v: %s", pvName, err, stderr)
+	}
+	if phase, claim, _ := strings.Cut(strings.TrimSpace(stdout), " "); phase == "Bound" {
+		return fmt.Errorf("PersistentVolume %s is bound to the claim %s, delete it first", pvName, claim)
+	}
+	if _, stderr, err := ocConfig.RunOcCommand("delete", "pv", pvName, "--ignore-not-found"); err != nil {
+		return fmt.Errorf("Failed to delete PersistentVolume %s %v: %s", pvName, err, stderr)
+	}
+
+	mountPoint := path.Join(volumesMountDir, name)
+	if _, _, err := sshRunner.Run("mountpoint", "-q", mountPoint); err == nil {
+		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Unmounting %s", mountPoint), "umount", mountPoint); err != nil {
+			return err
+		}
+	}
+	// the empty mount point would otherwise be writable on the disk of the VM
+	if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Removing %s", mountPoint), "rmdir", "--ignore-fail-on-non-empty", mountPoint); err != nil {
+		logging.Debugf("Cannot remove %s: %v", mountPoint, err)
+	}
+	return nil
+}
@@ -15,6 +15,11 @@ func RequiresRestartMsg(key string, _ interface{}) string {
 		"stop the CRC instance with 'crc stop' and restart it with 'crc start'.", key)
 }
 
+func RequiresDaemonRestartMsg(key string, _ interface{}) string {
+	return fmt.Sprintf("Changes to configuration property '%s' are only applied when the CRC daemon is started.\n"+
+		"If the CRC daemon is already running, then for this configuration change to take effect, restart it.", key)
+}
+
 func RequiresDeleteMsg(key string, _ interface{}) string {
 	return fmt.Sprintf("Changes to configuration property '%s' are only applied when the CRC instance is created.\n"+
 		"If you already have a running CRC instance, then for this configuration change to take effect, "+
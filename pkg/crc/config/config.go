@@ -87,38 +87,9 @@ func (c *Config) Set(key string, value interface{}) (string, error) {
 		return "", err
 	}
 
-	var castValue interface{}
-	var err error
-	switch setting.defaultValue.(type) {
-	case int:
-		castValue, err = cast.ToIntE(value)
-		if err != nil {
-			return "", fmt.Errorf(invalidProp, value, key, err)
-		}
-	case uint:
-		castValue, err = cast.ToUintE(value)
-		if err != nil {
-			return "", fmt.Errorf(invalidProp, value, key, err)
-		}
-	case string, Secret:
-		castValue = cast.ToString(value)
-	case bool:
-		castValue, err = cast.ToBoolE(value)
-		if err != nil {
-			return "", fmt.Errorf(invalidProp, value, key, err)
-		}
-	case Path:
-		path := cast.ToString(value)
-		path, err := filepath.Abs(path)
-		if err != nil {
-			return "", fmt.Errorf(invalidProp, value, key, err)
-		}
-		castValue = path
-
-	case preset.Preset:
-		castValue = cast.ToString(value)
-	default:
-		return "", fmt.Errorf(invalidType, value, key)
+	castValue, err := c.castValue(key, value)
+	if err != nil {
+		return "", err
 	}
 
 	// Make sure if user try to set same value which
@@ -147,6 +118,47 @@ func (c *Config) Set(key string, value interface{}) (string, error) {
 	return c.settingsByName[key].callbackFn(key, castValue), nil
 }
 
+// castValue converts 'value' to the type of the setting 'key'
+func (c *Config) castValue(key string, value interface{}) (interface{}, error) {
+	setting, ok := c.settingsByName[key]
+	if !ok {
+		return nil, fmt.Errorf(configPropDoesntExistMsg, key)
+	}
+
+	switch setting.defaultValue.(type) {
+	case int:
+		castValue, err := cast.ToIntE(value)
+		if err != nil {
+			return nil, fmt.Errorf(invalidProp, value, key, err)
+		}
+		return castValue, nil
+	case uint:
+		castValue, err := cast.ToUintE(value)
+		if err != nil {
+			return nil, fmt.Errorf(invalidProp, value, key, err)
+		}
+		return castValue, nil
+	case string, Secret:
+		return cast.ToString(value), nil
+	case bool:
+		castValue, err := cast.ToBoolE(value)
+		if err != nil {
+			return nil, fmt.Errorf(invalidProp, value, key, err)
+		}
+		return castValue, nil
+	case Path:
+		path, err := filepath.Abs(cast.ToString(value))
+		if err != nil {
+			return nil, fmt.Errorf(invalidProp, value, key, err)
+		}
+		return path, nil
+	case preset.Preset:
+		return cast.ToString(value), nil
+	default:
+		return nil, fmt.Errorf(invalidType, value, key)
+	}
+}
+
 // Unset unsets a given config key
 func (c *Config) Unset(key string) (string, error) {
 	setting, ok := c.settingsByName[key]
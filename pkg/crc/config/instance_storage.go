@@ -0,0 +1,141 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+	"github.com/spf13/cast"
+	"github.com/spf13/pflag"
+)
+
+// instanceSettings are the settings which can have a different value for
+// each CRC instance. For instances other than the default one, they are
+// stored in the instance configuration file instead of the global one.
+var instanceSettings = map[string]struct{}{
+	Preset:               {},
+	Bundle:               {},
+	CPUs:                 {},
+	Memory:               {},
+	DiskSize:             {},
+	PersistentVolumeSize: {},
+	IngressHTTPPort:      {},
+	IngressHTTPSPort:     {},
+}
+
+func IsInstanceSetting(key string) bool {
+	_, ok := instanceSettings[key]
+	return ok
+}
+
+// InstanceStorage is a RawStorage which stores the instance specific
+// settings in the configuration file of the current CRC instance, and all
+// the other settings in the global configuration file.
+type InstanceStorage struct {
+	global    *ViperStorage
+	envPrefix string
+	// instance is the name of the instance this storage is bound to. When
+	// it's empty, the instance named by the 'instance' setting is used.
+	instance string
+
+	storagesLock sync.Mutex
+	storages     map[string]*ViperStorage
+	flagSet      *pflag.FlagSet
+}
+
+func NewInstanceStorage(global *ViperStorage, envPrefix string) *InstanceStorage {
+	return &InstanceStorage{
+		global:    global,
+		envPrefix: envPrefix,
+		storages:  map[string]*ViperStorage{},
+	}
+}
+
+// ForInstance returns a storage bound to the instance 'name', regardless of
+// the value of the 'instance' setting
+func (s *InstanceStorage) ForInstance(name string) *InstanceStorage {
+	storage := NewInstanceStorage(s.global, s.envPrefix)
+	storage.instance = name
+	return storage
+}
+
+func (s *InstanceStorage) currentInstance() string {
+	if s.instance != "" {
+		return s.instance
+	}
+	name := cast.ToString(s.global.Get(Instance))
+	if name == "" {
+		return constants.DefaultName
+	}
+	return name
+}
+
+func (s *InstanceStorage) storageFor(key string) (*ViperStorage, error) {
+	if !IsInstanceSetting(key) {
+		return s.global, nil
+	}
+	name := s.currentInstance()
+	if name == constants.DefaultName {
+		return s.global, nil
+	}
+	configFile := constants.GetInstanceConfigPath(name)
+	if !crcos.FileExists(configFile) {
+		return nil, fmt.Errorf("instance '%s' does not exist", name)
+	}
+
+	s.storagesLock.Lock()
+	defer s.storagesLock.Unlock()
+	storage, ok := s.storages[name]
+	if !ok {
+		var err error
+		storage, err = NewViperStorage(configFile, s.envPrefix)
+		if err != nil {
+			return nil, err
+		}
+		if s.flagSet != nil {
+			if err := storage.BindFlagSet(s.flagSet); err != nil {
+				return nil, err
+			}
+		}
+		s.storages[name] = storage
+	}
+	return storage, nil
+}
+
+func (s *InstanceStorage) Get(key string) interface{} {
+	storage, err := s.storageFor(key)
+	if err != nil {
+		return nil
+	}
+	return storage.Get(key)
+}
+
+func (s *InstanceStorage) Set(key string, value interface{}) error {
+	storage, err := s.storageFor(key)
+	if err != nil {
+		return err
+	}
+	return storage.Set(key, value)
+}
+
+func (s *InstanceStorage) Unset(key string) error {
+	storage, err := s.storageFor(key)
+	if err != nil {
+		return err
+	}
+	return storage.Unset(key)
+}
+
+// BindFlagSet binds a flagset to their respective config properties
+func (s *InstanceStorage) BindFlagSet(flagSet *pflag.FlagSet) error {
+	s.storagesLock.Lock()
+	defer s.storagesLock.Unlock()
+	s.flagSet = flagSet
+	for _, storage := range s.storages {
+		if err := storage.BindFlagSet(flagSet); err != nil {
+			return err
+		}
+	}
+	return s.global.BindFlagSet(flagSet)
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileVersion is the version of the start profile format
+const ProfileVersion = "v1"
+
+// Profile is a declarative description of the configuration used by 'crc
+// start'. Its settings use the same names and validation as 'crc config set'.
+// It can be written in YAML or JSON:
+//
+//	version: v1
+//	settings:
+//	  preset: microshift
+//	  cpus: 4
+//	  memory: 8192
+type Profile struct {
+	Version  string                 `json:"version" yaml:"version"`
+	Settings map[string]interface{} `json:"settings" yaml:"settings"`
+}
+
+// ProfileChange is a setting whose value differs between the current
+// configuration and a profile
+type ProfileChange struct {
+	Key     string      `json:"key"`
+	Current interface{} `json:"current"`
+	Desired interface{} `json:"desired"`
+}
+
+// LoadProfile reads the YAML or JSON profile stored in 'path'
+func LoadProfile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProfile(data)
+}
+
+func ParseProfile(data []byte) (*Profile, error) {
+	var profile Profile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&profile); err != nil {
+		return nil, fmt.Errorf("Invalid profile: %w", err)
+	}
+	switch profile.Version {
+	case ProfileVersion:
+	case "":
+		return nil, fmt.Errorf("Invalid profile: missing version, expected '%s'", ProfileVersion)
+	default:
+		return nil, fmt.Errorf("Invalid profile: unsupported version '%s', expected '%s'", profile.Version, ProfileVersion)
+	}
+	return &profile, nil
+}
+
+// profileKeys returns the settings of the profile in the order they must be
+// applied. The preset comes first as the validation of other settings
+// depends on it.
+func (p *Profile) profileKeys() []string {
+	keys := make([]string, 0, len(p.Settings))
+	for key := range p.Settings {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i] == Preset || keys[j] == Preset {
+			return keys[i] == Preset
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func (c *Config) checkProfileSetting(key string, value interface{}) (interface{}, error) {
+	setting, ok := c.settingsByName[key]
+	if !ok {
+		return nil, fmt.Errorf(configPropDoesntExistMsg, key)
+	}
+	if key == Instance {
+		return nil, fmt.Errorf("Configuration property '%s' cannot be set from a profile", key)
+	}
+	if setting.isSecret {
+		return nil, fmt.Errorf("Configuration property '%s' is a secret and cannot be set from a profile", key)
+	}
+	return c.castValue(key, value)
+}
+
+// typedValue converts a value returned by castValue to the type Get uses for
+// the setting, so that the current and desired values can be compared as is
+func (c *Config) typedValue(key string, value interface{}) interface{} {
+	switch c.settingsByName[key].defaultValue.(type) {
+	case Path:
+		return Path(cast.ToString(value))
+	case preset.Preset:
+		if p, err := preset.ParsePresetE(cast.ToString(value)); err == nil {
+			return p
+		}
+	}
+	return value
+}
+
+// DiffProfile returns the settings which would be changed by applying 'profile'
+func (c *Config) DiffProfile(profile *Profile) ([]ProfileChange, error) {
+	changes := []ProfileChange{}
+	for _, key := range profile.profileKeys() {
+		desired, err := c.checkProfileSetting(key, profile.Settings[key])
+		if err != nil {
+			return nil, err
+		}
+		desired = c.typedValue(key, desired)
+		current := c.Get(key).Value
+		if reflect.DeepEqual(current, desired) {
+			continue
+		}
+		changes = append(changes, ProfileChange{
+			Key:     key,
+			Current: current,
+			Desired: desired,
+		})
+	}
+	return changes, nil
+}
+
+// ApplyProfile sets all the settings of 'profile' using the same validation
+// as 'crc config set'. If one of them is invalid, the settings which were
+// already changed are reverted, so that the configuration is left untouched.
+func (c *Config) ApplyProfile(profile *Profile) ([]ProfileChange, error) {
+	changes, err := c.DiffProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	previous := map[string]SettingValue{}
+	for i, change := range changes {
+		previous[change.Key] = c.Get(change.Key)
+		if _, err := c.Set(change.Key, change.Desired); err != nil {
+			c.revertProfileChanges(changes[:i], previous)
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func (c *Config) revertProfileChanges(changes []ProfileChange, previous map[string]SettingValue) {
+	// revert in reverse order so that the preset is restored last
+	for i := len(changes) - 1; i >= 0; i-- {
+		key := changes[i].Key
+		var err error
+		if previous[key].IsDefault {
+			_, err = c.Unset(key)
+		} else {
+			_, err = c.Set(key, previous[key].Value)
+		}
+		if err != nil {
+			logging.Warnf("Failed to revert configuration property '%s': %v", key, err)
+		}
+	}
+}
+
+// ExportProfile returns a profile with all the settings which don't have
+// their default value. Secrets and passwords are not exported.
+func (c *Config) ExportProfile() *Profile {
+	profile := &Profile{
+		Version:  ProfileVersion,
+		Settings: map[string]interface{}{},
+	}
+	for key, value := range c.AllConfigs() {
+		if value.Invalid || value.IsDefault || value.IsSecret {
+			continue
+		}
+		if key == Instance || key == KubeAdminPassword || key == DeveloperPassword {
+			continue
+		}
+		profile.Settings[key] = value.Value
+	}
+	return profile
+}
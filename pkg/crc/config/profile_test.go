@@ -0,0 +1,120 @@
+package config
+
+import (
+	"testing"
+
+	crcpreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseProfile(t *testing.T) {
+	profile, err := ParseProfile([]byte(`
+version: v1
+settings:
+  preset: microshift
+  cpus: 4
+`))
+	require.NoError(t, err)
+	assert.Equal(t, &Profile{
+		Version: ProfileVersion,
+		Settings: map[string]interface{}{
+			Preset: "microshift",
+			CPUs:   4,
+		},
+	}, profile)
+
+	profile, err = ParseProfile([]byte(`{"version": "v1", "settings": {"memory": 12000}}`))
+	require.NoError(t, err)
+	assert.Equal(t, 12000, profile.Settings[Memory])
+
+	_, err = ParseProfile([]byte(`settings: {}`))
+	assert.EqualError(t, err, "Invalid profile: missing version, expected 'v1'")
+	_, err = ParseProfile([]byte(`version: v2`))
+	assert.EqualError(t, err, "Invalid profile: unsupported version 'v2', expected 'v1'")
+	_, err = ParseProfile([]byte("version: v1\ncpu: 4\n"))
+	assert.Error(t, err)
+}
+
+func TestApplyProfile(t *testing.T) {
+	cfg, err := newInMemoryConfig()
+	require.NoError(t, err)
+
+	// the cpus value is only valid once the preset is applied
+	profile := &Profile{
+		Version: ProfileVersion,
+		Settings: map[string]interface{}{
+			CPUs:   3,
+			Preset: "microshift",
+		},
+	}
+	changes, err := cfg.DiffProfile(profile)
+	require.NoError(t, err)
+	assert.Equal(t, []string{Preset, CPUs}, []string{changes[0].Key, changes[1].Key})
+
+	_, err = cfg.ApplyProfile(profile)
+	require.NoError(t, err)
+	assert.Equal(t, crcpreset.Microshift, GetPreset(cfg))
+	assert.Equal(t, uint(3), cfg.Get(CPUs).AsUInt())
+
+	changes, err = cfg.DiffProfile(profile)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	assert.Equal(t, &Profile{
+		Version: ProfileVersion,
+		Settings: map[string]interface{}{
+			Preset: "microshift",
+			CPUs:   uint(3),
+		},
+	}, cfg.ExportProfile())
+}
+
+func TestApplyProfileIsAtomic(t *testing.T) {
+	cfg, err := newInMemoryConfig()
+	require.NoError(t, err)
+
+	_, err = cfg.ApplyProfile(&Profile{
+		Version: ProfileVersion,
+		Settings: map[string]interface{}{
+			Preset: "microshift",
+			CPUs:   1,
+		},
+	})
+	assert.Error(t, err)
+	assert.True(t, cfg.Get(Preset).IsDefault)
+	assert.True(t, cfg.Get(CPUs).IsDefault)
+
+	_, err = cfg.ApplyProfile(&Profile{
+		Version: ProfileVersion,
+		Settings: map[string]interface{}{
+			Instance: "crc",
+		},
+	})
+	assert.EqualError(t, err, "Configuration property 'instance' cannot be set from a profile")
+}
+
+func TestDiffProfileComparesTypedValues(t *testing.T) {
+	cfg, err := newInMemoryConfig()
+	require.NoError(t, err)
+
+	changes, err := cfg.DiffProfile(&Profile{
+		Version: ProfileVersion,
+		Settings: map[string]interface{}{
+			Preset:             "openshift",
+			CPUs:               "4",
+			DisableUpdateCheck: "false",
+		},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	changes, err = cfg.DiffProfile(&Profile{
+		Version: ProfileVersion,
+		Settings: map[string]interface{}{
+			DisableUpdateCheck: "true",
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []ProfileChange{{Key: DisableUpdateCheck, Current: false, Desired: true}}, changes)
+}
@@ -2,12 +2,17 @@ package config
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/registrycache"
 	"github.com/crc-org/crc/v2/pkg/crc/version"
+	"github.com/spf13/cast"
 )
 
 const (
@@ -33,11 +38,25 @@ const (
 	Preset                   = "preset"
 	EnableSharedDirs         = "enable-shared-dirs"
 	SharedDirPassword        = "shared-dir-password" // #nosec G101
+	SharedDirs               = "shared-dirs"
 	IngressHTTPPort          = "ingress-http-port"
 	IngressHTTPSPort         = "ingress-https-port"
 	EmergencyLogin           = "enable-emergency-login"
 	PersistentVolumeSize     = "persistent-volume-size"
 	EnableBundleQuayFallback = "enable-bundle-quay-fallback"
+	BundleMirrors            = "bundle-mirrors"
+	Instance                 = "instance"
+	ProvisionFile            = "provision-file"
+	DNSRecords               = "dns-records"
+	AppsDomain               = "apps-domain"
+	TrustClusterCA           = "trust-cluster-ca"
+	CustomCACertFile         = "custom-ca-cert-file"
+	CustomCAKeyFile          = "custom-ca-key-file"
+	CustomCertFile           = "custom-cert-file"
+	CustomKeyFile            = "custom-key-file"
+	RegistryCache            = "registry-cache"
+	RegistryCacheSize        = "registry-cache-size"
+	RegistryCacheRegistries  = "registry-cache-registries"
 )
 
 func RegisterSettings(cfg *Config) {
@@ -50,6 +69,22 @@ func RegisterSettings(cfg *Config) {
 		return ValidateBool(value)
 	}
 
+	validDNSRecords := func(value interface{}) (bool, string) {
+		if cast.ToString(value) != "" && GetNetworkMode(cfg) != network.UserNetworkingMode {
+			return false, fmt.Sprintf("%s can only be used with %s set to '%s'",
+				DNSRecords, NetworkMode, network.UserNetworkingMode)
+		}
+		return validateDNSRecords(value)
+	}
+
+	validCustomCAKeyFile := func(value interface{}) (bool, string) {
+		return validatePrivateKeyFile(value, cfg.Get(CustomCACertFile).AsString())
+	}
+
+	validCustomKeyFile := func(value interface{}) (bool, string) {
+		return validatePrivateKeyFile(value, cfg.Get(CustomCertFile).AsString())
+	}
+
 	validCPUs := func(value interface{}) (bool, string) {
 		return validateCPUs(value, GetPreset(cfg))
 	}
@@ -62,6 +97,8 @@ func RegisterSettings(cfg *Config) {
 		return validateBundlePath(value, GetPreset(cfg))
 	}
 
+	cfg.AddSetting(Instance, constants.DefaultName, validateInstance, SuccessfullyApplied,
+		fmt.Sprintf("Name of the CRC instance used by the crc commands (default: '%s')", constants.DefaultName))
 	// Preset setting should be on top because CPUs/Memory config depend on it.
 	cfg.AddSetting(Preset, version.GetDefaultPreset().String(), validatePreset, RequiresDeleteAndSetupMsg,
 		fmt.Sprintf("Virtual machine preset (valid values are: %s)", preset.AllPresets()))
@@ -87,6 +124,8 @@ func RegisterSettings(cfg *Config) {
 		fmt.Sprintf("Total size in GiB of the persistent volume used by the CSI driver for %s preset (must be greater than or equal to '%d')", preset.Microshift, constants.DefaultPersistentVolumeSize))
 	cfg.AddSetting(EnableSharedDirs, true, ValidateBool, SuccessfullyApplied,
 		"Mounts the host's home directory into the CRC VM (true/false, default: true)")
+	cfg.AddSetting(SharedDirs, "", validateSharedDirs, RequiresRestartMsg,
+		"Comma-separated list of additional host directories mounted into the CRC VM, as source[=target][;ro][;owner=UID:GID], the target defaults to the source, Linux and macOS only (string, like '/srv/data=/mnt/data;ro')")
 
 	if !version.IsInstaller() {
 		cfg.AddSetting(NetworkMode, string(defaultNetworkMode()), network.ValidateMode, network.SuccessfullyAppliedMode,
@@ -125,7 +164,37 @@ func RegisterSettings(cfg *Config) {
 		fmt.Sprintf("HTTPS port to use for OpenShift ingress/routes on the host (1024-65535, default: %d)", constants.OpenShiftIngressHTTPSPort))
 
 	cfg.AddSetting(EnableBundleQuayFallback, false, ValidateBool, SuccessfullyApplied,
-		"If bundle download from the default location fails, fallback to quay.io (true/false, default: false)")
+		fmt.Sprintf("If bundle download from the bundle mirrors fails, fallback to %s (true/false, default: false)", constants.QuayBundleMirror))
+	cfg.AddSetting(BundleMirrors, constants.DefaultBundleMirror, validateBundleMirrors, SuccessfullyApplied,
+		fmt.Sprintf("Comma-separated list of the http, https or docker base URIs the default bundle is downloaded from, tried in order (default: '%s')", constants.DefaultBundleMirror))
+
+	cfg.AddSetting(ProvisionFile, Path(""), validatePath, SuccessfullyApplied,
+		"Path to a file describing the manifests, operators and scripts to apply after the cluster is started")
+
+	cfg.AddSetting(DNSRecords, "", validDNSRecords, RequiresDaemonRestartMsg,
+		fmt.Sprintf("Comma-separated list of hostname=IP records of the user-mode network DNS server, '*.domain' resolves all the subdomains of domain, IP defaults to %s (string, like 'db.mycompany.test=192.168.127.3,*.apps.mycompany.test')", network.VirtualMachineIP))
+
+	cfg.AddSetting(AppsDomain, "", validateAppsDomain, RequiresRestartMsg,
+		"Custom domain under which the routes are served, in addition to the apps domain of the bundle, OpenShift and OKD only (string, like 'apps.mycompany.test')")
+
+	cfg.AddSetting(TrustClusterCA, false, ValidateBool, RequiresRestartMsg,
+		"Install the API server and ingress CAs of the cluster in the host trust store when starting it, Linux only (true/false, default: false)")
+
+	cfg.AddSetting(CustomCACertFile, Path(""), validateCACertificateFile, RequiresRestartMsg,
+		"Path to a CA certificate signing the API server and ingress certificates of the cluster with custom-ca-key-file, or issuing custom-cert-file")
+	cfg.AddSetting(CustomCAKeyFile, Path(""), validCustomCAKeyFile, RequiresRestartMsg,
+		"Path to the private key of custom-ca-cert-file")
+	cfg.AddSetting(CustomCertFile, Path(""), validateServingCertificateFile, RequiresRestartMsg,
+		"Path to a certificate presented by the API server and the ingress of the cluster, valid for their hostnames, with custom-key-file and the CA which issued it in custom-ca-cert-file")
+	cfg.AddSetting(CustomKeyFile, Path(""), validCustomKeyFile, RequiresRestartMsg,
+		"Path to the private key of custom-cert-file")
+
+	cfg.AddSetting(RegistryCache, false, ValidateBool, RequiresRestartMsg,
+		fmt.Sprintf("Pull the images of the cached registries through a registry cache run by the daemon, the images are kept on the host when the instance is deleted, %s network mode only (true/false, default: false)", network.UserNetworkingMode))
+	cfg.AddSetting(RegistryCacheSize, constants.DefaultRegistryCacheSize, validateRegistryCacheSize, SuccessfullyApplied,
+		fmt.Sprintf("Size limit in GiB of the registry cache, the least recently used images are removed when it is exceeded (default: %d)", constants.DefaultRegistryCacheSize))
+	cfg.AddSetting(RegistryCacheRegistries, constants.DefaultRegistryCacheRegistries, validateRegistryCacheRegistries, RequiresRestartMsg,
+		fmt.Sprintf("Comma-separated list of the registries cached by the registry cache (default: '%s')", constants.DefaultRegistryCacheRegistries))
 
 	if err := cfg.RegisterNotifier(Preset, presetChanged); err != nil {
 		logging.Debugf("Failed to register notifier for Preset: %v", err)
@@ -156,6 +225,58 @@ func defaultNetworkMode() network.Mode {
 	return network.UserNetworkingMode
 }
 
+// GetBundleMirrors returns the mirrors the default bundle is downloaded
+// from, in the order they must be tried
+func GetBundleMirrors(config Storage) []string {
+	var mirrors []string
+	for _, mirror := range strings.Split(config.Get(BundleMirrors).AsString(), ",") {
+		if mirror = strings.TrimSpace(mirror); mirror != "" {
+			mirrors = append(mirrors, mirror)
+		}
+	}
+	if config.Get(EnableBundleQuayFallback).AsBool() && !slices.Contains(mirrors, constants.QuayBundleMirror) {
+		mirrors = append(mirrors, constants.QuayBundleMirror)
+	}
+	return mirrors
+}
+
+// GetSharedDirs returns the additional directories mounted in the VM, the
+// ones which became invalid since they were configured, for example because
+// the source directory was removed, are skipped
+func GetSharedDirs(config Storage) []machineConfig.SharedDir {
+	var dirs []machineConfig.SharedDir
+	for _, entry := range strings.Split(config.Get(SharedDirs).AsString(), ",") {
+		parsed, err := machineConfig.ParseSharedDirs(entry)
+		if err != nil {
+			logging.Warnf("Skipping shared directory '%s': %v", entry, err)
+			continue
+		}
+		for _, dir := range parsed {
+			dirs = machineConfig.SetSharedDir(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// GetRegistryCacheRegistries returns the registries whose images are pulled
+// through the registry cache, none when it is disabled
+func GetRegistryCacheRegistries(config Storage) []string {
+	if !config.Get(RegistryCache).AsBool() {
+		return nil
+	}
+	registries, err := registrycache.ParseRegistries(config.Get(RegistryCacheRegistries).AsString())
+	if err != nil {
+		logging.Warnf("Ignoring the '%s' setting: %v", RegistryCacheRegistries, err)
+		return nil
+	}
+	return registries
+}
+
+// GetInstanceName returns the name of the CRC instance the commands act on
+func GetInstanceName(config Storage) string {
+	return config.Get(Instance).AsString()
+}
+
 func GetNetworkMode(config Storage) network.Mode {
 	if version.IsInstaller() {
 		return network.UserNetworkingMode
@@ -2,13 +2,16 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"go.podman.io/common/pkg/strongunits"
 
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	crcpreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
 	"github.com/crc-org/crc/v2/pkg/crc/version"
 
 	"github.com/spf13/cast"
@@ -186,6 +189,70 @@ func TestPath(t *testing.T) {
 	}, cfg.Get(ProxyCAFile))
 }
 
+func TestGetBundleMirrors(t *testing.T) {
+	cfg, err := newInMemoryConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{constants.DefaultBundleMirror}, GetBundleMirrors(cfg))
+
+	_, err = cfg.Set(EnableBundleQuayFallback, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{constants.DefaultBundleMirror, constants.QuayBundleMirror}, GetBundleMirrors(cfg))
+
+	_, err = cfg.Set(BundleMirrors, "https://mirror.example.com/bundles, docker://quay.io/crcont")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://mirror.example.com/bundles", "docker://quay.io/crcont"}, GetBundleMirrors(cfg))
+}
+
+func TestDNSRecordsRequireUserNetworking(t *testing.T) {
+	cfg, err := newInMemoryConfig()
+	require.NoError(t, err)
+
+	_, err = cfg.Set(NetworkMode, string(network.UserNetworkingMode))
+	require.NoError(t, err)
+	_, err = cfg.Set(DNSRecords, "db.mycompany.test")
+	assert.NoError(t, err)
+
+	_, err = cfg.Set(NetworkMode, string(network.SystemNetworkingMode))
+	require.NoError(t, err)
+	_, err = cfg.Set(DNSRecords, "db.mycompany.test")
+	assert.EqualError(t, err, "Value 'db.mycompany.test' for configuration property 'dns-records' is invalid, reason: dns-records can only be used with network-mode set to 'user'")
+}
+
+func TestCustomCertificateSettings(t *testing.T) {
+	dir := t.TempDir()
+	caKey, caCert, err := crctls.GetSelfSignedIngressCA()
+	require.NoError(t, err)
+	keyPEM, certPEM, err := crctls.GenerateServingCertificate(caKey, caCert, "api.crc.testing")
+	require.NoError(t, err)
+	caCertFile, caKeyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(caCertFile, crctls.CertToPem(caCert), 0600))
+	require.NoError(t, os.WriteFile(caKeyFile, crctls.PrivateKeyToPem(caKey), 0600))
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0600))
+
+	cfg, err := newInMemoryConfig()
+	require.NoError(t, err)
+
+	_, err = cfg.Set(CustomCACertFile, certFile)
+	assert.EqualError(t, err, fmt.Sprintf("Value '%s' for configuration property 'custom-ca-cert-file' is invalid, reason: '%s' is not a CA certificate", certFile, certFile))
+	_, err = cfg.Set(CustomCACertFile, caKeyFile)
+	assert.ErrorContains(t, err, "is not a pem certificate")
+	_, err = cfg.Set(CustomCACertFile, caCertFile)
+	assert.NoError(t, err)
+	_, err = cfg.Set(CustomCAKeyFile, keyFile)
+	assert.EqualError(t, err, fmt.Sprintf("Value '%s' for configuration property 'custom-ca-key-file' is invalid, reason: '%s' is not the private key of '%s'", keyFile, keyFile, caCertFile))
+	_, err = cfg.Set(CustomCAKeyFile, caKeyFile)
+	assert.NoError(t, err)
+
+	_, err = cfg.Set(CustomCertFile, certFile)
+	assert.NoError(t, err)
+	_, err = cfg.Set(CustomKeyFile, caKeyFile)
+	assert.ErrorContains(t, err, "is not the private key of")
+	_, err = cfg.Set(CustomKeyFile, keyFile)
+	assert.NoError(t, err)
+}
+
 func TestWhenInvalidKeySetThenErrorIsThrown(t *testing.T) {
 	// Given
 	cfg, err := newInMemoryConfig()
@@ -266,6 +333,9 @@ var configDefaultValuesTestArguments = []struct {
 	{
 		EnableBundleQuayFallback, false,
 	},
+	{
+		BundleMirrors, constants.DefaultBundleMirror,
+	},
 	{
 		Preset, "openshift",
 	},
@@ -349,6 +419,9 @@ var configProvidedValuesTestArguments = []struct {
 	{
 		EnableBundleQuayFallback, true,
 	},
+	{
+		BundleMirrors, "https://mirror.example.com/bundles",
+	},
 	{
 		Preset, "microshift",
 	},
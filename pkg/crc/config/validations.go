@@ -2,15 +2,22 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"os"
 	"runtime"
 	"strings"
 
 	"go.podman.io/common/pkg/strongunits"
 
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/network/httpproxy"
 	crcpreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/registrycache"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
 	"github.com/crc-org/crc/v2/pkg/crc/validation"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
 	"github.com/spf13/cast"
 )
 
@@ -132,6 +139,124 @@ func validateNoProxy(value interface{}) (bool, string) {
 	return true, ""
 }
 
+// validateBundleMirrors checks if the value is a comma-separated list of
+// http, https or docker URIs
+func validateBundleMirrors(value interface{}) (bool, string) {
+	mirrors := strings.Split(cast.ToString(value), ",")
+	for _, mirror := range mirrors {
+		mirror = strings.TrimSpace(mirror)
+		if mirror == "" {
+			continue
+		}
+		u, err := url.Parse(mirror)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "docker") {
+			return false, fmt.Sprintf("'%s' is not a valid bundle mirror, mirrors must be http, https or docker URIs", mirror)
+		}
+	}
+	return true, ""
+}
+
+func validateDNSRecords(value interface{}) (bool, string) {
+	if _, err := network.ParseDNSRecords(cast.ToString(value)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func validateSharedDirs(value interface{}) (bool, string) {
+	if _, err := machineConfig.ParseSharedDirs(cast.ToString(value)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+func validateAppsDomain(value interface{}) (bool, string) {
+	domain := cast.ToString(value)
+	if domain == "" {
+		return true, ""
+	}
+	if err := network.ValidateDomain(domain); err != nil {
+		return false, fmt.Sprintf("'%s' %v", domain, err)
+	}
+	return true, ""
+}
+
+// validateCertificateFile checks that the pem file 'value' holds a
+// certificate, a CA one when 'isCA' is true
+func validateCertificateFile(value interface{}, isCA bool) (bool, string) {
+	path := cast.ToString(value)
+	if path == "" {
+		return true, ""
+	}
+	certPEM, err := os.ReadFile(path)
+	if err != nil {
+		return false, err.Error()
+	}
+	cert, err := crctls.PemToCert(certPEM)
+	if err != nil {
+		return false, fmt.Sprintf("'%s' is not a pem certificate: %v", path, err)
+	}
+	if isCA && !cert.IsCA {
+		return false, fmt.Sprintf("'%s' is not a CA certificate", path)
+	}
+	return true, ""
+}
+
+func validateCACertificateFile(value interface{}) (bool, string) {
+	return validateCertificateFile(value, true)
+}
+
+func validateServingCertificateFile(value interface{}) (bool, string) {
+	return validateCertificateFile(value, false)
+}
+
+// validatePrivateKeyFile checks that the pem file 'value' holds the private
+// key of the certificate 'certFile', when it is set
+func validatePrivateKeyFile(value interface{}, certFile string) (bool, string) {
+	path := cast.ToString(value)
+	if path == "" {
+		return true, ""
+	}
+	keyPEM, err := os.ReadFile(path)
+	if err != nil {
+		return false, err.Error()
+	}
+	key, err := crctls.PemToSigner(keyPEM)
+	if err != nil {
+		return false, fmt.Sprintf("'%s' is not a pem private key: %v", path, err)
+	}
+	if certFile == "" {
+		return true, ""
+	}
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return false, err.Error()
+	}
+	cert, err := crctls.PemToCert(certPEM)
+	if err != nil || !crctls.KeyMatchesCertificate(key, cert) {
+		return false, fmt.Sprintf("'%s' is not the private key of '%s'", path, certFile)
+	}
+	return true, ""
+}
+
+func validateRegistryCacheSize(value interface{}) (bool, string) {
+	size, err := cast.ToUintE(value)
+	if err != nil {
+		return false, fmt.Sprintf("could not convert '%s' to integer", value)
+	}
+	if size == 0 {
+		return false, "must be greater than 0"
+	}
+	return true, ""
+}
+
+func validateRegistryCacheRegistries(value interface{}) (bool, string) {
+	if _, err := registrycache.ParseRegistries(cast.ToString(value)); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
 func validateYesNo(value interface{}) (bool, string) {
 	if cast.ToString(value) == "yes" || cast.ToString(value) == "no" {
 		return true, ""
@@ -164,3 +289,18 @@ func validatePort(value interface{}) (bool, string) {
 	}
 	return true, ""
 }
+
+// validateInstance checks if the provided value is the name of an existing CRC instance
+func validateInstance(value interface{}) (bool, string) {
+	name, err := cast.ToStringE(value)
+	if err != nil {
+		return false, "must be a valid instance name"
+	}
+	if err := validation.ValidateInstanceName(name); err != nil {
+		return false, err.Error()
+	}
+	if name != constants.DefaultName && !crcos.FileExists(constants.GetInstanceConfigPath(name)) {
+		return false, fmt.Sprintf("instance '%s' does not exist, create it with 'crc instance create %s'", name, name)
+	}
+	return true, ""
+}
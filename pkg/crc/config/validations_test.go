@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
 	"runtime"
 	"testing"
 
@@ -158,3 +159,120 @@ func TestValidatePersistentVolumeSize(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateBundleMirrors(t *testing.T) {
+	tests := []struct {
+		name                     string
+		mirrors                  string
+		expectedValidationResult bool
+	}{
+		{"empty value", "", true},
+		{"https mirror", "https://mirror.example.com/crc/bundles", true},
+		{"multiple mirrors", "https://mirror.example.com/crc/bundles, http://10.0.0.1:8080/bundles,docker://quay.io/crcont", true},
+		{"local path", "/srv/bundles", false},
+		{"unsupported scheme", "ftp://mirror.example.com/bundles", false},
+		{"missing host", "https:///bundles", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validateBundleMirrors(tt.mirrors)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validateBundleMirrors(%s) : got %v, want %v", tt.mirrors, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
+
+func TestValidateDNSRecords(t *testing.T) {
+	tests := []struct {
+		name                     string
+		records                  string
+		expectedValidationResult bool
+	}{
+		{"empty value", "", true},
+		{"record", "db.mycompany.test=192.168.127.3", true},
+		{"record and wildcard domain", "db.mycompany.test=192.168.127.3, *.apps.mycompany.test", true},
+		{"single label hostname", "db=192.168.127.3", false},
+		{"invalid IP", "db.mycompany.test=192.168.127", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validateDNSRecords(tt.records)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validateDNSRecords(%s) : got %v, want %v", tt.records, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
+
+func TestValidateSharedDirs(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name                     string
+		dirs                     string
+		expectedValidationResult bool
+	}{
+		{"empty value", "", true},
+		{"directory", dir, true},
+		{"directory with options", dir + "=/mnt/data;ro;owner=1000:1000", true},
+		{"relative target", dir + "=data", false},
+		{"missing directory", filepath.Join(dir, "missing"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validateSharedDirs(tt.dirs)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validateSharedDirs(%s) : got %v, want %v", tt.dirs, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
+
+func TestValidateAppsDomain(t *testing.T) {
+	tests := []struct {
+		name                     string
+		domain                   string
+		expectedValidationResult bool
+	}{
+		{"empty value", "", true},
+		{"domain", "apps.mycompany.test", true},
+		{"single label domain", "test", false},
+		{"wildcard domain", "*.apps.mycompany.test", false},
+		{"upper-case domain", "Apps.MyCompany.test", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validateAppsDomain(tt.domain)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validateAppsDomain(%s) : got %v, want %v", tt.domain, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
+
+func TestValidateRegistryCacheRegistries(t *testing.T) {
+	tests := []struct {
+		name                     string
+		registries               string
+		expectedValidationResult bool
+	}{
+		{"empty value", "", true},
+		{"registries", "quay.io, registry.redhat.io", true},
+		{"registry with port", "registry.mycompany.test:5000", true},
+		{"repository", "quay.io/crcont", false},
+		{"single label hostname", "registry", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actualValidationResult, _ := validateRegistryCacheRegistries(tt.registries)
+			if actualValidationResult != tt.expectedValidationResult {
+				t.Errorf("validateRegistryCacheRegistries(%s) : got %v, want %v", tt.registries, actualValidationResult, tt.expectedValidationResult)
+			}
+		})
+	}
+}
@@ -30,7 +30,7 @@ const (
 	CrcLandingPageURL         = "https://console.redhat.com/openshift/create/local" // #nosec G101
 	DefaultAdminHelperURLBase = "https://github.com/crc-org/admin-helper/releases/download/v%s/%s"
 	BackgroundLauncherURL     = "https://github.com/crc-org/win32-background-launcher/releases/download/v%s/win32-background-launcher.exe"
-	DefaultBundleURLBase      = "https://mirror.openshift.com/pub/openshift-v4/clients/crc/bundles/%s/%s/%s"
+	DefaultBundleMirror       = "https://mirror.openshift.com/pub/openshift-v4/clients/crc/bundles"
 	DefaultContext            = "admin"
 	DefaultDeveloperPassword  = "developer"
 	DaemonHTTPEndpoint        = "http://unix/api"
@@ -51,6 +51,10 @@ const (
 	AppsDomain          = ".apps-crc.testing"
 	MicroShiftAppDomain = ".apps.crc.testing"
 
+	// QuayBundleMirror is the bundle mirror list entry for the bundle
+	// images published on quay.io
+	QuayBundleMirror = "docker://" + RegistryURI
+
 	OpenShiftIngressHTTPPort  = 80
 	OpenShiftIngressHTTPSPort = 443
 
@@ -60,6 +64,15 @@ const (
 	Plan9TcpPort    = 564
 	Plan9HvsockGUID = "00009000-FACB-11E6-BD58-64006A7986D3"
 	Plan9HvsockPort = 36864
+
+	// RegistryCachePort is the port of the registry cache of the daemon on
+	// the gateway of the user-mode network
+	RegistryCachePort = 5000
+	// DefaultRegistryCacheSize is the default size limit in GiB of the
+	// registry cache
+	DefaultRegistryCacheSize = 20
+	// DefaultRegistryCacheRegistries are the registries cached by default
+	DefaultRegistryCacheRegistries = "docker.io,quay.io,registry.redhat.io,registry.access.redhat.com,ghcr.io"
 )
 
 var adminHelperExecutableForOs = map[string]string{
@@ -121,29 +134,121 @@ var (
 	MachineBaseDir     = CrcBaseDir
 	MachineCacheDir    = filepath.Join(MachineBaseDir, "cache")
 	MachineInstanceDir = filepath.Join(MachineBaseDir, "machines")
-	DaemonSocketPath   = filepath.Join(CrcBaseDir, "crc.sock")
-	KubeconfigFilePath = filepath.Join(MachineInstanceDir, DefaultName, "kubeconfig")
-	PasswdFilePath     = filepath.Join(MachineInstanceDir, DefaultName, "passwd")
+	CrcInstancesDir    = filepath.Join(CrcBaseDir, "instances")
+	// CrcVolumesDir holds the disks created by 'crc volume create', they
+	// are kept when the instances are deleted
+	CrcVolumesDir = filepath.Join(MachineBaseDir, "volumes")
+	// RegistryCacheDir holds the image layers and manifests of the registry
+	// cache, they are kept when the instances are deleted
+	RegistryCacheDir = filepath.Join(MachineBaseDir, "registry-cache")
+	DaemonSocketPath = filepath.Join(CrcBaseDir, "crc.sock")
 )
 
+// GetInstanceDir returns the directory holding the VM disk image, SSH keys,
+// kubeconfig and passwords of the instance 'name'
+func GetInstanceDir(name string) string {
+	return filepath.Join(MachineInstanceDir, name)
+}
+
+// GetInstanceConfigPath returns the path of the file storing the settings
+// which are specific to the instance 'name'. The default instance uses the
+// global configuration file.
+func GetInstanceConfigPath(name string) string {
+	if name == DefaultName {
+		return ConfigPath
+	}
+	return filepath.Join(CrcInstancesDir, fmt.Sprintf("%s.json", name))
+}
+
+func GetKubeconfigFilePath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "kubeconfig")
+}
+
+func GetPasswdFilePath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "passwd")
+}
+
+// GetInstanceSnapshotsDir returns the directory holding the snapshots of the
+// instance 'name'. They are removed together with the instance VM.
+func GetInstanceSnapshotsDir(name string) string {
+	return filepath.Join(GetInstanceDir(name), "snapshots")
+}
+
+// GetPortForwardsPath returns the path of the file storing the port
+// forwards of the instance 'name', which are applied each time it starts
+func GetPortForwardsPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "port-forwards.json")
+}
+
+// GetSharedDirOwnersPath returns the path of the file storing the owners of
+// the directories of the 'shared-dirs' setting of the instance 'name'
+func GetSharedDirOwnersPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "shared-dir-owners.json")
+}
+
+// GetVolumePath returns the path of the disk image of the volume 'name'
+func GetVolumePath(name string) string {
+	return filepath.Join(CrcVolumesDir, fmt.Sprintf("%s.qcow2", name))
+}
+
+// GetVolumesPath returns the path of the file describing the volumes and
+// the instances they are attached to
+func GetVolumesPath() string {
+	return filepath.Join(CrcVolumesDir, "volumes.json")
+}
+
+// GetIngressCACertPath returns the path of the CA signing the ingress
+// certificate of the instance 'name' when it serves a custom apps domain
+func GetIngressCACertPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "ingress-ca.crt")
+}
+
+func GetIngressCAKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "ingress-ca.key")
+}
+
+// GetIngressCertPath returns the path of the default ingress certificate
+// of the instance 'name' when crc generates it
+func GetIngressCertPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "ingress.crt")
+}
+
+func GetIngressKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "ingress.key")
+}
+
+// GetAPICertPath returns the path of the API server certificate of the
+// instance 'name' when crc signs it with a custom CA
+func GetAPICertPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "api.crt")
+}
+
+func GetAPIKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "api.key")
+}
+
 func GetDefaultBundlePath(preset crcpreset.Preset) string {
 	return filepath.Join(MachineCacheDir, GetDefaultBundle(preset))
 }
 
-func GetDefaultBundleDownloadURL(preset crcpreset.Preset) string {
-	return fmt.Sprintf(DefaultBundleURLBase,
-		preset.String(),
-		version.GetBundleVersion(preset),
-		GetDefaultBundle(preset),
-	)
+// GetBundleMirrorURL returns the URL of 'filename' in the directory of the
+// bundles released for 'bundleVersion' on the http(s) mirror 'mirror'
+func GetBundleMirrorURL(mirror string, preset crcpreset.Preset, bundleVersion, filename string) string {
+	return fmt.Sprintf("%s/%s/%s/%s", strings.TrimSuffix(mirror, "/"), preset.String(), bundleVersion, filename)
+}
+
+func GetDefaultBundleDownloadURL(mirror string, preset crcpreset.Preset) string {
+	return GetBundleMirrorURL(mirror, preset, version.GetBundleVersion(preset), GetDefaultBundle(preset))
 }
 
-func GetDefaultBundleSignedHashURL(preset crcpreset.Preset) string {
-	return fmt.Sprintf(DefaultBundleURLBase,
-		preset.String(),
-		version.GetBundleVersion(preset),
-		"sha256sum.txt.sig",
-	)
+func GetDefaultBundleSignedHashURL(mirror string, preset crcpreset.Preset) string {
+	return GetBundleSignedHashURL(mirror, preset, version.GetBundleVersion(preset))
+}
+
+// GetBundleSignedHashURL returns the URL of the signed sha256sum.txt file of
+// the bundles released for 'bundleVersion' on the http(s) mirror 'mirror'
+func GetBundleSignedHashURL(mirror string, preset crcpreset.Preset, bundleVersion string) string {
+	return GetBundleMirrorURL(mirror, preset, bundleVersion, "sha256sum.txt.sig")
 }
 
 func ResolveHelperPath(executableName string) string {
@@ -170,9 +275,9 @@ func GetHomeDir() string {
 	return homeDir
 }
 
-// EnsureBaseDirectoriesExist creates ~/.crc, ~/.crc/bin and ~/.crc/cache directories if it is not present
+// EnsureBaseDirectoriesExist creates ~/.crc, ~/.crc/bin, ~/.crc/cache and ~/.crc/instances directories if it is not present
 func EnsureBaseDirectoriesExist() error {
-	baseDirectories := []string{CrcBaseDir, MachineCacheDir, CrcBinDir}
+	baseDirectories := []string{CrcBaseDir, MachineCacheDir, CrcBinDir, CrcInstancesDir}
 	for _, baseDir := range baseDirectories {
 		err := os.MkdirAll(baseDir, 0750)
 		if err != nil {
@@ -182,29 +287,29 @@ func EnsureBaseDirectoriesExist() error {
 	return nil
 }
 
-func GetPublicKeyPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "id_ed25519.pub")
+func GetPublicKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "id_ed25519.pub")
 }
 
-func GetPrivateKeyPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "id_ed25519")
+func GetPrivateKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "id_ed25519")
 }
 
-func GetHostDockerSocketPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "docker.sock")
+func GetHostDockerSocketPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "docker.sock")
 }
 
 // For backward compatibility to v 2.40.0
-func GetECDSAPrivateKeyPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "id_ecdsa")
+func GetECDSAPrivateKeyPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "id_ecdsa")
 }
 
-func GetKubeAdminPasswordPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "kubeadmin-password")
+func GetKubeAdminPasswordPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "kubeadmin-password")
 }
 
-func GetDeveloperPasswordPath() string {
-	return filepath.Join(MachineInstanceDir, DefaultName, "developer-password")
+func GetDeveloperPasswordPath(name string) string {
+	return filepath.Join(GetInstanceDir(name), "developer-password")
 }
 
 func GetWin32BackgroundLauncherDownloadURL() string {
@@ -240,6 +345,12 @@ func GetDefaultBundleImageRegistry(preset crcpreset.Preset) string {
 	return fmt.Sprintf("//%s/%s:%s", RegistryURI, getImageName(preset), version.GetBundleVersion(preset))
 }
 
+// GetDefaultBundleImage returns the image of the default bundle on the
+// docker:// mirror 'mirror'
+func GetDefaultBundleImage(mirror string, preset crcpreset.Preset) string {
+	return fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(mirror, "/"), getImageName(preset), version.GetBundleVersion(preset))
+}
+
 func getImageName(preset crcpreset.Preset) string {
 	switch preset {
 	case crcpreset.OKD:
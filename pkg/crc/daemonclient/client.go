@@ -13,6 +13,10 @@ import (
 
 const genericDaemonNotRunningMessage = "Is 'crc daemon' running? Cannot reach daemon API"
 
+// apiTimeout is the timeout of the requests of the APIClient, the long
+// operations are polled with shorter requests
+const apiTimeout = 30 * time.Second
+
 type Client struct {
 	NetworkClient *networkclient.Client
 	APIClient     client.Client
@@ -24,14 +28,18 @@ func New() *Client {
 		NetworkClient: networkclient.New(&http.Client{
 			Transport: transport(),
 		}, "http://unix/network"),
-		APIClient: client.New(&http.Client{
-			Timeout:   30 * time.Second,
-			Transport: transport(),
-		}, "http://unix/api"),
+		APIClient: newAPIClient(transport(), "http://unix/api"),
 		SSEClient: client.NewSSEClient(transport()),
 	}
 }
 
+func newAPIClient(transport http.RoundTripper, baseURL string) client.Client {
+	return client.New(&http.Client{
+		Timeout:   apiTimeout,
+		Transport: transport,
+	}, baseURL)
+}
+
 func GetVersionFromDaemonAPI() (*client.VersionResult, error) {
 	apiClient := client.New(&http.Client{Transport: transport()}, "http://unix/api")
 	version, err := apiClient.Version()
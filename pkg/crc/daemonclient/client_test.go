@@ -0,0 +1,38 @@
+package daemonclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/api/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestOperationPollsWaitLessThanTheTimeout checks the polls of the
+// operations requested by the APIClient are not cut by its timeout
+func TestOperationPollsWaitLessThanTheTimeout(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		op := client.OperationResult{ID: "1", Type: client.OperationStop, State: client.OperationRunning}
+		if r.URL.Path == "/v1/operations/1" {
+			polls++
+			wait, err := time.ParseDuration(r.URL.Query().Get("wait"))
+			assert.NoError(t, err)
+			assert.LessOrEqual(t, wait, apiTimeout/3)
+			op.State = client.OperationSucceeded
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(op)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(op)
+	}))
+	defer server.Close()
+
+	assert.NoError(t, newAPIClient(http.DefaultTransport, server.URL).Stop())
+	assert.Equal(t, 1, polls)
+}
@@ -0,0 +1,232 @@
+// Package diagnose gathers the logs, the configuration and the state of the
+// host, of the VM and of the cluster into a single tarball which can be
+// attached to bug reports.
+package diagnose
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
+	"github.com/crc-org/crc/v2/pkg/crc/preflight"
+	"github.com/crc-org/crc/v2/pkg/crc/version"
+)
+
+const (
+	redacted = "<redacted>"
+	// minSecretLength avoids masking every occurrence of short values such
+	// as an empty or one letter password
+	minSecretLength = 4
+)
+
+var secretPatterns = []*regexp.Regexp{
+	// pull secret auths and passwords in JSON documents
+	regexp.MustCompile(`("(?:auth|password|identitytoken)"\s*:\s*")[^"]*(")`),
+	// OpenShift OAuth access tokens
+	regexp.MustCompile(`()sha256~[A-Za-z0-9_-]+()`),
+	// bearer tokens in HTTP headers
+	regexp.MustCompile(`((?i:bearer)\s+)[A-Za-z0-9._~+/=-]+()`),
+	// credentials in proxy URLs
+	regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+(@)`),
+}
+
+type Collector struct {
+	Config  crcConfig.Storage
+	Machine machine.Client
+	// DaemonLogs returns the logs kept in memory by the daemon
+	DaemonLogs func() []string
+	// LogFiles are the log files added to the tarball
+	LogFiles []string
+	// Preflight returns the results of the preflight checks
+	Preflight func(config crcConfig.Storage) []preflight.CheckResult
+
+	now func() time.Time
+}
+
+func NewCollector(config crcConfig.Storage, machine machine.Client) *Collector {
+	return &Collector{
+		Config:     config,
+		Machine:    machine,
+		DaemonLogs: logging.Memory.Messages,
+		LogFiles:   []string{constants.LogFilePath, constants.DaemonLogFilePath},
+		Preflight:  preflight.CheckHost,
+		now:        time.Now,
+	}
+}
+
+type file struct {
+	name    string
+	content []byte
+}
+
+// Collect writes to 'w' a gzipped tarball with the redacted diagnostic
+// files. The failure to collect one of the files is recorded in the
+// 'errors.txt' file of the tarball.
+func (c *Collector) Collect(ctx context.Context, w io.Writer) error {
+	var files []file
+	var collectErrors []string
+	add := func(name string, content []byte) {
+		files = append(files, file{name: name, content: content})
+	}
+	addError := func(what string, err error) {
+		logging.Debugf("Cannot collect %s: %v", what, err)
+		collectErrors = append(collectErrors, fmt.Sprintf("%s: %v", what, err))
+	}
+
+	add("version.txt", []byte(fmt.Sprintf("CRC version: %s+%s\nOS: %s\n", version.GetCRCVersion(), version.GetCommitSha(), runtime.GOOS+"/"+runtime.GOARCH)))
+
+	for _, logFile := range c.LogFiles {
+		content, err := os.ReadFile(logFile)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				addError(logFile, err)
+			}
+			continue
+		}
+		add(filepath.Join("logs", filepath.Base(logFile)), content)
+	}
+	if c.DaemonLogs != nil {
+		if messages := c.DaemonLogs(); len(messages) > 0 {
+			add("logs/daemon-memory.log", []byte(strings.Join(messages, "\n")+"\n"))
+		}
+	}
+
+	secrets := c.configSecrets()
+	add("config.txt", c.configView())
+
+	if c.Preflight != nil {
+		results, err := json.MarshalIndent(c.Preflight(c.Config), "", "  ")
+		if err != nil {
+			addError("preflight checks", err)
+		} else {
+			add("preflight.json", results)
+		}
+	}
+
+	if c.Machine != nil {
+		result, err := c.Machine.Diagnose(ctx)
+		if err != nil {
+			addError("virtual machine", err)
+		} else {
+			for _, f := range result.Files {
+				add(f.Name, f.Content)
+			}
+			secrets = append(secrets, result.Secrets...)
+			collectErrors = append(collectErrors, result.Errors...)
+		}
+	}
+
+	if len(collectErrors) > 0 {
+		add("errors.txt", []byte(strings.Join(collectErrors, "\n")+"\n"))
+	}
+
+	return c.write(w, files, newRedactor(secrets))
+}
+
+// configView is the equivalent of 'crc config view', the values of the
+// secret settings and of the passwords are masked
+func (c *Collector) configView() []byte {
+	var lines []string
+	for key, value := range c.Config.AllConfigs() {
+		if value.IsDefault {
+			continue
+		}
+		str := value.AsString()
+		if isSecret(key, value) {
+			str = redacted
+		}
+		lines = append(lines, fmt.Sprintf("- %-38s: %s", key, str))
+	}
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, "\n") + "\n")
+}
+
+// isSecret returns true for the secret settings and for the user defined
+// passwords, which are stored in clear text
+func isSecret(key string, value crcConfig.SettingValue) bool {
+	return value.IsSecret || strings.HasSuffix(key, "-password")
+}
+
+// configSecrets returns the values of the secret settings and the content of
+// the pull secret file
+func (c *Collector) configSecrets() []string {
+	var secrets []string
+	for key, value := range c.Config.AllConfigs() {
+		if isSecret(key, value) {
+			secrets = append(secrets, value.AsString())
+		}
+	}
+	if path := c.Config.Get(crcConfig.PullSecretFile).AsString(); path != "" {
+		if content, err := os.ReadFile(path); err == nil {
+			secrets = append(secrets, strings.TrimSpace(string(content)))
+		}
+	}
+	return secrets
+}
+
+func (c *Collector) write(w io.Writer, files []file, redact *redactor) error {
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+	prefix := fmt.Sprintf("crc-diagnose-%s", c.now().UTC().Format("20060102-150405"))
+	for _, f := range files {
+		content := redact.redact(f.content)
+		header := &tar.Header{
+			Name:    filepath.ToSlash(filepath.Join(prefix, f.name)),
+			Mode:    0600,
+			Size:    int64(len(content)),
+			ModTime: c.now(),
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tarWriter.Write(content); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+type redactor struct {
+	replacer *strings.Replacer
+}
+
+func newRedactor(secrets []string) *redactor {
+	var oldnew []string
+	// longer secrets first so that a secret containing another one is
+	// fully masked
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+	for _, secret := range secrets {
+		if len(secret) < minSecretLength {
+			continue
+		}
+		oldnew = append(oldnew, secret, redacted)
+	}
+	return &redactor{replacer: strings.NewReplacer(oldnew...)}
+}
+
+func (r *redactor) redact(content []byte) []byte {
+	str := r.replacer.Replace(string(content))
+	for _, pattern := range secretPatterns {
+		str = pattern.ReplaceAllString(str, "${1}"+redacted+"${2}")
+	}
+	return []byte(str)
+}
@@ -0,0 +1,72 @@
+package machine
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"os"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+)
+
+// configureAppsDomain makes the cluster ingress serve the routes under
+// 'appsDomain' in addition to the apps domain of the bundle, or reverts
+// this when 'appsDomain' is empty
+func configureAppsDomain(ctx context.Context, ocConfig oc.Config, appsDomain string) error {
+	if appsDomain == "" {
+		return cluster.RemoveAppsDomainFromCluster(ctx, ocConfig)
+	}
+	return cluster.EnsureAppsDomainInTheCluster(ctx, ocConfig, appsDomain)
+}
+
+// ingressCertificate returns the default ingress certificate of the
+// instance 'name' for the wildcard 'domains', followed by the CA which
+// signed it, and its key. The certificate is reused until it is about to
+// expire or the domains change.
+func ingressCertificate(name string, domains ...string) ([]byte, []byte, error) {
+	caKey, caCert, err := ingressCA(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	dnsNames := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		dnsNames = append(dnsNames, "*."+domain)
+	}
+	return signedCertificate(constants.GetIngressCertPath(name), constants.GetIngressKeyPath(name), caKey, caCert, dnsNames)
+}
+
+// ingressCA loads the CA of the instance 'name' signing its ingress
+// certificate, it is generated the first time
+func ingressCA(name string) (crypto.Signer, *x509.Certificate, error) {
+	if crcos.FileExists(constants.GetIngressCACertPath(name)) {
+		return loadCA(constants.GetIngressCACertPath(name), constants.GetIngressCAKeyPath(name))
+	}
+
+	key, cert, err := crctls.GetSelfSignedIngressCA()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(constants.GetIngressCACertPath(name), crctls.CertToPem(cert), 0600); err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(constants.GetIngressCAKeyPath(name), crctls.PrivateKeyToPem(key), 0600); err != nil {
+		return nil, nil, err
+	}
+	return key, cert, nil
+}
+
+// logHostResolutionOfAppsDomain tells how to resolve 'appsDomain' on the
+// host, the hosts file and DNS configuration of crc only cover the domains
+// of the bundle
+func logHostResolutionOfAppsDomain(appsDomain, instanceIP string, userNetworking bool) {
+	ip := instanceIP
+	if userNetworking {
+		ip = constants.LocalIP
+	}
+	logging.Infof("The routes are also served under *.%s, make sure this domain resolves to %s on the host", appsDomain, ip)
+}
@@ -0,0 +1,44 @@
+package machine
+
+import (
+	"os"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIngressCertificate(t *testing.T) {
+	machineInstanceDir := constants.MachineInstanceDir
+	constants.MachineInstanceDir = t.TempDir()
+	defer func() {
+		constants.MachineInstanceDir = machineInstanceDir
+	}()
+	require.NoError(t, os.MkdirAll(constants.GetInstanceDir("crc"), 0750))
+
+	certPEM, keyPEM, err := ingressCertificate("crc", "apps-crc.testing", "apps.mycompany.test")
+	require.NoError(t, err)
+	cert, err := crctls.PemToCert(certPEM)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.apps-crc.testing", "*.apps.mycompany.test"}, cert.DNSNames)
+	_, err = crctls.PemToPrivateKey(keyPEM)
+	require.NoError(t, err)
+
+	caPEM, err := os.ReadFile(constants.GetIngressCACertPath("crc"))
+	require.NoError(t, err)
+	assert.Contains(t, string(certPEM), string(caPEM))
+
+	// the certificate is reused as long as the domains are the same
+	sameCertPEM, _, err := ingressCertificate("crc", "apps.mycompany.test", "apps-crc.testing")
+	require.NoError(t, err)
+	assert.Equal(t, certPEM, sameCertPEM)
+
+	otherCertPEM, _, err := ingressCertificate("crc", "apps-crc.testing", "apps.example.test")
+	require.NoError(t, err)
+	assert.NotEqual(t, certPEM, otherCertPEM)
+	otherCaPEM, err := os.ReadFile(constants.GetIngressCACertPath("crc"))
+	require.NoError(t, err)
+	assert.Equal(t, caPEM, otherCaPEM)
+}
@@ -3,12 +3,14 @@ package bundle
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -250,6 +252,112 @@ func (bundle *CrcBundleInfo) checkDiskImageSize() error {
 	return nil
 }
 
+// VerifyChecksums checks the size and the sha256sum of the disk images and
+// of the files listed in the bundle metadata. All the mismatches are
+// reported in the returned error.
+func (bundle *CrcBundleInfo) VerifyChecksums() error {
+	var errs []error
+	for _, diskImage := range bundle.Storage.DiskImages {
+		if err := bundle.verifyFile(diskImage.File); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, file := range bundle.Storage.Files {
+		if err := bundle.verifyFile(file.File); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (bundle *CrcBundleInfo) verifyFile(file File) error {
+	path := bundle.resolvePath(file.Name)
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	expectedSize, err := strconv.ParseInt(file.Size, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid size for %s: %w", file.Name, err)
+	}
+	if stat.Size() != expectedSize {
+		return fmt.Errorf("unexpected size for %s: got %d instead of %d", file.Name, stat.Size(), expectedSize)
+	}
+	sum, err := sha256sum(path)
+	if err != nil {
+		return err
+	}
+	if sum != file.Checksum {
+		return fmt.Errorf("unexpected sha256sum for %s: got %s instead of %s", file.Name, sum, file.Checksum)
+	}
+	return nil
+}
+
+// VerifySignature checks the sha256sum of the bundle archive 'bundlePath'
+// against the sha256sum.txt file signed by Red Hat for its release. The
+// signed file is fetched from the mirror the archive was downloaded from,
+// then from the http(s) mirrors of 'mirrors'.
+func VerifySignature(bundlePath string, mirrors []string) error {
+	filenameInfo, err := GetBundleInfoFromName(filepath.Base(bundlePath))
+	if err != nil {
+		return err
+	}
+	if filenameInfo.CustomBundleSuffix != "" {
+		return fmt.Errorf("%s is a custom bundle, it is not signed", filepath.Base(bundlePath))
+	}
+	var signedHashURLs []string
+	for _, mirror := range signatureMirrors(bundlePath, mirrors) {
+		signedHashURLs = append(signedHashURLs, constants.GetBundleSignedHashURL(mirror, filenameInfo.Preset, filenameInfo.Version))
+	}
+	return verifySignature(signedHashURLs, bundlePath)
+}
+
+// signatureMirrors returns the http(s) mirrors the signed sha256sum.txt file
+// of 'bundlePath' can be fetched from, the one it was downloaded from first
+func signatureMirrors(bundlePath string, mirrors []string) []string {
+	var ret []string
+	if mirror, err := os.ReadFile(mirrorFilePath(bundlePath)); err == nil {
+		ret = append(ret, strings.TrimSpace(string(mirror)))
+	}
+	for _, mirror := range mirrors {
+		if strings.HasPrefix(mirror, "docker://") || slices.Contains(ret, mirror) {
+			continue
+		}
+		ret = append(ret, mirror)
+	}
+	if len(ret) == 0 {
+		ret = append(ret, constants.DefaultBundleMirror)
+	}
+	return ret
+}
+
+// mirrorFilePath returns the path of the file recording the mirror the
+// default bundle archive 'bundlePath' was downloaded from
+func mirrorFilePath(bundlePath string) string {
+	return bundlePath + ".mirror"
+}
+
+func verifySignature(signedHashURLs []string, bundlePath string) error {
+	var errs []error
+	for _, signedHashURL := range signedHashURLs {
+		expected, err := getVerifiedHash(signedHashURL, filepath.Base(bundlePath))
+		if err != nil {
+			logging.Debugf("Cannot get the signed sha256sum from %s: %v", signedHashURL, err)
+			errs = append(errs, fmt.Errorf("%s: %w", signedHashURL, err))
+			continue
+		}
+		sum, err := sha256sum(bundlePath)
+		if err != nil {
+			return err
+		}
+		if sum != expected {
+			return fmt.Errorf("unexpected sha256sum for %s: got %s instead of %s", filepath.Base(bundlePath), sum, expected)
+		}
+		return nil
+	}
+	return errors.Join(errs...)
+}
+
 func GetBundleNameWithoutExtension(bundleName string) string {
 	return strings.TrimSuffix(bundleName, bundleExtension)
 }
@@ -326,20 +434,20 @@ func GetBundleInfoFromName(bundleName string) (*FilenameInfo, error) {
 	return &filenameInfo, nil
 }
 
-func getBundleDownloadInfo(preset crcPreset.Preset) (*download.RemoteFile, error) {
-	sha256sum, err := getDefaultBundleVerifiedHash(preset)
+func getBundleDownloadInfo(mirror string, preset crcPreset.Preset) (*download.RemoteFile, error) {
+	sha256sum, err := getDefaultBundleVerifiedHash(mirror, preset)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get verified hash for default bundle: %w", err)
 	}
-	downloadInfo := download.NewRemoteFile(constants.GetDefaultBundleDownloadURL(preset), sha256sum)
+	downloadInfo := download.NewRemoteFile(constants.GetDefaultBundleDownloadURL(mirror, preset), sha256sum)
 	return downloadInfo, nil
 }
 
-// getDefaultBundleVerifiedHash downloads the sha256sum.txt.sig file from mirror.openshift.com
+// getDefaultBundleVerifiedHash downloads the sha256sum.txt.sig file from the mirror
 // then verifies it is signed by redhat release key, if signature is valid it returns the hash
 // for the default bundle of preset from the file
-func getDefaultBundleVerifiedHash(preset crcPreset.Preset) (string, error) {
-	return getVerifiedHash(constants.GetDefaultBundleSignedHashURL(preset), constants.GetDefaultBundle(preset))
+func getDefaultBundleVerifiedHash(mirror string, preset crcPreset.Preset) (string, error) {
+	return getVerifiedHash(constants.GetDefaultBundleSignedHashURL(mirror, preset), constants.GetDefaultBundle(preset))
 }
 
 func getVerifiedHash(url string, file string) (string, error) {
@@ -370,15 +478,49 @@ func getVerifiedHash(url string, file string) (string, error) {
 	return "", fmt.Errorf("%s hash is missing or shasums are malformed", file)
 }
 
-func downloadDefault(ctx context.Context, preset crcPreset.Preset) (string, error) {
-	downloadInfo, err := getBundleDownloadInfo(preset)
+func downloadDefault(ctx context.Context, mirror string, preset crcPreset.Preset) (string, error) {
+	if strings.HasPrefix(mirror, "docker://") {
+		return image.PullBundle(ctx, constants.GetDefaultBundleImage(mirror, preset))
+	}
+	downloadInfo, err := getBundleDownloadInfo(mirror, preset)
 	if err != nil {
 		return "", err
 	}
-	return downloadInfo.Download(ctx, constants.GetDefaultBundlePath(preset), 0664)
+	bundlePath, err := downloadInfo.Download(ctx, constants.GetDefaultBundlePath(preset), 0664)
+	if err != nil {
+		return "", err
+	}
+	// the signature of the archive is verified against this mirror later on
+	if err := os.WriteFile(mirrorFilePath(bundlePath), []byte(mirror), 0600); err != nil {
+		logging.Debugf("Cannot record the mirror %s was downloaded from: %v", bundlePath, err)
+	}
+	return bundlePath, nil
 }
 
-func Download(ctx context.Context, preset crcPreset.Preset, bundleURI string, enableBundleQuayFallback bool) (string, error) {
+// downloadDefaultFromMirrors tries to download the default bundle from each
+// of 'mirrors' in turn, until one of them succeeds
+func downloadDefaultFromMirrors(ctx context.Context, preset crcPreset.Preset, mirrors []string, downloadFromMirror func(ctx context.Context, mirror string, preset crcPreset.Preset) (string, error)) (string, error) {
+	if len(mirrors) == 0 {
+		return "", fmt.Errorf("no bundle mirror is configured")
+	}
+	var errs []error
+	for _, mirror := range mirrors {
+		bundlePath, err := downloadFromMirror(ctx, mirror, preset)
+		if err == nil {
+			return bundlePath, nil
+		}
+		if ctx.Err() != nil {
+			return "", err
+		}
+		logging.Infof("Unable to download bundle from %s: %v", mirror, err)
+		errs = append(errs, fmt.Errorf("%s: %w", mirror, err))
+	}
+	return "", fmt.Errorf("unable to download bundle from any mirror: %w", errors.Join(errs...))
+}
+
+// Download downloads the bundle 'bundleURI'. The default bundle of 'preset'
+// is downloaded from the first of 'mirrors' which has it.
+func Download(ctx context.Context, preset crcPreset.Preset, bundleURI string, mirrors []string) (string, error) {
 	// If we are asked to download
 	// ~/.crc/cache/crc_podman_libvirt_4.1.1.crcbundle, this means we want
 	// are downloading the default bundle for this release. This uses a
@@ -387,12 +529,7 @@ func Download(ctx context.Context, preset crcPreset.Preset, bundleURI string, en
 	if bundleURI == constants.GetDefaultBundlePath(preset) {
 		switch preset {
 		case crcPreset.OpenShift, crcPreset.Microshift:
-			downloadedBundlePath, err := downloadDefault(ctx, preset)
-			if err != nil && enableBundleQuayFallback {
-				logging.Info("Unable to download bundle from mirror, falling back to quay")
-				return image.PullBundle(ctx, constants.GetDefaultBundleImageRegistry(preset))
-			}
-			return downloadedBundlePath, err
+			return downloadDefaultFromMirrors(ctx, preset, mirrors, downloadDefault)
 		case crcPreset.OKD:
 			fallthrough
 		default:
@@ -1,8 +1,11 @@
 package bundle
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
@@ -207,6 +210,76 @@ func TestVerifiedHash(t *testing.T) {
 	require.ErrorContains(t, err, "signature made by unknown entity")
 }
 
+func TestVerifySignature(t *testing.T) {
+	bundlePath := filepath.Join(t.TempDir(), "crc_libvirt_4.13.0_amd64.crcbundle")
+	require.NoError(t, os.WriteFile(bundlePath, []byte("corrupted"), 0600))
+	err := verifySignature([]string{testDataURI(t, "sha256sum_correct_4.13.0.txt.sig")}, bundlePath)
+	require.ErrorContains(t, err, "unexpected sha256sum for crc_libvirt_4.13.0_amd64.crcbundle")
+
+	err = verifySignature([]string{testDataURI(t, "sha256sum_incorrect_4.13.0.txt.sig")}, bundlePath)
+	require.ErrorContains(t, err, "signature made by unknown entity")
+
+	// the next mirror is tried when the signed sha256sum cannot be fetched
+	err = verifySignature([]string{testDataURI(t, "missing.txt.sig"), testDataURI(t, "sha256sum_correct_4.13.0.txt.sig")}, bundlePath)
+	require.ErrorContains(t, err, "unexpected sha256sum for crc_libvirt_4.13.0_amd64.crcbundle")
+
+	require.EqualError(t, VerifySignature("crc_libvirt_4.13.0_amd64_1621489482.crcbundle", nil), "crc_libvirt_4.13.0_amd64_1621489482.crcbundle is a custom bundle, it is not signed")
+}
+
+func TestSignatureMirrors(t *testing.T) {
+	bundlePath := filepath.Join(t.TempDir(), "crc_libvirt_4.13.0_amd64.crcbundle")
+	mirrors := []string{"docker://quay.io/crcont", "https://mirror.example.com", "https://other.example.com"}
+	require.Equal(t, []string{"https://mirror.example.com", "https://other.example.com"}, signatureMirrors(bundlePath, mirrors))
+	require.Equal(t, []string{constants.DefaultBundleMirror}, signatureMirrors(bundlePath, nil))
+
+	// the mirror the bundle was downloaded from is tried first
+	require.NoError(t, os.WriteFile(mirrorFilePath(bundlePath), []byte("https://other.example.com"), 0600))
+	require.Equal(t, []string{"https://other.example.com", "https://mirror.example.com"}, signatureMirrors(bundlePath, mirrors))
+}
+
+func TestVerifyChecksums(t *testing.T) {
+	dir := t.TempDir()
+	createDummyBundleContent(t, dir, "crc_libvirt_4.6.1", "1.0")
+	repo := &Repository{
+		CacheDir: dir,
+	}
+	bundle, err := repo.Get("crc_libvirt_4.6.1")
+	require.NoError(t, err)
+
+	// the dummy files do not match the metadata
+	require.EqualError(t, bundle.VerifyChecksums(), "unexpected sha256sum for crc.qcow2: got ba1dfe475f9d92472eedfd45e1b3e2d4a9cd94355b0214e16a2df6ea864bf242 instead of 245a0e5acd4f09000a9a5f37d731082ed1cf3fdcad1b5320cbe9b153c9fd82a4\n"+
+		"unexpected size for oc: got 16 instead of 72728632")
+
+	bundle.Storage.DiskImages[0].Checksum = "ba1dfe475f9d92472eedfd45e1b3e2d4a9cd94355b0214e16a2df6ea864bf242"
+	bundle.Storage.Files = nil
+	require.NoError(t, bundle.VerifyChecksums())
+
+	require.NoError(t, os.WriteFile(bundle.GetDiskImagePath(), []byte("corrupted"), 0600))
+	require.ErrorContains(t, bundle.VerifyChecksums(), "unexpected sha256sum for crc.qcow2")
+}
+
+func TestDownloadDefaultFromMirrors(t *testing.T) {
+	var tried []string
+	downloadFromMirror := func(_ context.Context, mirror string, _ preset.Preset) (string, error) {
+		tried = append(tried, mirror)
+		if mirror == "https://good.example.com" {
+			return "/cache/bundle.crcbundle", nil
+		}
+		return "", errors.New("not found")
+	}
+
+	path, err := downloadDefaultFromMirrors(context.Background(), preset.OpenShift, []string{"https://bad.example.com", "https://good.example.com", "docker://quay.io/crcont"}, downloadFromMirror)
+	require.NoError(t, err)
+	require.Equal(t, "/cache/bundle.crcbundle", path)
+	require.Equal(t, []string{"https://bad.example.com", "https://good.example.com"}, tried)
+
+	_, err = downloadDefaultFromMirrors(context.Background(), preset.OpenShift, []string{"https://bad.example.com"}, downloadFromMirror)
+	require.EqualError(t, err, "unable to download bundle from any mirror: https://bad.example.com: not found")
+
+	_, err = downloadDefaultFromMirrors(context.Background(), preset.OpenShift, nil, downloadFromMirror)
+	require.EqualError(t, err, "no bundle mirror is configured")
+}
+
 func testDataURI(t *testing.T, sha256sum string) string {
 	absPath, err := filepath.Abs(filepath.Join("testdata", sha256sum))
 	require.NoError(t, err)
@@ -177,6 +177,74 @@ func (repo *Repository) CalculateBundleSha256Sum(bundlePath string) (string, err
 	return sha256sum(bundlePath)
 }
 
+// ArchivePath returns the path of the archive 'bundleName' was extracted
+// from. The archive is not always kept in the cache.
+func (repo *Repository) ArchivePath(bundleName string) string {
+	return filepath.Join(repo.CacheDir, GetBundleNameWithExtension(bundleName))
+}
+
+// Size returns the disk space used by 'bundleName' in the cache, including
+// its archive
+func (repo *Repository) Size(bundleName string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(filepath.Join(repo.CacheDir, GetBundleNameWithoutExtension(bundleName)), func(_ string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if info, err := os.Stat(repo.ArchivePath(bundleName)); err == nil {
+		size += info.Size()
+	}
+	return size, nil
+}
+
+// Remove deletes 'bundleName' and its archive from the cache
+func (repo *Repository) Remove(bundleName string) error {
+	if err := os.RemoveAll(filepath.Join(repo.CacheDir, GetBundleNameWithoutExtension(bundleName))); err != nil {
+		return err
+	}
+	for _, path := range []string{repo.ArchivePath(bundleName), mirrorFilePath(repo.ArchivePath(bundleName))} {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prune removes the bundles from the cache, except the 'keep' ones with the
+// most recent OpenShift version and the ones listed in 'inUse'. It returns
+// the names of the removed bundles.
+func (repo *Repository) Prune(keep int, inUse []string) ([]string, error) {
+	bundles, err := repo.List()
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for i, bundle := range bundles {
+		if i < keep || slices.Contains(inUse, bundle.GetBundleName()) {
+			continue
+		}
+		logging.Debugf("Removing bundle %s from the cache", bundle.GetBundleName())
+		if err := repo.Remove(bundle.GetBundleName()); err != nil {
+			return removed, errors.Wrapf(err, "cannot remove bundle %s", bundle.GetBundleName())
+		}
+		removed = append(removed, bundle.GetBundleName())
+	}
+	return removed, nil
+}
+
 var defaultRepo = &Repository{
 	CacheDir: constants.MachineCacheDir,
 	OcBinDir: constants.CrcOcBinDir,
@@ -204,3 +272,15 @@ func Extract(ctx context.Context, path string) (*CrcBundleInfo, error) {
 func List() ([]CrcBundleInfo, error) {
 	return defaultRepo.List()
 }
+
+func ArchivePath(bundleName string) string {
+	return defaultRepo.ArchivePath(bundleName)
+}
+
+func Size(bundleName string) (int64, error) {
+	return defaultRepo.Size(bundleName)
+}
+
+func Prune(keep int, inUse []string) ([]string, error) {
+	return defaultRepo.Prune(keep, inUse)
+}
@@ -120,6 +120,51 @@ func TestListBundles(t *testing.T) {
 	}, names)
 }
 
+func TestPruneBundles(t *testing.T) {
+	dir := t.TempDir()
+
+	createDummyBundleContent(t, dir, "crc_libvirt_4.6.15", "1.0")
+	createDummyBundleContent(t, dir, "crc_libvirt_4.7.0", "1.0")
+	createDummyBundleContent(t, dir, "crc_libvirt_4.8.0", "1.0")
+	createDummyBundleContent(t, dir, "crc_libvirt_4.10.0", "1.0")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "crc_libvirt_4.7.0.crcbundle"), []byte("archive"), 0600))
+
+	repo := &Repository{
+		CacheDir: dir,
+	}
+
+	removed, err := repo.Prune(2, []string{"crc_libvirt_4.6.15"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"crc_libvirt_4.7.0"}, removed)
+	assert.NoFileExists(t, filepath.Join(dir, "crc_libvirt_4.7.0.crcbundle"))
+	assert.NoDirExists(t, filepath.Join(dir, "crc_libvirt_4.7.0"))
+
+	bundles, err := repo.List()
+	assert.NoError(t, err)
+	var names []string
+	for _, bundle := range bundles {
+		names = append(names, bundle.GetBundleName())
+	}
+	assert.Equal(t, []string{"crc_libvirt_4.10.0", "crc_libvirt_4.8.0", "crc_libvirt_4.6.15"}, names)
+}
+
+func TestBundleSize(t *testing.T) {
+	dir := t.TempDir()
+
+	createDummyBundleContent(t, dir, "crc_libvirt_4.6.1", "1.0")
+	repo := &Repository{
+		CacheDir: dir,
+	}
+	sizeWithoutArchive, err := repo.Size("crc_libvirt_4.6.1")
+	assert.NoError(t, err)
+	assert.Greater(t, sizeWithoutArchive, int64(0))
+
+	assert.NoError(t, os.WriteFile(repo.ArchivePath("crc_libvirt_4.6.1"), []byte("archive"), 0600))
+	size, err := repo.Size("crc_libvirt_4.6.1.crcbundle")
+	assert.NoError(t, err)
+	assert.Equal(t, sizeWithoutArchive+int64(len("archive")), size)
+}
+
 func createDummyBundleContent(t *testing.T, dir, name, version string) {
 	bundleDir := filepath.Join(dir, name)
 	assert.NoError(t, os.MkdirAll(bundleDir, 0755))
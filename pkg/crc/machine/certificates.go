@@ -0,0 +1,296 @@
+package machine
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
+	"github.com/pkg/errors"
+)
+
+// minimumServingCertValidity is the remaining validity below which the
+// certificates signed by crc are regenerated on start
+const minimumServingCertValidity = 30 * 24 * time.Hour
+
+// servingCertificates are the certificates presented by the cluster instead
+// of the ones of the bundle, they are nil when the bundle ones are used
+type servingCertificates struct {
+	ingressCert []byte
+	ingressKey  []byte
+	apiCert     []byte
+	apiKey      []byte
+	// ca holds the certificates to trust for them
+	ca []byte
+}
+
+// getServingCertificates returns the certificates of the instance 'name',
+// from the custom certificates when they are configured. Otherwise the
+// ingress certificate is signed by the CA of the instance when the cluster
+// serves a custom apps domain.
+func getServingCertificates(name string, bundleInfo *bundle.CrcBundleInfo, appsDomain string, custom types.CustomCertificates) (*servingCertificates, error) {
+	ingressDomains := []string{"*." + bundleInfo.ClusterInfo.AppsDomain}
+	if appsDomain != "" {
+		ingressDomains = append(ingressDomains, "*."+appsDomain)
+	}
+
+	switch {
+	case custom.CertFile != "":
+		return customServingCertificates(custom, append(ingressDomains, bundleInfo.GetAPIHostname()))
+	case custom.CACertFile != "":
+		caKey, caCert, err := loadCA(custom.CACertFile, custom.CAKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		ingressCert, ingressKey, err := signedCertificate(constants.GetIngressCertPath(name), constants.GetIngressKeyPath(name), caKey, caCert, ingressDomains)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to generate the ingress certificate")
+		}
+		apiCert, apiKey, err := signedCertificate(constants.GetAPICertPath(name), constants.GetAPIKeyPath(name), caKey, caCert, []string{bundleInfo.GetAPIHostname()})
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to generate the API server certificate")
+		}
+		return &servingCertificates{
+			ingressCert: ingressCert,
+			ingressKey:  ingressKey,
+			apiCert:     apiCert,
+			apiKey:      apiKey,
+			ca:          crctls.CertToPem(caCert),
+		}, nil
+	case appsDomain != "":
+		ingressCert, ingressKey, err := ingressCertificate(name, bundleInfo.ClusterInfo.AppsDomain, appsDomain)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to generate the ingress certificate")
+		}
+		caPEM, err := os.ReadFile(constants.GetIngressCACertPath(name))
+		if err != nil {
+			return nil, err
+		}
+		return &servingCertificates{
+			ingressCert: ingressCert,
+			ingressKey:  ingressKey,
+			ca:          caPEM,
+		}, nil
+	default:
+		return &servingCertificates{}, nil
+	}
+}
+
+// customServingCertificates uses the pre-issued certificate for both the API
+// server and the ingress. It must be issued by the custom CA, which is the
+// one to trust, and cover all of 'dnsNames'.
+func customServingCertificates(custom types.CustomCertificates, dnsNames []string) (*servingCertificates, error) {
+	certPEM, err := os.ReadFile(custom.CertFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := os.ReadFile(custom.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+	caPEM, err := os.ReadFile(custom.CACertFile)
+	if err != nil {
+		return nil, err
+	}
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid certificate %s", custom.CertFile)
+	}
+	cert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(cert.NotAfter) {
+		return nil, fmt.Errorf("Certificate %s expired on %s", custom.CertFile, cert.NotAfter.Format(time.RFC3339))
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("Invalid CA certificate %s", custom.CACertFile)
+	}
+	intermediates := x509.NewCertPool()
+	for _, der := range keyPair.Certificate[1:] {
+		intermediate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid certificate %s", custom.CertFile)
+		}
+		intermediates.AddCert(intermediate)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates}); err != nil {
+		return nil, errors.Wrapf(err, "Certificate %s is not issued by the CA %s", custom.CertFile, custom.CACertFile)
+	}
+	for _, dnsName := range dnsNames {
+		if err := cert.VerifyHostname(dnsName); err != nil {
+			return nil, fmt.Errorf("Certificate %s is not valid for %s", custom.CertFile, dnsName)
+		}
+	}
+	return &servingCertificates{
+		ingressCert: certPEM,
+		ingressKey:  keyPEM,
+		apiCert:     certPEM,
+		apiKey:      keyPEM,
+		ca:          caPEM,
+	}, nil
+}
+
+// validateCustomCertificates checks that either a CA or a certificate is
+// configured, each with its key. The certificate comes with the certificate
+// of the CA which issued it.
+func validateCustomCertificates(custom types.CustomCertificates) error {
+	if (custom.CertFile == "") != (custom.KeyFile == "") {
+		return fmt.Errorf("Both the custom certificate and key files must be set")
+	}
+	if custom.CertFile != "" {
+		if custom.CACertFile == "" {
+			return fmt.Errorf("The custom certificate requires the custom CA certificate file of its issuer")
+		}
+		if custom.CAKeyFile != "" {
+			return fmt.Errorf("Either a custom CA key or a custom certificate can be set, not both")
+		}
+		return nil
+	}
+	if (custom.CACertFile == "") != (custom.CAKeyFile == "") {
+		return fmt.Errorf("Both the custom CA certificate and key files must be set")
+	}
+	return nil
+}
+
+// configureServingCertificates makes the cluster present the certificates
+// of 'startConfig', or the ones of the bundle when there are none, and
+// serve the routes under its apps domain. It returns the CAs to trust for
+// the certificates. They are applied again when 'force' is true.
+func configureServingCertificates(ctx context.Context, name string, ocConfig oc.Config, sshRunner *crcssh.Runner, bundleInfo *bundle.CrcBundleInfo, startConfig types.StartConfig, force bool) ([]byte, error) {
+	certs, err := getServingCertificates(name, bundleInfo, startConfig.AppsDomain, startConfig.CustomCertificates)
+	if err != nil {
+		return nil, err
+	}
+
+	if certs.ingressCert != nil {
+		err = cluster.EnsureIngressCertificateInTheCluster(ctx, ocConfig, sshRunner, certs.ingressCert, certs.ingressKey, force)
+	} else {
+		err = cluster.RemoveIngressCertificateFromCluster(ctx, ocConfig)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to configure the ingress certificate")
+	}
+	// the console and the OAuth server reach the routes through the ingress
+	if certs.ingressCert != nil {
+		err = cluster.EnsureIngressCAInTheProxyTrustedCA(ctx, ocConfig, sshRunner, certs.ca)
+	} else {
+		err = cluster.RemoveIngressCAFromProxyTrustedCA(ctx, ocConfig, sshRunner)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to configure the trusted CA of the cluster")
+	}
+	if err := configureAppsDomain(ctx, ocConfig, startConfig.AppsDomain); err != nil {
+		return nil, errors.Wrap(err, "Failed to configure the apps domain")
+	}
+
+	if certs.apiCert != nil {
+		// oc must trust the new certificate before the API server presents it
+		kubeconfigFilePath := constants.GetKubeconfigFilePath(name)
+		updated, caErr := addCAToKubeconfig(kubeconfigFilePath, certs.ca)
+		if caErr != nil {
+			return nil, errors.Wrap(caErr, "Failed to add the custom CA to the kubeconfig")
+		}
+		if updated {
+			if err := sshRunner.CopyFile(kubeconfigFilePath, ocConfig.KubeconfigPath, 0644); err != nil {
+				return nil, errors.Wrap(err, "Failed to copy kubeconfig file to VM")
+			}
+		}
+		err = cluster.EnsureAPIServerCertificateInTheCluster(ctx, ocConfig, sshRunner, bundleInfo.GetAPIHostname(), certs.apiCert, certs.apiKey, force)
+	} else {
+		err = cluster.RemoveAPIServerCertificateFromCluster(ctx, ocConfig)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to configure the API server certificate")
+	}
+	return certs.ca, nil
+}
+
+// signedCertificate returns the certificate for 'dnsNames' signed by the
+// CA, followed by the CA, and its key. They are stored in 'certPath' and
+// 'keyPath' and reused until they are about to expire, the CA or the names
+// change.
+func signedCertificate(certPath, keyPath string, caKey crypto.Signer, caCert *x509.Certificate, dnsNames []string) ([]byte, []byte, error) {
+	certPEM, certErr := os.ReadFile(certPath)
+	keyPEM, keyErr := os.ReadFile(keyPath)
+	if certErr == nil && keyErr == nil {
+		cert, err := crctls.PemToCert(certPEM)
+		if err == nil && isSignedCertUsable(cert, caCert, dnsNames) {
+			return certPEM, keyPEM, nil
+		}
+	}
+
+	logging.Debugf("Generating a certificate for %v", dnsNames)
+	keyPEM, certPEM, err := crctls.GenerateServingCertificate(caKey, caCert, dnsNames...)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM = append(certPEM, crctls.CertToPem(caCert)...)
+	if err := os.WriteFile(certPath, certPEM, 0600); err != nil {
+		return nil, nil, err
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return nil, nil, err
+	}
+	return certPEM, keyPEM, nil
+}
+
+func isSignedCertUsable(cert, caCert *x509.Certificate, dnsNames []string) bool {
+	if cert.CheckSignatureFrom(caCert) != nil {
+		return false
+	}
+	if time.Until(cert.NotAfter) < minimumServingCertValidity {
+		return false
+	}
+	if len(cert.DNSNames) != len(dnsNames) {
+		return false
+	}
+	for _, dnsName := range dnsNames {
+		if !slices.Contains(cert.DNSNames, dnsName) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadCA loads the CA certificate and its key, the key may be RSA, ECDSA or
+// Ed25519 in any of the pem encodings of PemToSigner
+func loadCA(certFile, keyFile string) (crypto.Signer, *x509.Certificate, error) {
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := crctls.PemToCert(certPEM)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Invalid CA certificate %s", certFile)
+	}
+	if !cert.IsCA {
+		return nil, nil, fmt.Errorf("Certificate %s is not a CA", certFile)
+	}
+	key, err := crctls.PemToSigner(keyPEM)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Invalid CA key %s", keyFile)
+	}
+	if !crctls.KeyMatchesCertificate(key, cert) {
+		return nil, nil, fmt.Errorf("Key %s does not match the CA certificate %s", keyFile, certFile)
+	}
+	return key, cert, nil
+}
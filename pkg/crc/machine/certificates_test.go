@@ -0,0 +1,172 @@
+package machine
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedCertificate(t *testing.T) {
+	machineInstanceDir := constants.MachineInstanceDir
+	constants.MachineInstanceDir = t.TempDir()
+	defer func() {
+		constants.MachineInstanceDir = machineInstanceDir
+	}()
+	require.NoError(t, os.MkdirAll(constants.GetInstanceDir("crc"), 0750))
+	certPath, keyPath := constants.GetIngressCertPath("crc"), constants.GetIngressKeyPath("crc")
+
+	caKey, caCert, err := ingressCA("crc")
+	require.NoError(t, err)
+	certPEM, keyPEM, err := signedCertificate(certPath, keyPath, caKey, caCert, []string{"*.apps-crc.testing", "*.apps.mycompany.test"})
+	require.NoError(t, err)
+	cert, err := crctls.PemToCert(certPEM)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.apps-crc.testing", "*.apps.mycompany.test"}, cert.DNSNames)
+	_, err = crctls.PemToPrivateKey(keyPEM)
+	require.NoError(t, err)
+	assert.Contains(t, string(certPEM), string(crctls.CertToPem(caCert)))
+
+	// the CA of the instance is generated once
+	_, sameCACert, err := ingressCA("crc")
+	require.NoError(t, err)
+	assert.Equal(t, caCert, sameCACert)
+
+	// the certificate is reused as long as the CA and the names are the same
+	sameCertPEM, _, err := signedCertificate(certPath, keyPath, caKey, caCert, []string{"*.apps.mycompany.test", "*.apps-crc.testing"})
+	require.NoError(t, err)
+	assert.Equal(t, certPEM, sameCertPEM)
+
+	otherCertPEM, _, err := signedCertificate(certPath, keyPath, caKey, caCert, []string{"*.apps-crc.testing", "*.apps.example.test"})
+	require.NoError(t, err)
+	assert.NotEqual(t, certPEM, otherCertPEM)
+
+	otherCAKey, otherCACert, err := crctls.GetSelfSignedIngressCA()
+	require.NoError(t, err)
+	otherCACertPEM, _, err := signedCertificate(certPath, keyPath, otherCAKey, otherCACert, []string{"*.apps-crc.testing", "*.apps.example.test"})
+	require.NoError(t, err)
+	otherCert, err := crctls.PemToCert(otherCACertPEM)
+	require.NoError(t, err)
+	assert.NoError(t, otherCert.CheckSignatureFrom(otherCACert))
+}
+
+func TestLoadCAWithPKCS8AndECKeys(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	ecPKCS8DER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	rsaKey, err := crctls.PrivateKey()
+	require.NoError(t, err)
+	rsaPKCS8DER, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		key   crypto.Signer
+		block *pem.Block
+	}{
+		{"EC", ecKey, &pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}},
+		{"PKCS#8 EC", ecKey, &pem.Block{Type: "PRIVATE KEY", Bytes: ecPKCS8DER}},
+		{"PKCS#8 RSA", rsaKey, &pem.Block{Type: "PRIVATE KEY", Bytes: rsaPKCS8DER}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: "custom-ca"},
+				NotBefore:             time.Now(),
+				NotAfter:              time.Now().Add(time.Hour),
+				KeyUsage:              x509.KeyUsageCertSign,
+				BasicConstraintsValid: true,
+				IsCA:                  true,
+			}
+			caDER, err := x509.CreateCertificate(rand.Reader, template, template, test.key.Public(), test.key)
+			require.NoError(t, err)
+			caCertFile, caKeyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+			require.NoError(t, os.WriteFile(caCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}), 0600))
+			require.NoError(t, os.WriteFile(caKeyFile, pem.EncodeToMemory(test.block), 0600))
+
+			caKey, caCert, err := loadCA(caCertFile, caKeyFile)
+			require.NoError(t, err)
+			certPEM, _, err := signedCertificate(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), caKey, caCert, []string{"api.crc.testing"})
+			require.NoError(t, err)
+			cert, err := crctls.PemToCert(certPEM)
+			require.NoError(t, err)
+			assert.NoError(t, cert.CheckSignatureFrom(caCert))
+		})
+	}
+
+	dir := t.TempDir()
+	_, otherCACert, err := crctls.GetSelfSignedIngressCA()
+	require.NoError(t, err)
+	caCertFile, caKeyFile := filepath.Join(dir, "ca.crt"), filepath.Join(dir, "ca.key")
+	require.NoError(t, os.WriteFile(caCertFile, crctls.CertToPem(otherCACert), 0600))
+	require.NoError(t, os.WriteFile(caKeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecDER}), 0600))
+	_, _, err = loadCA(caCertFile, caKeyFile)
+	assert.ErrorContains(t, err, "does not match the CA certificate")
+}
+
+func TestCustomServingCertificates(t *testing.T) {
+	dir := t.TempDir()
+	caKey, caCert, err := crctls.GetSelfSignedIngressCA()
+	require.NoError(t, err)
+	keyPEM, certPEM, err := crctls.GenerateServingCertificate(caKey, caCert, "api.crc.testing", "*.apps-crc.testing")
+	require.NoError(t, err)
+	_, otherCACert, err := crctls.GetSelfSignedIngressCA()
+	require.NoError(t, err)
+	custom := types.CustomCertificates{
+		CACertFile: filepath.Join(dir, "ca.crt"),
+		CertFile:   filepath.Join(dir, "tls.crt"),
+		KeyFile:    filepath.Join(dir, "tls.key"),
+	}
+	otherCACertFile := filepath.Join(dir, "other-ca.crt")
+	require.NoError(t, os.WriteFile(custom.CACertFile, crctls.CertToPem(caCert), 0600))
+	require.NoError(t, os.WriteFile(otherCACertFile, crctls.CertToPem(otherCACert), 0600))
+	require.NoError(t, os.WriteFile(custom.CertFile, certPEM, 0600))
+	require.NoError(t, os.WriteFile(custom.KeyFile, keyPEM, 0600))
+
+	certs, err := customServingCertificates(custom, []string{"*.apps-crc.testing", "api.crc.testing"})
+	require.NoError(t, err)
+	assert.Equal(t, certPEM, certs.ingressCert)
+	assert.Equal(t, certPEM, certs.apiCert)
+	assert.Equal(t, crctls.CertToPem(caCert), certs.ca)
+
+	_, err = customServingCertificates(custom, []string{"*.apps-crc.testing", "*.apps.mycompany.test", "api.crc.testing"})
+	assert.EqualError(t, err, fmt.Sprintf("Certificate %s is not valid for *.apps.mycompany.test", custom.CertFile))
+
+	custom.CACertFile = otherCACertFile
+	_, err = customServingCertificates(custom, []string{"*.apps-crc.testing", "api.crc.testing"})
+	assert.ErrorContains(t, err, fmt.Sprintf("Certificate %s is not issued by the CA %s", custom.CertFile, otherCACertFile))
+}
+
+func TestValidateCustomCertificates(t *testing.T) {
+	assert.NoError(t, validateCustomCertificates(types.CustomCertificates{}))
+	assert.NoError(t, validateCustomCertificates(types.CustomCertificates{CACertFile: "ca.crt", CAKeyFile: "ca.key"}))
+	assert.NoError(t, validateCustomCertificates(types.CustomCertificates{CACertFile: "ca.crt", CertFile: "tls.crt", KeyFile: "tls.key"}))
+	assert.EqualError(t, validateCustomCertificates(types.CustomCertificates{CACertFile: "ca.crt"}),
+		"Both the custom CA certificate and key files must be set")
+	assert.EqualError(t, validateCustomCertificates(types.CustomCertificates{KeyFile: "tls.key"}),
+		"Both the custom certificate and key files must be set")
+	assert.EqualError(t, validateCustomCertificates(types.CustomCertificates{CertFile: "tls.crt", KeyFile: "tls.key"}),
+		"The custom certificate requires the custom CA certificate file of its issuer")
+	assert.EqualError(t, validateCustomCertificates(types.CustomCertificates{CACertFile: "ca.crt", CAKeyFile: "ca.key", CertFile: "tls.crt", KeyFile: "tls.key"}),
+		"Either a custom CA key or a custom certificate can be set, not both")
+}
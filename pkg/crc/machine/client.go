@@ -5,11 +5,13 @@ import (
 	"time"
 
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
 	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/kofalt/go-memoize"
+	"go.podman.io/common/pkg/strongunits"
 )
 
 type Client interface {
@@ -27,6 +29,27 @@ type Client interface {
 	IsRunning() (bool, error)
 	GenerateBundle(forceStop bool) error
 	GetPreset() crcPreset.Preset
+
+	CreateSnapshot(name string) (*types.SnapshotInfo, error)
+	ListSnapshots() ([]types.SnapshotInfo, error)
+	RestoreSnapshot(name string) error
+	DeleteSnapshot(name string) error
+
+	Diagnose(ctx context.Context) (*types.DiagnoseResult, error)
+
+	ExposePort(forward types.PortForward) (*types.PortForward, error)
+	UnexposePort(protocol, local string) error
+	ListPortForwards() ([]types.PortForwardInfo, error)
+
+	Resize(resizeConfig types.ResizeConfig) (*types.ResizeResult, error)
+
+	CreateVolume(name string, size strongunits.GiB) (*types.VolumeInfo, error)
+	DeleteVolume(name string) error
+	AttachVolume(name string) (*types.VolumeInfo, error)
+	DetachVolume(name string) error
+	ListVolumes() ([]types.VolumeInfo, error)
+	MountSharedDir(dir config.SharedDir) (bool, error)
+	UnmountSharedDir(target string) (bool, error)
 }
 
 type client struct {
@@ -71,3 +94,11 @@ func (client *client) modifyHostsFile() bool {
 func (client *client) monitoringEnabled() bool {
 	return client.config.Get(crcConfig.EnableClusterMonitoring).AsBool()
 }
+
+func (client *client) trustClusterCA() bool {
+	return client.config.Get(crcConfig.TrustClusterCA).AsBool()
+}
+
+func (client *client) registryCacheRegistries() []string {
+	return crcConfig.GetRegistryCacheRegistries(client.config)
+}
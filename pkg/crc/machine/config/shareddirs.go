@@ -0,0 +1,131 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrInvalidSharedDir is returned for the shared directories which
+	// cannot be mounted in the VM
+	ErrInvalidSharedDir = errors.New("invalid shared directory")
+	// ErrSharedDirNotFound is returned when removing a directory which is
+	// not in the 'shared-dirs' setting
+	ErrSharedDirNotFound = errors.New("shared directory not found")
+)
+
+var ownerRegex = regexp.MustCompile(`^[0-9]+:[0-9]+$`)
+
+// SharedDir is a host directory shared with the VM in addition to the home
+// directory, as stored in the 'shared-dirs' config setting
+type SharedDir struct {
+	// Source is the directory on the host
+	Source string `json:"source"`
+	// Target is the mount point in the VM, it defaults to Source
+	Target   string `json:"target,omitempty"`
+	ReadOnly bool   `json:"readOnly,omitempty"`
+	// Owner is the UID:GID owning the files of the host user in the VM
+	Owner string `json:"owner,omitempty"`
+}
+
+func (dir SharedDir) String() string {
+	entry := dir.Source
+	if dir.Target != dir.Source {
+		entry = fmt.Sprintf("%s=%s", entry, dir.Target)
+	}
+	if dir.ReadOnly {
+		entry += ";ro"
+	}
+	if dir.Owner != "" {
+		entry = fmt.Sprintf("%s;owner=%s", entry, dir.Owner)
+	}
+	return entry
+}
+
+// NormalizeSharedDir checks that the source of 'dir' is an existing
+// directory and that its target is an absolute path of the VM, and fills
+// in the default target
+func NormalizeSharedDir(dir SharedDir) (SharedDir, error) {
+	if !filepath.IsAbs(dir.Source) {
+		return dir, fmt.Errorf("%w: '%s' is not an absolute path", ErrInvalidSharedDir, dir.Source)
+	}
+	dir.Source = filepath.Clean(dir.Source)
+	info, err := os.Stat(dir.Source)
+	if err != nil {
+		return dir, fmt.Errorf("%w: %v", ErrInvalidSharedDir, err)
+	}
+	if !info.IsDir() {
+		return dir, fmt.Errorf("%w: '%s' is not a directory", ErrInvalidSharedDir, dir.Source)
+	}
+	if dir.Target == "" {
+		dir.Target = filepath.ToSlash(dir.Source)
+	}
+	if !path.IsAbs(dir.Target) {
+		return dir, fmt.Errorf("%w: the target '%s' is not an absolute path", ErrInvalidSharedDir, dir.Target)
+	}
+	dir.Target = path.Clean(dir.Target)
+	if dir.Target == "/" {
+		return dir, fmt.Errorf("%w: '/' cannot be used as target", ErrInvalidSharedDir)
+	}
+	if dir.Owner != "" && !ownerRegex.MatchString(dir.Owner) {
+		return dir, fmt.Errorf("%w: the owner '%s' is not UID:GID", ErrInvalidSharedDir, dir.Owner)
+	}
+	return dir, nil
+}
+
+// ParseSharedDirs parses a comma-separated list of
+// source[=target][;ro][;owner=UID:GID] entries, as stored in the
+// 'shared-dirs' config setting
+func ParseSharedDirs(value string) ([]SharedDir, error) {
+	var dirs []SharedDir
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		fields := strings.Split(entry, ";")
+		var dir SharedDir
+		dir.Source, dir.Target, _ = strings.Cut(fields[0], "=")
+		for _, option := range fields[1:] {
+			switch {
+			case option == "ro":
+				dir.ReadOnly = true
+			case strings.HasPrefix(option, "owner="):
+				dir.Owner = strings.TrimPrefix(option, "owner=")
+			default:
+				return nil, fmt.Errorf("%w: unknown option '%s' for '%s'", ErrInvalidSharedDir, option, dir.Source)
+			}
+		}
+		dir, err := NormalizeSharedDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		dirs = SetSharedDir(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// FormatSharedDirs is the reverse of ParseSharedDirs
+func FormatSharedDirs(dirs []SharedDir) string {
+	var entries []string
+	for _, dir := range dirs {
+		entries = append(entries, dir.String())
+	}
+	return strings.Join(entries, ",")
+}
+
+// SetSharedDir adds 'dir' to 'dirs', replacing the directory with the same
+// target
+func SetSharedDir(dirs []SharedDir, dir SharedDir) []SharedDir {
+	for i, existing := range dirs {
+		if existing.Target == dir.Target {
+			dirs[i] = dir
+			return dirs
+		}
+	}
+	return append(dirs, dir)
+}
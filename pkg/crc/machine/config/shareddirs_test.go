@@ -0,0 +1,46 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSharedDirs(t *testing.T) {
+	dir := t.TempDir()
+	data := filepath.Join(dir, "data")
+	require.NoError(t, os.Mkdir(data, 0750))
+	target := filepath.ToSlash(data)
+
+	dirs, err := ParseSharedDirs(fmt.Sprintf("%s=/mnt/src/;ro, %s ,%s;owner=1000:1000,", dir, data, data))
+	require.NoError(t, err)
+	assert.Equal(t, []SharedDir{
+		{Source: dir, Target: "/mnt/src", ReadOnly: true},
+		{Source: data, Target: target, Owner: "1000:1000"},
+	}, dirs)
+	assert.Equal(t, fmt.Sprintf("%s=/mnt/src;ro,%s;owner=1000:1000", dir, data), FormatSharedDirs(dirs))
+
+	dirs, err = ParseSharedDirs("")
+	assert.NoError(t, err)
+	assert.Empty(t, dirs)
+
+	for value, expected := range map[string]string{
+		"data":                                  "invalid shared directory: 'data' is not an absolute path",
+		dir + "=mnt":                            "invalid shared directory: the target 'mnt' is not an absolute path",
+		dir + "=/":                              "invalid shared directory: '/' cannot be used as target",
+		dir + ";rw":                             fmt.Sprintf("invalid shared directory: unknown option 'rw' for '%s'", dir),
+		dir + ";owner=core":                     "invalid shared directory: the owner 'core' is not UID:GID",
+		filepath.Join(dir, "missing") + "=/mnt": "",
+	} {
+		_, err := ParseSharedDirs(value)
+		assert.True(t, errors.Is(err, ErrInvalidSharedDir))
+		if expected != "" {
+			assert.EqualError(t, err, expected)
+		}
+	}
+}
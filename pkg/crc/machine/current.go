@@ -0,0 +1,161 @@
+package machine
+
+import (
+	"context"
+	"sync"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"go.podman.io/common/pkg/strongunits"
+)
+
+// CurrentInstance is a Client forwarding all the calls to the client of the
+// instance named by the 'instance' setting. This is used by long running
+// processes such as the daemon, which must follow 'crc instance switch'.
+type CurrentInstance struct {
+	config    crcConfig.Storage
+	newClient func(name string) Client
+
+	clientsLock sync.Mutex
+	clients     map[string]Client
+}
+
+func NewCurrentInstance(config crcConfig.Storage, newClient func(name string) Client) *CurrentInstance {
+	return &CurrentInstance{
+		config:    config,
+		newClient: newClient,
+		clients:   map[string]Client{},
+	}
+}
+
+func (c *CurrentInstance) current() Client {
+	name := crcConfig.GetInstanceName(c.config)
+
+	c.clientsLock.Lock()
+	defer c.clientsLock.Unlock()
+	client, ok := c.clients[name]
+	if !ok {
+		client = c.newClient(name)
+		c.clients[name] = client
+	}
+	return client
+}
+
+func (c *CurrentInstance) GetName() string {
+	return c.current().GetName()
+}
+
+func (c *CurrentInstance) GetConsoleURL() (*types.ConsoleResult, error) {
+	return c.current().GetConsoleURL()
+}
+
+func (c *CurrentInstance) ConnectionDetails() (*types.ConnectionDetails, error) {
+	return c.current().ConnectionDetails()
+}
+
+func (c *CurrentInstance) Delete() error {
+	return c.current().Delete()
+}
+
+func (c *CurrentInstance) Exists() (bool, error) {
+	return c.current().Exists()
+}
+
+func (c *CurrentInstance) PowerOff() error {
+	return c.current().PowerOff()
+}
+
+func (c *CurrentInstance) Start(ctx context.Context, startConfig types.StartConfig) (*types.StartResult, error) {
+	return c.current().Start(ctx, startConfig)
+}
+
+func (c *CurrentInstance) Status() (*types.ClusterStatusResult, error) {
+	return c.current().Status()
+}
+
+func (c *CurrentInstance) GetClusterLoad() (*types.ClusterLoadResult, error) {
+	return c.current().GetClusterLoad()
+}
+
+func (c *CurrentInstance) Stop() (state.State, error) {
+	return c.current().Stop()
+}
+
+func (c *CurrentInstance) IsRunning() (bool, error) {
+	return c.current().IsRunning()
+}
+
+func (c *CurrentInstance) GenerateBundle(forceStop bool) error {
+	return c.current().GenerateBundle(forceStop)
+}
+
+func (c *CurrentInstance) GetPreset() crcPreset.Preset {
+	return c.current().GetPreset()
+}
+
+func (c *CurrentInstance) CreateSnapshot(name string) (*types.SnapshotInfo, error) {
+	return c.current().CreateSnapshot(name)
+}
+
+func (c *CurrentInstance) ListSnapshots() ([]types.SnapshotInfo, error) {
+	return c.current().ListSnapshots()
+}
+
+func (c *CurrentInstance) RestoreSnapshot(name string) error {
+	return c.current().RestoreSnapshot(name)
+}
+
+func (c *CurrentInstance) DeleteSnapshot(name string) error {
+	return c.current().DeleteSnapshot(name)
+}
+
+func (c *CurrentInstance) Diagnose(ctx context.Context) (*types.DiagnoseResult, error) {
+	return c.current().Diagnose(ctx)
+}
+
+func (c *CurrentInstance) ExposePort(forward types.PortForward) (*types.PortForward, error) {
+	return c.current().ExposePort(forward)
+}
+
+func (c *CurrentInstance) UnexposePort(protocol, local string) error {
+	return c.current().UnexposePort(protocol, local)
+}
+
+func (c *CurrentInstance) ListPortForwards() ([]types.PortForwardInfo, error) {
+	return c.current().ListPortForwards()
+}
+
+func (c *CurrentInstance) Resize(resizeConfig types.ResizeConfig) (*types.ResizeResult, error) {
+	return c.current().Resize(resizeConfig)
+}
+
+func (c *CurrentInstance) CreateVolume(name string, size strongunits.GiB) (*types.VolumeInfo, error) {
+	return c.current().CreateVolume(name, size)
+}
+
+func (c *CurrentInstance) DeleteVolume(name string) error {
+	return c.current().DeleteVolume(name)
+}
+
+func (c *CurrentInstance) AttachVolume(name string) (*types.VolumeInfo, error) {
+	return c.current().AttachVolume(name)
+}
+
+func (c *CurrentInstance) DetachVolume(name string) error {
+	return c.current().DetachVolume(name)
+}
+
+func (c *CurrentInstance) ListVolumes() ([]types.VolumeInfo, error) {
+	return c.current().ListVolumes()
+}
+
+func (c *CurrentInstance) MountSharedDir(dir config.SharedDir) (bool, error) {
+	return c.current().MountSharedDir(dir)
+}
+
+func (c *CurrentInstance) UnmountSharedDir(target string) (bool, error) {
+	return c.current().UnmountSharedDir(target)
+}
@@ -19,6 +19,10 @@ func (client *client) Delete() error {
 		return errors.Wrap(err, "Cannot remove machine")
 	}
 
+	if err := releaseVolumes(client.name); err != nil {
+		logging.Warnf("Failed to detach the volumes of the instance: %v", err)
+	}
+
 	// In case usermode networking make sure all the port bind on host should be released
 	if client.useVSock() {
 		if err := unexposePorts(); err != nil {
@@ -26,7 +30,7 @@ func (client *client) Delete() error {
 		}
 	}
 
-	if err := cleanKubeconfig(getGlobalKubeConfigPath(), getGlobalKubeConfigPath()); err != nil {
+	if err := cleanKubeconfig(client.name, getGlobalKubeConfigPath(), getGlobalKubeConfigPath()); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			logging.Warnf("Failed to remove crc contexts from kubeconfig: %v", err)
 		}
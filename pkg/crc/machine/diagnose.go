@@ -0,0 +1,148 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/pkg/errors"
+)
+
+const (
+	// inspectDir is the directory in the VM where 'oc adm inspect' writes
+	// the cluster operators resources
+	inspectDir = "/tmp/crc-diagnose-inspect"
+	// inspectTimeout is the timeout of 'oc adm inspect', which can take
+	// a few minutes
+	inspectTimeout = "5m"
+)
+
+type diagnoseResult struct {
+	types.DiagnoseResult
+}
+
+func (r *diagnoseResult) add(name string, content []byte) {
+	r.Files = append(r.Files, types.DiagnosticFile{Name: name, Content: content})
+}
+
+func (r *diagnoseResult) addError(what string, err error) {
+	logging.Debugf("Cannot collect %s: %v", what, err)
+	r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", what, err))
+}
+
+// Diagnose collects the bundle metadata, the VM journal and the state of
+// the cluster operators. The failure to collect one of them is reported
+// in the Errors of the result instead of stopping the collection.
+func (client *client) Diagnose(ctx context.Context) (*types.DiagnoseResult, error) {
+	result := &diagnoseResult{}
+	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	if err != nil {
+		if errors.Is(err, errMissingHost(client.name)) {
+			result.add("vm/state.txt", []byte("The virtual machine does not exist\n"))
+			return &result.DiagnoseResult, nil
+		}
+		if !errors.Is(err, errInvalidBundleMetadata) {
+			return nil, errors.Wrap(err, fmt.Sprintf("Cannot load '%s' virtual machine", client.name))
+		}
+		result.addError("bundle metadata", err)
+	}
+	defer vm.Close()
+
+	if vm.bundle != nil {
+		bundleInfo, err := json.MarshalIndent(vm.bundle, "", "  ")
+		if err != nil {
+			result.addError("bundle metadata", err)
+		} else {
+			result.add("bundle-info.json", bundleInfo)
+		}
+	}
+
+	vmState, err := vm.State()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get machine state")
+	}
+	result.add("vm/state.txt", []byte(fmt.Sprintf("%s\n", vmState)))
+	if vmState != state.Running {
+		return &result.DiagnoseResult, nil
+	}
+
+	sshRunner, err := vm.SSHRunner()
+	if err != nil {
+		result.addError("VM journal", err)
+		return &result.DiagnoseResult, nil
+	}
+	defer sshRunner.Close()
+
+	journal, _, err := sshRunner.RunPrivate("sudo", "journalctl", "--no-pager", "--boot")
+	if err != nil {
+		result.addError("VM journal", err)
+	} else {
+		result.add("vm/journal.log", []byte(journal))
+	}
+
+	if vm.bundle == nil || !vm.bundle.IsOpenShift() {
+		return &result.DiagnoseResult, nil
+	}
+
+	for _, passwordPath := range []string{constants.GetKubeAdminPasswordPath(client.name), constants.GetDeveloperPasswordPath(client.name)} {
+		if password, err := cluster.GetUserPassword(passwordPath); err == nil {
+			result.Secrets = append(result.Secrets, password)
+		}
+	}
+
+	ip, err := vm.IP()
+	if err != nil {
+		result.addError("cluster operators status", err)
+	} else if status, err := cluster.GetClusterOperatorsStatus(ctx, ip, constants.GetKubeconfigFilePath(client.name)); err != nil {
+		result.addError("cluster operators status", err)
+	} else {
+		total, available, progressing, degraded := status.Counts()
+		result.add("cluster/operators-status.txt", []byte(fmt.Sprintf("%s\ntotal: %d, available: %d, progressing: %d, degraded: %d\n",
+			status, total, available, progressing, degraded)))
+	}
+
+	ocConfig := oc.UseOCWithSSH(sshRunner)
+	ocConfig.Timeout = inspectTimeout
+	if operators, _, err := ocConfig.RunOcCommand("get", "clusteroperators", "-o", "wide"); err != nil {
+		result.addError("cluster operators", err)
+	} else {
+		result.add("cluster/clusteroperators.txt", []byte(operators))
+	}
+
+	if _, _, err := ocConfig.RunOcCommand("adm", "inspect", "clusteroperators", "--dest-dir", inspectDir); err != nil {
+		result.addError("oc adm inspect", err)
+		return &result.DiagnoseResult, nil
+	}
+	defer func() {
+		if _, _, err := sshRunner.Run("rm", "-rf", inspectDir); err != nil {
+			logging.Debugf("Cannot remove %s: %v", inspectDir, err)
+		}
+	}()
+	// the file names are separated by NUL characters, they can contain spaces
+	files, _, err := sshRunner.Run("find", inspectDir, "-type", "f", "-print0")
+	if err != nil {
+		result.addError("oc adm inspect", err)
+		return &result.DiagnoseResult, nil
+	}
+	for _, file := range strings.Split(strings.TrimSuffix(files, "\x00"), "\x00") {
+		if file == "" {
+			continue
+		}
+		content, _, err := sshRunner.RunPrivate(ssh.QuoteCommand([]string{"cat", file}))
+		if err != nil {
+			result.addError(file, err)
+			continue
+		}
+		result.add("cluster/inspect/"+strings.TrimPrefix(file, inspectDir+"/"), []byte(content))
+	}
+
+	return &result.DiagnoseResult, nil
+}
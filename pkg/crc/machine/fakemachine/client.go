@@ -3,11 +3,14 @@ package fakemachine
 import (
 	"context"
 	"errors"
+	"time"
 
+	"github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/crc-org/crc/v2/pkg/crc/network/httpproxy"
 	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"go.podman.io/common/pkg/strongunits"
 )
 
 func NewClient() *Client {
@@ -22,6 +25,8 @@ func NewFailingClient() *Client {
 
 type Client struct {
 	Failing bool
+	// StartDuration is how long Start takes
+	StartDuration time.Duration
 }
 
 var DummyClusterConfig = types.ClusterConfig{
@@ -79,6 +84,7 @@ func (c *Client) GenerateBundle(_ bool) error {
 }
 
 func (c *Client) Start(_ context.Context, _ types.StartConfig) (*types.StartResult, error) {
+	time.Sleep(c.StartDuration)
 	if c.Failing {
 		return nil, errors.New("Failed to start")
 	}
@@ -126,3 +132,152 @@ func (c *Client) GetPreset() preset.Preset {
 func (c *Client) GetClusterLoad() (*types.ClusterLoadResult, error) {
 	return nil, errors.New("not implemented")
 }
+
+var DummySnapshot = types.SnapshotInfo{
+	Name:         "clean",
+	CreationTime: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC),
+	BundleName:   "crc_libvirt_4.15.0_amd64.crcbundle",
+	DiskSize:     1_000_000_000,
+}
+
+func (c *Client) CreateSnapshot(name string) (*types.SnapshotInfo, error) {
+	if c.Failing {
+		return nil, errors.New("snapshot failed")
+	}
+	snapshot := DummySnapshot
+	snapshot.Name = name
+	return &snapshot, nil
+}
+
+func (c *Client) ListSnapshots() ([]types.SnapshotInfo, error) {
+	if c.Failing {
+		return nil, errors.New("snapshot list failed")
+	}
+	return []types.SnapshotInfo{DummySnapshot}, nil
+}
+
+func (c *Client) RestoreSnapshot(_ string) error {
+	if c.Failing {
+		return errors.New("snapshot restore failed")
+	}
+	return nil
+}
+
+func (c *Client) DeleteSnapshot(_ string) error {
+	if c.Failing {
+		return errors.New("snapshot delete failed")
+	}
+	return nil
+}
+
+func (c *Client) Diagnose(_ context.Context) (*types.DiagnoseResult, error) {
+	if c.Failing {
+		return nil, errors.New("diagnose failed")
+	}
+	return &types.DiagnoseResult{
+		Files: []types.DiagnosticFile{
+			{Name: "vm/state.txt", Content: []byte("Running\n")},
+			{Name: "vm/journal.log", Content: []byte("kubelet: using password dummy-kubeadmin-password\n")},
+		},
+		Secrets: []string{"dummy-kubeadmin-password"},
+	}, nil
+}
+
+var DummyPortForward = types.PortForward{
+	Protocol: "tcp",
+	Local:    "127.0.0.1:5432",
+	Remote:   "192.168.127.2:30432",
+}
+
+func (c *Client) ExposePort(forward types.PortForward) (*types.PortForward, error) {
+	if c.Failing {
+		return nil, errors.New("port expose failed")
+	}
+	return &forward, nil
+}
+
+func (c *Client) UnexposePort(_, _ string) error {
+	if c.Failing {
+		return errors.New("port unexpose failed")
+	}
+	return nil
+}
+
+func (c *Client) ListPortForwards() ([]types.PortForwardInfo, error) {
+	if c.Failing {
+		return nil, errors.New("port list failed")
+	}
+	return []types.PortForwardInfo{{PortForward: DummyPortForward, Active: true}}, nil
+}
+
+func (c *Client) Resize(resizeConfig types.ResizeConfig) (*types.ResizeResult, error) {
+	if c.Failing {
+		return nil, errors.New("resize failed")
+	}
+	return &types.ResizeResult{
+		CPUs:     resizeConfig.CPUs,
+		Memory:   resizeConfig.Memory,
+		DiskSize: resizeConfig.DiskSize,
+		Changed:  []string{"cpus", "memory", "disk-size"},
+	}, nil
+}
+
+var DummyVolume = types.VolumeInfo{
+	Name: "data",
+	Size: 10,
+}
+
+func (c *Client) CreateVolume(name string, size strongunits.GiB) (*types.VolumeInfo, error) {
+	if c.Failing {
+		return nil, errors.New("volume create failed")
+	}
+	return &types.VolumeInfo{Name: name, Size: size}, nil
+}
+
+func (c *Client) DeleteVolume(_ string) error {
+	if c.Failing {
+		return errors.New("volume delete failed")
+	}
+	return nil
+}
+
+func (c *Client) AttachVolume(name string) (*types.VolumeInfo, error) {
+	if c.Failing {
+		return nil, errors.New("volume attach failed")
+	}
+	return &types.VolumeInfo{
+		Name:             name,
+		Size:             DummyVolume.Size,
+		Instance:         "crc",
+		Device:           "vdb",
+		PersistentVolume: "crc-volume-" + name,
+	}, nil
+}
+
+func (c *Client) DetachVolume(_ string) error {
+	if c.Failing {
+		return errors.New("volume detach failed")
+	}
+	return nil
+}
+
+func (c *Client) ListVolumes() ([]types.VolumeInfo, error) {
+	if c.Failing {
+		return nil, errors.New("volume list failed")
+	}
+	return []types.VolumeInfo{DummyVolume}, nil
+}
+
+func (c *Client) MountSharedDir(_ config.SharedDir) (bool, error) {
+	if c.Failing {
+		return false, errors.New("shared dir mount failed")
+	}
+	return true, nil
+}
+
+func (c *Client) UnmountSharedDir(_ string) (bool, error) {
+	if c.Failing {
+		return false, errors.New("shared dir unmount failed")
+	}
+	return true, nil
+}
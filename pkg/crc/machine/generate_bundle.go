@@ -76,7 +76,7 @@ func (client *client) GenerateBundle(forceStop bool) error {
 		return err
 	}
 
-	if err := copier.CopyPrivateSSHKey(constants.GetPrivateKeyPath()); err != nil {
+	if err := copier.CopyPrivateSSHKey(constants.GetPrivateKeyPath(client.name)); err != nil {
 		return err
 	}
 
@@ -87,7 +87,7 @@ func (client *client) GenerateBundle(forceStop bool) error {
 	// Copy disk image
 	logging.Infof("Copying the disk image to %s", customBundleNameWithoutExtension)
 	logging.Debugf("Absolute path of custom bundle directory: %s", customBundleDir)
-	diskPath, diskFormat, err := copyDiskImage(customBundleDir)
+	diskPath, diskFormat, err := copyDiskImage(client.name, customBundleDir)
 	if err != nil {
 		return err
 	}
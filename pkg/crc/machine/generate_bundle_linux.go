@@ -8,12 +8,12 @@ import (
 	crcos "github.com/crc-org/crc/v2/pkg/os"
 )
 
-func copyDiskImage(destDir string) (string, string, error) {
+func copyDiskImage(name, destDir string) (string, string, error) {
 	const destFormat = "qcow2"
 
-	imageName := fmt.Sprintf("%s.qcow2", constants.DefaultName)
+	imageName := fmt.Sprintf("%s.qcow2", name)
 
-	srcPath := filepath.Join(constants.MachineInstanceDir, constants.DefaultName, imageName)
+	srcPath := filepath.Join(constants.GetInstanceDir(name), imageName)
 	destPath := filepath.Join(destDir, imageName)
 
 	_, _, err := crcos.RunWithDefaultLocale("qemu-img", "convert", "-f", "qcow2", "-O", destFormat, srcPath, destPath)
@@ -0,0 +1,163 @@
+package machine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/validation"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+	"github.com/pkg/errors"
+)
+
+// ListInstances returns the names of all the known CRC instances. The
+// default instance always exists and is listed first.
+func ListInstances() ([]string, error) {
+	entries, err := os.ReadDir(constants.CrcInstancesDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if name == constants.DefaultName || validation.ValidateInstanceName(name) != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{constants.DefaultName}, names...), nil
+}
+
+// InstanceExists returns true when 'name' was created with CreateInstance or
+// is the default instance
+func InstanceExists(name string) bool {
+	if name == constants.DefaultName {
+		return true
+	}
+	return crcos.FileExists(constants.GetInstanceConfigPath(name))
+}
+
+// CreateInstance registers a new CRC instance named 'name'. Its virtual
+// machine is created on the first 'crc start' while this instance is the
+// current one.
+func CreateInstance(name string) error {
+	if err := validation.ValidateInstanceName(name); err != nil {
+		return err
+	}
+	if InstanceExists(name) {
+		return fmt.Errorf("instance '%s' already exists", name)
+	}
+	if err := os.MkdirAll(constants.CrcInstancesDir, 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(constants.GetInstanceConfigPath(name), []byte("{}\n"), 0600)
+}
+
+// GetInstanceState returns the state of the virtual machine of the instance
+// 'name', and false if this virtual machine has not been created yet
+func GetInstanceState(name string) (state.State, bool, error) {
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	exists, err := libMachineAPIClient.Exists(name)
+	if err != nil {
+		return state.Error, false, errors.Wrap(err, "Cannot check if machine exists")
+	}
+	if !exists {
+		return state.Stopped, false, nil
+	}
+	vm, err := loadVirtualMachine(name, false)
+	if err != nil && !errors.Is(err, errInvalidBundleMetadata) {
+		return state.Error, true, err
+	}
+	defer vm.Close()
+	vmState, err := vm.State()
+	if err != nil {
+		return state.Error, true, err
+	}
+	return vmState, true, nil
+}
+
+// checkNoOtherInstanceRunning makes sure no instance other than 'name' is
+// running. All the instances share the same IP addresses and host ports, so
+// only one of them can run at a time.
+func checkNoOtherInstanceRunning(name string) error {
+	names, err := ListInstances()
+	if err != nil {
+		return err
+	}
+	for _, other := range names {
+		if other == name {
+			continue
+		}
+		vmState, exists, err := GetInstanceState(other)
+		if err != nil {
+			logging.Debugf("Cannot get state of instance %s: %v", other, err)
+			continue
+		}
+		if exists && vmState == state.Running {
+			return fmt.Errorf("instance '%s' is running, only one instance can run at a time, stop it with 'crc stop' after 'crc instance switch %s'", other, other)
+		}
+	}
+	return nil
+}
+
+// BundlesInUse returns the names of the bundles used by the virtual
+// machines of the CRC instances and by their snapshots, whose disk images
+// are backed by the disk image of the bundle, with the names of the
+// instances using them
+func BundlesInUse() (map[string][]string, error) {
+	names, err := ListInstances()
+	if err != nil {
+		return nil, err
+	}
+	libMachineAPIClient, cleanup := createLibMachineClient()
+	defer cleanup()
+	inUse := map[string][]string{}
+	for _, name := range names {
+		if err := addSnapshotBundles(inUse, name); err != nil {
+			return nil, err
+		}
+		exists, err := libMachineAPIClient.Exists(name)
+		if err != nil {
+			return nil, errors.Wrap(err, "Cannot check if machine exists")
+		}
+		if !exists {
+			continue
+		}
+		host, err := libMachineAPIClient.Load(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot load machine %s", name)
+		}
+		bundleName, err := host.Driver.GetBundleName()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot get the bundle of instance %s", name)
+		}
+		bundleName = bundle.GetBundleNameWithoutExtension(bundleName)
+		inUse[bundleName] = append(inUse[bundleName], name)
+	}
+	return inUse, nil
+}
+
+// addSnapshotBundles adds the bundles of the snapshots of the instance
+// 'name' to 'inUse'
+func addSnapshotBundles(inUse map[string][]string, name string) error {
+	snapshots, err := listSnapshots(name)
+	if err != nil {
+		return errors.Wrapf(err, "Cannot list the snapshots of instance %s", name)
+	}
+	for _, snapshot := range snapshots {
+		bundleName := bundle.GetBundleNameWithoutExtension(snapshot.BundleName)
+		inUse[bundleName] = append(inUse[bundleName], fmt.Sprintf("%s (snapshot %s)", name, snapshot.Name))
+	}
+	return nil
+}
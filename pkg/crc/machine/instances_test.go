@@ -0,0 +1,34 @@
+package machine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListInstances(t *testing.T) {
+	instancesDir := constants.CrcInstancesDir
+	constants.CrcInstancesDir = t.TempDir()
+	defer func() {
+		constants.CrcInstancesDir = instancesDir
+	}()
+
+	names, err := ListInstances()
+	require.NoError(t, err)
+	assert.Equal(t, []string{constants.DefaultName}, names)
+
+	require.NoError(t, CreateInstance("ocp"))
+	require.NoError(t, CreateInstance("microshift"))
+	require.NoError(t, os.WriteFile(filepath.Join(constants.CrcInstancesDir, "notes.txt"), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(constants.CrcInstancesDir, "Invalid_Name.json"), nil, 0600))
+
+	names, err = ListInstances()
+	require.NoError(t, err)
+	assert.Equal(t, []string{constants.DefaultName, "microshift", "ocp"}, names)
+	assert.True(t, InstanceExists("ocp"))
+	assert.False(t, InstanceExists("missing"))
+}
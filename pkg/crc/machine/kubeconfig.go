@@ -1,6 +1,7 @@
 package machine
 
 import (
+	"bytes"
 	gocontext "context"
 	"crypto/tls"
 	"crypto/x509"
@@ -26,10 +27,37 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
-const (
-	adminContext     = "crc-admin"
-	developerContext = "crc-developer"
-)
+// adminContext returns the name of the kubeconfig context of the kubeadmin
+// user for the instance 'name'. The default instance keeps the historical
+// 'crc-admin' name.
+func adminContext(name string) string {
+	return fmt.Sprintf("%s-admin", name)
+}
+
+func developerContext(name string) string {
+	return fmt.Sprintf("%s-developer", name)
+}
+
+// instanceEntryName returns the name of the kubeconfig cluster or user
+// 'entry' of the instance 'name'. All the instances use the same API server
+// URL, so the entries of the instances other than the default one, which
+// keeps the historical names, are suffixed with the instance name.
+func instanceEntryName(name, entry string) string {
+	if name == constants.DefaultName {
+		return entry
+	}
+	return fmt.Sprintf("%s/%s", entry, name)
+}
+
+// isInstanceEntry returns true when the kubeconfig entry 'entry' belongs to
+// the instance 'name', see instanceEntryName. The instance names cannot
+// contain a '/'.
+func isInstanceEntry(name, entry string) bool {
+	if name == constants.DefaultName {
+		return !strings.Contains(entry, "/")
+	}
+	return strings.HasSuffix(entry, "/"+name)
+}
 
 func updateClientCrtAndKeyToKubeconfig(clientKey, clientCrt []byte, srcKubeconfigPath, destKubeconfigPath string) error {
 	cfg, err := clientcmd.LoadFromFile(srcKubeconfigPath)
@@ -50,7 +78,32 @@ func updateClientCrtAndKeyToKubeconfig(clientKey, clientCrt []byte, srcKubeconfi
 	return clientcmd.WriteToFile(*cfg, destKubeconfigPath)
 }
 
-func writeKubeconfig(ip string, clusterConfig *types.ClusterConfig, ingressHTTPSPort uint) error {
+// addCAToKubeconfig adds the pem certificates 'ca' to the certificate
+// authorities of the clusters of the kubeconfig file. It returns false when
+// they were already present.
+func addCAToKubeconfig(kubeconfigFile string, ca []byte) (bool, error) {
+	cfg, err := clientcmd.LoadFromFile(kubeconfigFile)
+	if err != nil {
+		return false, err
+	}
+	var updated bool
+	for _, cluster := range cfg.Clusters {
+		if bytes.Contains(cluster.CertificateAuthorityData, bytes.TrimSpace(ca)) {
+			continue
+		}
+		cluster.CertificateAuthorityData = caBundle(cluster.CertificateAuthorityData, ca)
+		updated = true
+	}
+	if !updated {
+		return false, nil
+	}
+	return true, clientcmd.WriteToFile(*cfg, kubeconfigFile)
+}
+
+// writeKubeconfig adds the admin and developer contexts of the instance to
+// the global kubeconfig. 'extraCA' are the CAs of the custom certificates of
+// the cluster, if any.
+func writeKubeconfig(name, ip string, clusterConfig *types.ClusterConfig, ingressHTTPSPort uint, extraCA []byte) error {
 	kubeconfig, cfg, err := GetGlobalKubeConfig()
 	if err != nil {
 		return err
@@ -59,12 +112,15 @@ func writeKubeconfig(ip string, clusterConfig *types.ClusterConfig, ingressHTTPS
 	if err != nil {
 		return err
 	}
+	if len(extraCA) > 0 {
+		ca = caBundle(ca, extraCA)
+	}
 	host, err := hostname(clusterConfig.ClusterAPI)
 	if err != nil {
 		return err
 	}
 
-	cfg.Clusters[host] = &api.Cluster{
+	cfg.Clusters[instanceEntryName(name, host)] = &api.Cluster{
 		Server:                   clusterConfig.ClusterAPI,
 		CertificateAuthorityData: ca,
 	}
@@ -73,7 +129,7 @@ func writeKubeconfig(ip string, clusterConfig *types.ClusterConfig, ingressHTTPS
 	if err != nil {
 		return err
 	}
-	if err := addContext(cfg, clusterConfig.ClusterAPI, adminContext, "kubeadmin", kubeadminToken, "default"); err != nil {
+	if err := addContext(cfg, name, clusterConfig.ClusterAPI, adminContext(name), "kubeadmin", kubeadminToken, "default"); err != nil {
 		return err
 	}
 
@@ -81,12 +137,12 @@ func writeKubeconfig(ip string, clusterConfig *types.ClusterConfig, ingressHTTPS
 	if err != nil {
 		return err
 	}
-	if err := addContext(cfg, clusterConfig.ClusterAPI, developerContext, "developer", developerToken, ""); err != nil {
+	if err := addContext(cfg, name, clusterConfig.ClusterAPI, developerContext(name), "developer", developerToken, ""); err != nil {
 		return err
 	}
 
 	if cfg.CurrentContext == "" {
-		cfg.CurrentContext = adminContext
+		cfg.CurrentContext = adminContext(name)
 	}
 
 	return clientcmd.WriteToFile(*cfg, kubeconfig)
@@ -143,7 +199,7 @@ func hostname(clusterAPI string) (string, error) {
 	return strings.ReplaceAll(h, ".", "-"), nil
 }
 
-func addContext(cfg *api.Config, clusterAPI, context, username, token, namespace string) error {
+func addContext(cfg *api.Config, name, clusterAPI, context, username, token, namespace string) error {
 	host, err := hostname(clusterAPI)
 	if err != nil {
 		return err
@@ -154,12 +210,13 @@ func addContext(cfg *api.Config, clusterAPI, context, username, token, namespace
 	if err != nil {
 		return err
 	}
+	clusterUser = instanceEntryName(name, clusterUser)
 
 	cfg.AuthInfos[clusterUser] = &api.AuthInfo{
 		Token: token,
 	}
 	cfg.Contexts[context] = &api.Context{
-		Cluster:   host,
+		Cluster:   instanceEntryName(name, host),
 		AuthInfo:  clusterUser,
 		Namespace: namespace,
 	}
@@ -228,7 +285,9 @@ func getGlobalKubeConfigPath() string {
 	return filepath.Join(constants.GetHomeDir(), ".kube", "config")
 }
 
-func cleanKubeconfig(input, output string) error {
+// cleanKubeconfig removes the clusters of the instance 'instance' from the
+// kubeconfig, with their contexts and the users only these contexts use
+func cleanKubeconfig(instance, input, output string) error {
 	cfg, err := clientcmd.LoadFromFile(input)
 	if err != nil {
 		return err
@@ -236,7 +295,7 @@ func cleanKubeconfig(input, output string) error {
 
 	var clusterNames []string
 	for name, cluster := range cfg.Clusters {
-		if cluster.Server == fmt.Sprintf("https://api%s:6443", constants.ClusterDomain) {
+		if cluster.Server == fmt.Sprintf("https://api%s:6443", constants.ClusterDomain) && isInstanceEntry(instance, name) {
 			clusterNames = append(clusterNames, name)
 		}
 	}
@@ -271,11 +330,13 @@ func cleanKubeconfig(input, output string) error {
 	return clientcmd.WriteToFile(*cfg, output)
 }
 
-func mergeKubeConfigFile(kubeConfigFile string) error {
-	return mergeConfigHelper(kubeConfigFile, getGlobalKubeConfigPath())
+func mergeKubeConfigFile(name, kubeConfigFile string) error {
+	return mergeConfigHelper(name, kubeConfigFile, getGlobalKubeConfigPath())
 }
 
-func mergeConfigHelper(kubeConfigFile, globalConfigFile string) error {
+// mergeConfigHelper merges the kubeconfig of the instance 'name' into the
+// global kubeconfig, see instanceEntryName for the names of its entries
+func mergeConfigHelper(name, kubeConfigFile, globalConfigFile string) error {
 
 	globalConfigPath, globalConf, err := getKubeConfigFromFile(globalConfigFile)
 	if err != nil {
@@ -292,19 +353,21 @@ func mergeConfigHelper(kubeConfigFile, globalConfigFile string) error {
 		return err
 	}
 	// Merge the currentConf to globalConfig
-	for name, cluster := range cfg.Clusters {
-		globalConf.Clusters[name] = cluster
+	for clusterName, cluster := range cfg.Clusters {
+		globalConf.Clusters[instanceEntryName(name, clusterName)] = cluster
 	}
 
-	for name, authInfo := range cfg.AuthInfos {
-		globalConf.AuthInfos[name] = authInfo
+	for user, authInfo := range cfg.AuthInfos {
+		globalConf.AuthInfos[instanceEntryName(name, user)] = authInfo
 	}
 
-	for name, context := range cfg.Contexts {
-		globalConf.Contexts[name] = context
+	for contextName, context := range cfg.Contexts {
+		context.Cluster = instanceEntryName(name, context.Cluster)
+		context.AuthInfo = instanceEntryName(name, context.AuthInfo)
+		globalConf.Contexts[instanceEntryName(name, contextName)] = context
 	}
 
-	globalConf.CurrentContext = cfg.CurrentContext
+	globalConf.CurrentContext = instanceEntryName(name, cfg.CurrentContext)
 	return clientcmd.WriteToFile(*globalConf, globalConfigPath)
 }
 
@@ -1,11 +1,16 @@
 package machine
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
@@ -44,7 +49,7 @@ func TestCertificateAuthority(t *testing.T) {
 func TestCleanKubeconfig(t *testing.T) {
 	dir := t.TempDir()
 
-	assert.NoError(t, cleanKubeconfig(filepath.Join("testdata", "kubeconfig.in"), filepath.Join(dir, "kubeconfig")))
+	assert.NoError(t, cleanKubeconfig(constants.DefaultName, filepath.Join("testdata", "kubeconfig.in"), filepath.Join(dir, "kubeconfig")))
 	actual, err := os.ReadFile(filepath.Join(dir, "kubeconfig"))
 	assert.NoError(t, err)
 	expected, err := os.ReadFile(filepath.Join("testdata", "kubeconfig.out"))
@@ -56,7 +61,7 @@ func TestCleanKubeConfigIdempotency(t *testing.T) {
 	// Given
 	dir := t.TempDir()
 	// When
-	assert.NoError(t, cleanKubeconfig(filepath.Join("testdata", "kubeconfig.out"), filepath.Join(dir, "kubeconfig")))
+	assert.NoError(t, cleanKubeconfig(constants.DefaultName, filepath.Join("testdata", "kubeconfig.out"), filepath.Join(dir, "kubeconfig")))
 	actual, err := os.ReadFile(filepath.Join(dir, "kubeconfig"))
 	// Then
 	assert.NoError(t, err)
@@ -69,7 +74,7 @@ func TestCleanKubeConfigShouldDoNothingWhenClusterDomainIsNotEqualToCrcTesting(t
 	// Given
 	dir := t.TempDir()
 	// When
-	assert.NoError(t, cleanKubeconfig(filepath.Join("testdata", "kubeconfig-without-api-crc-testing-cluster-domain"), filepath.Join(dir, "kubeconfig")))
+	assert.NoError(t, cleanKubeconfig(constants.DefaultName, filepath.Join("testdata", "kubeconfig-without-api-crc-testing-cluster-domain"), filepath.Join(dir, "kubeconfig")))
 	actual, err := os.ReadFile(filepath.Join(dir, "kubeconfig"))
 	// Then
 	assert.NoError(t, err)
@@ -78,6 +83,33 @@ func TestCleanKubeConfigShouldDoNothingWhenClusterDomainIsNotEqualToCrcTesting(t
 	assert.YAMLEq(t, string(expected), string(actual))
 }
 
+func TestCleanKubeconfigOfOneInstance(t *testing.T) {
+	cfg := api.NewConfig()
+	for _, name := range []string{constants.DefaultName, "other"} {
+		cfg.Clusters[instanceEntryName(name, "api-crc-testing:6443")] = &api.Cluster{Server: "https://api.crc.testing:6443"}
+		require.NoError(t, addContext(cfg, name, "https://api.crc.testing:6443", adminContext(name), "kubeadmin", "token", "default"))
+		require.NoError(t, addContext(cfg, name, "https://api.crc.testing:6443", developerContext(name), "developer", "token", ""))
+	}
+	cfg.CurrentContext = adminContext("other")
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, clientcmd.WriteToFile(*cfg, kubeconfig))
+
+	require.NoError(t, cleanKubeconfig("other", kubeconfig, kubeconfig))
+	cleaned, err := clientcmd.LoadFromFile(kubeconfig)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-crc-testing:6443"}, slices.Sorted(maps.Keys(cleaned.Clusters)))
+	assert.Equal(t, []string{"crc-admin", "crc-developer"}, slices.Sorted(maps.Keys(cleaned.Contexts)))
+	assert.Equal(t, []string{"developer/api-crc-testing:6443", "kubeadmin/api-crc-testing:6443"}, slices.Sorted(maps.Keys(cleaned.AuthInfos)))
+	assert.Empty(t, cleaned.CurrentContext)
+
+	require.NoError(t, cleanKubeconfig(constants.DefaultName, kubeconfig, kubeconfig))
+	cleaned, err = clientcmd.LoadFromFile(kubeconfig)
+	require.NoError(t, err)
+	assert.Empty(t, cleaned.Clusters)
+	assert.Empty(t, cleaned.Contexts)
+	assert.Empty(t, cleaned.AuthInfos)
+}
+
 func TestUpdateUserCaAndKeyToKubeconfig(t *testing.T) {
 	f, err := os.CreateTemp("", "kubeconfig")
 	assert.NoError(t, err, "")
@@ -133,7 +165,7 @@ func Test_mergeKubeConfigFile(t *testing.T) {
 	assert.NoError(t, err, "failed to create temporary kubeconfig file")
 	defer os.Remove(secondaryConfigPath)
 
-	err = mergeConfigHelper(secondaryConfigPath, primaryConfigPath)
+	err = mergeConfigHelper(constants.DefaultName, secondaryConfigPath, primaryConfigPath)
 	assert.NoError(t, err, "failed to modify kubeconfig")
 
 	// Load the modified kubeconfig to ensure it was merged correctly
@@ -156,6 +188,7 @@ func Test_mergeKubeConfigFile(t *testing.T) {
 
 func Test_addContext(t *testing.T) {
 	type input struct {
+		instance   string
 		clusterAPI string
 		username   string
 		context    string
@@ -164,6 +197,7 @@ func Test_addContext(t *testing.T) {
 	}
 
 	type expected struct {
+		cluster   string
 		user      string
 		namespace string
 	}
@@ -173,27 +207,52 @@ func Test_addContext(t *testing.T) {
 		expected expected
 	}{
 		{
-			input{"https://abcdd.api.com", "foo", "foo@abcdd", "secretToken", "kube-system"},
-			expected{"foo/abcdd-api-com", "kube-system"},
+			input{"crc", "https://abcdd.api.com", "foo", "foo@abcdd", "secretToken", "kube-system"},
+			expected{"abcdd-api-com:443", "foo/abcdd-api-com", "kube-system"},
 		},
 		{
-			input{"https://api.crc.testing:6443", "kubeadmin", "kubeadm", "secretToken", "default"},
-			expected{"kubeadmin/api-crc-testing:6443", "default"},
+			input{"crc", "https://api.crc.testing:6443", "kubeadmin", "kubeadm", "secretToken", "default"},
+			expected{"api-crc-testing:6443", "kubeadmin/api-crc-testing:6443", "default"},
 		},
 		{
-			input{"https://api.crc.testing:6443", "kubeadmin", "kubeadm", "secretToken", ""},
-			expected{"kubeadmin/api-crc-testing:6443", ""},
+			input{"crc", "https://api.crc.testing:6443", "kubeadmin", "kubeadm", "secretToken", ""},
+			expected{"api-crc-testing:6443", "kubeadmin/api-crc-testing:6443", ""},
+		},
+		{
+			input{"other", "https://api.crc.testing:6443", "kubeadmin", "other-admin", "secretToken", "default"},
+			expected{"api-crc-testing:6443/other", "kubeadmin/api-crc-testing:6443/other", "default"},
 		},
 	}
 
 	cfg := api.NewConfig()
 
 	for _, tt := range tests {
-		err := addContext(cfg, tt.in.clusterAPI, tt.in.context, tt.in.username, tt.in.token, tt.in.namespace)
+		err := addContext(cfg, tt.in.instance, tt.in.clusterAPI, tt.in.context, tt.in.username, tt.in.token, tt.in.namespace)
 		assert.NoError(t, err)
 		assert.Contains(t, cfg.Contexts, tt.in.context, "Expected context not found")
+		assert.Equal(t, tt.expected.cluster, cfg.Contexts[tt.in.context].Cluster, "Expected cluster not found")
 		assert.Equal(t, cfg.Contexts[tt.in.context].Namespace, tt.expected.namespace, "Expected namespace not found")
 		assert.Contains(t, cfg.AuthInfos, tt.expected.user, "Expected AuthInfo not found")
 		assert.Contains(t, cfg.AuthInfos[tt.expected.user].Token, tt.in.token, "Expected token not found")
 	}
 }
+
+func TestAddCAToKubeconfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(dummyKubeconfigFileContent), 0600))
+	ca, err := certificateAuthority(kubeconfig)
+	require.NoError(t, err)
+
+	_, caCert, err := crctls.GetSelfSignedIngressCA()
+	require.NoError(t, err)
+	updated, err := addCAToKubeconfig(kubeconfig, crctls.CertToPem(caCert))
+	require.NoError(t, err)
+	assert.True(t, updated)
+	updated, err = addCAToKubeconfig(kubeconfig, crctls.CertToPem(caCert))
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	newCA, err := certificateAuthority(kubeconfig)
+	require.NoError(t, err)
+	assert.Equal(t, string(caBundle(ca, crctls.CertToPem(caCert))), string(newCA))
+}
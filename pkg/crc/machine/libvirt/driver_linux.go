@@ -22,7 +22,9 @@ func CreateHost(machineConfig config.MachineConfig) *libvirt.Driver {
 		libvirtDriver.Network = DefaultNetwork
 	}
 
-	libvirtDriver.StoragePool = DefaultStoragePool
+	// each instance has its own storage pool, named after it, the default
+	// instance keeps using DefaultStoragePool
+	libvirtDriver.StoragePool = machineConfig.Name
 	libvirtDriver.SharedDirs = configureShareDirs(machineConfig)
 
 	return libvirtDriver
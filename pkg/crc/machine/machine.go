@@ -13,7 +13,7 @@ import (
 	"github.com/crc-org/machine/libmachine/drivers"
 )
 
-func getClusterConfig(bundleInfo *bundle.CrcBundleInfo) (*types.ClusterConfig, error) {
+func getClusterConfig(name string, bundleInfo *bundle.CrcBundleInfo) (*types.ClusterConfig, error) {
 	if !bundleInfo.IsOpenShift() {
 		return &types.ClusterConfig{
 			ClusterType: bundleInfo.GetBundleType(),
@@ -21,11 +21,11 @@ func getClusterConfig(bundleInfo *bundle.CrcBundleInfo) (*types.ClusterConfig, e
 		}, nil
 	}
 
-	kubeadminPassword, err := cluster.GetUserPassword(constants.GetKubeAdminPasswordPath())
+	kubeadminPassword, err := cluster.GetUserPassword(constants.GetKubeAdminPasswordPath(name))
 	if err != nil {
 		return nil, fmt.Errorf("Error reading kubeadmin password from bundle %v", err)
 	}
-	developerPassword, err := cluster.GetUserPassword(constants.GetDeveloperPasswordPath())
+	developerPassword, err := cluster.GetUserPassword(constants.GetDeveloperPasswordPath(name))
 	if err != nil {
 		return nil, fmt.Errorf("error reading developer password from bundle %v", err)
 	}
@@ -0,0 +1,298 @@
+package machine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+
+	gvtypes "github.com/containers/gvisor-tap-vsock/pkg/types"
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrPortForwardingUnsupported is returned when the port forwards are
+	// managed while the instance does not use the user network mode
+	ErrPortForwardingUnsupported = errors.New("port forwarding requires the user network mode, run 'crc config set network-mode user'")
+	// ErrInvalidPortForward is returned for the port forwards with an
+	// invalid protocol or address
+	ErrInvalidPortForward = errors.New("invalid port forward")
+	// ErrPortForwardExists is returned when the host port is already
+	// forwarded
+	ErrPortForwardExists = errors.New("port is already forwarded")
+	// ErrPortForwardNotFound is returned when removing a port forward
+	// which does not exist
+	ErrPortForwardNotFound = errors.New("port forward not found")
+)
+
+// ExposePort forwards a host port to the VM network, right away when the
+// instance is running, and each time it starts
+func (client *client) ExposePort(forward types.PortForward) (*types.PortForward, error) {
+	if !client.useVSock() {
+		return nil, ErrPortForwardingUnsupported
+	}
+	forward, err := normalizePortForward(forward)
+	if err != nil {
+		return nil, err
+	}
+	unlock, err := lockFile(constants.GetPortForwardsPath(client.name))
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	forwards, err := loadPortForwards(client.name)
+	if err != nil {
+		return nil, err
+	}
+	for _, reserved := range client.reservedPorts() {
+		if string(reserved.Protocol) == forward.Protocol && sameLocalPort(reserved.Local, forward.Local) {
+			return nil, fmt.Errorf("%w: %s/%s is used by crc", ErrPortForwardExists, forward.Protocol, reserved.Local)
+		}
+	}
+	for _, existing := range forwards {
+		if existing.Protocol == forward.Protocol && sameLocalPort(existing.Local, forward.Local) {
+			return nil, fmt.Errorf("%w: %s/%s is forwarded to %s", ErrPortForwardExists, existing.Protocol, existing.Local, existing.Remote)
+		}
+	}
+
+	running, err := client.IsRunning()
+	if err != nil {
+		return nil, err
+	}
+	if running {
+		if err := daemonclient.New().NetworkClient.Expose(toExposeRequest(forward)); err != nil {
+			return nil, errors.Wrapf(err, "failed to expose port %s -> %s", forward.Local, forward.Remote)
+		}
+	}
+	if err := savePortForwards(client.name, append(forwards, forward)); err != nil {
+		return nil, err
+	}
+	return &forward, nil
+}
+
+// UnexposePort removes the forward of the host address 'local'
+func (client *client) UnexposePort(protocol, local string) error {
+	if !client.useVSock() {
+		return ErrPortForwardingUnsupported
+	}
+	protocol, err := normalizeProtocol(protocol)
+	if err != nil {
+		return err
+	}
+	local, err = normalizeAddress(local, constants.LocalIP)
+	if err != nil {
+		return fmt.Errorf("%w: local address: %v", ErrInvalidPortForward, err)
+	}
+	unlock, err := lockFile(constants.GetPortForwardsPath(client.name))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	forwards, err := loadPortForwards(client.name)
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(forwards, func(existing types.PortForward) bool {
+		return existing.Protocol == protocol && existing.Local == local
+	})
+	if index == -1 {
+		return fmt.Errorf("%w: %s/%s", ErrPortForwardNotFound, protocol, local)
+	}
+	forward := forwards[index]
+
+	running, err := client.IsRunning()
+	if err != nil {
+		return err
+	}
+	if running {
+		if err := daemonclient.New().NetworkClient.Unexpose(&gvtypes.UnexposeRequest{Protocol: gvtypes.TransportProtocol(forward.Protocol), Local: forward.Local}); err != nil {
+			return errors.Wrapf(err, "failed to unexpose port %s", forward.Local)
+		}
+	}
+	return savePortForwards(client.name, append(forwards[:index], forwards[index+1:]...))
+}
+
+// ListPortForwards returns the port forwards of the instance, and whether
+// they are currently applied
+func (client *client) ListPortForwards() ([]types.PortForwardInfo, error) {
+	if !client.useVSock() {
+		return nil, ErrPortForwardingUnsupported
+	}
+	forwards, err := loadPortForwards(client.name)
+	if err != nil {
+		return nil, err
+	}
+	var opened []gvtypes.ExposeRequest
+	if len(forwards) > 0 {
+		running, err := client.IsRunning()
+		if err != nil {
+			return nil, err
+		}
+		if running {
+			if opened, err = listOpenPorts(daemonclient.New()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	infos := []types.PortForwardInfo{}
+	for _, forward := range forwards {
+		infos = append(infos, types.PortForwardInfo{
+			PortForward: forward,
+			Active:      isOpened(opened, *toExposeRequest(forward)),
+		})
+	}
+	return infos, nil
+}
+
+// exposePortForwards applies the port forwards of the instance, a failure
+// does not prevent the instance from starting
+func exposePortForwards(name string, daemonClient *daemonclient.Client, alreadyOpenedPorts []gvtypes.ExposeRequest) {
+	forwards, err := loadPortForwards(name)
+	if err != nil {
+		logging.Warnf("Cannot load the port forwards: %v", err)
+		return
+	}
+	for _, forward := range forwards {
+		port := toExposeRequest(forward)
+		if isOpened(alreadyOpenedPorts, *port) {
+			continue
+		}
+		if err := daemonClient.NetworkClient.Expose(port); err != nil {
+			logging.Warnf("Failed to expose port %s -> %s: %v", port.Local, port.Remote, err)
+		}
+	}
+}
+
+func (client *client) reservedPorts() []gvtypes.ExposeRequest {
+	return vsockPorts(client.name, client.GetPreset(),
+		client.config.Get(crcConfig.IngressHTTPPort).AsUInt(),
+		client.config.Get(crcConfig.IngressHTTPSPort).AsUInt())
+}
+
+func toExposeRequest(forward types.PortForward) *gvtypes.ExposeRequest {
+	return &gvtypes.ExposeRequest{
+		Protocol: gvtypes.TransportProtocol(forward.Protocol),
+		Local:    forward.Local,
+		Remote:   forward.Remote,
+	}
+}
+
+// normalizePortForward validates 'forward' and fills its defaults: the tcp
+// protocol, the 127.0.0.1 local address and the VM remote address
+func normalizePortForward(forward types.PortForward) (types.PortForward, error) {
+	protocol, err := normalizeProtocol(forward.Protocol)
+	if err != nil {
+		return forward, err
+	}
+	local, err := normalizeAddress(forward.Local, constants.LocalIP)
+	if err != nil {
+		return forward, fmt.Errorf("%w: local address: %v", ErrInvalidPortForward, err)
+	}
+	remote, err := normalizeAddress(forward.Remote, virtualMachineIP)
+	if err != nil {
+		return forward, fmt.Errorf("%w: remote address: %v", ErrInvalidPortForward, err)
+	}
+	forward.Protocol, forward.Local, forward.Remote = protocol, local, remote
+	return forward, nil
+}
+
+func normalizeProtocol(protocol string) (string, error) {
+	switch protocol {
+	case "":
+		return string(gvtypes.TCP), nil
+	case string(gvtypes.TCP), string(gvtypes.UDP):
+		return protocol, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported protocol '%s', must be tcp or udp", ErrInvalidPortForward, protocol)
+	}
+}
+
+// normalizeAddress returns 'address' as host:port, with 'defaultHost' when
+// it is only a port
+func normalizeAddress(address, defaultHost string) (string, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = defaultHost, address
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil || portNumber < 1 || portNumber > 65535 {
+		return "", fmt.Errorf("'%s' is not a valid port", port)
+	}
+	if host != "" && net.ParseIP(host) == nil {
+		return "", fmt.Errorf("'%s' is not an IP address", host)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// sameLocalPort returns true when the host addresses 'a' and 'b' cannot be
+// listened on at the same time
+func sameLocalPort(a, b string) bool {
+	hostA, portA, errA := net.SplitHostPort(a)
+	hostB, portB, errB := net.SplitHostPort(b)
+	if errA != nil || errB != nil || portA != portB {
+		return false
+	}
+	return hostA == hostB || isUnspecified(hostA) || isUnspecified(hostB)
+}
+
+func isUnspecified(host string) bool {
+	return host == "" || net.ParseIP(host).IsUnspecified()
+}
+
+func loadPortForwards(name string) ([]types.PortForward, error) {
+	data, err := os.ReadFile(constants.GetPortForwardsPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var forwards []types.PortForward
+	if err := json.Unmarshal(data, &forwards); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", constants.GetPortForwardsPath(name))
+	}
+	return forwards, nil
+}
+
+func savePortForwards(name string, forwards []types.PortForward) error {
+	if forwards == nil {
+		forwards = []types.PortForward{}
+	}
+	data, err := json.MarshalIndent(forwards, "", "  ")
+	if err != nil {
+		return err
+	}
+	return replaceFile(constants.GetPortForwardsPath(name), data)
+}
+
+// lockFile serializes the updates of the file 'path' by the daemon and the
+// crc commands, the returned function releases it
+func lockFile(path string) (func(), error) {
+	unlock, err := crcos.LockFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to lock %s", path)
+	}
+	return func() {
+		if err := unlock(); err != nil {
+			logging.Debugf("Failed to unlock %s: %v", path, err)
+		}
+	}, nil
+}
+
+// replaceFile replaces the file 'path' with 'data' at once, so that it can
+// be read while it is updated
+func replaceFile(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
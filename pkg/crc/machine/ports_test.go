@@ -0,0 +1,104 @@
+package machine
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePortForward(t *testing.T) {
+	for _, test := range []struct {
+		forward  types.PortForward
+		expected types.PortForward
+		err      string
+	}{
+		{
+			forward:  types.PortForward{Local: "8080", Remote: "30080"},
+			expected: types.PortForward{Protocol: "tcp", Local: "127.0.0.1:8080", Remote: "192.168.127.2:30080"},
+		},
+		{
+			forward:  types.PortForward{Protocol: "udp", Local: ":5353", Remote: "192.168.127.3:53"},
+			expected: types.PortForward{Protocol: "udp", Local: ":5353", Remote: "192.168.127.3:53"},
+		},
+		{
+			forward:  types.PortForward{Local: "[::1]:5432", Remote: "5432"},
+			expected: types.PortForward{Protocol: "tcp", Local: "[::1]:5432", Remote: "192.168.127.2:5432"},
+		},
+		{
+			forward: types.PortForward{Protocol: "unix", Local: "8080", Remote: "80"},
+			err:     "invalid port forward: unsupported protocol 'unix', must be tcp or udp",
+		},
+		{
+			forward: types.PortForward{Local: "70000", Remote: "80"},
+			err:     "invalid port forward: local address: '70000' is not a valid port",
+		},
+		{
+			forward: types.PortForward{Local: "8080", Remote: "db.example.com:5432"},
+			err:     "invalid port forward: remote address: 'db.example.com' is not an IP address",
+		},
+	} {
+		forward, err := normalizePortForward(test.forward)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err)
+			assert.True(t, errors.Is(err, ErrInvalidPortForward))
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, forward)
+	}
+}
+
+func TestSameLocalPort(t *testing.T) {
+	assert.True(t, sameLocalPort("127.0.0.1:8080", "127.0.0.1:8080"))
+	assert.True(t, sameLocalPort(":8080", "127.0.0.1:8080"))
+	assert.True(t, sameLocalPort("0.0.0.0:8080", "192.168.1.2:8080"))
+	assert.False(t, sameLocalPort("127.0.0.1:8080", "192.168.1.2:8080"))
+	assert.False(t, sameLocalPort("127.0.0.1:8080", "127.0.0.1:8081"))
+}
+
+func TestPortForwardsRequireUserNetworking(t *testing.T) {
+	config := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(config)
+	_, err := config.Set(crcConfig.NetworkMode, string(network.SystemNetworkingMode))
+	require.NoError(t, err)
+	client := &client{name: "crc", config: config}
+
+	_, err = client.ExposePort(types.PortForward{Local: "8080", Remote: "80"})
+	assert.ErrorIs(t, err, ErrPortForwardingUnsupported)
+	assert.ErrorIs(t, client.UnexposePort("tcp", "8080"), ErrPortForwardingUnsupported)
+}
+
+func TestExposePortConflicts(t *testing.T) {
+	machineInstanceDir := constants.MachineInstanceDir
+	constants.MachineInstanceDir = t.TempDir()
+	defer func() {
+		constants.MachineInstanceDir = machineInstanceDir
+	}()
+	require.NoError(t, os.MkdirAll(constants.GetInstanceDir("crc"), 0750))
+
+	config := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
+	crcConfig.RegisterSettings(config)
+	_, err := config.Set(crcConfig.NetworkMode, string(network.UserNetworkingMode))
+	require.NoError(t, err)
+	client := &client{name: "crc", config: config}
+
+	require.NoError(t, savePortForwards("crc", []types.PortForward{{Protocol: "tcp", Local: "127.0.0.1:5432", Remote: "192.168.127.2:30432"}}))
+
+	_, err = client.ExposePort(types.PortForward{Local: ":5432", Remote: "5432"})
+	assert.EqualError(t, err, "port is already forwarded: tcp/127.0.0.1:5432 is forwarded to 192.168.127.2:30432")
+	_, err = client.ExposePort(types.PortForward{Local: "6443", Remote: "6443"})
+	assert.EqualError(t, err, "port is already forwarded: tcp/127.0.0.1:6443 is used by crc")
+
+	assert.ErrorIs(t, client.UnexposePort("udp", "5432"), ErrPortForwardNotFound)
+
+	forwards, err := loadPortForwards("crc")
+	require.NoError(t, err)
+	assert.Len(t, forwards, 1)
+}
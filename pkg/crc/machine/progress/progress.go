@@ -0,0 +1,262 @@
+// Package progress reports the phases of the start of an instance, so that
+// clients of the daemon can display the progress of the start.
+package progress
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type Phase string
+
+const (
+	PhaseBundleExtraction     Phase = "bundle-extraction"
+	PhaseVMCreation           Phase = "vm-creation"
+	PhaseVMStart              Phase = "vm-start"
+	PhaseSSHReady             Phase = "ssh-ready"
+	PhaseDNSChecks            Phase = "dns-checks"
+	PhaseCertRenewal          Phase = "cert-renewal"
+	PhaseClusterStart         Phase = "cluster-start"
+	PhasePullSecret           Phase = "pull-secret"
+	PhaseOperatorsStabilizing Phase = "operators-stabilizing"
+	PhaseProvisioning         Phase = "provisioning"
+	// PhaseDone is reported once the start succeeded
+	PhaseDone Phase = "done"
+)
+
+// phases are the phases of a start in the order they happen, with their
+// typical duration. Depending on the preset and on the state of the
+// instance, some of them are skipped.
+var phases = []struct {
+	phase    Phase
+	duration time.Duration
+}{
+	{PhaseBundleExtraction, 60 * time.Second},
+	{PhaseVMCreation, 15 * time.Second},
+	{PhaseVMStart, 30 * time.Second},
+	{PhaseSSHReady, 30 * time.Second},
+	{PhaseDNSChecks, 10 * time.Second},
+	{PhaseCertRenewal, 60 * time.Second},
+	{PhaseClusterStart, 60 * time.Second},
+	{PhasePullSecret, 60 * time.Second},
+	{PhaseOperatorsStabilizing, 240 * time.Second},
+	{PhaseProvisioning, 60 * time.Second},
+}
+
+type Status string
+
+const (
+	StatusStarted   Status = "started"
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Event is reported when a phase starts, progresses, completes or fails.
+// Percent and ETASeconds are estimations for the whole start, based on the
+// typical duration of the phases.
+type Event struct {
+	Phase      Phase
+	Status     Status
+	Message    string
+	Percent    int
+	ETASeconds int64  `json:"ETASeconds,omitempty"`
+	Error      string `json:"Error,omitempty"`
+}
+
+type Reporter func(event Event)
+
+type contextKey struct{}
+
+var key = contextKey{}
+
+type tracker struct {
+	lock   sync.Mutex
+	report Reporter
+	now    func() time.Time
+
+	// current is the index of the current phase in 'phases', -1 before
+	// the first one is started
+	current      int
+	message      string
+	fraction     float64
+	phaseStarted time.Time
+	// elapsed and expected are the time spent in the phases which were not
+	// skipped, and their typical duration. They are used to scale the ETA.
+	elapsed  time.Duration
+	expected time.Duration
+}
+
+// NewContext returns a context in which the start progress is reported to
+// 'report'
+func NewContext(ctx context.Context, report Reporter) context.Context {
+	return context.WithValue(ctx, key, newTracker(report, time.Now))
+}
+
+func newTracker(report Reporter, now func() time.Time) *tracker {
+	return &tracker{
+		report:  report,
+		now:     now,
+		current: -1,
+	}
+}
+
+func trackerFromContext(ctx context.Context) *tracker {
+	if t, ok := ctx.Value(key).(*tracker); ok {
+		return t
+	}
+	return nil
+}
+
+// StartPhase reports that 'phase' started. The previous phase is reported
+// as completed.
+func StartPhase(ctx context.Context, phase Phase, message string) {
+	if t := trackerFromContext(ctx); t != nil {
+		t.startPhase(phase, message)
+	}
+}
+
+// Update reports the progress within the current phase, 'fraction' is
+// between 0 and 1
+func Update(ctx context.Context, fraction float64, message string) {
+	if t := trackerFromContext(ctx); t != nil {
+		t.update(fraction, message)
+	}
+}
+
+// Done reports that the start succeeded
+func Done(ctx context.Context) {
+	if t := trackerFromContext(ctx); t != nil {
+		t.done()
+	}
+}
+
+// Fail reports that the current phase failed with 'err'
+func Fail(ctx context.Context, err error) {
+	if t := trackerFromContext(ctx); t != nil {
+		t.fail(err)
+	}
+}
+
+func indexOf(phase Phase) int {
+	for i, p := range phases {
+		if p.phase == phase {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *tracker) startPhase(phase Phase, message string) {
+	index := indexOf(phase)
+	if index == -1 {
+		return
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.current == len(phases) {
+		return
+	}
+	t.completeCurrent()
+	t.current = index
+	t.message = message
+	t.fraction = 0
+	t.phaseStarted = t.now()
+	t.emit(StatusStarted, "")
+}
+
+func (t *tracker) update(fraction float64, message string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.inPhase() {
+		return
+	}
+	t.fraction = min(max(fraction, 0), 1)
+	if message != "" {
+		t.message = message
+	}
+	t.emit(StatusRunning, "")
+}
+
+func (t *tracker) done() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.current == len(phases) {
+		return
+	}
+	t.completeCurrent()
+	t.current = len(phases)
+	t.report(Event{
+		Phase:   PhaseDone,
+		Status:  StatusCompleted,
+		Message: "Started the instance",
+		Percent: 100,
+	})
+}
+
+func (t *tracker) fail(err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if !t.inPhase() {
+		return
+	}
+	t.emit(StatusFailed, err.Error())
+}
+
+// inPhase returns whether a phase is running, that is the first phase was
+// started and the start is not done. It must be called with the lock held.
+func (t *tracker) inPhase() bool {
+	return t.current >= 0 && t.current < len(phases)
+}
+
+// completeCurrent reports the current phase as completed. It must be
+// called with the lock held.
+func (t *tracker) completeCurrent() {
+	if !t.inPhase() {
+		return
+	}
+	t.elapsed += t.now().Sub(t.phaseStarted)
+	t.expected += phases[t.current].duration
+	t.fraction = 1
+	t.emit(StatusCompleted, "")
+}
+
+func (t *tracker) emit(status Status, errorMessage string) {
+	if !t.inPhase() {
+		return
+	}
+	var total, done time.Duration
+	for i, p := range phases {
+		total += p.duration
+		if i < t.current {
+			done += p.duration
+		}
+	}
+	current := phases[t.current].duration
+	done += time.Duration(t.fraction * float64(current))
+
+	// the phases run so far give the speed of this host compared to the
+	// typical durations
+	elapsed, expected := t.elapsed, t.expected
+	if status != StatusCompleted {
+		elapsed += t.now().Sub(t.phaseStarted)
+		expected += time.Duration(t.fraction * float64(current))
+	}
+	remaining := total - done
+	if expected > 0 && elapsed > 0 {
+		remaining = time.Duration(float64(remaining) * float64(elapsed) / float64(expected))
+	}
+
+	event := Event{
+		Phase:   phases[t.current].phase,
+		Status:  status,
+		Message: t.message,
+		Percent: int(100 * done / total),
+		Error:   errorMessage,
+	}
+	if status != StatusFailed {
+		event.ETASeconds = int64(remaining.Seconds())
+	}
+	t.report(event)
+}
@@ -0,0 +1,92 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestContext(events *[]Event) (context.Context, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	tracker := newTracker(func(event Event) {
+		*events = append(*events, event)
+	}, clock.Now)
+	return context.WithValue(context.Background(), key, tracker), clock
+}
+
+func TestPhases(t *testing.T) {
+	var events []Event
+	ctx, clock := newTestContext(&events)
+
+	StartPhase(ctx, PhaseBundleExtraction, "Downloading bundle")
+	clock.advance(30 * time.Second)
+	Update(ctx, 0.5, "Extracting bundle")
+	clock.advance(30 * time.Second)
+	// the VM creation is skipped when the VM exists
+	StartPhase(ctx, PhaseVMStart, "Starting the VM")
+	clock.advance(time.Minute)
+	Fail(ctx, errors.New("Error starting machine"))
+
+	assert.Equal(t, []Event{
+		{Phase: PhaseBundleExtraction, Status: StatusStarted, Message: "Downloading bundle", Percent: 0, ETASeconds: 625},
+		{Phase: PhaseBundleExtraction, Status: StatusRunning, Message: "Extracting bundle", Percent: 4, ETASeconds: 595},
+		{Phase: PhaseBundleExtraction, Status: StatusCompleted, Message: "Extracting bundle", Percent: 9, ETASeconds: 565},
+		{Phase: PhaseVMStart, Status: StatusStarted, Message: "Starting the VM", Percent: 12, ETASeconds: 550},
+		{Phase: PhaseVMStart, Status: StatusFailed, Message: "Starting the VM", Percent: 12, Error: "Error starting machine"},
+	}, events)
+}
+
+func TestETAScaledBySpeed(t *testing.T) {
+	var events []Event
+	ctx, clock := newTestContext(&events)
+
+	StartPhase(ctx, PhaseBundleExtraction, "Loading bundle")
+	// twice slower than the typical duration
+	clock.advance(2 * time.Minute)
+	StartPhase(ctx, PhaseVMCreation, "Creating the VM")
+
+	assert.Equal(t, Event{Phase: PhaseVMCreation, Status: StatusStarted, Message: "Creating the VM", Percent: 9, ETASeconds: 1130}, events[len(events)-1])
+}
+
+func TestDone(t *testing.T) {
+	var events []Event
+	ctx, clock := newTestContext(&events)
+
+	StartPhase(ctx, PhaseOperatorsStabilizing, "Waiting for the cluster operators to stabilize")
+	clock.advance(4 * time.Minute)
+	Done(ctx)
+	// progress and errors after the end of the start are not reported
+	Update(ctx, 0.5, "Waiting for the cluster operators")
+	StartPhase(ctx, PhaseVMStart, "Starting the VM")
+	Fail(ctx, errors.New("failure"))
+	Done(ctx)
+
+	assert.Equal(t, []Event{
+		{Phase: PhaseOperatorsStabilizing, Status: StatusStarted, Message: "Waiting for the cluster operators to stabilize", Percent: 52, ETASeconds: 300},
+		{Phase: PhaseOperatorsStabilizing, Status: StatusCompleted, Message: "Waiting for the cluster operators to stabilize", Percent: 90, ETASeconds: 60},
+		{Phase: PhaseDone, Status: StatusCompleted, Message: "Started the instance", Percent: 100},
+	}, events)
+}
+
+func TestWithoutTracker(t *testing.T) {
+	ctx := context.Background()
+	StartPhase(ctx, PhaseVMStart, "Starting the VM")
+	Update(ctx, 0.5, "")
+	Fail(ctx, errors.New("failure"))
+	Done(ctx)
+}
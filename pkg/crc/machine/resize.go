@@ -0,0 +1,149 @@
+package machine
+
+import (
+	"fmt"
+	"strings"
+
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/validation"
+	"github.com/crc-org/crc/v2/pkg/libmachine/host"
+	"github.com/crc-org/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+	"go.podman.io/common/pkg/strongunits"
+)
+
+// Resize changes the resources of the instance. The drivers can't apply
+// them to a running VM, so it is stopped first and RestartRequired is set
+// in the result: the caller must start it again, which also grows the root
+// filesystem to the new disk size. The settings are updated as well so that
+// the next start keeps the new resources.
+func (client *client) Resize(resizeConfig types.ResizeConfig) (*types.ResizeResult, error) {
+	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot load machine")
+	}
+	current, err := currentResources(vm.Host)
+	if err != nil {
+		vm.Close()
+		return nil, errors.Wrap(err, "Cannot get the resources of the VM")
+	}
+	requested, changed, err := resizeChanges(current, resizeConfig, client.GetPreset())
+	if err != nil {
+		vm.Close()
+		return nil, err
+	}
+	result := &types.ResizeResult{
+		CPUs:     requested.CPUs,
+		Memory:   requested.Memory,
+		DiskSize: requested.DiskSize,
+		Changed:  changed,
+	}
+	if len(changed) == 0 {
+		vm.Close()
+		return result, nil
+	}
+
+	vmState, err := vm.State()
+	vm.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get VM status")
+	}
+	if vmState == state.Running {
+		logging.Infof("Stopping the instance to change %s...", strings.Join(changed, ", "))
+		if _, err := client.Stop(); err != nil {
+			return nil, err
+		}
+		result.RestartRequired = true
+	}
+
+	vm, err = client.loadStoppedVirtualMachine()
+	if err != nil {
+		return nil, err
+	}
+	defer vm.Close()
+	if err := applyResources(vm, requested); err != nil {
+		return nil, err
+	}
+	settings := map[string]interface{}{
+		crcConfig.CPUs:     requested.CPUs,
+		crcConfig.Memory:   uint(requested.Memory),
+		crcConfig.DiskSize: uint(requested.DiskSize),
+	}
+	for _, key := range changed {
+		if _, err := client.config.Set(key, settings[key]); err != nil {
+			return nil, errors.Wrapf(err, "Cannot update the %s setting", key)
+		}
+	}
+	return result, nil
+}
+
+// currentResources returns the resources of the VM from its driver
+// configuration
+func currentResources(host *host.Host) (types.ResizeConfig, error) {
+	driver, err := loadDriverConfig(host)
+	if err != nil {
+		return types.ResizeConfig{}, err
+	}
+	return types.ResizeConfig{
+		CPUs:     driver.CPU,
+		Memory:   strongunits.MiB(driver.Memory),
+		DiskSize: strongunits.ToGiB(strongunits.B(driver.DiskCapacity)),
+	}, nil
+}
+
+// resizeChanges returns the resources once 'requested' is applied to
+// 'current', and the settings which change. The disk can't be shrunk since
+// the root filesystem only grows.
+func resizeChanges(current, requested types.ResizeConfig, preset crcPreset.Preset) (types.ResizeConfig, []string, error) {
+	resources := current
+	changed := []string{}
+	if requested.CPUs != 0 && requested.CPUs != current.CPUs {
+		if err := validation.ValidateCPUs(requested.CPUs, preset); err != nil {
+			return current, nil, err
+		}
+		resources.CPUs = requested.CPUs
+		changed = append(changed, crcConfig.CPUs)
+	}
+	if requested.Memory != 0 && requested.Memory != current.Memory {
+		if err := validation.ValidateMemory(requested.Memory, preset); err != nil {
+			return current, nil, err
+		}
+		resources.Memory = requested.Memory
+		changed = append(changed, crcConfig.Memory)
+	}
+	if requested.DiskSize != 0 && requested.DiskSize != current.DiskSize {
+		if requested.DiskSize < current.DiskSize {
+			return current, nil, fmt.Errorf("Cannot shrink the disk from %d GiB to %d GiB", current.DiskSize, requested.DiskSize)
+		}
+		if err := validation.ValidateDiskSize(requested.DiskSize); err != nil {
+			return current, nil, err
+		}
+		resources.DiskSize = requested.DiskSize
+		changed = append(changed, crcConfig.DiskSize)
+	}
+	return resources, changed, nil
+}
+
+func applyResources(vm *virtualMachine, resources types.ResizeConfig) error {
+	if err := setMemory(vm.Host, resources.Memory); err != nil {
+		return resizeError(crcConfig.Memory, err)
+	}
+	if err := setVcpus(vm.Host, resources.CPUs); err != nil {
+		return resizeError(crcConfig.CPUs, err)
+	}
+	if err := setDiskSize(vm.Host, resources.DiskSize); err != nil {
+		return resizeError(crcConfig.DiskSize, err)
+	}
+	return vm.api.Save(vm.Host)
+}
+
+func resizeError(key string, err error) error {
+	if err == drivers.ErrNotImplemented {
+		return fmt.Errorf("Cannot change %s, the machine driver does not support it", key)
+	}
+	return errors.Wrapf(err, "Cannot change %s", key)
+}
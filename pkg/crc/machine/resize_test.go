@@ -0,0 +1,34 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeChanges(t *testing.T) {
+	current := types.ResizeConfig{CPUs: 4, Memory: 10752, DiskSize: 31}
+
+	resources, changed, err := resizeChanges(current, types.ResizeConfig{}, crcPreset.OpenShift)
+	assert.NoError(t, err)
+	assert.Equal(t, current, resources)
+	assert.Empty(t, changed)
+
+	resources, changed, err = resizeChanges(current, types.ResizeConfig{CPUs: 4, DiskSize: 31}, crcPreset.OpenShift)
+	assert.NoError(t, err)
+	assert.Equal(t, current, resources)
+	assert.Empty(t, changed)
+
+	resources, changed, err = resizeChanges(current, types.ResizeConfig{CPUs: 6, DiskSize: 50}, crcPreset.OpenShift)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ResizeConfig{CPUs: 6, Memory: 10752, DiskSize: 50}, resources)
+	assert.Equal(t, []string{"cpus", "disk-size"}, changed)
+
+	_, _, err = resizeChanges(current, types.ResizeConfig{CPUs: 2}, crcPreset.OpenShift)
+	assert.EqualError(t, err, "requires CPUs >= 4")
+
+	_, _, err = resizeChanges(types.ResizeConfig{CPUs: 4, Memory: 10752, DiskSize: 50}, types.ResizeConfig{DiskSize: 40}, crcPreset.OpenShift)
+	assert.EqualError(t, err, "Cannot shrink the disk from 50 GiB to 40 GiB")
+}
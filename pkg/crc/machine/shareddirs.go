@@ -0,0 +1,321 @@
+package machine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/crc-org/crc/v2/pkg/libmachine/host"
+	libmachine "github.com/crc-org/machine/libmachine/drivers"
+	"github.com/pkg/errors"
+)
+
+// sharedDirTagPrefix is the prefix of the tags of the directories of the
+// 'shared-dirs' setting, the home directory uses dir0, dir1...
+const sharedDirTagPrefix = "share-"
+
+// MountSharedDir shares 'dir' with the running VM when the driver can add
+// it at runtime, which is only the case of libvirt. It returns false when
+// the directory is only mounted on the next start.
+func (client *client) MountSharedDir(dir config.SharedDir) (bool, error) {
+	dir, err := config.NormalizeSharedDir(dir)
+	if err != nil {
+		return false, err
+	}
+	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	if err != nil {
+		return false, errors.Wrap(err, "Cannot load machine")
+	}
+	defer vm.Close()
+	vmState, err := vm.State()
+	if err != nil {
+		return false, errors.Wrap(err, "Cannot get VM status")
+	}
+	if vmState != state.Running || !sharedDirsHotplug {
+		return false, nil
+	}
+
+	sshRunner, err := vm.SSHRunner()
+	if err != nil {
+		return false, errors.Wrap(err, "Error creating the ssh client")
+	}
+	defer sshRunner.Close()
+	sharedDirs, err := getSharedDirs(vm.Host)
+	if err != nil {
+		return false, err
+	}
+	if index := slices.IndexFunc(sharedDirs, func(mount libmachine.SharedDir) bool { return mount.Target == dir.Target }); index != -1 {
+		if err := unmountSharedDir(vm, sshRunner, sharedDirs[index]); err != nil {
+			return false, err
+		}
+		sharedDirs = slices.Delete(sharedDirs, index, index+1)
+	}
+
+	logging.Infof("Mounting %s at %s...", dir.Source, dir.Target)
+	mount := driverSharedDir(dir)
+	if err := attachSharedDirDevice(client.name, dir, true); err != nil {
+		return false, errors.Wrapf(err, "Cannot share %s with the VM", dir.Source)
+	}
+	if err := setSharedDirs(vm, append(sharedDirs, mount)); err != nil {
+		return false, err
+	}
+	if err := setSharedDirOwner(client.name, dir.Target, dir.Owner); err != nil {
+		return false, err
+	}
+	if err := mountSharedDir(vm, sshRunner, mount); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UnmountSharedDir removes the directory mounted at 'target' from the
+// running VM when the driver can do it at runtime. It returns false when the
+// directory is only removed on the next start.
+func (client *client) UnmountSharedDir(target string) (bool, error) {
+	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	if err != nil {
+		return false, errors.Wrap(err, "Cannot load machine")
+	}
+	defer vm.Close()
+	vmState, err := vm.State()
+	if err != nil {
+		return false, errors.Wrap(err, "Cannot get VM status")
+	}
+	if vmState != state.Running || !sharedDirsHotplug {
+		return false, nil
+	}
+	sharedDirs, err := getSharedDirs(vm.Host)
+	if err != nil {
+		return false, err
+	}
+	index := slices.IndexFunc(sharedDirs, func(mount libmachine.SharedDir) bool {
+		return isAdditionalSharedDir(mount) && mount.Target == target
+	})
+	if index == -1 {
+		return true, nil
+	}
+
+	sshRunner, err := vm.SSHRunner()
+	if err != nil {
+		return false, errors.Wrap(err, "Error creating the ssh client")
+	}
+	defer sshRunner.Close()
+	if err := unmountSharedDir(vm, sshRunner, sharedDirs[index]); err != nil {
+		return false, err
+	}
+	if err := setSharedDirs(vm, slices.Delete(sharedDirs, index, index+1)); err != nil {
+		return false, err
+	}
+	return true, setSharedDirOwner(client.name, target, "")
+}
+
+// updateSharedDirs replaces the directories of the 'shared-dirs' setting in
+// the configuration of the stopped VM
+func (client *client) updateSharedDirs(vm *virtualMachine, dirs []config.SharedDir) error {
+	if len(dirs) > 0 && runtime.GOOS == "windows" {
+		logging.Warn("The shared-dirs setting is ignored, only the home directory can be shared on Windows")
+		dirs = nil
+	}
+	sharedDirs, err := getSharedDirs(vm.Host)
+	if err != nil {
+		return err
+	}
+	sharedDirs = slices.DeleteFunc(sharedDirs, isAdditionalSharedDir)
+	owners := map[string]string{}
+	for _, dir := range dirs {
+		sharedDirs = append(sharedDirs, driverSharedDir(dir))
+		if dir.Owner != "" {
+			owners[dir.Target] = dir.Owner
+		}
+	}
+	if err := setSharedDirs(vm, sharedDirs); err != nil {
+		return err
+	}
+	if err := saveSharedDirOwners(client.name, owners); err != nil {
+		return err
+	}
+	return syncSharedDirDevices(client.name, dirs)
+}
+
+func getSharedDirs(host *host.Host) ([]libmachine.SharedDir, error) {
+	driver, err := loadDriverConfig(host)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Clone(driver.SharedDirs), nil
+}
+
+func setSharedDirs(vm *virtualMachine, sharedDirs []libmachine.SharedDir) error {
+	sharedDirsSetter := func(driver *libmachine.VMDriver) bool {
+		if slices.Equal(driver.SharedDirs, sharedDirs) {
+			return false
+		}
+		driver.SharedDirs = sharedDirs
+		return true
+	}
+	if err := updateDriverValue(vm.Host, sharedDirsSetter); err != nil {
+		return err
+	}
+	return vm.api.Save(vm.Host)
+}
+
+// additionalSharedDirs returns the directories of the 'shared-dirs' setting
+// in the configuration of the VM
+func additionalSharedDirs(host *host.Host) ([]config.SharedDir, error) {
+	sharedDirs, err := getSharedDirs(host)
+	if err != nil {
+		return nil, err
+	}
+	// the driver configuration has no owner field, it is kept aside
+	owners, err := loadSharedDirOwners(host.Name)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []config.SharedDir
+	for _, mount := range sharedDirs {
+		if isAdditionalSharedDir(mount) {
+			dirs = append(dirs, config.SharedDir{
+				Source:   mount.Source,
+				Target:   mount.Target,
+				ReadOnly: mount.ReadOnly,
+				Owner:    owners[mount.Target],
+			})
+		}
+	}
+	return dirs, nil
+}
+
+// loadSharedDirOwners returns the owners of the shared directories of the
+// instance 'name' indexed by their target
+func loadSharedDirOwners(name string) (map[string]string, error) {
+	owners := map[string]string{}
+	data, err := os.ReadFile(constants.GetSharedDirOwnersPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return owners, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &owners); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", constants.GetSharedDirOwnersPath(name))
+	}
+	return owners, nil
+}
+
+func saveSharedDirOwners(name string, owners map[string]string) error {
+	data, err := json.MarshalIndent(owners, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(constants.GetSharedDirOwnersPath(name), data, 0600)
+}
+
+// setSharedDirOwner records the owner of the directory mounted at 'target',
+// an empty owner removes it
+func setSharedDirOwner(name, target, owner string) error {
+	owners, err := loadSharedDirOwners(name)
+	if err != nil {
+		return err
+	}
+	if owner == "" {
+		delete(owners, target)
+	} else {
+		owners[target] = owner
+	}
+	return saveSharedDirOwners(name, owners)
+}
+
+func isAdditionalSharedDir(mount libmachine.SharedDir) bool {
+	return strings.HasPrefix(mount.Tag, sharedDirTagPrefix)
+}
+
+// sharedDirTag derives the tag of the directory from its target, the tags
+// of virtiofs are limited to 36 bytes
+func sharedDirTag(target string) string {
+	sum := sha256.Sum256([]byte(target))
+	return sharedDirTagPrefix + hex.EncodeToString(sum[:8])
+}
+
+func driverSharedDir(dir config.SharedDir) libmachine.SharedDir {
+	return libmachine.SharedDir{
+		Source:   dir.Source,
+		Target:   dir.Target,
+		Tag:      sharedDirTag(dir.Target),
+		Type:     "virtiofs",
+		ReadOnly: dir.ReadOnly,
+	}
+}
+
+func mountSharedDir(vm *virtualMachine, sshRunner *crcssh.Runner, mount libmachine.SharedDir) error {
+	// Try to create the mount directory and if it fails then
+	// make the file system mutable and again try to create the
+	// mount directory.
+	// If the directory is already exists, then `mkdir -p` won't return an error.
+	if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Creating %s", mount.Target), crcssh.QuoteCommand([]string{"mkdir", "-p", mount.Target})); err != nil {
+		if _, _, err := sshRunner.RunPrivileged("Making / mutable", "chattr", "-i", "/"); err != nil {
+			return err
+		}
+		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Creating %s", mount.Target), crcssh.QuoteCommand([]string{"mkdir", "-p", mount.Target})); err != nil {
+			return err
+		}
+		if _, _, err := sshRunner.RunPrivileged("Making / immutable again", "chattr", "+i", "/"); err != nil {
+			return err
+		}
+	}
+	logging.Debugf("Mounting tag %s at %s", mount.Tag, mount.Target)
+	switch mount.Type {
+	case "virtiofs":
+		options := "context=system_u:object_r:container_file_t:s0"
+		if mount.ReadOnly {
+			options = "ro," + options
+		}
+		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Mounting %s", mount.Target), crcssh.QuoteCommand([]string{"mount", "-o", options, "-t", mount.Type, mount.Tag, mount.Target})); err != nil {
+			return err
+		}
+
+	case "9p":
+		if vm.bundle.IsMicroshift() {
+			// temporarily disable 9P file sharing for microshift until
+			// new bundles are released
+			break
+		}
+		// change owner to core user to allow mounting to it as a non-root user
+		if _, _, err := sshRunner.RunPrivileged("Changing owner of mount directory", crcssh.QuoteCommand([]string{"chown", "core:core", mount.Target})); err != nil {
+			return err
+		}
+		if _, _, err := sshRunner.Run(crcssh.QuoteCommand([]string{"9pfs", "-V", "-p", fmt.Sprintf("%d", constants.Plan9HvsockPort), "2", mount.Target})); err != nil {
+			logging.Warnf("Failed to connect to 9p server over hvsock: %v", err)
+			logging.Warnf("Falling back to 9p over TCP")
+			if _, _, err := sshRunner.Run(crcssh.QuoteCommand([]string{"9pfs", constants.VSockGateway, mount.Target})); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("Unknown Shared dir type requested: %s", mount.Type)
+	}
+	return nil
+}
+
+func unmountSharedDir(vm *virtualMachine, sshRunner *crcssh.Runner, mount libmachine.SharedDir) error {
+	logging.Infof("Unmounting %s...", mount.Target)
+	if _, _, err := sshRunner.Run(crcssh.QuoteCommand([]string{"mountpoint", "-q", mount.Target})); err == nil {
+		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Unmounting %s", mount.Target), crcssh.QuoteCommand([]string{"umount", mount.Target})); err != nil {
+			return err
+		}
+	}
+	if err := detachSharedDirDevice(vm.name, mount.Tag, true); err != nil {
+		return errors.Wrapf(err, "Cannot stop sharing %s with the VM", mount.Source)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package machine
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/config"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+)
+
+// sharedDirsHotplug is true as libvirt can add virtiofs devices to a running
+// VM
+const sharedDirsHotplug = true
+
+type domainFilesystems struct {
+	Filesystems []filesystemDevice `xml:"devices>filesystem"`
+}
+
+type filesystemDevice struct {
+	XMLName    xml.Name  `xml:"filesystem"`
+	Type       string    `xml:"type,attr"`
+	AccessMode string    `xml:"accessmode,attr"`
+	Driver     fsDriver  `xml:"driver"`
+	Source     fsDir     `xml:"source"`
+	Target     fsDir     `xml:"target"`
+	ReadOnly   *struct{} `xml:"readonly"`
+	IDMap      *fsIDMap  `xml:"idmap"`
+}
+
+type fsDriver struct {
+	Type string `xml:"type,attr"`
+}
+
+type fsDir struct {
+	Dir string `xml:"dir,attr"`
+}
+
+type fsIDMap struct {
+	UID fsIDRange `xml:"uid"`
+	GID fsIDRange `xml:"gid"`
+}
+
+type fsIDRange struct {
+	Start  int `xml:"start,attr"`
+	Target int `xml:"target,attr"`
+	Count  int `xml:"count,attr"`
+}
+
+// syncSharedDirDevices replaces the virtiofs devices of the directories of
+// the 'shared-dirs' setting in the definition of the stopped libvirt domain.
+// They are not part of the domain template of the machine driver.
+func syncSharedDirDevices(domain string, dirs []config.SharedDir) error {
+	devices, err := filesystemDevices(domain)
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if strings.HasPrefix(device.Target.Dir, sharedDirTagPrefix) {
+			if err := modifyDomainDevice(domain, "detach-device", device, false); err != nil {
+				return err
+			}
+		}
+	}
+	for _, dir := range dirs {
+		if err := attachSharedDirDevice(domain, dir, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attachSharedDirDevice adds the virtiofs device of 'dir' to the libvirt
+// domain, and to the running VM when 'live' is true
+func attachSharedDirDevice(domain string, dir config.SharedDir, live bool) error {
+	device, err := toFilesystemDevice(dir)
+	if err != nil {
+		return err
+	}
+	return modifyDomainDevice(domain, "attach-device", device, live)
+}
+
+// detachSharedDirDevice removes the virtiofs device with the tag 'tag' from
+// the libvirt domain, and from the running VM when 'live' is true
+func detachSharedDirDevice(domain, tag string, live bool) error {
+	devices, err := filesystemDevices(domain)
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if device.Target.Dir == tag {
+			return modifyDomainDevice(domain, "detach-device", device, live)
+		}
+	}
+	return nil
+}
+
+func toFilesystemDevice(dir config.SharedDir) (filesystemDevice, error) {
+	device := filesystemDevice{
+		Type:       "mount",
+		AccessMode: "passthrough",
+		Driver:     fsDriver{Type: "virtiofs"},
+		Source:     fsDir{Dir: dir.Source},
+		Target:     fsDir{Dir: sharedDirTag(dir.Target)},
+	}
+	if dir.ReadOnly {
+		device.ReadOnly = &struct{}{}
+	}
+	if dir.Owner != "" {
+		uid, gid, _ := strings.Cut(dir.Owner, ":")
+		guestUID, err := strconv.Atoi(uid)
+		if err != nil {
+			return device, err
+		}
+		guestGID, err := strconv.Atoi(gid)
+		if err != nil {
+			return device, err
+		}
+		// the files of the host user are owned by UID:GID in the VM
+		device.IDMap = &fsIDMap{
+			UID: fsIDRange{Start: guestUID, Target: os.Getuid(), Count: 1},
+			GID: fsIDRange{Start: guestGID, Target: os.Getgid(), Count: 1},
+		}
+	}
+	return device, nil
+}
+
+func filesystemDevices(domain string) ([]filesystemDevice, error) {
+	stdout, stderr, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "dumpxml", domain, "--inactive")
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, stderr)
+	}
+	var filesystems domainFilesystems
+	if err := xml.Unmarshal([]byte(stdout), &filesystems); err != nil {
+		return nil, err
+	}
+	return filesystems.Filesystems, nil
+}
+
+// modifyDomainDevice runs 'virsh attach-device' or 'virsh detach-device'
+// with the definition of 'device'
+func modifyDomainDevice(domain, command string, device filesystemDevice, live bool) error {
+	data, err := xml.Marshal(device)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp("", "crc-shared-dir-*.xml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	args := []string{"--connect", "qemu:///system", command, domain, file.Name(), "--config"}
+	if live {
+		args = append(args, "--live")
+	}
+	_, stderr, err := crcos.RunWithDefaultLocale("virsh", args...)
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, stderr)
+	}
+	return nil
+}
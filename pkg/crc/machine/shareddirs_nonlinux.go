@@ -0,0 +1,34 @@
+//go:build !linux
+
+package machine
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/config"
+)
+
+// sharedDirsHotplug is false as vfkit and Hyper-V only share directories
+// when the VM starts
+const sharedDirsHotplug = false
+
+// syncSharedDirDevices has nothing to do, the driver shares the directories
+// of its configuration when the VM starts
+func syncSharedDirDevices(_ string, dirs []config.SharedDir) error {
+	for _, dir := range dirs {
+		if dir.Owner != "" {
+			logging.Warnf("The owner of %s is ignored, it is only supported on Linux", dir.Source)
+		}
+	}
+	return nil
+}
+
+func attachSharedDirDevice(_ string, _ config.SharedDir, _ bool) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
+
+func detachSharedDirDevice(_, _ string, _ bool) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
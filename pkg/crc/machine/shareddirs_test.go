@@ -0,0 +1,31 @@
+package machine
+
+import (
+	"os"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSharedDirOwners(t *testing.T) {
+	machineInstanceDir := constants.MachineInstanceDir
+	constants.MachineInstanceDir = t.TempDir()
+	defer func() {
+		constants.MachineInstanceDir = machineInstanceDir
+	}()
+	require.NoError(t, os.MkdirAll(constants.GetInstanceDir("crc"), 0750))
+
+	owners, err := loadSharedDirOwners("crc")
+	require.NoError(t, err)
+	assert.Empty(t, owners)
+
+	require.NoError(t, setSharedDirOwner("crc", "/mnt/data", "1000:1000"))
+	require.NoError(t, setSharedDirOwner("crc", "/mnt/src", "1001:1001"))
+	require.NoError(t, setSharedDirOwner("crc", "/mnt/src", ""))
+
+	owners, err = loadSharedDirOwners("crc")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/mnt/data": "1000:1000"}, owners)
+}
@@ -0,0 +1,225 @@
+package machine
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/validation"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+	"github.com/pkg/errors"
+	"go.podman.io/common/pkg/strongunits"
+)
+
+const (
+	snapshotMetadataFile = "snapshot.json"
+	snapshotDiskImage    = "disk.qcow2"
+)
+
+// snapshotFiles returns the instance files which must match the content of
+// the disk image, and are saved and restored together with it
+func snapshotFiles(name string) []string {
+	return []string{
+		constants.GetKubeconfigFilePath(name),
+		constants.GetKubeAdminPasswordPath(name),
+		constants.GetDeveloperPasswordPath(name),
+		constants.GetPrivateKeyPath(name),
+		constants.GetPublicKeyPath(name),
+	}
+}
+
+func (client *client) snapshotDir(snapshotName string) string {
+	return filepath.Join(constants.GetInstanceSnapshotsDir(client.name), snapshotName)
+}
+
+// loadStoppedVirtualMachine returns the virtual machine of the instance
+// after checking it is stopped, as its disk image can't be copied while it
+// is in use
+func (client *client) loadStoppedVirtualMachine() (*virtualMachine, error) {
+	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot load machine")
+	}
+	vmState, err := vm.State()
+	if err != nil {
+		vm.Close()
+		return nil, errors.Wrap(err, "Cannot get VM status")
+	}
+	if vmState != state.Stopped {
+		vm.Close()
+		return nil, errors.New("Instance must be stopped, run 'crc stop' first")
+	}
+	return vm, nil
+}
+
+func (client *client) CreateSnapshot(snapshotName string) (*types.SnapshotInfo, error) {
+	if err := validation.ValidateSnapshotName(snapshotName); err != nil {
+		return nil, err
+	}
+	snapshotDir := client.snapshotDir(snapshotName)
+	if crcos.FileExists(snapshotDir) {
+		return nil, fmt.Errorf("snapshot '%s' already exists", snapshotName)
+	}
+
+	vm, err := client.loadStoppedVirtualMachine()
+	if err != nil {
+		return nil, err
+	}
+	defer vm.Close()
+
+	if err := os.MkdirAll(snapshotDir, 0750); err != nil {
+		return nil, err
+	}
+	info, err := createSnapshot(vm, snapshotName, snapshotDir)
+	if err != nil {
+		if err := os.RemoveAll(snapshotDir); err != nil {
+			logging.Debugf("Failed to remove %s: %v", snapshotDir, err)
+		}
+		return nil, err
+	}
+	return info, nil
+}
+
+func createSnapshot(vm *virtualMachine, snapshotName, snapshotDir string) (*types.SnapshotInfo, error) {
+	logging.Infof("Saving the disk image of the instance to snapshot '%s'...", snapshotName)
+	diskPath := filepath.Join(snapshotDir, snapshotDiskImage)
+	if err := saveDiskImage(vm.name, vm.bundle.GetDiskImagePath(), diskPath); err != nil {
+		return nil, errors.Wrap(err, "Cannot save disk image")
+	}
+	for _, file := range snapshotFiles(vm.name) {
+		if !crcos.FileExists(file) {
+			continue
+		}
+		if err := crcos.CopyFile(file, filepath.Join(snapshotDir, filepath.Base(file))); err != nil {
+			return nil, err
+		}
+	}
+
+	diskInfo, err := os.Stat(diskPath)
+	if err != nil {
+		return nil, err
+	}
+	info := &types.SnapshotInfo{
+		Name:         snapshotName,
+		CreationTime: time.Now(),
+		BundleName:   vm.bundle.GetBundleName(),
+		DiskSize:     strongunits.B(diskInfo.Size()),
+	}
+	bin, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return info, os.WriteFile(filepath.Join(snapshotDir, snapshotMetadataFile), bin, 0600)
+}
+
+func loadSnapshotInfo(snapshotDir string) (*types.SnapshotInfo, error) {
+	bin, err := os.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	var info types.SnapshotInfo
+	if err := json.Unmarshal(bin, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (client *client) getSnapshot(snapshotName string) (*types.SnapshotInfo, error) {
+	if err := validation.ValidateSnapshotName(snapshotName); err != nil {
+		return nil, err
+	}
+	info, err := loadSnapshotInfo(client.snapshotDir(snapshotName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("snapshot '%s' does not exist", snapshotName)
+	}
+	return info, err
+}
+
+func (client *client) ListSnapshots() ([]types.SnapshotInfo, error) {
+	return listSnapshots(client.name)
+}
+
+// listSnapshots returns the snapshots of the instance 'name', the oldest
+// first
+func listSnapshots(name string) ([]types.SnapshotInfo, error) {
+	entries, err := os.ReadDir(constants.GetInstanceSnapshotsDir(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return []types.SnapshotInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snapshots := []types.SnapshotInfo{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := loadSnapshotInfo(filepath.Join(constants.GetInstanceSnapshotsDir(name), entry.Name()))
+		if err != nil {
+			logging.Debugf("Ignoring invalid snapshot %s: %v", entry.Name(), err)
+			continue
+		}
+		snapshots = append(snapshots, *info)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreationTime.Before(snapshots[j].CreationTime)
+	})
+	return snapshots, nil
+}
+
+func (client *client) RestoreSnapshot(snapshotName string) error {
+	info, err := client.getSnapshot(snapshotName)
+	if err != nil {
+		return err
+	}
+
+	vm, err := client.loadStoppedVirtualMachine()
+	if err != nil {
+		return err
+	}
+	defer vm.Close()
+
+	if info.BundleName != vm.bundle.GetBundleName() {
+		return fmt.Errorf("snapshot '%s' was created with bundle %s, but the instance uses %s", snapshotName, info.BundleName, vm.bundle.GetBundleName())
+	}
+
+	logging.Infof("Restoring the disk image of the instance from snapshot '%s'...", snapshotName)
+	snapshotDir := client.snapshotDir(snapshotName)
+	if err := restoreDiskImage(client.name, vm.bundle.GetDiskImagePath(), filepath.Join(snapshotDir, snapshotDiskImage)); err != nil {
+		return errors.Wrap(err, "Cannot restore disk image")
+	}
+	return restoreSnapshotFiles(snapshotDir, snapshotFiles(client.name))
+}
+
+// restoreSnapshotFiles copies the files saved in snapshotDir over the instance
+// files, and removes the instance files the snapshot did not have so that they
+// do not outlive the state they were created for.
+func restoreSnapshotFiles(snapshotDir string, files []string) error {
+	for _, file := range files {
+		saved := filepath.Join(snapshotDir, filepath.Base(file))
+		if !crcos.FileExists(saved) {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		if err := crcos.CopyFile(saved, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (client *client) DeleteSnapshot(snapshotName string) error {
+	if _, err := client.getSnapshot(snapshotName); err != nil {
+		return err
+	}
+	return os.RemoveAll(client.snapshotDir(snapshotName))
+}
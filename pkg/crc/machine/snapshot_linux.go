@@ -0,0 +1,35 @@
+package machine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+)
+
+func instanceDiskImagePath(name string) string {
+	return filepath.Join(constants.GetInstanceDir(name), fmt.Sprintf("%s.qcow2", name))
+}
+
+// saveDiskImage stores the instance disk image to destPath as a qcow2 overlay
+// on top of the bundle disk image, so that only the changes made since the
+// creation of the instance use disk space
+func saveDiskImage(name, bundleDiskImagePath, destPath string) error {
+	_, _, err := crcos.RunWithDefaultLocale("qemu-img", "convert", "-f", "qcow2", "-O", "qcow2",
+		"-B", bundleDiskImagePath, "-F", "qcow2", instanceDiskImagePath(name), destPath)
+	return err
+}
+
+func restoreDiskImage(name, bundleDiskImagePath, srcPath string) error {
+	diskImagePath := instanceDiskImagePath(name)
+	tmpPath := fmt.Sprintf("%s.restore", diskImagePath)
+	_, _, err := crcos.RunWithDefaultLocale("qemu-img", "convert", "-f", "qcow2", "-O", "qcow2",
+		"-B", bundleDiskImagePath, "-F", "qcow2", srcPath, tmpPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return os.Rename(tmpPath, diskImagePath)
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+
+package machine
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func saveDiskImage(_, _, _ string) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
+
+func restoreDiskImage(_, _, _ string) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
@@ -0,0 +1,88 @@
+package machine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSnapshot(t *testing.T, client *client, info types.SnapshotInfo) {
+	dir := client.snapshotDir(info.Name)
+	require.NoError(t, os.MkdirAll(dir, 0750))
+	bin, err := json.Marshal(info)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, snapshotMetadataFile), bin, 0600))
+}
+
+func TestListAndDeleteSnapshots(t *testing.T) {
+	machineInstanceDir := constants.MachineInstanceDir
+	constants.MachineInstanceDir = t.TempDir()
+	defer func() {
+		constants.MachineInstanceDir = machineInstanceDir
+	}()
+
+	client := &client{name: "crc"}
+	snapshots, err := client.ListSnapshots()
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	older := types.SnapshotInfo{Name: "older", CreationTime: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)}
+	newer := types.SnapshotInfo{Name: "newer", CreationTime: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)}
+	writeSnapshot(t, client, newer)
+	writeSnapshot(t, client, older)
+	require.NoError(t, os.MkdirAll(client.snapshotDir("incomplete"), 0750))
+
+	snapshots, err = client.ListSnapshots()
+	require.NoError(t, err)
+	assert.Equal(t, []types.SnapshotInfo{older, newer}, snapshots)
+
+	assert.NoError(t, client.DeleteSnapshot("older"))
+	assert.EqualError(t, client.DeleteSnapshot("older"), "snapshot 'older' does not exist")
+	assert.Error(t, client.DeleteSnapshot("../crc"))
+
+	snapshots, err = client.ListSnapshots()
+	require.NoError(t, err)
+	assert.Equal(t, []types.SnapshotInfo{newer}, snapshots)
+}
+
+func TestSnapshotBundlesAreInUse(t *testing.T) {
+	machineInstanceDir := constants.MachineInstanceDir
+	constants.MachineInstanceDir = t.TempDir()
+	defer func() {
+		constants.MachineInstanceDir = machineInstanceDir
+	}()
+
+	client := &client{name: "crc"}
+	writeSnapshot(t, client, types.SnapshotInfo{Name: "before-upgrade", BundleName: "crc_libvirt_4.18.1_amd64.crcbundle"})
+
+	inUse := map[string][]string{}
+	require.NoError(t, addSnapshotBundles(inUse, "crc"))
+	require.NoError(t, addSnapshotBundles(inUse, "other"))
+	assert.Equal(t, map[string][]string{
+		"crc_libvirt_4.18.1_amd64": {"crc (snapshot before-upgrade)"},
+	}, inUse)
+}
+
+func TestRestoreSnapshotFiles(t *testing.T) {
+	snapshotDir := t.TempDir()
+	instanceDir := t.TempDir()
+	saved := filepath.Join(instanceDir, "kubeconfig")
+	unsaved := filepath.Join(instanceDir, "kubeadmin-password")
+	require.NoError(t, os.WriteFile(filepath.Join(snapshotDir, "kubeconfig"), []byte("snapshot"), 0600))
+	require.NoError(t, os.WriteFile(saved, []byte("instance"), 0600))
+	require.NoError(t, os.WriteFile(unsaved, []byte("instance"), 0600))
+
+	require.NoError(t, restoreSnapshotFiles(snapshotDir, []string{saved, unsaved, filepath.Join(instanceDir, "missing")}))
+
+	content, err := os.ReadFile(saved)
+	require.NoError(t, err)
+	assert.Equal(t, "snapshot", string(content))
+	assert.NoFileExists(t, unsaved)
+}
@@ -6,7 +6,9 @@ import (
 	"crypto/x509"
 	"fmt"
 	"math/rand"
+	"net"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -19,12 +21,14 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/config"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/progress"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	"github.com/crc-org/crc/v2/pkg/crc/network"
 	"github.com/crc-org/crc/v2/pkg/crc/network/httpproxy"
 	"github.com/crc-org/crc/v2/pkg/crc/oc"
 	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/crc-org/crc/v2/pkg/crc/provision"
 	"github.com/crc-org/crc/v2/pkg/crc/services"
 	"github.com/crc-org/crc/v2/pkg/crc/services/dns"
 	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
@@ -32,6 +36,7 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/telemetry"
 	crctls "github.com/crc-org/crc/v2/pkg/crc/tls"
 	"github.com/crc-org/crc/v2/pkg/crc/validation"
+	"github.com/crc-org/crc/v2/pkg/download"
 	"github.com/crc-org/crc/v2/pkg/libmachine/host"
 	crcos "github.com/crc-org/crc/v2/pkg/os"
 	"github.com/crc-org/machine/libmachine/drivers"
@@ -43,19 +48,28 @@ import (
 
 const minimumMemoryForMonitoring = strongunits.MiB(14336)
 
-func getCrcBundleInfo(ctx context.Context, preset crcPreset.Preset, bundleName, bundlePath string, enableBundleQuayFallback bool) (*bundle.CrcBundleInfo, error) {
+func getCrcBundleInfo(ctx context.Context, preset crcPreset.Preset, bundleName, bundlePath string, bundleMirrors []string) (*bundle.CrcBundleInfo, error) {
 	bundleInfo, err := bundle.Use(bundleName)
 	if err == nil {
 		logging.Infof("Loading bundle: %s...", bundleName)
+		progress.StartPhase(ctx, progress.PhaseBundleExtraction, fmt.Sprintf("Loading bundle %s", bundleName))
 		return bundleInfo, nil
 	}
 	logging.Debugf("Failed to load bundle %s: %v", bundleName, err)
 	logging.Infof("Downloading bundle: %s...", bundleName)
-	bundlePath, err = bundle.Download(ctx, preset, bundlePath, enableBundleQuayFallback)
+	progress.StartPhase(ctx, progress.PhaseBundleExtraction, fmt.Sprintf("Downloading bundle %s", bundleName))
+	downloadCtx := download.WithProgress(ctx, func(current, total int64) {
+		if total > 0 {
+			// the extraction is the second half of the phase
+			progress.Update(ctx, float64(current)/float64(total)/2, "")
+		}
+	})
+	bundlePath, err = bundle.Download(downloadCtx, preset, bundlePath, bundleMirrors)
 	if err != nil {
 		return nil, err
 	}
 	logging.Infof("Extracting bundle: %s...", bundleName)
+	progress.Update(ctx, 0.5, fmt.Sprintf("Extracting bundle %s", bundleName))
 	if _, err := bundle.Extract(ctx, bundlePath); err != nil {
 		return nil, err
 	}
@@ -100,6 +114,11 @@ func (client *client) updateVMConfig(startConfig types.StartConfig, vm *virtualM
 		}
 	}
 
+	/* Shared directories */
+	if err := client.updateSharedDirs(vm, startConfig.SharedDirs); err != nil {
+		return errors.Wrap(err, "Failed to update the shared directories")
+	}
+
 	return nil
 }
 
@@ -200,7 +219,9 @@ func growLVForMicroshift(sshRunner crcos.CommandRunner, lvFullName string, rootP
 	return nil
 }
 
-func configureSharedDirs(vm *virtualMachine, sshRunner *crcssh.Runner) error {
+// configureSharedDirs mounts the directories of the 'shared-dirs' setting,
+// and the home directory when 'mountHomeDir' is true
+func configureSharedDirs(vm *virtualMachine, sshRunner *crcssh.Runner, mountHomeDir bool) error {
 	logging.Debugf("Configuring shared directories")
 	sharedDirs, err := vm.Driver.GetSharedDirs()
 	if err != nil {
@@ -214,60 +235,33 @@ func configureSharedDirs(vm *virtualMachine, sshRunner *crcssh.Runner) error {
 		}
 		return err
 	}
+	if !mountHomeDir {
+		sharedDirs = slices.DeleteFunc(sharedDirs, func(mount drivers.SharedDir) bool {
+			return !isAdditionalSharedDir(mount)
+		})
+	}
 	if len(sharedDirs) == 0 {
 		return nil
 	}
 	logging.Infof("Configuring shared directories")
 	for _, mount := range sharedDirs {
-		// Try to create the mount directory and if it fails then
-		// make the file system mutable and again try to create the
-		// mount directory.
-		// If the directory is already exists, then `mkdir -p` won't return an error.
-		if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Creating %s", mount.Target), "mkdir", "-p", mount.Target); err != nil {
-			if _, _, err := sshRunner.RunPrivileged("Making / mutable", "chattr", "-i", "/"); err != nil {
-				return err
-			}
-			if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Creating %s", mount.Target), "mkdir", "-p", mount.Target); err != nil {
-				return err
-			}
-			if _, _, err := sshRunner.RunPrivileged("Making / immutable again", "chattr", "+i", "/"); err != nil {
-				return err
-			}
-		}
-		logging.Debugf("Mounting tag %s at %s", mount.Tag, mount.Target)
-		switch mount.Type {
-		case "virtiofs":
-			if _, _, err := sshRunner.RunPrivileged(fmt.Sprintf("Mounting %s", mount.Target), "mount", "-o", "context=\"system_u:object_r:container_file_t:s0\"", "-t", mount.Type, mount.Tag, mount.Target); err != nil {
-				return err
-			}
-
-		case "9p":
-			if vm.bundle.IsMicroshift() {
-				// temporarily disable 9P file sharing for microshift until
-				// new bundles are released
-				break
-			}
-			// change owner to core user to allow mounting to it as a non-root user
-			if _, _, err := sshRunner.RunPrivileged("Changing owner of mount directory", "chown", "core:core", mount.Target); err != nil {
-				return err
-			}
-			if _, _, err := sshRunner.Run("9pfs -V -p", fmt.Sprintf("%d", constants.Plan9HvsockPort), "2", mount.Target); err != nil {
-				logging.Warnf("Failed to connect to 9p server over hvsock: %v", err)
-				logging.Warnf("Falling back to 9p over TCP")
-				if _, _, err := sshRunner.Run("9pfs", constants.VSockGateway, mount.Target); err != nil {
-					return err
-				}
-			}
-
-		default:
-			return fmt.Errorf("Unknown Shared dir type requested: %s", mount.Type)
+		if err := mountSharedDir(vm, sshRunner, mount); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func (client *client) Start(ctx context.Context, startConfig types.StartConfig) (*types.StartResult, error) {
+func (client *client) Start(ctx context.Context, startConfig types.StartConfig) (_ *types.StartResult, err error) {
+	defer func() {
+		if err != nil {
+			progress.Fail(ctx, err)
+			return
+		}
+		progress.Done(ctx)
+	}()
+
 	telemetry.SetCPUs(ctx, startConfig.CPUs)
 	telemetry.SetMemory(ctx, uint64(startConfig.Memory.ToBytes()))
 	telemetry.SetDiskSize(ctx, uint64(startConfig.DiskSize.ToBytes()))
@@ -283,17 +277,21 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 	}
 
 	if exists {
-		if err := checkMachineInstanceDir(); err != nil {
+		if err := checkMachineInstanceDir(client.name); err != nil {
 			return nil, err
 		}
 	}
 
+	if err := checkNoOtherInstanceRunning(client.name); err != nil {
+		return nil, err
+	}
+
 	bundleNameFromURI, err := bundle.GetBundleNameFromURI(startConfig.BundlePath)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error getting bundle name")
 	}
 	bundleName := bundle.GetBundleNameWithoutExtension(bundleNameFromURI)
-	crcBundleMetadata, err := getCrcBundleInfo(ctx, startConfig.Preset, bundleName, startConfig.BundlePath, startConfig.EnableBundleQuayFallback)
+	crcBundleMetadata, err := getCrcBundleInfo(ctx, startConfig.Preset, bundleName, startConfig.BundlePath, startConfig.BundleMirrors)
 	if err != nil {
 		return nil, errors.Wrap(err, "Error getting bundle metadata")
 	}
@@ -312,6 +310,7 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		}
 
 		logging.Infof("Creating CRC VM for %s %s...", startConfig.Preset.ForDisplay(), crcBundleMetadata.GetVersion())
+		progress.StartPhase(ctx, progress.PhaseVMCreation, fmt.Sprintf("Creating the VM for %s %s", startConfig.Preset.ForDisplay(), crcBundleMetadata.GetVersion()))
 
 		sharedDirs := []string{}
 		if homeDir, err := os.UserHomeDir(); err == nil {
@@ -362,7 +361,7 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 	}
 	if vmState == state.Running {
 		logging.Infof("A CRC VM for %s %s is already running", startConfig.Preset.ForDisplay(), vm.bundle.GetVersion())
-		clusterConfig, err := getClusterConfig(vm.bundle)
+		clusterConfig, err := getClusterConfig(client.name, vm.bundle)
 		if err != nil {
 			return nil, errors.Wrap(err, "Cannot create cluster configuration")
 		}
@@ -380,9 +379,13 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 	}
 
 	logging.Infof("Starting CRC VM for %s %s...", startConfig.Preset, vm.bundle.GetVersion())
+	progress.StartPhase(ctx, progress.PhaseVMStart, fmt.Sprintf("Starting the VM for %s %s", startConfig.Preset, vm.bundle.GetVersion()))
 
 	if client.useVSock() {
-		if err := exposePorts(startConfig.Preset, startConfig.IngressHTTPPort, startConfig.IngressHTTPSPort); err != nil {
+		if err := exposePorts(client.name, startConfig.Preset, startConfig.IngressHTTPPort, startConfig.IngressHTTPSPort); err != nil {
+			return nil, err
+		}
+		if err := addAppsDomainToDNS(startConfig.AppsDomain); err != nil {
 			return nil, err
 		}
 	}
@@ -416,24 +419,25 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 	defer sshRunner.Close()
 
 	logging.Debug("Waiting until ssh is available")
+	progress.StartPhase(ctx, progress.PhaseSSHReady, "Waiting for SSH to be available")
 	if err := sshRunner.WaitForConnectivity(ctx, 300*time.Second); err != nil {
 		return nil, errors.Wrap(err, "Failed to connect to the CRC VM with SSH -- virtual machine might be unreachable")
 	}
 	logging.Info("CRC VM is running")
 
 	if startConfig.EmergencyLogin {
-		if err := enableEmergencyLogin(sshRunner); err != nil {
+		if err := enableEmergencyLogin(client.name, sshRunner); err != nil {
 			return nil, errors.Wrap(err, "Error enabling emergency login")
 		}
 	} else {
-		if err := disableEmergencyLogin(sshRunner); err != nil {
+		if err := disableEmergencyLogin(client.name, sshRunner); err != nil {
 			return nil, errors.Wrap(err, "Error deleting the password for core user")
 		}
 	}
 
 	// Post VM start immediately update SSH key and copy kubeconfig to instance
 	// dir and VM
-	if err := updateSSHKeyPair(sshRunner); err != nil {
+	if err := updateSSHKeyPair(client.name, sshRunner); err != nil {
 		return nil, errors.Wrap(err, "Error updating public key")
 	}
 
@@ -464,10 +468,8 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 			return nil, errors.Wrap(err, "Failed to add nameserver to the VM")
 		}
 	}
-	if startConfig.EnableSharedDirs {
-		if err := configureSharedDirs(vm, sshRunner); err != nil {
-			return nil, err
-		}
+	if err := configureSharedDirs(vm, sshRunner, startConfig.EnableSharedDirs); err != nil {
+		return nil, err
 	}
 
 	if _, _, err := sshRunner.RunPrivileged("make root Podman socket accessible", "chmod 777 /run/podman/ /run/podman/podman.sock"); err != nil {
@@ -492,9 +494,11 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		BundleMetadata:  *vm.bundle,
 		NetworkMode:     client.networkMode(),
 		ModifyHostsFile: client.modifyHostsFile(),
+		AppsDomain:      startConfig.AppsDomain,
 	}
 
 	// Run the DNS server inside the VM
+	progress.StartPhase(ctx, progress.PhaseDNSChecks, "Checking the DNS resolution")
 	if err := dns.RunPostStart(servicePostStartConfig); err != nil {
 		return nil, errors.Wrap(err, "Error running post start")
 	}
@@ -524,6 +528,10 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		logging.Warn(fmt.Sprintf("Failed to query DNS from host: %v", err))
 	}
 
+	if err := client.configureRegistryCache(sshRunner); err != nil {
+		return nil, errors.Wrap(err, "Failed to configure the registry cache")
+	}
+
 	if vm.bundle.IsMicroshift() {
 		// **************************
 		//  END OF MICROSHIFT START CODE
@@ -533,7 +541,8 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		ocConfig.Context = "microshift"
 		ocConfig.Cluster = "microshift"
 
-		if err := startMicroshift(ctx, sshRunner, ocConfig, startConfig.PullSecret); err != nil {
+		progress.StartPhase(ctx, progress.PhaseClusterStart, "Starting MicroShift")
+		if err := startMicroshift(ctx, sshRunner, ocConfig, constants.GetKubeconfigFilePath(client.name), startConfig.PullSecret); err != nil {
 			return nil, err
 		}
 
@@ -543,7 +552,11 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 			}
 		}
 		logging.Info("Adding microshift context to kubeconfig...")
-		if err := mergeKubeConfigFile(constants.KubeconfigFilePath); err != nil {
+		if err := mergeKubeConfigFile(client.name, constants.GetKubeconfigFilePath(client.name)); err != nil {
+			return nil, err
+		}
+
+		if err := provisionCluster(ctx, startConfig.ProvisionFile, ocConfig, sshRunner, constants.GetKubeconfigFilePath(client.name)); err != nil {
 			return nil, err
 		}
 
@@ -555,6 +568,7 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 
 	// Check the certs validity inside the vm
 	logging.Info("Verifying validity of the kubelet certificates...")
+	progress.StartPhase(ctx, progress.PhaseCertRenewal, "Verifying the validity of the kubelet certificates")
 	certsExpired, err := cluster.CheckCertsValidity(sshRunner)
 	if err != nil {
 		return nil, errors.Wrap(err, "Failed to check certificate validity")
@@ -573,6 +587,7 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		return nil, errors.Wrap(err, "Failed to renew TLS certificates: please check if a newer CRC release is available")
 	}
 
+	progress.StartPhase(ctx, progress.PhaseClusterStart, "Waiting for the API server")
 	if err := cluster.WaitForAPIServer(ctx, ocConfig); err != nil {
 		return nil, errors.Wrap(err, "Error waiting for apiserver")
 	}
@@ -585,15 +600,16 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		return nil, err
 	}
 
+	progress.StartPhase(ctx, progress.PhasePullSecret, "Updating the pull secret and the credentials of the cluster")
 	if err := cluster.EnsurePullSecretPresentInTheCluster(ctx, ocConfig, startConfig.PullSecret); err != nil {
 		return nil, errors.Wrap(err, "Failed to update cluster pull secret")
 	}
 
-	if err := cluster.EnsureSSHKeyPresentInTheCluster(ctx, ocConfig, constants.GetPublicKeyPath()); err != nil {
+	if err := cluster.EnsureSSHKeyPresentInTheCluster(ctx, ocConfig, constants.GetPublicKeyPath(client.name)); err != nil {
 		return nil, errors.Wrap(err, "Failed to update ssh public key to machine config")
 	}
 
-	if err := cluster.UpdateUserPasswords(ctx, ocConfig, startConfig.KubeAdminPassword, startConfig.DeveloperPassword); err != nil {
+	if err := cluster.UpdateUserPasswords(ctx, ocConfig, client.name, startConfig.KubeAdminPassword, startConfig.DeveloperPassword); err != nil {
 		return nil, errors.Wrap(err, "Failed to update kubeadmin user password")
 	}
 
@@ -614,12 +630,21 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 		}
 	}
 
-	if err := updateKubeconfig(ctx, ocConfig, sshRunner, vm.bundle.GetKubeConfigPath()); err != nil {
+	if err := updateKubeconfig(ctx, ocConfig, sshRunner, vm.bundle.GetKubeConfigPath(), constants.GetKubeconfigFilePath(client.name)); err != nil {
 		return nil, errors.Wrap(err, "Failed to update kubeconfig file")
 	}
 
+	// the renewal of the cluster certificates may revert the custom ones
+	customCA, err := configureServingCertificates(ctx, client.name, ocConfig, sshRunner, vm.bundle, startConfig, cluster.CertsRenewed(certsExpired))
+	if err != nil {
+		return nil, err
+	}
+
+	ensureVolumesInTheCluster(ctx, client.name, ocConfig, sshRunner)
+
 	logging.Infof("Starting %s instance... [waiting for the cluster to stabilize]", startConfig.Preset)
-	if err := cluster.WaitForClusterStable(ctx, instanceIP, constants.KubeconfigFilePath, proxyConfig); err != nil {
+	progress.StartPhase(ctx, progress.PhaseOperatorsStabilizing, "Waiting for the cluster operators to stabilize")
+	if err := cluster.WaitForClusterStable(ctx, instanceIP, constants.GetKubeconfigFilePath(client.name), proxyConfig); err != nil {
 		logging.Warnf("Cluster is not ready: %v", err)
 	}
 
@@ -629,16 +654,31 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 
 	waitForProxyPropagation(ctx, ocConfig, proxyConfig)
 
-	clusterConfig, err := getClusterConfig(vm.bundle)
+	clusterConfig, err := getClusterConfig(client.name, vm.bundle)
 	if err != nil {
 		return nil, errors.Wrap(err, "Cannot get cluster configuration")
 	}
 
-	logging.Info("Adding crc-admin and crc-developer contexts to kubeconfig...")
-	if err := writeKubeconfig(instanceIP, clusterConfig, startConfig.IngressHTTPSPort); err != nil {
+	logging.Infof("Adding %s and %s contexts to kubeconfig...", adminContext(client.name), developerContext(client.name))
+	if err := writeKubeconfig(client.name, instanceIP, clusterConfig, startConfig.IngressHTTPSPort, customCA); err != nil {
 		logging.Errorf("Cannot update kubeconfig: %v", err)
 	}
 
+	if client.trustClusterCA() {
+		logging.Info("Adding the cluster CAs to the host trust store...")
+		if err := addClusterCAToTrustStore(ocConfig, clusterConfig, customCA); err != nil {
+			logging.Warnf("Cannot add the cluster CAs to the host trust store: %v", err)
+		}
+	}
+
+	if err := provisionCluster(ctx, startConfig.ProvisionFile, ocConfig, sshRunner, constants.GetKubeconfigFilePath(client.name)); err != nil {
+		return nil, err
+	}
+
+	if startConfig.AppsDomain != "" {
+		logHostResolutionOfAppsDomain(startConfig.AppsDomain, instanceIP, client.useVSock())
+	}
+
 	return &types.StartResult{
 		KubeletStarted: true,
 		ClusterConfig:  *clusterConfig,
@@ -646,6 +686,38 @@ func (client *client) Start(ctx context.Context, startConfig types.StartConfig)
 	}, nil
 }
 
+// configureRegistryCache makes the cluster pull the images through the
+// registry cache of the daemon, which can only be reached from the user-mode
+// network
+func (client *client) configureRegistryCache(sshRunner *crcssh.Runner) error {
+	registries := client.registryCacheRegistries()
+	if len(registries) > 0 && !client.useVSock() {
+		logging.Warnf("The registry cache is not used, it requires the '%s' network mode", network.UserNetworkingMode)
+		registries = nil
+	}
+	location := net.JoinHostPort(constants.VSockGateway, strconv.Itoa(constants.RegistryCachePort))
+	return cluster.ConfigureRegistryCache(sshRunner, location, registries)
+}
+
+// provisionCluster applies the manifests, operators and scripts described in
+// 'provisionFile' once the cluster is up. This is done on every start, so the
+// provisioning steps must be idempotent.
+func provisionCluster(ctx context.Context, provisionFile string, ocConfig oc.Config, sshRunner *crcssh.Runner, kubeconfigPath string) error {
+	if provisionFile == "" {
+		return nil
+	}
+	provisionConfig, err := provision.Load(provisionFile)
+	if err != nil {
+		return err
+	}
+	logging.Infof("Provisioning the cluster using %s...", provisionFile)
+	progress.StartPhase(ctx, progress.PhaseProvisioning, fmt.Sprintf("Provisioning the cluster using %s", provisionFile))
+	if err := provision.NewProvisioner(ocConfig, sshRunner, kubeconfigPath).Provision(ctx, provisionConfig); err != nil {
+		return errors.Wrap(err, "Failed to provision the cluster")
+	}
+	return nil
+}
+
 func (client *client) IsRunning() (bool, error) {
 	vm, err := loadVirtualMachine(client.name, client.useVSock())
 	if err != nil {
@@ -666,6 +738,15 @@ func (client *client) IsRunning() (bool, error) {
 }
 
 func (client *client) validateStartConfig(startConfig types.StartConfig) error {
+	if startConfig.AppsDomain != "" && startConfig.Preset == crcPreset.Microshift {
+		return fmt.Errorf("A custom apps domain is not supported with the %s preset", crcPreset.Microshift)
+	}
+	if err := validateCustomCertificates(startConfig.CustomCertificates); err != nil {
+		return err
+	}
+	if (startConfig.CustomCertificates != types.CustomCertificates{}) && startConfig.Preset == crcPreset.Microshift {
+		return fmt.Errorf("Custom certificates are not supported with the %s preset", crcPreset.Microshift)
+	}
 	if client.monitoringEnabled() && startConfig.Memory < minimumMemoryForMonitoring {
 		return fmt.Errorf("Too little memory (%s) allocated to the virtual machine to start the monitoring stack, %s is the minimum",
 			units.BytesSize(float64(startConfig.Memory.ToBytes())),
@@ -700,14 +781,14 @@ func createHost(machineConfig config.MachineConfig, preset crcPreset.Preset) err
 	}
 
 	logging.Info("Generating new SSH key pair...")
-	if err := crcssh.GenerateSSHKey(constants.GetPrivateKeyPath()); err != nil {
+	if err := crcssh.GenerateSSHKey(constants.GetPrivateKeyPath(machineConfig.Name)); err != nil {
 		return fmt.Errorf("Error generating ssh key pair: %v", err)
 	}
 	if preset == crcPreset.OpenShift || preset == crcPreset.OKD {
-		if err := cluster.GenerateUserPassword(constants.GetKubeAdminPasswordPath(), "kubeadmin"); err != nil {
+		if err := cluster.GenerateUserPassword(constants.GetKubeAdminPasswordPath(machineConfig.Name), "kubeadmin"); err != nil {
 			return errors.Wrap(err, "Error generating new kubeadmin password")
 		}
-		if err = os.WriteFile(constants.GetDeveloperPasswordPath(), []byte(constants.DefaultDeveloperPassword), 0o600); err != nil {
+		if err = os.WriteFile(constants.GetDeveloperPasswordPath(machineConfig.Name), []byte(constants.DefaultDeveloperPassword), 0o600); err != nil {
 			return errors.Wrap(err, "Error writing developer password")
 		}
 	}
@@ -752,8 +833,9 @@ func addNameServerToInstance(sshRunner *crcssh.Runner, ns string) error {
 	return nil
 }
 
-func enableEmergencyLogin(sshRunner *crcssh.Runner) error {
-	if crcos.FileExists(constants.PasswdFilePath) {
+func enableEmergencyLogin(name string, sshRunner *crcssh.Runner) error {
+	passwdFilePath := constants.GetPasswdFilePath(name)
+	if crcos.FileExists(passwdFilePath) {
 		return nil
 	}
 	charset := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -761,23 +843,23 @@ func enableEmergencyLogin(sshRunner *crcssh.Runner) error {
 	for i := range b {
 		b[i] = charset[rand.Intn(len(charset))] //nolint:gosec
 	}
-	if err := os.WriteFile(constants.PasswdFilePath, b, 0o600); err != nil {
+	if err := os.WriteFile(passwdFilePath, b, 0o600); err != nil {
 		return err
 	}
-	logging.Infof("Emergency login password for core user is stored to %s", constants.PasswdFilePath)
+	logging.Infof("Emergency login password for core user is stored to %s", passwdFilePath)
 	_, _, err := sshRunner.Run(fmt.Sprintf("sudo passwd core -f --unlock && echo %s | sudo passwd core --stdin", b))
 	return err
 }
 
-func disableEmergencyLogin(sshRunner *crcssh.Runner) error {
-	defer os.Remove(constants.PasswdFilePath)
+func disableEmergencyLogin(name string, sshRunner *crcssh.Runner) error {
+	defer os.Remove(constants.GetPasswdFilePath(name))
 	_, _, err := sshRunner.RunPrivileged("disable core user password", "passwd", "--lock", "core")
 	return err
 }
 
-func updateSSHKeyPair(sshRunner *crcssh.Runner) error {
+func updateSSHKeyPair(name string, sshRunner *crcssh.Runner) error {
 	// Read generated public key
-	publicKey, err := os.ReadFile(constants.GetPublicKeyPath())
+	publicKey, err := os.ReadFile(constants.GetPublicKeyPath(name))
 	if err != nil {
 		return err
 	}
@@ -799,7 +881,7 @@ func updateSSHKeyPair(sshRunner *crcssh.Runner) error {
 }
 
 func copyKubeconfigFileWithUpdatedUserClientCertAndKey(selfSignedCAKey *rsa.PrivateKey, selfSignedCACert *x509.Certificate, srcKubeConfigPath, dstKubeConfigPath string) error {
-	if _, err := os.Stat(constants.KubeconfigFilePath); err == nil {
+	if _, err := os.Stat(dstKubeConfigPath); err == nil {
 		return nil
 	}
 	clientKey, clientCert, err := crctls.GenerateClientCertificate(selfSignedCAKey, selfSignedCACert)
@@ -861,25 +943,25 @@ func ensureRoutesControllerIsRunning(sshRunner *crcssh.Runner, ocConfig oc.Confi
 	return err
 }
 
-func updateKubeconfig(ctx context.Context, ocConfig oc.Config, sshRunner *crcssh.Runner, kubeconfigFilePath string) error {
+func updateKubeconfig(ctx context.Context, ocConfig oc.Config, sshRunner *crcssh.Runner, bundleKubeconfigFilePath, kubeconfigFilePath string) error {
 	selfSignedCAKey, selfSignedCACert, err := crctls.GetSelfSignedCA()
 	if err != nil {
 		return errors.Wrap(err, "Not able to generate root CA key and Cert")
 	}
-	if err := copyKubeconfigFileWithUpdatedUserClientCertAndKey(selfSignedCAKey, selfSignedCACert, kubeconfigFilePath, constants.KubeconfigFilePath); err != nil {
-		return errors.Wrapf(err, "Failed to copy kubeconfig file: %s", constants.KubeconfigFilePath)
+	if err := copyKubeconfigFileWithUpdatedUserClientCertAndKey(selfSignedCAKey, selfSignedCACert, bundleKubeconfigFilePath, kubeconfigFilePath); err != nil {
+		return errors.Wrapf(err, "Failed to copy kubeconfig file: %s", kubeconfigFilePath)
 	}
-	adminClientCA, err := adminClientCertificate(constants.KubeconfigFilePath)
+	adminClientCA, err := adminClientCertificate(kubeconfigFilePath)
 	if err != nil {
 		return errors.Wrap(err, "Not able to get user CA")
 	}
-	if err := cluster.EnsureGeneratedClientCAPresentInTheCluster(ctx, ocConfig, sshRunner, selfSignedCACert, adminClientCA); err != nil {
+	if err := cluster.EnsureGeneratedClientCAPresentInTheCluster(ctx, ocConfig, sshRunner, kubeconfigFilePath, selfSignedCACert, adminClientCA); err != nil {
 		return errors.Wrap(err, "Failed to update user CA to cluster")
 	}
 	return nil
 }
 
-func startMicroshift(ctx context.Context, sshRunner *crcssh.Runner, ocConfig oc.Config, pullSec cluster.PullSecretLoader) error {
+func startMicroshift(ctx context.Context, sshRunner *crcssh.Runner, ocConfig oc.Config, kubeconfigFilePath string, pullSec cluster.PullSecretLoader) error {
 	logging.Infof("Starting Microshift service... [takes around 1min]")
 	if err := ensurePullSecretPresentInVM(sshRunner, pullSec); err != nil {
 		return err
@@ -887,20 +969,20 @@ func startMicroshift(ctx context.Context, sshRunner *crcssh.Runner, ocConfig oc.
 	if _, _, err := sshRunner.RunPrivileged("Starting microshift service", "systemctl", "start", "microshift"); err != nil {
 		return err
 	}
-	if err := sshRunner.CopyFileFromVM(fmt.Sprintf("/var/lib/microshift/resources/kubeadmin/api%s/kubeconfig", constants.ClusterDomain), constants.KubeconfigFilePath, 0o600); err != nil {
+	if err := sshRunner.CopyFileFromVM(fmt.Sprintf("/var/lib/microshift/resources/kubeadmin/api%s/kubeconfig", constants.ClusterDomain), kubeconfigFilePath, 0o600); err != nil {
 		return err
 	}
-	if err := sshRunner.CopyFile(constants.KubeconfigFilePath, "/opt/kubeconfig", 0o644); err != nil {
+	if err := sshRunner.CopyFile(kubeconfigFilePath, "/opt/kubeconfig", 0o644); err != nil {
 		return err
 	}
 
 	return cluster.WaitForAPIServer(ctx, ocConfig)
 }
 
-func checkMachineInstanceDir() error {
+func checkMachineInstanceDir(name string) error {
 	requiredFiles := []string{
-		constants.GetPrivateKeyPath(),
-		constants.GetPublicKeyPath(),
+		constants.GetPrivateKeyPath(name),
+		constants.GetPublicKeyPath(name),
 	}
 	for _, filePath := range requiredFiles {
 		if !crcos.FileExists(filePath) {
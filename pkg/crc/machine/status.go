@@ -18,7 +18,7 @@ import (
 	"github.com/pkg/errors"
 )
 
-type openShiftStatusSupplierFunc func(context.Context, string) types.OpenshiftStatus
+type openShiftStatusSupplierFunc func(context.Context, string) (types.OpenshiftStatus, *types.ClusterOperatorsStatus)
 
 func (client *client) Status() (*types.ClusterStatusResult, error) {
 	vm, err := loadVirtualMachine(client.name, client.useVSock())
@@ -49,12 +49,22 @@ func (client *client) Status() (*types.ClusterStatusResult, error) {
 	ramSize, ramUse := client.getRAMStatus(vm)
 	diskSize, diskUse := client.getDiskDetails(vm)
 	pvSize, pvUse := client.getPVCSize(vm)
-	var openShiftStatusSupplier = getOpenShiftStatus
+	var openShiftStatusSupplier = client.getOpenShiftStatus
 	if vm.bundle.IsMicroshift() {
-		openShiftStatusSupplier = getMicroShiftStatus
+		openShiftStatusSupplier = client.getMicroShiftStatus
 	}
 
-	return createClusterStatusResult(vmStatus, vm.bundle.GetBundleType(), vm.bundle.GetVersion(), ip, diskSize, diskUse, ramSize, ramUse, pvUse, pvSize, openShiftStatusSupplier)
+	clusterStatusResult, err := createClusterStatusResult(vmStatus, vm.bundle.GetBundleType(), vm.bundle.GetVersion(), ip, diskSize, diskUse, ramSize, ramUse, pvUse, pvSize, openShiftStatusSupplier)
+	if err != nil {
+		return nil, err
+	}
+	if vmStatus == state.Running {
+		clusterStatusResult.SharedDirs, err = additionalSharedDirs(vm.Host)
+		if err != nil {
+			logging.Debugf("Cannot get the shared directories: %v", err)
+		}
+	}
+	return clusterStatusResult, nil
 }
 
 func createClusterStatusResult(vmStatus state.State, bundleType preset.Preset, vmBundleVersion, vmIP string, diskSize, diskUse, ramSize, ramUse strongunits.B, pvUse, pvSize strongunits.B, openShiftStatusSupplier openShiftStatusSupplierFunc) (*types.ClusterStatusResult, error) {
@@ -81,7 +91,7 @@ func createClusterStatusResult(vmStatus state.State, bundleType preset.Preset, v
 	clusterStatusResult.DiskSize = diskSize
 	clusterStatusResult.RAMSize = ramSize
 	clusterStatusResult.RAMUse = ramUse
-	clusterStatusResult.OpenshiftStatus = openShiftStatusSupplier(context.Background(), vmIP)
+	clusterStatusResult.OpenshiftStatus, clusterStatusResult.ClusterOperators = openShiftStatusSupplier(context.Background(), vmIP)
 
 	if bundleType == preset.Microshift {
 		clusterStatusResult.PersistentVolumeUse = pvUse
@@ -147,22 +157,28 @@ func (client *client) getDiskDetails(vm *virtualMachine) (strongunits.B, strongu
 	return disk.([]strongunits.B)[0], disk.([]strongunits.B)[1]
 }
 
-func getOpenShiftStatus(ctx context.Context, ip string) types.OpenshiftStatus {
-	status, err := cluster.GetClusterOperatorsStatus(ctx, ip, constants.KubeconfigFilePath)
+func (client *client) getOpenShiftStatus(ctx context.Context, ip string) (types.OpenshiftStatus, *types.ClusterOperatorsStatus) {
+	status, err := cluster.GetClusterOperatorsStatus(ctx, ip, constants.GetKubeconfigFilePath(client.name))
 	if err != nil {
 		logging.Debugf("cannot get OpenShift status: %v", err)
-		return types.OpenshiftUnreachable
+		return types.OpenshiftUnreachable, nil
+	}
+	total, available, progressing, degraded := status.Counts()
+	return getStatus(status), &types.ClusterOperatorsStatus{
+		Total:       total,
+		Available:   available,
+		Progressing: progressing,
+		Degraded:    degraded,
 	}
-	return getStatus(status)
 }
 
-func getMicroShiftStatus(ctx context.Context, ip string) types.OpenshiftStatus {
-	status, err := cluster.GetClusterNodeStatus(ctx, ip, constants.KubeconfigFilePath)
+func (client *client) getMicroShiftStatus(ctx context.Context, ip string) (types.OpenshiftStatus, *types.ClusterOperatorsStatus) {
+	status, err := cluster.GetClusterNodeStatus(ctx, ip, constants.GetKubeconfigFilePath(client.name))
 	if err != nil {
 		logging.Debugf("failed to get microshift node status: %v", err)
-		return types.OpenshiftUnreachable
+		return types.OpenshiftUnreachable, nil
 	}
-	return getStatus(status)
+	return getStatus(status), nil
 }
 
 func getStatus(status *cluster.Status) types.OpenshiftStatus {
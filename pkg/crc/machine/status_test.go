@@ -235,7 +235,9 @@ func TestCreateClusterStatusResultShouldSetOpenShiftStatusAsExpected(t *testing.
 		t.Run(tt.name, func(t *testing.T) {
 			// Given
 			// When
-			actualClusterStatusResult, err := createClusterStatusResult(tt.vmStatus, tt.vmBundleType, "v4.5.1", "127.0.0.1", 32, 16, 12, 8, 16, 32, func(context.Context, string) types.OpenshiftStatus { return types.OpenshiftRunning })
+			actualClusterStatusResult, err := createClusterStatusResult(tt.vmStatus, tt.vmBundleType, "v4.5.1", "127.0.0.1", 32, 16, 12, 8, 16, 32, func(context.Context, string) (types.OpenshiftStatus, *types.ClusterOperatorsStatus) {
+				return types.OpenshiftRunning, nil
+			})
 
 			// Then
 			assert.NoError(t, err)
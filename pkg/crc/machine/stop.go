@@ -10,7 +10,7 @@ import (
 
 func (client *client) Stop() (state.State, error) {
 	defer func(input, output string) {
-		err := cleanKubeconfig(input, output)
+		err := cleanKubeconfig(client.name, input, output)
 		if err != nil && !errors.Is(err, os.ErrNotExist) {
 			logging.Warnf("Failed to remove crc contexts from kubeconfig: %v", err)
 		}
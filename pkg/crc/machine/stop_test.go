@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	crcOs "github.com/crc-org/crc/v2/pkg/os"
 	"github.com/stretchr/testify/assert"
@@ -57,7 +58,7 @@ func TestClient_WhenStopInvoked_ThenKubeConfigUpdatedIfRequired(t *testing.T) {
 			err = os.Setenv("KUBECONFIG", kubeConfigPath)
 			assert.NoError(t, err)
 			crcConfigStorage := crcConfig.New(crcConfig.NewEmptyInMemoryStorage(), crcConfig.NewEmptyInMemorySecretStorage())
-			client := NewClient("test-client", false, crcConfigStorage)
+			client := NewClient(constants.DefaultName, false, crcConfigStorage)
 
 			// When
 			clusterState, _ := client.Stop()
@@ -7,13 +7,25 @@ import (
 	"time"
 
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/metrics"
 	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	"go.podman.io/common/pkg/strongunits"
 )
 
 const startCancelTimeout = 15 * time.Second
 
+var (
+	// ErrBusy is returned when an operation is requested while another one
+	// is in progress
+	ErrBusy = errors.New("cluster is busy")
+	// ErrStoppingOrDeleting is returned when an operation is requested while
+	// the cluster is being stopped or deleted
+	ErrStoppingOrDeleting = errors.New("cluster is stopping or deleting")
+)
+
 type State string
 
 const (
@@ -21,6 +33,13 @@ const (
 	Deleting State = "Deleting"
 	Stopping State = "Stopping"
 	Starting State = "Starting"
+	// Snapshotting is used while a snapshot is created or restored
+	Snapshotting State = "Snapshotting"
+	// Resizing is used while the resources of the instance are changed
+	Resizing State = "Resizing"
+	// Updating is used while the port forwards or the volumes of the
+	// instance are changed
+	Updating State = "Updating"
 )
 
 type Synchronized struct {
@@ -76,7 +95,7 @@ func (s *Synchronized) prepareStart(startCancel context.CancelFunc) error {
 	s.stateLock.Lock()
 	defer s.stateLock.Unlock()
 	if s.currentStateUnlocked() != Idle {
-		return errors.New("cluster is busy")
+		return ErrBusy
 	}
 	s.startCancel = startCancel
 	s.currentState = Starting
@@ -90,7 +109,9 @@ func (s *Synchronized) Start(ctx context.Context, startConfig types.StartConfig)
 		return nil, err
 	}
 
+	begin := time.Now()
 	startResult, err := s.underlying.Start(ctx, startConfig)
+	metrics.ObserveOperation(metrics.StartOperation, time.Since(begin), err)
 	s.syncOperationDone <- Starting
 	return startResult, err
 }
@@ -123,7 +144,9 @@ func (s *Synchronized) prepareStopDelete(state State) error {
 	case Idle:
 		break
 	case Deleting, Stopping:
-		return errors.New("cluster is stopping or deleting")
+		return ErrStoppingOrDeleting
+	case Snapshotting, Resizing, Updating:
+		return ErrBusy
 	default:
 		return errors.New("invalid condition")
 	}
@@ -132,12 +155,187 @@ func (s *Synchronized) prepareStopDelete(state State) error {
 	return nil
 }
 
+func (s *Synchronized) prepareSnapshot() error {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+	if s.currentStateUnlocked() != Idle {
+		return ErrBusy
+	}
+	s.currentState = Snapshotting
+
+	return nil
+}
+
+func (s *Synchronized) CreateSnapshot(name string) (*types.SnapshotInfo, error) {
+	if err := s.prepareSnapshot(); err != nil {
+		return nil, err
+	}
+
+	info, err := s.underlying.CreateSnapshot(name)
+	s.syncOperationDone <- Snapshotting
+	return info, err
+}
+
+func (s *Synchronized) RestoreSnapshot(name string) error {
+	if err := s.prepareSnapshot(); err != nil {
+		return err
+	}
+
+	err := s.underlying.RestoreSnapshot(name)
+	s.syncOperationDone <- Snapshotting
+	return err
+}
+
+func (s *Synchronized) ListSnapshots() ([]types.SnapshotInfo, error) {
+	// a snapshot being created is incomplete
+	if s.CurrentState() == Snapshotting {
+		return nil, ErrBusy
+	}
+	return s.underlying.ListSnapshots()
+}
+
+func (s *Synchronized) DeleteSnapshot(name string) error {
+	if err := s.prepareSnapshot(); err != nil {
+		return err
+	}
+
+	err := s.underlying.DeleteSnapshot(name)
+	s.syncOperationDone <- Snapshotting
+	return err
+}
+
+func (s *Synchronized) Diagnose(ctx context.Context) (*types.DiagnoseResult, error) {
+	return s.underlying.Diagnose(ctx)
+}
+
+func (s *Synchronized) prepareUpdate() error {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+	if s.currentStateUnlocked() != Idle {
+		return ErrBusy
+	}
+	s.currentState = Updating
+
+	return nil
+}
+
+func (s *Synchronized) ExposePort(forward types.PortForward) (*types.PortForward, error) {
+	if err := s.prepareUpdate(); err != nil {
+		return nil, err
+	}
+
+	result, err := s.underlying.ExposePort(forward)
+	s.syncOperationDone <- Updating
+	return result, err
+}
+
+func (s *Synchronized) UnexposePort(protocol, local string) error {
+	if err := s.prepareUpdate(); err != nil {
+		return err
+	}
+
+	err := s.underlying.UnexposePort(protocol, local)
+	s.syncOperationDone <- Updating
+	return err
+}
+
+func (s *Synchronized) ListPortForwards() ([]types.PortForwardInfo, error) {
+	switch s.CurrentState() {
+	case Deleting:
+		return nil, ErrStoppingOrDeleting
+	case Snapshotting:
+		// a snapshot being restored replaces the port forwards
+		return nil, ErrBusy
+	default:
+		return s.underlying.ListPortForwards()
+	}
+}
+
+func (s *Synchronized) CreateVolume(name string, size strongunits.GiB) (*types.VolumeInfo, error) {
+	if err := s.prepareUpdate(); err != nil {
+		return nil, err
+	}
+
+	volume, err := s.underlying.CreateVolume(name, size)
+	s.syncOperationDone <- Updating
+	return volume, err
+}
+
+func (s *Synchronized) DeleteVolume(name string) error {
+	if err := s.prepareUpdate(); err != nil {
+		return err
+	}
+
+	err := s.underlying.DeleteVolume(name)
+	s.syncOperationDone <- Updating
+	return err
+}
+
+func (s *Synchronized) AttachVolume(name string) (*types.VolumeInfo, error) {
+	if err := s.prepareUpdate(); err != nil {
+		return nil, err
+	}
+
+	volume, err := s.underlying.AttachVolume(name)
+	s.syncOperationDone <- Updating
+	return volume, err
+}
+
+func (s *Synchronized) DetachVolume(name string) error {
+	if err := s.prepareUpdate(); err != nil {
+		return err
+	}
+
+	err := s.underlying.DetachVolume(name)
+	s.syncOperationDone <- Updating
+	return err
+}
+
+func (s *Synchronized) ListVolumes() ([]types.VolumeInfo, error) {
+	// the volumes of an instance being deleted are released
+	if s.CurrentState() == Deleting {
+		return nil, ErrStoppingOrDeleting
+	}
+	return s.underlying.ListVolumes()
+}
+
+func (s *Synchronized) MountSharedDir(dir config.SharedDir) (bool, error) {
+	return s.underlying.MountSharedDir(dir)
+}
+
+func (s *Synchronized) UnmountSharedDir(target string) (bool, error) {
+	return s.underlying.UnmountSharedDir(target)
+}
+
+func (s *Synchronized) prepareResize() error {
+	s.stateLock.Lock()
+	defer s.stateLock.Unlock()
+	if s.currentStateUnlocked() != Idle {
+		return ErrBusy
+	}
+	s.currentState = Resizing
+
+	return nil
+}
+
+func (s *Synchronized) Resize(resizeConfig types.ResizeConfig) (*types.ResizeResult, error) {
+	if err := s.prepareResize(); err != nil {
+		return nil, err
+	}
+
+	result, err := s.underlying.Resize(resizeConfig)
+	s.syncOperationDone <- Resizing
+	return result, err
+}
+
 func (s *Synchronized) Stop() (state.State, error) {
 	if err := s.prepareStopDelete(Stopping); err != nil {
 		return state.Error, err
 	}
 
+	begin := time.Now()
 	st, err := s.underlying.Stop()
+	metrics.ObserveOperation(metrics.StopOperation, time.Since(begin), err)
 	s.syncOperationDone <- Stopping
 
 	return st, err
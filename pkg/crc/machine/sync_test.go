@@ -6,10 +6,12 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
 	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/stretchr/testify/assert"
+	"go.podman.io/common/pkg/strongunits"
 )
 
 func TestOneStartAtTheSameTime(t *testing.T) {
@@ -107,11 +109,107 @@ func TestCancelStart(t *testing.T) {
 	assert.Equal(t, Idle, syncMachine.CurrentState())
 }
 
+func TestStopDeleteWhileSnapshotting(t *testing.T) {
+	isRunning := make(chan struct{}, 1)
+	snapshotCh := make(chan struct{}, 1)
+	waitingMachine := &waitingMachine{
+		isRunning:          isRunning,
+		snapshotCompleteCh: snapshotCh,
+	}
+	syncMachine := NewSynchronizedMachine(waitingMachine)
+
+	lock := &sync.WaitGroup{}
+	lock.Add(1)
+	go func() {
+		defer lock.Done()
+		assert.NoError(t, syncMachine.RestoreSnapshot("before-upgrade"))
+	}()
+
+	<-isRunning
+	assert.Equal(t, Snapshotting, syncMachine.CurrentState())
+	assert.EqualError(t, syncMachine.Delete(), "cluster is busy")
+	_, err := syncMachine.Stop()
+	assert.EqualError(t, err, "cluster is busy")
+	_, err = syncMachine.ListSnapshots()
+	assert.EqualError(t, err, "cluster is busy")
+	assert.EqualError(t, syncMachine.DeleteSnapshot("before-upgrade"), "cluster is busy")
+
+	snapshotCh <- struct{}{}
+	lock.Wait()
+
+	assert.Equal(t, Idle, syncMachine.CurrentState())
+}
+
+func TestStopDeleteWhileResizing(t *testing.T) {
+	isRunning := make(chan struct{}, 1)
+	resizeCh := make(chan struct{}, 1)
+	waitingMachine := &waitingMachine{
+		isRunning:        isRunning,
+		resizeCompleteCh: resizeCh,
+	}
+	syncMachine := NewSynchronizedMachine(waitingMachine)
+
+	lock := &sync.WaitGroup{}
+	lock.Add(1)
+	go func() {
+		defer lock.Done()
+		_, err := syncMachine.Resize(types.ResizeConfig{})
+		assert.NoError(t, err)
+	}()
+
+	<-isRunning
+	assert.Equal(t, Resizing, syncMachine.CurrentState())
+	assert.EqualError(t, syncMachine.Delete(), "cluster is busy")
+	_, err := syncMachine.Stop()
+	assert.EqualError(t, err, "cluster is busy")
+
+	resizeCh <- struct{}{}
+	lock.Wait()
+
+	assert.Equal(t, Idle, syncMachine.CurrentState())
+}
+
+func TestStopDeleteWhileExposingAPort(t *testing.T) {
+	isRunning := make(chan struct{}, 1)
+	updateCh := make(chan struct{}, 1)
+	waitingMachine := &waitingMachine{
+		isRunning:        isRunning,
+		updateCompleteCh: updateCh,
+	}
+	syncMachine := NewSynchronizedMachine(waitingMachine)
+
+	lock := &sync.WaitGroup{}
+	lock.Add(1)
+	go func() {
+		defer lock.Done()
+		_, err := syncMachine.ExposePort(types.PortForward{Protocol: "tcp", Local: ":8080", Remote: "192.168.127.2:80"})
+		assert.NoError(t, err)
+	}()
+
+	<-isRunning
+	assert.Equal(t, Updating, syncMachine.CurrentState())
+	assert.EqualError(t, syncMachine.Delete(), "cluster is busy")
+	_, err := syncMachine.Stop()
+	assert.EqualError(t, err, "cluster is busy")
+	_, err = syncMachine.Start(context.Background(), types.StartConfig{})
+	assert.EqualError(t, err, "cluster is busy")
+	assert.EqualError(t, syncMachine.UnexposePort("tcp", ":8080"), "cluster is busy")
+	assert.EqualError(t, syncMachine.DetachVolume("data"), "cluster is busy")
+
+	updateCh <- struct{}{}
+	lock.Wait()
+
+	assert.Equal(t, Idle, syncMachine.CurrentState())
+}
+
 type waitingMachine struct {
-	isRunning        chan struct{}
-	startCompleteCh  chan struct{}
-	stopCompleteCh   chan struct{}
-	deleteCompleteCh chan struct{}
+	isRunning          chan struct{}
+	startCompleteCh    chan struct{}
+	stopCompleteCh     chan struct{}
+	deleteCompleteCh   chan struct{}
+	snapshotCompleteCh chan struct{}
+	resizeCompleteCh   chan struct{}
+	updateCompleteCh   chan struct{}
 }
 
 func (m *waitingMachine) IsRunning() (bool, error) {
@@ -178,3 +276,73 @@ func (m *waitingMachine) GetPreset() crcPreset.Preset {
 func (m *waitingMachine) GetClusterLoad() (*types.ClusterLoadResult, error) {
 	return nil, errors.New("not implemented")
 }
+
+func (m *waitingMachine) CreateSnapshot(_ string) (*types.SnapshotInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) ListSnapshots() ([]types.SnapshotInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) RestoreSnapshot(_ string) error {
+	m.isRunning <- struct{}{}
+	<-m.snapshotCompleteCh
+	return nil
+}
+
+func (m *waitingMachine) DeleteSnapshot(_ string) error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) Diagnose(_ context.Context) (*types.DiagnoseResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) ExposePort(forward types.PortForward) (*types.PortForward, error) {
+	m.isRunning <- struct{}{}
+	<-m.updateCompleteCh
+	return &forward, nil
+}
+
+func (m *waitingMachine) UnexposePort(_, _ string) error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) ListPortForwards() ([]types.PortForwardInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) Resize(_ types.ResizeConfig) (*types.ResizeResult, error) {
+	m.isRunning <- struct{}{}
+	<-m.resizeCompleteCh
+	return &types.ResizeResult{}, nil
+}
+
+func (m *waitingMachine) CreateVolume(_ string, _ strongunits.GiB) (*types.VolumeInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) DeleteVolume(_ string) error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) AttachVolume(_ string) (*types.VolumeInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) DetachVolume(_ string) error {
+	return errors.New("not implemented")
+}
+
+func (m *waitingMachine) ListVolumes() ([]types.VolumeInfo, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *waitingMachine) MountSharedDir(_ config.SharedDir) (bool, error) {
+	return false, errors.New("not implemented")
+}
+
+func (m *waitingMachine) UnmountSharedDir(_ string) (bool, error) {
+	return false, errors.New("not implemented")
+}
@@ -0,0 +1,42 @@
+package machine
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/crc-org/crc/v2/pkg/crc/adminhelper"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+)
+
+// addClusterCAToTrustStore installs the CAs of the API server and of the
+// ingress of the cluster, and the CAs of its custom certificates
+// 'extraCA', in the host trust store, so that the routes are trusted by
+// browsers and curl
+func addClusterCAToTrustStore(ocConfig oc.Config, clusterConfig *types.ClusterConfig, extraCA []byte) error {
+	apiCA, err := certificateAuthority(clusterConfig.KubeConfig)
+	if err != nil {
+		return err
+	}
+	ingressCA, stderr, err := ocConfig.RunOcCommand("get", "configmap", "default-ingress-cert", "-n", "openshift-config-managed",
+		"-o", `jsonpath="{.data.ca-bundle\.crt}"`)
+	if err != nil {
+		return fmt.Errorf("Failed to get the ingress CA %v: %s", err, stderr)
+	}
+	return adminhelper.AddToTrustStore(caBundle(apiCA, []byte(ingressCA), extraCA))
+}
+
+// caBundle concatenates the pem certificates 'cas', each ending with a
+// newline
+func caBundle(cas ...[]byte) []byte {
+	var bundle bytes.Buffer
+	for _, ca := range cas {
+		ca = bytes.TrimSpace(ca)
+		if len(ca) == 0 {
+			continue
+		}
+		bundle.Write(ca)
+		bundle.WriteByte('\n')
+	}
+	return bundle.Bytes()
+}
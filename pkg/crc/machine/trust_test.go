@@ -0,0 +1,16 @@
+package machine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCABundle(t *testing.T) {
+	apiCA := "-----BEGIN CERTIFICATE-----\napi\n-----END CERTIFICATE-----"
+	ingressCA := "-----BEGIN CERTIFICATE-----\ningress\n-----END CERTIFICATE-----\n\n"
+
+	assert.Equal(t, apiCA+"\n"+"-----BEGIN CERTIFICATE-----\ningress\n-----END CERTIFICATE-----\n",
+		string(caBundle([]byte(apiCA), []byte(""), []byte(ingressCA))))
+	assert.Empty(t, caBundle())
+}
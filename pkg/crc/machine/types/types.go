@@ -1,7 +1,10 @@
 package types
 
 import (
+	"time"
+
 	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	machineConfig "github.com/crc-org/crc/v2/pkg/crc/machine/config"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
 	"github.com/crc-org/crc/v2/pkg/crc/network/httpproxy"
 	crcpreset "github.com/crc-org/crc/v2/pkg/crc/preset"
@@ -36,6 +39,8 @@ type StartConfig struct {
 	EnableSharedDirs  bool
 	SharedDirPassword string
 	SharedDirUsername string
+	// Host directories mounted in addition to the home directory
+	SharedDirs []machineConfig.SharedDir
 
 	// Ports to access openshift routes
 	IngressHTTPPort  uint
@@ -47,8 +52,29 @@ type StartConfig struct {
 	// Persistent volume size
 	PersistentVolumeSize int
 
-	// Enable bundle quay fallback
-	EnableBundleQuayFallback bool
+	// Mirrors the default bundle is downloaded from, in order
+	BundleMirrors []string
+
+	// File describing the provisioning done once the cluster is started
+	ProvisionFile string
+
+	// Custom domain served by the cluster ingress, in addition to the
+	// apps domain of the bundle
+	AppsDomain string
+
+	// Certificates presented by the API server and the ingress instead of
+	// the ones of the bundle
+	CustomCertificates CustomCertificates
+}
+
+// CustomCertificates are the pem files of either a CA signing the
+// certificates of the cluster, or of a certificate issued for both the API
+// server and the apps domains
+type CustomCertificates struct {
+	CACertFile string
+	CAKeyFile  string
+	CertFile   string
+	KeyFile    string
 }
 
 type ClusterConfig struct {
@@ -86,6 +112,18 @@ type ClusterStatusResult struct {
 	PersistentVolumeUse  strongunits.B
 	PersistentVolumeSize strongunits.B
 	Preset               crcpreset.Preset
+	ClusterOperators     *ClusterOperatorsStatus
+	// SharedDirs are the directories of the 'shared-dirs' setting shared
+	// with the running VM
+	SharedDirs []machineConfig.SharedDir
+}
+
+// ClusterOperatorsStatus counts the OpenShift cluster operators by condition
+type ClusterOperatorsStatus struct {
+	Total       int
+	Available   int
+	Progressing int
+	Degraded    int
 }
 
 type ClusterLoadResult struct {
@@ -116,3 +154,76 @@ type ConnectionDetails struct {
 	SSHUsername string
 	SSHKeys     []string
 }
+
+type SnapshotInfo struct {
+	Name         string
+	CreationTime time.Time
+	BundleName   string
+	DiskSize     strongunits.B
+}
+
+// VolumeInfo describes a disk created by 'crc volume create', it is kept
+// when the instances are deleted
+type VolumeInfo struct {
+	Name string
+	Size strongunits.GiB
+	// Instance is the name of the instance the volume is attached to
+	Instance string `json:"Instance,omitempty"`
+	// Device is the disk target of the volume in the VM, for example vdb
+	Device string `json:"Device,omitempty"`
+	// PersistentVolume is the cluster resource exposing the volume
+	PersistentVolume string `json:"PersistentVolume,omitempty"`
+}
+
+// ResizeConfig holds the resources requested for the instance, the zero
+// values keep the current ones
+type ResizeConfig struct {
+	CPUs     uint            `json:"cpus,omitempty"`
+	Memory   strongunits.MiB `json:"memory,omitempty"`
+	DiskSize strongunits.GiB `json:"diskSize,omitempty"`
+}
+
+// ResizeResult holds the resources of the instance after a resize
+type ResizeResult struct {
+	CPUs     uint
+	Memory   strongunits.MiB
+	DiskSize strongunits.GiB
+	// Changed lists the settings of the resources which were changed
+	Changed []string
+	// RestartRequired is true when the instance was running and was stopped
+	// to apply the changes, it must be started again
+	RestartRequired bool
+}
+
+// DiagnosticFile is a file of the tarball created by 'crc diagnose'
+type DiagnosticFile struct {
+	Name    string
+	Content []byte
+}
+
+type DiagnoseResult struct {
+	Files []DiagnosticFile
+	// Secrets are the values which must be masked in the tarball
+	Secrets []string
+	// Errors are the failures to collect some of the files
+	Errors []string
+}
+
+// PortForward forwards the connections to a port of the host to a port of
+// the VM, or of another address reachable from the VM network
+type PortForward struct {
+	// Protocol is 'tcp' or 'udp'
+	Protocol string `json:"protocol"`
+	// Local is the host address, for example 127.0.0.1:8080
+	Local string `json:"local"`
+	// Remote is the address in the VM network, for example
+	// 192.168.127.2:30080
+	Remote string `json:"remote"`
+}
+
+// PortForwardInfo is a port forward of the instance, Active is true when it
+// is currently applied
+type PortForwardInfo struct {
+	PortForward
+	Active bool `json:"active"`
+}
@@ -108,5 +108,5 @@ func (vm *virtualMachine) SSHRunner() (*ssh.Runner, error) {
 	if err != nil {
 		return nil, err
 	}
-	return ssh.CreateRunner(ip, vm.SSHPort(), constants.GetPrivateKeyPath(), constants.GetECDSAPrivateKeyPath(), vm.bundle.GetSSHKeyPath())
+	return ssh.CreateRunner(ip, vm.SSHPort(), constants.GetPrivateKeyPath(vm.name), constants.GetECDSAPrivateKeyPath(vm.name), vm.bundle.GetSSHKeyPath())
 }
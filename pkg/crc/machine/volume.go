@@ -0,0 +1,350 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/crc-org/crc/v2/pkg/crc/cluster"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
+	crcssh "github.com/crc-org/crc/v2/pkg/crc/ssh"
+	"github.com/crc-org/crc/v2/pkg/crc/validation"
+	"github.com/pkg/errors"
+	"go.podman.io/common/pkg/strongunits"
+)
+
+var (
+	// ErrInvalidVolume is returned for the volumes with an invalid name or
+	// size
+	ErrInvalidVolume = errors.New("invalid volume")
+	// ErrVolumeExists is returned when creating a volume which already
+	// exists
+	ErrVolumeExists = errors.New("volume already exists")
+	// ErrVolumeNotFound is returned for the volumes which do not exist
+	ErrVolumeNotFound = errors.New("volume not found")
+	// ErrVolumeAttached is returned when the volume is attached to another
+	// instance, or when deleting an attached volume
+	ErrVolumeAttached = errors.New("volume is attached")
+)
+
+// volumeDevices are the disk targets available for the volumes, vda is the
+// disk of the instance
+var volumeDevices = []string{"vdb", "vdc", "vdd", "vde", "vdf", "vdg", "vdh", "vdi"}
+
+// CreateVolume creates the disk image of a volume, which can be attached to
+// the instances and is kept when they are deleted
+func (client *client) CreateVolume(name string, size strongunits.GiB) (*types.VolumeInfo, error) {
+	if err := validation.ValidateVolumeName(name); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVolume, err)
+	}
+	if size == 0 {
+		return nil, fmt.Errorf("%w: the size must be at least 1 GiB", ErrInvalidVolume)
+	}
+	unlock, err := lockVolumes()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	volumes, err := loadVolumes()
+	if err != nil {
+		return nil, err
+	}
+	if slices.ContainsFunc(volumes, func(volume types.VolumeInfo) bool { return volume.Name == name }) {
+		return nil, fmt.Errorf("%w: %s", ErrVolumeExists, name)
+	}
+
+	logging.Infof("Creating volume %s of %d GiB...", name, size)
+	if err := createVolumeImage(constants.GetVolumePath(name), size); err != nil {
+		return nil, errors.Wrap(err, "Cannot create the disk image of the volume")
+	}
+	volume := types.VolumeInfo{
+		Name: name,
+		Size: size,
+	}
+	if err := saveVolumes(append(volumes, volume)); err != nil {
+		return nil, err
+	}
+	return &volume, nil
+}
+
+// DeleteVolume removes a detached volume and its data
+func (client *client) DeleteVolume(name string) error {
+	unlock, err := lockVolumes()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	volumes, index, err := findVolume(name)
+	if err != nil {
+		return err
+	}
+	if volumes[index].Instance != "" {
+		return fmt.Errorf("%w: %s is attached to instance %s, detach it first", ErrVolumeAttached, name, volumes[index].Instance)
+	}
+	if err := os.Remove(constants.GetVolumePath(name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return saveVolumes(append(volumes[:index], volumes[index+1:]...))
+}
+
+// ListVolumes returns the volumes and the instances they are attached to
+func (client *client) ListVolumes() ([]types.VolumeInfo, error) {
+	volumes, err := loadVolumes()
+	if err != nil {
+		return nil, err
+	}
+	for i := range volumes {
+		if volumes[i].Instance != "" {
+			volumes[i].PersistentVolume = cluster.VolumePersistentVolumeName(volumes[i].Name)
+		}
+	}
+	return volumes, nil
+}
+
+// AttachVolume adds the volume to the disks of the instance VM, and exposes
+// it as a PersistentVolume right away when the instance is running, and
+// each time it starts
+func (client *client) AttachVolume(name string) (*types.VolumeInfo, error) {
+	if client.GetPreset() == crcPreset.Microshift {
+		return nil, fmt.Errorf("Volumes are not supported with the %s preset, use the persistent-volume-size setting instead", crcPreset.Microshift)
+	}
+	unlock, err := lockVolumes()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	volumes, index, err := findVolume(name)
+	if err != nil {
+		return nil, err
+	}
+	volume := volumes[index]
+	switch volume.Instance {
+	case "":
+	case client.name:
+		volume.PersistentVolume = cluster.VolumePersistentVolumeName(name)
+		return &volume, nil
+	default:
+		return nil, fmt.Errorf("%w: %s is attached to instance %s", ErrVolumeAttached, name, volume.Instance)
+	}
+
+	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot load machine")
+	}
+	defer vm.Close()
+	device, err := freeVolumeDevice(volumes, client.name)
+	if err != nil {
+		return nil, err
+	}
+	logging.Infof("Attaching volume %s to instance %s...", name, client.name)
+	if err := attachVolumeDisk(client.name, constants.GetVolumePath(name), device, name); err != nil {
+		return nil, errors.Wrap(err, "Cannot attach the disk of the volume")
+	}
+	volume.Instance, volume.Device = client.name, device
+	volumes[index] = volume
+	if err := saveVolumes(volumes); err != nil {
+		return nil, err
+	}
+
+	vmState, err := vm.State()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot get VM status")
+	}
+	if vmState == state.Running {
+		sshRunner, err := vm.SSHRunner()
+		if err != nil {
+			return nil, errors.Wrap(err, "Error creating the ssh client")
+		}
+		defer sshRunner.Close()
+		if err := cluster.EnsureVolumeInTheCluster(context.Background(), oc.UseOCWithSSH(sshRunner), sshRunner, name, volume.Size); err != nil {
+			return nil, errors.Wrapf(err, "Cannot expose volume %s in the cluster", name)
+		}
+	}
+	volume.PersistentVolume = cluster.VolumePersistentVolumeName(name)
+	return &volume, nil
+}
+
+// DetachVolume removes the volume from the disks of the instance VM, its
+// PersistentVolume must not be bound to a claim. The instance must be
+// running to check this.
+func (client *client) DetachVolume(name string) error {
+	unlock, err := lockVolumes()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	volumes, index, err := findVolume(name)
+	if err != nil {
+		return err
+	}
+	volume := volumes[index]
+	if volume.Instance != client.name {
+		return fmt.Errorf("%w: %s is not attached to instance %s", ErrVolumeNotFound, name, client.name)
+	}
+
+	vm, err := loadVirtualMachine(client.name, client.useVSock())
+	if err != nil {
+		return errors.Wrap(err, "Cannot load machine")
+	}
+	defer vm.Close()
+	vmState, err := vm.State()
+	if err != nil {
+		return errors.Wrap(err, "Cannot get VM status")
+	}
+	if vmState != state.Running {
+		return fmt.Errorf("%w: start instance %s to detach %s, its PersistentVolume may be bound to a claim", ErrVolumeAttached, client.name, name)
+	}
+	sshRunner, err := vm.SSHRunner()
+	if err != nil {
+		return errors.Wrap(err, "Error creating the ssh client")
+	}
+	defer sshRunner.Close()
+	if err := cluster.RemoveVolumeFromCluster(context.Background(), oc.UseOCWithSSH(sshRunner), sshRunner, name); err != nil {
+		return err
+	}
+	logging.Infof("Detaching volume %s from instance %s...", name, client.name)
+	if err := detachVolumeDisk(client.name, volume.Device); err != nil {
+		return errors.Wrap(err, "Cannot detach the disk of the volume")
+	}
+	volumes[index].Instance, volumes[index].Device = "", ""
+	return saveVolumes(volumes)
+}
+
+// ensureVolumesInTheCluster exposes the volumes attached to the instance
+// 'name' once it started, and removes the unbound PersistentVolumes of the
+// volumes which are not attached to it anymore, like after a snapshot
+// restore. A failure does not prevent the instance from starting.
+func ensureVolumesInTheCluster(ctx context.Context, name string, ocConfig oc.Config, sshRunner *crcssh.Runner) {
+	volumes, err := loadVolumes()
+	if err != nil {
+		logging.Warnf("Cannot load the volumes: %v", err)
+		return
+	}
+	inCluster, err := cluster.VolumesInTheCluster(ctx, ocConfig)
+	if err != nil {
+		logging.Warnf("Cannot list the volumes of the cluster: %v", err)
+	}
+	for _, volume := range detachedVolumes(inCluster, volumes, name) {
+		logging.Infof("Removing the PersistentVolume of volume %s, which is detached...", volume)
+		if err := cluster.RemoveVolumeFromCluster(ctx, ocConfig, sshRunner, volume); err != nil {
+			logging.Warnf("Cannot remove the PersistentVolume of volume %s: %v", volume, err)
+		}
+	}
+	for _, volume := range volumes {
+		if volume.Instance != name {
+			continue
+		}
+		if err := cluster.EnsureVolumeInTheCluster(ctx, ocConfig, sshRunner, volume.Name, volume.Size); err != nil {
+			logging.Warnf("Cannot expose volume %s in the cluster: %v", volume.Name, err)
+		}
+	}
+}
+
+// detachedVolumes returns the volumes of 'inCluster', which have a
+// PersistentVolume in the cluster of the instance 'name', but are not
+// attached to it anymore
+func detachedVolumes(inCluster []string, volumes []types.VolumeInfo, name string) []string {
+	var detached []string
+	for _, volume := range inCluster {
+		attached := slices.ContainsFunc(volumes, func(info types.VolumeInfo) bool {
+			return info.Name == volume && info.Instance == name
+		})
+		if !attached {
+			detached = append(detached, volume)
+		}
+	}
+	return detached
+}
+
+// releaseVolumes marks the volumes attached to the instance 'name' as
+// detached, once its VM was removed
+func releaseVolumes(name string) error {
+	if _, err := os.Stat(constants.GetVolumesPath()); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	unlock, err := lockVolumes()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	volumes, err := loadVolumes()
+	if err != nil {
+		return err
+	}
+	released := false
+	for i := range volumes {
+		if volumes[i].Instance == name {
+			volumes[i].Instance, volumes[i].Device = "", ""
+			released = true
+		}
+	}
+	if !released {
+		return nil
+	}
+	return saveVolumes(volumes)
+}
+
+// freeVolumeDevice returns the first disk target of the instance 'name'
+// which is not used by a volume
+func freeVolumeDevice(volumes []types.VolumeInfo, name string) (string, error) {
+	for _, device := range volumeDevices {
+		used := slices.ContainsFunc(volumes, func(volume types.VolumeInfo) bool {
+			return volume.Instance == name && volume.Device == device
+		})
+		if !used {
+			return device, nil
+		}
+	}
+	return "", fmt.Errorf("At most %d volumes can be attached to an instance", len(volumeDevices))
+}
+
+func findVolume(name string) ([]types.VolumeInfo, int, error) {
+	volumes, err := loadVolumes()
+	if err != nil {
+		return nil, -1, err
+	}
+	index := slices.IndexFunc(volumes, func(volume types.VolumeInfo) bool { return volume.Name == name })
+	if index == -1 {
+		return nil, -1, fmt.Errorf("%w: %s", ErrVolumeNotFound, name)
+	}
+	return volumes, index, nil
+}
+
+// lockVolumes serializes the updates of the volumes file, the volumes are
+// shared by all the instances
+func lockVolumes() (func(), error) {
+	if err := os.MkdirAll(constants.CrcVolumesDir, 0750); err != nil {
+		return nil, err
+	}
+	return lockFile(constants.GetVolumesPath())
+}
+
+func loadVolumes() ([]types.VolumeInfo, error) {
+	data, err := os.ReadFile(constants.GetVolumesPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return []types.VolumeInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var volumes []types.VolumeInfo
+	if err := json.Unmarshal(data, &volumes); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", constants.GetVolumesPath())
+	}
+	return volumes, nil
+}
+
+func saveVolumes(volumes []types.VolumeInfo) error {
+	data, err := json.MarshalIndent(volumes, "", "  ")
+	if err != nil {
+		return err
+	}
+	return replaceFile(constants.GetVolumesPath(), data)
+}
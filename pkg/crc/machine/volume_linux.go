@@ -0,0 +1,32 @@
+package machine
+
+import (
+	"fmt"
+
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+	"go.podman.io/common/pkg/strongunits"
+)
+
+func createVolumeImage(path string, size strongunits.GiB) error {
+	_, _, err := crcos.RunWithDefaultLocale("qemu-img", "create", "-f", "qcow2", path, fmt.Sprintf("%dG", size))
+	return err
+}
+
+// attachVolumeDisk adds the disk image to the libvirt domain of the instance.
+// --persistent changes its definition, and the running VM if any.
+func attachVolumeDisk(domain, path, device, serial string) error {
+	_, stderr, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "attach-disk", domain, path, device,
+		"--driver", "qemu", "--subdriver", "qcow2", "--targetbus", "virtio", "--serial", serial, "--persistent")
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, stderr)
+	}
+	return nil
+}
+
+func detachVolumeDisk(domain, device string) error {
+	_, stderr, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "detach-disk", domain, device, "--persistent")
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, stderr)
+	}
+	return nil
+}
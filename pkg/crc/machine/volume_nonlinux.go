@@ -0,0 +1,22 @@
+//go:build !linux
+
+package machine
+
+import (
+	"fmt"
+	"runtime"
+
+	"go.podman.io/common/pkg/strongunits"
+)
+
+func createVolumeImage(_ string, _ strongunits.GiB) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
+
+func attachVolumeDisk(_, _, _, _ string) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
+
+func detachVolumeDisk(_, _ string) error {
+	return fmt.Errorf("Not implemented for %s", runtime.GOOS)
+}
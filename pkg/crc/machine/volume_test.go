@@ -0,0 +1,89 @@
+package machine
+
+import (
+	"os"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreeVolumeDevice(t *testing.T) {
+	volumes := []types.VolumeInfo{
+		{Name: "data", Instance: "crc", Device: "vdb"},
+		{Name: "logs", Instance: "other", Device: "vdc"},
+		{Name: "spare"},
+	}
+	device, err := freeVolumeDevice(volumes, "crc")
+	assert.NoError(t, err)
+	assert.Equal(t, "vdc", device)
+
+	device, err = freeVolumeDevice(volumes, "other")
+	assert.NoError(t, err)
+	assert.Equal(t, "vdb", device)
+
+	volumes = nil
+	for _, device := range volumeDevices {
+		volumes = append(volumes, types.VolumeInfo{Name: device, Instance: "crc", Device: device})
+	}
+	_, err = freeVolumeDevice(volumes, "crc")
+	assert.EqualError(t, err, "At most 8 volumes can be attached to an instance")
+}
+
+func TestDetachedVolumes(t *testing.T) {
+	volumes := []types.VolumeInfo{
+		{Name: "data", Instance: "crc", Device: "vdb"},
+		{Name: "logs", Instance: "other", Device: "vdb"},
+		{Name: "spare"},
+	}
+	assert.Equal(t, []string{"logs", "spare", "deleted"}, detachedVolumes([]string{"data", "logs", "spare", "deleted"}, volumes, "crc"))
+	assert.Empty(t, detachedVolumes([]string{"data"}, volumes, "crc"))
+	assert.Empty(t, detachedVolumes(nil, volumes, "crc"))
+}
+
+func TestDeleteAndReleaseVolumes(t *testing.T) {
+	volumesDir := constants.CrcVolumesDir
+	constants.CrcVolumesDir = t.TempDir()
+	defer func() {
+		constants.CrcVolumesDir = volumesDir
+	}()
+	client := &client{name: "crc"}
+
+	volumes, err := client.ListVolumes()
+	require.NoError(t, err)
+	assert.Empty(t, volumes)
+
+	require.NoError(t, saveVolumes([]types.VolumeInfo{
+		{Name: "data", Size: 10, Instance: "crc", Device: "vdb"},
+		{Name: "logs", Size: 5},
+	}))
+	require.NoError(t, os.WriteFile(constants.GetVolumePath("logs"), nil, 0600))
+
+	volumes, err = client.ListVolumes()
+	require.NoError(t, err)
+	assert.Equal(t, []types.VolumeInfo{
+		{Name: "data", Size: 10, Instance: "crc", Device: "vdb", PersistentVolume: "crc-volume-data"},
+		{Name: "logs", Size: 5},
+	}, volumes)
+
+	assert.ErrorIs(t, client.DeleteVolume("data"), ErrVolumeAttached)
+	assert.ErrorIs(t, client.DeleteVolume("unknown"), ErrVolumeNotFound)
+	assert.NoError(t, client.DeleteVolume("logs"))
+	assert.NoFileExists(t, constants.GetVolumePath("logs"))
+
+	// the volumes are kept when the instance is deleted
+	require.NoError(t, releaseVolumes("crc"))
+	volumes, err = client.ListVolumes()
+	require.NoError(t, err)
+	assert.Equal(t, []types.VolumeInfo{{Name: "data", Size: 10}}, volumes)
+}
+
+func TestCreateVolumeValidation(t *testing.T) {
+	client := &client{name: "crc"}
+	_, err := client.CreateVolume("Data", 10)
+	assert.ErrorIs(t, err, ErrInvalidVolume)
+	_, err = client.CreateVolume("data", 0)
+	assert.EqualError(t, err, "invalid volume: the size must be at least 1 GiB")
+}
@@ -12,12 +12,13 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/daemonclient"
 	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/network"
 	crcPreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/pkg/errors"
 )
 
-func exposePorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint) error {
-	portsToExpose := vsockPorts(preset, ingressHTTPPort, ingressHTTPSPort)
+func exposePorts(name string, preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint) error {
+	portsToExpose := vsockPorts(name, preset, ingressHTTPPort, ingressHTTPSPort)
 	daemonClient := daemonclient.New()
 	alreadyOpenedPorts, err := listOpenPorts(daemonClient)
 	if err != nil {
@@ -35,6 +36,25 @@ func exposePorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint
 			return errors.Wrapf(err, "failed to expose port %s -> %s", port.Local, port.Remote)
 		}
 	}
+	exposePortForwards(name, daemonClient, alreadyOpenedPorts)
+	return nil
+}
+
+// addAppsDomainToDNS resolves the subdomains of 'appsDomain' to the VM in
+// the DNS server of the user-mode network
+func addAppsDomainToDNS(appsDomain string) error {
+	if appsDomain == "" {
+		return nil
+	}
+	daemonClient := daemonclient.New()
+	zones, err := daemonClient.NetworkClient.ListDNS()
+	if err != nil {
+		return errors.Wrap(err, "failed to list the DNS zones")
+	}
+	zone := network.DNSRecordZone(zones, network.DNSRecord{Hostname: "*." + appsDomain, IP: network.VirtualMachineIP})
+	if err := daemonClient.NetworkClient.AddDNS(&zone); err != nil {
+		return errors.Wrapf(err, "failed to add DNS zone for %s", appsDomain)
+	}
 	return nil
 }
 
@@ -84,9 +104,9 @@ const (
 	cockpitPort      = "9090"
 )
 
-func vsockPorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint) []types.ExposeRequest {
+func vsockPorts(name string, preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint) []types.ExposeRequest {
 	socketProtocol := types.UNIX
-	socketLocal := constants.GetHostDockerSocketPath()
+	socketLocal := constants.GetHostDockerSocketPath(name)
 	if runtime.GOOS == "windows" {
 		socketProtocol = types.NPIPE
 		socketLocal = constants.DefaultPodmanNamedPipe
@@ -100,7 +120,7 @@ func vsockPorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint)
 		{
 			Protocol: socketProtocol,
 			Local:    socketLocal,
-			Remote:   getSSHTunnelURI(),
+			Remote:   getSSHTunnelURI(name),
 		},
 	}
 
@@ -129,14 +149,14 @@ func vsockPorts(preset crcPreset.Preset, ingressHTTPPort, ingressHTTPSPort uint)
 	return exposeRequest
 }
 
-func getSSHTunnelURI() string {
+func getSSHTunnelURI(name string) string {
 	u := url.URL{
 		Scheme:     "ssh-tunnel",
 		User:       url.User("core"),
 		Host:       net.JoinHostPort(virtualMachineIP, internalSSHPort),
 		Path:       "/run/podman/podman.sock",
 		ForceQuery: false,
-		RawQuery:   fmt.Sprintf("key=%s", url.QueryEscape(constants.GetPrivateKeyPath())),
+		RawQuery:   fmt.Sprintf("key=%s", url.QueryEscape(constants.GetPrivateKeyPath(name))),
 	}
 	return u.String()
 }
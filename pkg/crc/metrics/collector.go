@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+)
+
+// Machine is the subset of machine.Client used to collect the metrics
+type Machine interface {
+	Status() (*types.ClusterStatusResult, error)
+	GetClusterLoad() (*types.ClusterLoadResult, error)
+}
+
+// Network reports the traffic between the host and the instance
+type Network interface {
+	BytesSent() uint64
+	BytesReceived() uint64
+}
+
+type collector struct {
+	machine Machine
+	network Network
+}
+
+// NewHandler returns an http.Handler serving the metrics of the instance in
+// the OpenMetrics text format. 'network' is nil when user mode networking
+// is not used.
+func NewHandler(machine Machine, network Network) http.Handler {
+	return &collector{
+		machine: machine,
+		network: network,
+	}
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var buf bytes.Buffer
+	if err := writeFamilies(&buf, c.collect()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
+func (c *collector) collect() []*family {
+	var families []*family
+	families = append(families, c.statusFamilies()...)
+	families = append(families, c.loadFamilies()...)
+	families = append(families, operations.families()...)
+	if c.network != nil {
+		families = append(families,
+			newCounter("crc_network_sent_bytes", "bytes", "Bytes sent to the instance by the user mode network stack").
+				add(float64(c.network.BytesSent())),
+			newCounter("crc_network_received_bytes", "bytes", "Bytes received from the instance by the user mode network stack").
+				add(float64(c.network.BytesReceived())),
+		)
+	}
+	return families
+}
+
+func (c *collector) statusFamilies() []*family {
+	status, err := c.machine.Status()
+	if err != nil {
+		logging.Debugf("Cannot get the instance status for the metrics: %v", err)
+		return nil
+	}
+	running := 0.0
+	if status.CrcStatus == state.Running {
+		running = 1
+	}
+	families := []*family{
+		newGauge("crc_vm_running", "", "Whether the instance virtual machine is running").
+			add(running),
+		newGauge("crc_cluster_info", "", "Status of the cluster running in the instance").
+			add(1, label{"preset", status.Preset.String()}, label{"status", string(status.OpenshiftStatus)}, label{"version", status.OpenshiftVersion}),
+		newGauge("crc_vm_memory_used_bytes", "bytes", "Memory used in the instance").
+			add(float64(status.RAMUse)),
+		newGauge("crc_vm_memory_size_bytes", "bytes", "Memory size of the instance").
+			add(float64(status.RAMSize)),
+		newGauge("crc_vm_disk_used_bytes", "bytes", "Disk space used in the instance").
+			add(float64(status.DiskUse)),
+		newGauge("crc_vm_disk_size_bytes", "bytes", "Disk size of the instance").
+			add(float64(status.DiskSize)),
+	}
+	if operators := status.ClusterOperators; operators != nil {
+		families = append(families,
+			newGauge("crc_cluster_operators", "", "Number of cluster operators by condition").
+				add(float64(operators.Total), label{"condition", "all"}).
+				add(float64(operators.Available), label{"condition", "available"}).
+				add(float64(operators.Progressing), label{"condition", "progressing"}).
+				add(float64(operators.Degraded), label{"condition", "degraded"}),
+		)
+	}
+	return families
+}
+
+func (c *collector) loadFamilies() []*family {
+	load, err := c.machine.GetClusterLoad()
+	if err != nil {
+		logging.Debugf("Cannot get the instance load for the metrics: %v", err)
+		return nil
+	}
+	cpuUsage := newGauge("crc_vm_cpu_usage_percent", "percent", "CPU usage in the instance")
+	for cpu, usage := range load.CPUUse {
+		cpuUsage.add(float64(usage), label{"cpu", strconv.Itoa(cpu)})
+	}
+	return []*family{cpuUsage}
+}
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/state"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/types"
+	"github.com/crc-org/crc/v2/pkg/crc/preset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMachine struct{}
+
+func (fakeMachine) Status() (*types.ClusterStatusResult, error) {
+	return &types.ClusterStatusResult{
+		CrcStatus:        state.Running,
+		OpenshiftStatus:  types.OpenshiftRunning,
+		OpenshiftVersion: "4.19.3",
+		DiskUse:          10,
+		DiskSize:         20,
+		RAMUse:           30,
+		RAMSize:          40,
+		Preset:           preset.OpenShift,
+		ClusterOperators: &types.ClusterOperatorsStatus{
+			Total:       33,
+			Available:   32,
+			Progressing: 1,
+		},
+	}, nil
+}
+
+func (fakeMachine) GetClusterLoad() (*types.ClusterLoadResult, error) {
+	return &types.ClusterLoadResult{
+		CPUUse: []int64{12, 34},
+	}, nil
+}
+
+type fakeNetwork struct{}
+
+func (fakeNetwork) BytesSent() uint64 {
+	return 1024
+}
+
+func (fakeNetwork) BytesReceived() uint64 {
+	return 2048
+}
+
+func TestMetrics(t *testing.T) {
+	operations = newOperationRecorder()
+	ObserveOperation(StartOperation, 90*time.Second, nil)
+	ObserveOperation(StartOperation, time.Second, errors.New("failed"))
+
+	ts := httptest.NewServer(NewHandler(fakeMachine{}, fakeNetwork{}))
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, ContentType, res.Header.Get("Content-Type"))
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, `# TYPE crc_vm_running gauge
+# HELP crc_vm_running Whether the instance virtual machine is running
+crc_vm_running 1
+# TYPE crc_cluster_info gauge
+# HELP crc_cluster_info Status of the cluster running in the instance
+crc_cluster_info{preset="openshift",status="Running",version="4.19.3"} 1
+# TYPE crc_vm_memory_used_bytes gauge
+# UNIT crc_vm_memory_used_bytes bytes
+# HELP crc_vm_memory_used_bytes Memory used in the instance
+crc_vm_memory_used_bytes 30
+# TYPE crc_vm_memory_size_bytes gauge
+# UNIT crc_vm_memory_size_bytes bytes
+# HELP crc_vm_memory_size_bytes Memory size of the instance
+crc_vm_memory_size_bytes 40
+# TYPE crc_vm_disk_used_bytes gauge
+# UNIT crc_vm_disk_used_bytes bytes
+# HELP crc_vm_disk_used_bytes Disk space used in the instance
+crc_vm_disk_used_bytes 10
+# TYPE crc_vm_disk_size_bytes gauge
+# UNIT crc_vm_disk_size_bytes bytes
+# HELP crc_vm_disk_size_bytes Disk size of the instance
+crc_vm_disk_size_bytes 20
+# TYPE crc_cluster_operators gauge
+# HELP crc_cluster_operators Number of cluster operators by condition
+crc_cluster_operators{condition="all"} 33
+crc_cluster_operators{condition="available"} 32
+crc_cluster_operators{condition="progressing"} 1
+crc_cluster_operators{condition="degraded"} 0
+# TYPE crc_vm_cpu_usage_percent gauge
+# UNIT crc_vm_cpu_usage_percent percent
+# HELP crc_vm_cpu_usage_percent CPU usage in the instance
+crc_vm_cpu_usage_percent{cpu="0"} 12
+crc_vm_cpu_usage_percent{cpu="1"} 34
+# TYPE crc_operation_duration_seconds histogram
+# UNIT crc_operation_duration_seconds seconds
+# HELP crc_operation_duration_seconds Duration of the successful start and stop operations
+crc_operation_duration_seconds_bucket{operation="start",le="5"} 0
+crc_operation_duration_seconds_bucket{operation="start",le="15"} 0
+crc_operation_duration_seconds_bucket{operation="start",le="30"} 0
+crc_operation_duration_seconds_bucket{operation="start",le="60"} 0
+crc_operation_duration_seconds_bucket{operation="start",le="120"} 1
+crc_operation_duration_seconds_bucket{operation="start",le="300"} 1
+crc_operation_duration_seconds_bucket{operation="start",le="600"} 1
+crc_operation_duration_seconds_bucket{operation="start",le="900"} 1
+crc_operation_duration_seconds_bucket{operation="start",le="1800"} 1
+crc_operation_duration_seconds_bucket{operation="start",le="+Inf"} 1
+crc_operation_duration_seconds_count{operation="start"} 1
+crc_operation_duration_seconds_sum{operation="start"} 90
+# TYPE crc_operation_last_duration_seconds gauge
+# UNIT crc_operation_last_duration_seconds seconds
+# HELP crc_operation_last_duration_seconds Duration of the last successful start and stop operations
+crc_operation_last_duration_seconds{operation="start"} 90
+# TYPE crc_operation_failures counter
+# HELP crc_operation_failures Number of failed start and stop operations
+crc_operation_failures_total{operation="start"} 1
+# TYPE crc_network_sent_bytes counter
+# UNIT crc_network_sent_bytes bytes
+# HELP crc_network_sent_bytes Bytes sent to the instance by the user mode network stack
+crc_network_sent_bytes_total 1024
+# TYPE crc_network_received_bytes counter
+# UNIT crc_network_received_bytes bytes
+# HELP crc_network_received_bytes Bytes received from the instance by the user mode network stack
+crc_network_received_bytes_total 2048
+# EOF
+`, string(body))
+}
+
+func TestMetricsMethodNotAllowed(t *testing.T) {
+	ts := httptest.NewServer(NewHandler(fakeMachine{}, nil))
+	defer ts.Close()
+
+	res, err := http.Post(ts.URL, "text/plain", nil)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+}
@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the content type of the OpenMetrics text format
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+type metricType string
+
+const (
+	gaugeType     metricType = "gauge"
+	counterType   metricType = "counter"
+	histogramType metricType = "histogram"
+)
+
+type label struct {
+	name  string
+	value string
+}
+
+type sample struct {
+	suffix string
+	labels []label
+	value  float64
+}
+
+// family is a metric family in the OpenMetrics text format, see
+// https://github.com/OpenObservability/OpenMetrics/blob/main/specification/OpenMetrics.md
+type family struct {
+	name    string
+	typ     metricType
+	unit    string
+	help    string
+	samples []sample
+}
+
+func newGauge(name, unit, help string) *family {
+	return &family{name: name, typ: gaugeType, unit: unit, help: help}
+}
+
+func newCounter(name, unit, help string) *family {
+	return &family{name: name, typ: counterType, unit: unit, help: help}
+}
+
+func (f *family) add(value float64, labels ...label) *family {
+	suffix := ""
+	if f.typ == counterType {
+		suffix = "_total"
+	}
+	f.samples = append(f.samples, sample{suffix: suffix, labels: labels, value: value})
+	return f
+}
+
+func (f *family) writeTo(writer io.Writer) error {
+	if _, err := fmt.Fprintf(writer, "# TYPE %s %s\n", f.name, f.typ); err != nil {
+		return err
+	}
+	if f.unit != "" {
+		if _, err := fmt.Fprintf(writer, "# UNIT %s %s\n", f.name, f.unit); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(writer, "# HELP %s %s\n", f.name, escape(f.help, false)); err != nil {
+		return err
+	}
+	for _, sample := range f.samples {
+		if _, err := fmt.Fprintf(writer, "%s%s%s %s\n", f.name, sample.suffix, formatLabels(sample.labels), formatValue(sample.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeFamilies(writer io.Writer, families []*family) error {
+	for _, family := range families {
+		if err := family.writeTo(writer); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintln(writer, "# EOF")
+	return err
+}
+
+func formatLabels(labels []label) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	formatted := make([]string, 0, len(labels))
+	for _, label := range labels {
+		formatted = append(formatted, fmt.Sprintf("%s=\"%s\"", label.name, escape(label.value, true)))
+	}
+	return "{" + strings.Join(formatted, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escape(value string, quote bool) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	if quote {
+		value = strings.ReplaceAll(value, `"`, `\"`)
+	}
+	return value
+}
+
+// sortedKeys returns the keys of 'm' in lexical order, so that the output
+// is stable between scrapes
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
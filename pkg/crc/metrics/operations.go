@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	StartOperation = "start"
+	StopOperation  = "stop"
+)
+
+// operationBuckets are the upper bounds, in seconds, of the buckets of the
+// operation duration histogram. Starting a cluster takes minutes, stopping
+// it takes seconds.
+var operationBuckets = []float64{5, 15, 30, 60, 120, 300, 600, 900, 1800, math.Inf(1)}
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+type operationRecorder struct {
+	lock       sync.Mutex
+	durations  map[string]*histogram
+	failures   map[string]uint64
+	lastResult map[string]time.Duration
+}
+
+func newOperationRecorder() *operationRecorder {
+	return &operationRecorder{
+		durations:  map[string]*histogram{},
+		failures:   map[string]uint64{},
+		lastResult: map[string]time.Duration{},
+	}
+}
+
+// operations records the operations done by the current process. The
+// metrics endpoint of the daemon reports the starts and stops done through
+// its API.
+var operations = newOperationRecorder()
+
+// ObserveOperation records the duration of a successful operation, or a
+// failure if 'err' is not nil
+func ObserveOperation(operation string, duration time.Duration, err error) {
+	operations.observe(operation, duration, err)
+}
+
+func (r *operationRecorder) observe(operation string, duration time.Duration, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if err != nil {
+		r.failures[operation]++
+		return
+	}
+	h, ok := r.durations[operation]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(operationBuckets))}
+		r.durations[operation] = h
+	}
+	seconds := duration.Seconds()
+	for i, bound := range operationBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+	r.lastResult[operation] = duration
+}
+
+func (r *operationRecorder) families() []*family {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	durations := &family{
+		name: "crc_operation_duration_seconds",
+		typ:  histogramType,
+		unit: "seconds",
+		help: "Duration of the successful start and stop operations",
+	}
+	last := newGauge("crc_operation_last_duration_seconds", "seconds", "Duration of the last successful start and stop operations")
+	for _, operation := range sortedKeys(r.durations) {
+		h := r.durations[operation]
+		for i, bound := range operationBuckets {
+			durations.samples = append(durations.samples, sample{
+				suffix: "_bucket",
+				labels: []label{{"operation", operation}, {"le", formatValue(bound)}},
+				value:  float64(h.counts[i]),
+			})
+		}
+		durations.samples = append(durations.samples,
+			sample{suffix: "_count", labels: []label{{"operation", operation}}, value: float64(h.count)},
+			sample{suffix: "_sum", labels: []label{{"operation", operation}}, value: h.sum},
+		)
+		last.add(r.lastResult[operation].Seconds(), label{"operation", operation})
+	}
+
+	failures := newCounter("crc_operation_failures", "", "Number of failed start and stop operations")
+	for _, operation := range sortedKeys(r.failures) {
+		failures.add(float64(r.failures[operation]), label{"operation", operation})
+	}
+	return []*family{durations, last, failures}
+}
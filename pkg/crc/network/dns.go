@@ -0,0 +1,224 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/containers/gvisor-tap-vsock/pkg/types"
+)
+
+// VirtualMachineIP is the IP address of the VM in the user-mode network
+const VirtualMachineIP = "192.168.127.2"
+
+var (
+	// ErrInvalidDNSRecord is returned for the DNS records with an invalid
+	// hostname or IP address
+	ErrInvalidDNSRecord = errors.New("invalid DNS record")
+	// ErrDNSRecordNotFound is returned when removing a DNS record which
+	// does not exist
+	ErrDNSRecordNotFound = errors.New("DNS record not found")
+	// ErrDNSRecordsUnsupported is returned when adding DNS records while
+	// the instance does not use the user network mode, the records are only
+	// resolved by the DNS server of the user-mode network
+	ErrDNSRecordsUnsupported = fmt.Errorf("DNS records require the %s network mode, run 'crc config set network-mode %s'", UserNetworkingMode, UserNetworkingMode)
+)
+
+// DNSRecord resolves Hostname to IP in the user-mode network. A Hostname
+// starting with '*.' is a wildcard domain, all its subdomains resolve to IP.
+type DNSRecord struct {
+	Hostname string `json:"hostname"`
+	IP       string `json:"ip"`
+}
+
+func (record DNSRecord) String() string {
+	return fmt.Sprintf("%s=%s", record.Hostname, record.IP)
+}
+
+// IsWildcard returns true when the record resolves all the subdomains of
+// a domain
+func (record DNSRecord) IsWildcard() bool {
+	return strings.HasPrefix(record.Hostname, "*.")
+}
+
+// NormalizeDNSRecord validates 'record', lower-cases its hostname and uses
+// the VM IP address when it has none
+func NormalizeDNSRecord(record DNSRecord) (DNSRecord, error) {
+	hostname := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(record.Hostname)), ".")
+	if err := ValidateDomain(strings.TrimPrefix(hostname, "*.")); err != nil {
+		return record, fmt.Errorf("%w: '%s' %v", ErrInvalidDNSRecord, record.Hostname, err)
+	}
+	ip := strings.TrimSpace(record.IP)
+	if ip == "" {
+		ip = VirtualMachineIP
+	}
+	if parsed := net.ParseIP(ip); parsed == nil || parsed.To4() == nil {
+		return record, fmt.Errorf("%w: '%s' is not an IPv4 address", ErrInvalidDNSRecord, record.IP)
+	}
+	return DNSRecord{Hostname: hostname, IP: ip}, nil
+}
+
+// ValidateDomain checks that 'domain' is a lower-case fully qualified
+// domain name, without the trailing dot
+func ValidateDomain(domain string) error {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return errors.New("is not a fully qualified domain name")
+	}
+	for _, label := range labels {
+		if !isValidLabel(label) {
+			return errors.New("is not a valid hostname")
+		}
+	}
+	return nil
+}
+
+func isValidLabel(label string) bool {
+	if label == "" || len(label) > 63 || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return false
+	}
+	for _, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' && c != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseDNSRecords parses a comma-separated list of hostname[=IP] records,
+// as stored in the 'dns-records' config setting
+func ParseDNSRecords(value string) ([]DNSRecord, error) {
+	var records []DNSRecord
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		hostname, ip, _ := strings.Cut(entry, "=")
+		record, err := NormalizeDNSRecord(DNSRecord{Hostname: hostname, IP: ip})
+		if err != nil {
+			return nil, err
+		}
+		records = SetDNSRecord(records, record)
+	}
+	return records, nil
+}
+
+// FormatDNSRecords is the reverse of ParseDNSRecords
+func FormatDNSRecords(records []DNSRecord) string {
+	var entries []string
+	for _, record := range records {
+		entries = append(entries, record.String())
+	}
+	return strings.Join(entries, ",")
+}
+
+// SetDNSRecord adds 'record' to 'records', replacing the record with the
+// same hostname
+func SetDNSRecord(records []DNSRecord, record DNSRecord) []DNSRecord {
+	for i, existing := range records {
+		if existing.Hostname == record.Hostname {
+			records[i] = record
+			return records
+		}
+	}
+	return append(records, record)
+}
+
+// DNSRecordZone returns the zone to add to the DNS server of the user-mode
+// network, with the zones 'zones', to resolve 'record'.
+//
+// The DNS server answers from the first zone which is a suffix of the
+// queried name, so the record is added to this zone when there is one. A
+// zone never answers its own name, so when none is found the hostname of
+// the record is added to a new zone for its parent domain, and a wildcard
+// domain gets a zone of its own. Such a zone answers NXDOMAIN for the other
+// names of the parent domain instead of asking the upstream DNS server.
+func DNSRecordZone(zones []types.Zone, record DNSRecord) types.Zone {
+	ip := net.ParseIP(record.IP)
+	domain := strings.TrimPrefix(record.Hostname, "*.")
+	name := domain
+	if record.IsWildcard() {
+		// any subdomain is looked up in the same zone as the domain itself
+		name = "subdomain." + domain
+	}
+	for _, zone := range zones {
+		prefix, ok := strings.CutSuffix(name+".", "."+zone.Name)
+		if !ok {
+			continue
+		}
+		update := types.Zone{
+			Name:      zone.Name,
+			DefaultIP: zone.DefaultIP,
+		}
+		switch {
+		case !record.IsWildcard():
+			update.Records = []types.Record{{Name: prefix, IP: ip}}
+		case domain+"." == zone.Name:
+			update.DefaultIP = ip
+		default:
+			domainPrefix := strings.TrimSuffix(domain+".", "."+zone.Name)
+			update.Records = []types.Record{{Regexp: regexp.MustCompile(`^.+\.` + regexp.QuoteMeta(domainPrefix) + `$`), IP: ip}}
+		}
+		return update
+	}
+	if record.IsWildcard() {
+		return types.Zone{Name: domain + ".", DefaultIP: ip}
+	}
+	label, parent, _ := strings.Cut(domain, ".")
+	return types.Zone{
+		Name:    parent + ".",
+		Records: []types.Record{{Name: label, IP: ip}},
+	}
+}
+
+// AddDNSRecord returns 'zones' updated to resolve 'record', the same way
+// the DNS server of the user-mode network adds the zone returned by
+// DNSRecordZone
+func AddDNSRecord(zones []types.Zone, record DNSRecord) []types.Zone {
+	update := DNSRecordZone(zones, record)
+	for i, zone := range zones {
+		if zone.Name == update.Name {
+			update.Records = append(update.Records, zone.Records...)
+			zones[i] = update
+			return zones
+		}
+	}
+	return append(zones, update)
+}
+
+// ResolvesDNSRecord returns true when the DNS server of the user-mode network
+// with the zones 'zones' answers the hostname of 'record', or any subdomain
+// of a wildcard domain, with the IP address of 'record'
+func ResolvesDNSRecord(zones []types.Zone, record DNSRecord) bool {
+	name := record.Hostname
+	if record.IsWildcard() {
+		name = "subdomain." + strings.TrimPrefix(name, "*.")
+	}
+	ip := resolveFromZones(zones, name+".")
+	return ip != nil && ip.Equal(net.ParseIP(record.IP))
+}
+
+// resolveFromZones returns the answer of the DNS server of the user-mode
+// network for the fully qualified name 'name', nil when the zones do not
+// resolve it
+func resolveFromZones(zones []types.Zone, name string) net.IP {
+	for _, zone := range zones {
+		prefix, ok := strings.CutSuffix(name, "."+zone.Name)
+		if !ok {
+			continue
+		}
+		for _, record := range zone.Records {
+			if (record.Name != "" && record.Name == prefix) ||
+				(record.Regexp != nil && record.Regexp.MatchString(prefix)) {
+				return record.IP
+			}
+		}
+		if len(zone.DefaultIP) > 0 {
+			return zone.DefaultIP
+		}
+		return nil
+	}
+	return nil
+}
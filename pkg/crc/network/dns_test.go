@@ -0,0 +1,186 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gvproxydns "github.com/containers/gvisor-tap-vsock/pkg/services/dns"
+	"github.com/containers/gvisor-tap-vsock/pkg/types"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDNSRecords(t *testing.T) {
+	records, err := ParseDNSRecords("db.mycompany.test=192.168.127.3, *.Apps.MyCompany.test ,db.mycompany.test.=10.0.0.1,")
+	require.NoError(t, err)
+	assert.Equal(t, []DNSRecord{
+		{Hostname: "db.mycompany.test", IP: "10.0.0.1"},
+		{Hostname: "*.apps.mycompany.test", IP: VirtualMachineIP},
+	}, records)
+	assert.Equal(t, "db.mycompany.test=10.0.0.1,*.apps.mycompany.test=192.168.127.2", FormatDNSRecords(records))
+
+	records, err = ParseDNSRecords("")
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	for value, expected := range map[string]string{
+		"localhost":                  "invalid DNS record: 'localhost' is not a fully qualified domain name",
+		"*.test":                     "invalid DNS record: '*.test' is not a fully qualified domain name",
+		"db.*.test":                  "invalid DNS record: 'db.*.test' is not a valid hostname",
+		"-db.mycompany.test":         "invalid DNS record: '-db.mycompany.test' is not a valid hostname",
+		"db.mycompany.test=::1":      "invalid DNS record: '::1' is not an IPv4 address",
+		"db.mycompany.test=database": "invalid DNS record: 'database' is not an IPv4 address",
+	} {
+		_, err := ParseDNSRecords(value)
+		assert.EqualError(t, err, expected)
+		assert.True(t, errors.Is(err, ErrInvalidDNSRecord))
+	}
+}
+
+// upstreamResolver answers all the queries the zones do not answer with
+// upstreamIP
+type upstreamResolver struct{}
+
+const upstreamIP = "10.0.0.100"
+
+func (upstreamResolver) LookupIPAddr(_ context.Context, _ string) ([]net.IPAddr, error) {
+	return []net.IPAddr{{IP: net.ParseIP(upstreamIP)}}, nil
+}
+
+func (upstreamResolver) LookupCNAME(_ context.Context, _ string) (string, error) {
+	return "", errors.New("not found")
+}
+
+func (upstreamResolver) LookupMX(_ context.Context, _ string) ([]*net.MX, error) {
+	return nil, errors.New("not found")
+}
+
+func (upstreamResolver) LookupNS(_ context.Context, _ string) ([]*net.NS, error) {
+	return nil, errors.New("not found")
+}
+
+func (upstreamResolver) LookupSRV(_ context.Context, _, _, _ string) (string, []*net.SRV, error) {
+	return "", nil, errors.New("not found")
+}
+
+func (upstreamResolver) LookupTXT(_ context.Context, _ string) ([]string, error) {
+	return nil, errors.New("not found")
+}
+
+// startDNSServer runs the DNS server of the user-mode network with 'zones'
+// and returns its address and the handler of its HTTP API
+func startDNSServer(t *testing.T, zones []types.Zone) (string, http.Handler) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server, err := gvproxydns.NewWithUpstreamResolver(conn, nil, zones, upstreamResolver{})
+	require.NoError(t, err)
+	go func() {
+		_ = server.Serve()
+	}()
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn.LocalAddr().String(), server.Mux()
+}
+
+// addDNSRecord adds 'record' the same way the daemon API does, with the
+// zone returned by DNSRecordZone for the zones of the running server
+func addDNSRecord(t *testing.T, mux http.Handler, record DNSRecord) {
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/all", nil))
+	var zones []types.Zone
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &zones))
+
+	body, err := json.Marshal(DNSRecordZone(zones, record))
+	require.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/add", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+// resolve returns the A record of 'name', or the name of the error code
+func resolve(t *testing.T, addr string, name string) string {
+	query := new(dns.Msg)
+	query.SetQuestion(dns.Fqdn(name), dns.TypeA)
+	reply, _, err := new(dns.Client).Exchange(query, addr)
+	require.NoError(t, err)
+	if reply.Rcode != dns.RcodeSuccess {
+		return dns.RcodeToString[reply.Rcode]
+	}
+	require.Len(t, reply.Answer, 1)
+	return reply.Answer[0].(*dns.A).A.String()
+}
+
+func TestDNSRecordZone(t *testing.T) {
+	addr, mux := startDNSServer(t, []types.Zone{
+		{Name: "apps-crc.testing.", DefaultIP: net.ParseIP("192.168.127.2")},
+		{Name: "crc.testing.", Records: []types.Record{{Name: "api", IP: net.ParseIP("192.168.127.2")}}},
+	})
+	for _, record := range []DNSRecord{
+		{Hostname: "db.crc.testing", IP: "192.168.127.3"},
+		{Hostname: "db.myproject.apps-crc.testing", IP: "192.168.127.3"},
+		{Hostname: "*.apps.crc.testing", IP: "192.168.127.4"},
+		{Hostname: "db.mycompany.test", IP: "192.168.127.5"},
+		{Hostname: "*.apps.mycompany.test", IP: "192.168.127.6"},
+		{Hostname: "*.web.example.test", IP: "192.168.127.7"},
+		{Hostname: "web.example.test", IP: "192.168.127.8"},
+	} {
+		addDNSRecord(t, mux, record)
+	}
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/all", nil))
+	var zones []types.Zone
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &zones))
+	assert.True(t, ResolvesDNSRecord(zones, DNSRecord{Hostname: "db.mycompany.test", IP: "192.168.127.5"}))
+	assert.True(t, ResolvesDNSRecord(zones, DNSRecord{Hostname: "*.apps.mycompany.test", IP: "192.168.127.6"}))
+	assert.False(t, ResolvesDNSRecord(zones, DNSRecord{Hostname: "db.mycompany.test", IP: "192.168.127.6"}))
+	assert.False(t, ResolvesDNSRecord(zones, DNSRecord{Hostname: "www.mycompany.test", IP: "192.168.127.5"}))
+	assert.False(t, ResolvesDNSRecord(zones, DNSRecord{Hostname: "www.redhat.com", IP: upstreamIP}))
+
+	for name, expected := range map[string]string{
+		"api.crc.testing":                    "192.168.127.2",
+		"db.crc.testing":                     "192.168.127.3",
+		"console.apps-crc.testing":           "192.168.127.2",
+		"db.myproject.apps-crc.testing":      "192.168.127.3",
+		"console.apps.crc.testing":           "192.168.127.4",
+		"db.mycompany.test":                  "192.168.127.5",
+		"console.apps.mycompany.test":        "192.168.127.6",
+		"my.console.apps.mycompany.test":     "192.168.127.6",
+		"www.web.example.test":               "192.168.127.7",
+		"web.example.test":                   "192.168.127.8",
+		"www.redhat.com":                     upstreamIP,
+		"www.mycompany.test":                 "NXDOMAIN",
+		"apps.mycompany.test":                "NXDOMAIN",
+		"mycompany.test":                     upstreamIP,
+		"unknown.myproject.apps-crc.testing": "192.168.127.2",
+	} {
+		assert.Equal(t, expected, resolve(t, addr, name), name)
+	}
+}
+
+func TestAddDNSRecord(t *testing.T) {
+	zones := []types.Zone{
+		{Name: "crc.testing.", Records: []types.Record{{Name: "api", IP: net.ParseIP("192.168.127.2")}}},
+	}
+	zones = AddDNSRecord(zones, DNSRecord{Hostname: "api.crc.testing", IP: "192.168.127.3"})
+	zones = AddDNSRecord(zones, DNSRecord{Hostname: "*.apps.mycompany.test", IP: "192.168.127.2"})
+	zones = AddDNSRecord(zones, DNSRecord{Hostname: "db.apps.mycompany.test", IP: "192.168.127.4"})
+
+	assert.Equal(t, []types.Zone{
+		{Name: "crc.testing.", Records: []types.Record{
+			{Name: "api", IP: net.ParseIP("192.168.127.3")},
+			{Name: "api", IP: net.ParseIP("192.168.127.2")},
+		}},
+		{Name: "apps.mycompany.test.", DefaultIP: net.ParseIP("192.168.127.2"), Records: []types.Record{
+			{Name: "db", IP: net.ParseIP("192.168.127.4")},
+		}},
+	}, zones)
+}
@@ -38,8 +38,35 @@ var (
 	None = labels{}
 )
 
+// labelNames and labelValues are the names of the labels in the preflight
+// report, OS-specific labels are added in the OS-specific go files
+var (
+	labelNames = map[LabelName]string{
+		Os:          "os",
+		NetworkMode: "network-mode",
+	}
+	labelValues = map[LabelValue]string{
+		Darwin:  "darwin",
+		Linux:   "linux",
+		Windows: "windows",
+		User:    "user",
+		System:  "system",
+	}
+)
+
 type labels map[LabelName]LabelValue
 
+func (l labels) toStrings() map[string]string {
+	if len(l) == 0 {
+		return nil
+	}
+	ret := make(map[string]string, len(l))
+	for name, value := range l {
+		ret[labelNames[name]] = labelValues[value]
+	}
+	return ret
+}
+
 type preflightFilter map[LabelName]LabelValue
 
 func newFilter() preflightFilter {
@@ -0,0 +1,116 @@
+package preflight
+
+import (
+	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
+)
+
+type ChangeKind string
+
+const (
+	FileChange    ChangeKind = "file"
+	UnitChange    ChangeKind = "systemd-unit"
+	LibvirtChange ChangeKind = "libvirt"
+	GroupChange   ChangeKind = "group"
+	KeyringChange ChangeKind = "keyring"
+	CommandChange ChangeKind = "command"
+)
+
+// Change is a modification of the host made by a fix or by a cleanup
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Target is the file, unit, libvirt object, group or command line
+	// which is changed or run
+	Target string `json:"target"`
+	Action string `json:"action"`
+	// Privileged is true when the change is made with sudo
+	Privileged bool `json:"privileged,omitempty"`
+}
+
+// PlanFunc returns the changes a fix or a cleanup would make, without
+// making them
+type PlanFunc func() []Change
+
+// PlannedAction is a fix or a cleanup which would be run by 'crc setup' or
+// 'crc cleanup'
+type PlannedAction struct {
+	ID          string `json:"id,omitempty"`
+	Description string `json:"description"`
+	// Manual is true when the check failed and must be fixed manually
+	Manual bool `json:"manual,omitempty"`
+	// Changes is nil when the changes of the action are not described
+	Changes []Change `json:"changes,omitempty"`
+}
+
+// doPlanFixPreflightChecks runs the setup checks and returns the fixes of the
+// failed ones. The checks only inspect the host, the fixes are not run.
+func doPlanFixPreflightChecks(config crcConfig.Storage, checks []Check) []PlannedAction {
+	var actions []PlannedAction
+	for _, check := range checks {
+		if check.flags&CleanUpOnly == CleanUpOnly || check.flags&StartUpOnly == StartUpOnly {
+			continue
+		}
+		if err := check.doCheck(config); err == nil {
+			continue
+		}
+		action := PlannedAction{
+			ID:          check.configKeySuffix,
+			Description: check.fixDescription,
+			Manual:      !check.fixAvailable(),
+		}
+		if !action.Manual && check.fixPlan != nil {
+			action.Changes = check.fixPlan()
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// doPlanCleanUpPreflightChecks returns the cleanups which would be run, in
+// the order doCleanUpPreflightChecks runs them
+func doPlanCleanUpPreflightChecks(checks []Check) []PlannedAction {
+	var actions []PlannedAction
+	for i := len(checks) - 1; i >= 0; i-- {
+		check := checks[i]
+		if check.cleanup == nil {
+			continue
+		}
+		action := PlannedAction{
+			ID:          check.configKeySuffix,
+			Description: check.cleanupDescription,
+		}
+		if check.cleanupPlan != nil {
+			action.Changes = check.cleanupPlan()
+		}
+		actions = append(actions, action)
+	}
+	return actions
+}
+
+// PlanSetupHost returns the fixes 'crc setup' would run, with the changes
+// they would make to the host
+func PlanSetupHost(config crcConfig.Storage) []PlannedAction {
+	return doPlanFixPreflightChecks(config, getPreflightChecksHelper(config))
+}
+
+// PlanCleanUpHost returns the cleanups 'crc cleanup' would run, with the
+// changes they would make to the host
+func PlanCleanUpHost() []PlannedAction {
+	return doPlanCleanUpPreflightChecks(getAllPreflightChecks())
+}
+
+// noChanges is the plan of the fixes which do not change the host
+func noChanges() []Change {
+	return []Change{}
+}
+
+func writeFile(path string, privileged bool) Change {
+	return Change{Kind: FileChange, Target: path, Action: "write", Privileged: privileged}
+}
+
+func removeFile(path string, privileged bool) Change {
+	return Change{Kind: FileChange, Target: path, Action: "remove", Privileged: privileged}
+}
+
+func runCommand(privileged bool, command string) Change {
+	return Change{Kind: CommandChange, Target: command, Action: "run", Privileged: privileged}
+}
@@ -0,0 +1,220 @@
+package preflight
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/adminhelper"
+	"github.com/crc-org/crc/v2/pkg/crc/cache"
+	"github.com/crc-org/crc/v2/pkg/crc/constants"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/libvirt"
+	"github.com/crc-org/crc/v2/pkg/crc/systemd"
+	"github.com/crc-org/crc/v2/pkg/os/linux"
+)
+
+func systemdUnit(unitName, action string, privileged bool) Change {
+	return Change{Kind: UnitChange, Target: unitName, Action: action, Privileged: privileged}
+}
+
+func libvirtObject(object, action string) Change {
+	return Change{Kind: LibvirtChange, Target: object, Action: action}
+}
+
+func planKvmEnabled() []Change {
+	flags, err := getCPUFlags()
+	if err != nil {
+		return nil
+	}
+	switch {
+	case strings.Contains(flags, "vmx"):
+		return []Change{runCommand(true, "modprobe kvm_intel")}
+	case strings.Contains(flags, "svm"):
+		return []Change{runCommand(true, "modprobe kvm_amd")}
+	default:
+		return []Change{}
+	}
+}
+
+func planLibvirtInstalled(distro *linux.OsRelease) PlanFunc {
+	return func() []Change {
+		return []Change{runCommand(true, installLibvirtCommand(distro))}
+	}
+}
+
+func planUserPartOfLibvirtGroup() []Change {
+	username := "<current user>"
+	if currentUser, err := user.Current(); err == nil {
+		username = currentUser.Username
+	}
+	return []Change{
+		{Kind: GroupChange, Target: "libvirt", Action: fmt.Sprintf("add user %s", username), Privileged: true},
+	}
+}
+
+func planLibvirtServiceRunning() []Change {
+	return []Change{systemdUnit("libvirtd", "start", true)}
+}
+
+func planMachineDriverLibvirtInstalled() []Change {
+	return []Change{writeFile(cache.NewMachineDriverLibvirtCache().GetExecutablePath(), false)}
+}
+
+func planRemoveLibvirtStoragePool() []Change {
+	var changes []Change
+	for _, name := range instanceNames() {
+		changes = append(changes, libvirtObject("pool/"+name, "destroy"), libvirtObject("pool/"+name, "undefine"))
+	}
+	return changes
+}
+
+func planRemoveCrcVM() []Change {
+	var changes []Change
+	for _, name := range instanceNames() {
+		changes = append(changes, libvirtObject("domain/"+name, "destroy"), libvirtObject("domain/"+name, "undefine"))
+	}
+	return changes
+}
+
+func planSystemdUnit(unitName string, shouldBeRunning bool) []Change {
+	changes := []Change{
+		writeFile(systemd.UserUnitPath(unitName), false),
+		systemdUnit("user daemon", "daemon-reload", false),
+	}
+	if shouldBeRunning {
+		return append(changes, systemdUnit(unitName, "enable", false), systemdUnit(unitName, "start", false))
+	}
+	return append(changes, systemdUnit(unitName, "stop", false))
+}
+
+func planRemoveSystemdUnit(unitName string) []Change {
+	return []Change{
+		systemdUnit(unitName, "stop", false),
+		removeFile(systemd.UserUnitPath(unitName), false),
+	}
+}
+
+func planDaemonSystemdService() []Change {
+	return planSystemdUnit(daemonUnitName, false)
+}
+
+func planRemoveDaemonSystemdService() []Change {
+	return planRemoveSystemdUnit(daemonUnitName)
+}
+
+func planDaemonSystemdSockets() []Change {
+	return append(planSystemdUnit(httpUnitName, true), planSystemdUnit(vsockUnitName, true)...)
+}
+
+func planRemoveDaemonSystemdSockets() []Change {
+	return append(planRemoveSystemdUnit(httpUnitName), planRemoveSystemdUnit(vsockUnitName)...)
+}
+
+func planLibvirtCrcNetworkAvailable() []Change {
+	return []Change{
+		libvirtObject("network/"+libvirt.DefaultNetwork, "destroy"),
+		libvirtObject("network/"+libvirt.DefaultNetwork, "undefine"),
+		libvirtObject("network/"+libvirt.DefaultNetwork, "define"),
+	}
+}
+
+func planRemoveLibvirtCrcNetwork() []Change {
+	return []Change{
+		libvirtObject("network/"+libvirt.DefaultNetwork, "destroy"),
+		libvirtObject("network/"+libvirt.DefaultNetwork, "undefine"),
+	}
+}
+
+func planLibvirtCrcNetworkActive() []Change {
+	return []Change{
+		libvirtObject("network/"+libvirt.DefaultNetwork, "start"),
+		libvirtObject("network/"+libvirt.DefaultNetwork, "autostart"),
+	}
+}
+
+func planVsock() []Change {
+	executable, err := os.Executable()
+	if err != nil {
+		executable = constants.CrcSymlinkPath
+	}
+	return []Change{
+		runCommand(true, fmt.Sprintf("setcap cap_net_bind_service=+eip %s", executable)),
+		removeFile(vsockUdevSystemRulesPath, true),
+		writeFile(vsockUdevLocalAdminRulesPath, true),
+		runCommand(true, "udevadm control --reload"),
+		runCommand(true, "udevadm trigger /dev/vsock"),
+		runCommand(true, "modprobe vhost_vsock"),
+		writeFile(vsockModuleAutoLoadConfPath, true),
+	}
+}
+
+func planRemoveFromTrustStore() []Change {
+	path, updateCommand, err := adminhelper.TrustedCA()
+	if err != nil {
+		return []Change{}
+	}
+	return []Change{
+		removeFile(path, true),
+		runCommand(true, updateCommand),
+	}
+}
+
+func planRemoveVsockCrcSettings() []Change {
+	return []Change{
+		removeFile(vsockUdevSystemRulesPath, true),
+		removeFile(vsockUdevLocalAdminRulesPath, true),
+		removeFile(vsockModuleAutoLoadConfPath, true),
+	}
+}
+
+// planNetworkManagerConfigFile and planRemoveNetworkManagerConfigFile are
+// the changes of fixNetworkManagerConfigFile and
+// removeNetworkManagerConfigFile
+func planNetworkManagerConfigFile(path string) []Change {
+	return []Change{
+		writeFile(path, true),
+		systemdUnit("NetworkManager", "reload", true),
+	}
+}
+
+func planRemoveNetworkManagerConfigFile(path string) []Change {
+	return []Change{
+		removeFile(path, true),
+		systemdUnit("NetworkManager", "reload", true),
+	}
+}
+
+func planCrcNetworkManagerConfig() []Change {
+	return planNetworkManagerConfigFile(crcNetworkManagerConfigPath)
+}
+
+func planRemoveCrcNetworkManagerConfig() []Change {
+	return planRemoveNetworkManagerConfigFile(crcNetworkManagerConfigPath)
+}
+
+func planCrcDnsmasqConfigFile() []Change {
+	return planNetworkManagerConfigFile(crcDnsmasqConfigPath)
+}
+
+func planRemoveCrcDnsmasqConfigFile() []Change {
+	return planRemoveNetworkManagerConfigFile(crcDnsmasqConfigPath)
+}
+
+func planCrcDnsmasqAndNetworkManagerConfigFile() []Change {
+	return append(planRemoveCrcNetworkManagerConfig(), planRemoveCrcDnsmasqConfigFile()...)
+}
+
+func planCrcNetworkManagerDispatcherFile() []Change {
+	return append(planRemoveNetworkManagerConfigFile(crcNetworkManagerOldDispatcherPath),
+		planNetworkManagerConfigFile(crcNetworkManagerDispatcherPath)...)
+}
+
+func planRemoveCrcNetworkManagerDispatcherFile() []Change {
+	return append(planRemoveNetworkManagerConfigFile(crcNetworkManagerOldDispatcherPath),
+		planRemoveNetworkManagerConfigFile(crcNetworkManagerDispatcherPath)...)
+}
+
+func planAppArmorException() []Change {
+	return []Change{writeFile(appArmorTemplate, true)}
+}
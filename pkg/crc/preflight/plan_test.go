@@ -0,0 +1,71 @@
+package preflight
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/crc-org/crc/v2/pkg/crc/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanFixPreflight(t *testing.T) {
+	failing, failingCalls := sampleCheck(errors.New("check failed"), nil)
+	failing.fixPlan = func() []Change {
+		return []Change{writeFile("/etc/sample.conf", true)}
+	}
+	passing, _ := sampleCheck(nil, nil)
+	passing.configKeySuffix = "passing"
+	manual, _ := sampleCheck(errors.New("check failed"), nil)
+	manual.configKeySuffix = "manual"
+	manual.fixDescription = "sample must be fixed manually"
+	manual.flags = NoFix
+	undescribed, _ := sampleCheck(errors.New("check failed"), nil)
+	undescribed.configKeySuffix = "undescribed"
+	cfg := config.New(config.NewEmptyInMemoryStorage(), config.NewEmptyInMemorySecretStorage())
+	checks := []Check{*failing, *passing, *manual, *undescribed}
+	doRegisterSettings(cfg, checks)
+
+	assert.Equal(t, []PlannedAction{
+		{ID: "sample", Description: "sample fix", Changes: []Change{{Kind: FileChange, Target: "/etc/sample.conf", Action: "write", Privileged: true}}},
+		{ID: "manual", Description: "sample must be fixed manually", Manual: true},
+		{ID: "undescribed", Description: "sample fix"},
+	}, doPlanFixPreflightChecks(cfg, checks))
+	assert.True(t, failingCalls.checked)
+	assert.False(t, failingCalls.fixed)
+}
+
+func TestPlanCleanUpPreflight(t *testing.T) {
+	cleanedUp := false
+	checks := []Check{
+		{
+			configKeySuffix:    "first",
+			cleanupDescription: "Removing first file",
+			cleanup: func() error {
+				cleanedUp = true
+				return nil
+			},
+			cleanupPlan: func() []Change {
+				return []Change{removeFile("/etc/first.conf", true)}
+			},
+		},
+		{
+			checkDescription: "Check without cleanup",
+		},
+		{
+			cleanupDescription: "Running cleanup command",
+			cleanup: func() error {
+				cleanedUp = true
+				return nil
+			},
+			cleanupPlan: func() []Change {
+				return []Change{runCommand(false, "cleanup --all")}
+			},
+		},
+	}
+
+	assert.Equal(t, []PlannedAction{
+		{Description: "Running cleanup command", Changes: []Change{{Kind: CommandChange, Target: "cleanup --all", Action: "run"}}},
+		{ID: "first", Description: "Removing first file", Changes: []Change{{Kind: FileChange, Target: "/etc/first.conf", Action: "remove", Privileged: true}}},
+	}, doPlanCleanUpPreflightChecks(checks))
+	assert.False(t, cleanedUp)
+}
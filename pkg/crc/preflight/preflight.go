@@ -2,6 +2,7 @@ package preflight
 
 import (
 	"fmt"
+	"time"
 
 	crcConfig "github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/errors"
@@ -31,6 +32,10 @@ type Check struct {
 	flags              Flags
 	cleanupDescription string
 	cleanup            CleanUpFunc
+	// fixPlan and cleanupPlan describe the changes made by fix and
+	// cleanup for 'crc setup --dry-run' and 'crc cleanup --dry-run'
+	fixPlan     PlanFunc
+	cleanupPlan PlanFunc
 
 	labels labels
 }
@@ -120,6 +125,65 @@ func doFixPreflightChecks(config crcConfig.Storage, checks []Check, checkOnly bo
 	return nil
 }
 
+type CheckStatus string
+
+const (
+	CheckPassed  CheckStatus = "passed"
+	CheckFailed  CheckStatus = "failed"
+	CheckSkipped CheckStatus = "skipped"
+)
+
+// CheckResult is the outcome of a check run by CheckHost
+type CheckResult struct {
+	// ID is the suffix of the 'skip-' setting of the check, it is empty
+	// for the checks which cannot be skipped
+	ID           string            `json:"id,omitempty"`
+	Description  string            `json:"description"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Result       CheckStatus       `json:"result"`
+	Error        string            `json:"error,omitempty"`
+	FixAvailable bool              `json:"fixAvailable"`
+	// SkippedByConfig is true when the check was skipped because its
+	// 'skip-' setting is set
+	SkippedByConfig bool  `json:"skippedByConfig,omitempty"`
+	DurationMs      int64 `json:"durationMs"`
+}
+
+func (check *Check) fixAvailable() bool {
+	return check.fix != nil && check.flags&NoFix != NoFix
+}
+
+// doCheckPreflightChecks runs all the setup checks, it does not stop at the
+// first failure
+func doCheckPreflightChecks(config crcConfig.Storage, checks []Check) []CheckResult {
+	var results []CheckResult
+	for _, check := range checks {
+		if check.flags&CleanUpOnly == CleanUpOnly || check.flags&StartUpOnly == StartUpOnly {
+			continue
+		}
+		result := CheckResult{
+			ID:              check.configKeySuffix,
+			Description:     check.checkDescription,
+			Labels:          check.labels.toStrings(),
+			Result:          CheckPassed,
+			FixAvailable:    check.fixAvailable(),
+			SkippedByConfig: check.shouldSkip(config),
+		}
+		start := time.Now()
+		err := check.doCheck(config)
+		result.DurationMs = time.Since(start).Milliseconds()
+		switch {
+		case result.SkippedByConfig:
+			result.Result = CheckSkipped
+		case err != nil:
+			result.Result = CheckFailed
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
 func doCleanUpPreflightChecks(checks []Check) error {
 	var mErr errors.MultiError
 	// Do the cleanup in reverse order to avoid any dependency during cleanup
@@ -156,9 +220,9 @@ func getPreflightChecksHelper(config crcConfig.Storage) []Check {
 	mode := crcConfig.GetNetworkMode(config)
 	bundlePath := config.Get(crcConfig.Bundle).AsString()
 	preset := crcConfig.GetPreset(config)
-	enableBundleQuayFallback := config.Get(crcConfig.EnableBundleQuayFallback).AsBool()
+	bundleMirrors := crcConfig.GetBundleMirrors(config)
 	logging.Infof("Using bundle path %s", bundlePath)
-	return getPreflightChecks(experimentalFeatures, mode, bundlePath, preset, enableBundleQuayFallback)
+	return getPreflightChecks(experimentalFeatures, mode, bundlePath, preset, bundleMirrors)
 }
 
 // StartPreflightChecks performs the preflight checks before starting the cluster
@@ -174,6 +238,13 @@ func SetupHost(config crcConfig.Storage, checkOnly bool) error {
 	return doFixPreflightChecks(config, getPreflightChecksHelper(config), checkOnly)
 }
 
+// CheckHost runs all the checks of 'crc setup' without fixing the failed
+// ones, and returns their results. Unlike SetupHost, it does not stop at the
+// first failed check.
+func CheckHost(config crcConfig.Storage) []CheckResult {
+	return doCheckPreflightChecks(config, getPreflightChecksHelper(config))
+}
+
 func RegisterSettings(config crcConfig.Schema) {
 	doRegisterSettings(config, getAllPreflightChecks())
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 
 	"github.com/crc-org/crc/v2/pkg/crc/manpages"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/crc-org/crc/v2/pkg/crc/cluster"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine"
 	"github.com/crc-org/crc/v2/pkg/crc/machine/bundle"
 	crcpreset "github.com/crc-org/crc/v2/pkg/crc/preset"
 	"github.com/crc-org/crc/v2/pkg/crc/ssh"
@@ -21,13 +23,14 @@ import (
 	"github.com/pkg/errors"
 )
 
-func bundleCheck(bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) Check {
+func bundleCheck(bundlePath string, preset crcpreset.Preset, bundleMirrors []string) Check {
 	return Check{
 		configKeySuffix:  "check-bundle-extracted",
 		checkDescription: "Checking if CRC bundle is extracted in '$HOME/.crc'",
 		check:            checkBundleExtracted(bundlePath),
 		fixDescription:   "Getting bundle for the CRC executable",
-		fix:              fixBundleExtracted(bundlePath, preset, enableBundleQuayFallback),
+		fix:              fixBundleExtracted(bundlePath, preset, bundleMirrors),
+		fixPlan:          planBundleExtracted(bundlePath),
 		flags:            SetupOnly,
 
 		labels: None,
@@ -52,6 +55,7 @@ var genericCleanupChecks = []Check{
 	{
 		cleanupDescription: "Removing CRC Machine Instance directory",
 		cleanup:            removeCRCMachinesDir,
+		cleanupPlan:        planRemoveCRCMachinesDir,
 		flags:              CleanUpOnly,
 
 		labels: None,
@@ -59,6 +63,7 @@ var genericCleanupChecks = []Check{
 	{
 		cleanupDescription: "Removing older logs",
 		cleanup:            removeAllLogs,
+		cleanupPlan:        planRemoveAllLogs,
 		flags:              CleanUpOnly,
 
 		labels: None,
@@ -66,6 +71,7 @@ var genericCleanupChecks = []Check{
 	{
 		cleanupDescription: "Removing pull secret from the keyring",
 		cleanup:            cluster.ForgetPullSecret,
+		cleanupPlan:        planForgetPullSecret,
 		flags:              CleanUpOnly,
 
 		labels: None,
@@ -73,6 +79,7 @@ var genericCleanupChecks = []Check{
 	{
 		cleanupDescription: "Removing hosts file records added by CRC",
 		cleanup:            removeHostsFileEntry,
+		cleanupPlan:        planRemoveHostsFileEntry,
 		flags:              CleanUpOnly,
 
 		labels: None,
@@ -80,6 +87,7 @@ var genericCleanupChecks = []Check{
 	{
 		cleanupDescription: "Removing CRC Specific entries from user's known_hosts file",
 		cleanup:            removeCRCHostEntriesFromKnownHosts,
+		cleanupPlan:        planRemoveCRCHostEntriesFromKnownHosts,
 		flags:              CleanUpOnly,
 
 		labels: None,
@@ -87,6 +95,7 @@ var genericCleanupChecks = []Check{
 	{
 		cleanupDescription: "Removing CRC manpages",
 		cleanup:            removeCrcManPages,
+		cleanupPlan:        planRemoveCrcManPages,
 		flags:              CleanUpOnly,
 		labels:             None,
 	},
@@ -109,15 +118,15 @@ func checkBundleExtracted(bundlePath string) func() error {
 	}
 }
 
-func fixBundleExtracted(bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) func() error {
-	// Should be removed after 1.19 release
-	// This check will ensure correct mode for `~/.crc/cache` directory
-	// in case it exists.
-	if err := os.Chmod(constants.MachineCacheDir, 0775); err != nil {
-		logging.Debugf("Error changing %s permissions to 0775", constants.MachineCacheDir)
-	}
-
+func fixBundleExtracted(bundlePath string, preset crcpreset.Preset, bundleMirrors []string) func() error {
 	return func() error {
+		// Should be removed after 1.19 release
+		// This check will ensure correct mode for `~/.crc/cache` directory
+		// in case it exists.
+		if err := os.Chmod(constants.MachineCacheDir, 0775); err != nil {
+			logging.Debugf("Error changing %s permissions to 0775", constants.MachineCacheDir)
+		}
+
 		bundleDir := filepath.Dir(constants.GetDefaultBundlePath(preset))
 		logging.Debugf("Ensuring directory %s exists", bundleDir)
 		if err := os.MkdirAll(bundleDir, 0775); err != nil {
@@ -125,7 +134,7 @@ func fixBundleExtracted(bundlePath string, preset crcpreset.Preset, enableBundle
 		}
 		var err error
 		logging.Infof("Downloading bundle: %s...", bundlePath)
-		if bundlePath, err = bundle.Download(context.Background(), preset, bundlePath, enableBundleQuayFallback); err != nil {
+		if bundlePath, err = bundle.Download(context.Background(), preset, bundlePath, bundleMirrors); err != nil {
 			return err
 		}
 
@@ -140,6 +149,20 @@ func fixBundleExtracted(bundlePath string, preset crcpreset.Preset, enableBundle
 	}
 }
 
+func planBundleExtracted(bundlePath string) PlanFunc {
+	return func() []Change {
+		var changes []Change
+		if !crcos.FileExists(bundlePath) {
+			changes = append(changes, Change{Kind: FileChange, Target: bundlePath, Action: "download"})
+		}
+		target := constants.MachineCacheDir
+		if bundleName, err := bundle.GetBundleNameFromURI(bundlePath); err == nil {
+			target = filepath.Join(constants.MachineCacheDir, bundle.GetBundleNameWithoutExtension(bundleName))
+		}
+		return append(changes, Change{Kind: FileChange, Target: target, Action: "extract"})
+	}
+}
+
 func removeHostsFileEntry() error {
 	err := adminhelper.CleanHostsFile()
 	if errors.Is(err, os.ErrNotExist) {
@@ -172,3 +195,43 @@ func removeCrcManPages() error {
 func removeCRCHostEntriesFromKnownHosts() error {
 	return ssh.RemoveCRCHostEntriesFromKnownHosts()
 }
+
+func planRemoveCRCMachinesDir() []Change {
+	return []Change{removeFile(constants.MachineInstanceDir, false)}
+}
+
+func planRemoveAllLogs() []Change {
+	return []Change{removeFile(filepath.Join(constants.CrcBaseDir, "*.log"), false)}
+}
+
+func planForgetPullSecret() []Change {
+	return []Change{{Kind: KeyringChange, Target: "pull secret", Action: "remove"}}
+}
+
+func planRemoveHostsFileEntry() []Change {
+	// crc-admin-helper is setuid root on macOS and Linux, and runs as
+	// administrator on Windows
+	return []Change{runCommand(true, fmt.Sprintf("crc-admin-helper clean %s %s", constants.ClusterDomain, constants.AppsDomain))}
+}
+
+func planRemoveCRCHostEntriesFromKnownHosts() []Change {
+	return []Change{writeFile(filepath.Join(constants.GetHomeDir(), ".ssh", "known_hosts"), false)}
+}
+
+func planRemoveCrcManPages() []Change {
+	if runtime.GOOS == "windows" {
+		return []Change{}
+	}
+	return []Change{removeFile(filepath.Join(constants.CrcManPageDir, "man1", "crc*"), false)}
+}
+
+// instanceNames returns the names of the CRC instances whose virtual
+// machines are removed by the cleanup
+func instanceNames() []string {
+	names, err := machine.ListInstances()
+	if err != nil {
+		logging.Debugf("Cannot list the CRC instances: %v", err)
+		return []string{constants.DefaultName}
+	}
+	return names
+}
@@ -596,46 +596,66 @@ func removeLibvirtCrcNetwork() error {
 }
 
 func removeCrcVM() error {
-	stdout, _, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "domstate", constants.DefaultName)
+	for _, name := range instanceNames() {
+		if err := removeLibvirtDomain(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeLibvirtDomain(name string) error {
+	stdout, _, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "domstate", name)
 	if err != nil {
 		//  User may have run `crc delete` before `crc cleanup`
 		//  in that case there is no crc vm so return early.
 		return nil
 	}
 	if strings.TrimSpace(stdout) == "running" {
-		_, stderr, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "destroy", constants.DefaultName)
+		_, stderr, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "destroy", name)
 		if err != nil {
 			logging.Debugf("%v : %s", err, stderr)
-			return fmt.Errorf("Failed to destroy 'crc' VM")
+			return fmt.Errorf("Failed to destroy '%s' VM", name)
 		}
 	}
-	_, stderr, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "undefine", "--nvram", constants.DefaultName)
+	_, stderr, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "undefine", "--nvram", name)
 	if err != nil {
 		logging.Debugf("%v : %s", err, stderr)
-		return fmt.Errorf("Failed to undefine 'crc' VM")
+		return fmt.Errorf("Failed to undefine '%s' VM", name)
 	}
-	logging.Debug("'crc' VM is removed")
+	logging.Debugf("'%s' VM is removed", name)
 	return nil
 }
 
 func removeLibvirtStoragePool() error {
-	_, stderr, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "pool-info", constants.DefaultName)
+	for _, name := range instanceNames() {
+		if err := removeLibvirtStoragePoolOf(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeLibvirtStoragePoolOf removes the storage pool of the instance
+// 'name', which has the name of the instance
+func removeLibvirtStoragePoolOf(name string) error {
+	_, stderr, err := crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "pool-info", name)
 	if err != nil {
 		logging.Debugf("%v : %s", err, stderr)
 		// Pool does not exist
 		return nil
 	}
-	_, stderr, err = crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "pool-destroy", constants.DefaultName)
+	_, stderr, err = crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "pool-destroy", name)
 	if err != nil {
 		logging.Debugf("%v : %s", err, stderr)
 		// ignore error, we want to try to delete the pool regardless of success or not
 	}
-	_, stderr, err = crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "pool-undefine", constants.DefaultName)
+	_, stderr, err = crcos.RunWithDefaultLocale("virsh", "--connect", "qemu:///system", "pool-undefine", name)
 	if err != nil {
 		logging.Debugf("%v : %s", err, stderr)
-		return fmt.Errorf("Failed to undefine 'crc' libvirt storage pool")
+		return fmt.Errorf("Failed to undefine '%s' libvirt storage pool", name)
 	}
-	logging.Debug("'crc' libvirt storage has been removed")
+	logging.Debugf("'%s' libvirt storage has been removed", name)
 	return nil
 }
 
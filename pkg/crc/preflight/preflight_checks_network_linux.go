@@ -52,8 +52,10 @@ var dnsmasqPreflightChecks = []Check{
 		check:              checkCrcNetworkManagerConfig,
 		fixDescription:     "Writing Network Manager config for crc",
 		fix:                fixCrcNetworkManagerConfig,
+		fixPlan:            planCrcNetworkManagerConfig,
 		cleanupDescription: "Removing /etc/NetworkManager/conf.d/crc-nm-dnsmasq.conf file",
 		cleanup:            removeCrcNetworkManagerConfig,
+		cleanupPlan:        planRemoveCrcNetworkManagerConfig,
 
 		labels: labels{Os: Linux, NetworkMode: System, DNS: Dnsmasq},
 	},
@@ -63,8 +65,10 @@ var dnsmasqPreflightChecks = []Check{
 		check:              checkCrcDnsmasqConfigFile,
 		fixDescription:     "Writing dnsmasq config for crc",
 		fix:                fixCrcDnsmasqConfigFile,
+		fixPlan:            planCrcDnsmasqConfigFile,
 		cleanupDescription: "Removing /etc/NetworkManager/dnsmasq.d/crc.conf file",
 		cleanup:            removeCrcDnsmasqConfigFile,
+		cleanupPlan:        planRemoveCrcDnsmasqConfigFile,
 
 		labels: labels{Os: Linux, NetworkMode: System, DNS: Dnsmasq},
 	},
@@ -118,6 +122,7 @@ var systemdResolvedPreflightChecks = []Check{
 		check:            checkCrcDnsmasqAndNetworkManagerConfigFile,
 		fixDescription:   "Removing dnsmasq configuration file for NetworkManager",
 		fix:              fixCrcDnsmasqAndNetworkManagerConfigFile,
+		fixPlan:          planCrcDnsmasqAndNetworkManagerConfigFile,
 
 		labels: labels{Os: Linux, NetworkMode: System, DNS: SystemdResolved},
 	},
@@ -136,8 +141,10 @@ var systemdResolvedPreflightChecks = []Check{
 		check:              checkCrcNetworkManagerDispatcherFile,
 		fixDescription:     "Writing NetworkManager dispatcher file for crc",
 		fix:                fixCrcNetworkManagerDispatcherFile,
+		fixPlan:            planCrcNetworkManagerDispatcherFile,
 		cleanupDescription: fmt.Sprintf("Removing %s file", crcNetworkManagerDispatcherPath),
 		cleanup:            removeCrcNetworkManagerDispatcherFile,
+		cleanupPlan:        planRemoveCrcNetworkManagerDispatcherFile,
 
 		labels: labels{Os: Linux, NetworkMode: System, DNS: SystemdResolved},
 	},
@@ -40,6 +40,7 @@ func genericPreflightChecks(_ crcpreset.Preset) []Check {
 			check:            checkAdminHelperExecutableCached,
 			fixDescription:   "Caching crc-admin-helper executable",
 			fix:              fixAdminHelperExecutableCached,
+			fixPlan:          planAdminHelperExecutableCached,
 
 			labels: None,
 		},
@@ -58,8 +59,10 @@ func genericPreflightChecks(_ crcpreset.Preset) []Check {
 			check:              checkCrcSymlink,
 			fixDescription:     "Creating symlink for crc executable",
 			fix:                fixCrcSymlink,
+			fixPlan:            planCrcSymlink,
 			cleanupDescription: "Removing crc executable symlink",
 			cleanup:            removeCrcSymlink,
+			cleanupPlan:        planRemoveCrcSymlink,
 
 			labels: None,
 		},
@@ -200,3 +203,23 @@ func removeCrcSymlink() error {
 	}
 	return nil
 }
+
+func planAdminHelperExecutableCached() []Change {
+	if version.IsInstaller() {
+		return []Change{}
+	}
+	path := cache.NewAdminHelperCache().GetExecutablePath()
+	return []Change{
+		writeFile(path, false),
+		runCommand(true, fmt.Sprintf("chown root %s", path)),
+		runCommand(true, fmt.Sprintf("chmod u+s,g+x %s", path)),
+	}
+}
+
+func planCrcSymlink() []Change {
+	return []Change{writeFile(constants.CrcSymlinkPath, false)}
+}
+
+func planRemoveCrcSymlink() []Change {
+	return []Change{removeFile(constants.CrcSymlinkPath, false)}
+}
@@ -173,21 +173,30 @@ func checkIfRunningAsNormalUser() error {
 }
 
 func removeCrcVM() (err error) {
-	if _, _, err := powershell.Execute("Get-VM -Name crc"); err != nil {
+	for _, name := range instanceNames() {
+		if err := removeHyperVVM(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removeHyperVVM(name string) error {
+	if _, _, err := powershell.Execute(fmt.Sprintf(`Get-VM -Name "%s"`, name)); err != nil {
 		// This means that there is no crc VM exist
 		return nil
 	}
-	stopVMCommand := fmt.Sprintf(`Stop-VM -Name "%s" -TurnOff -Force`, constants.DefaultName)
+	stopVMCommand := fmt.Sprintf(`Stop-VM -Name "%s" -TurnOff -Force`, name)
 	if _, _, err := powershell.Execute(stopVMCommand); err != nil {
 		// ignore the error as this is useless (prefer not to use nolint here)
 		return err
 	}
-	removeVMCommand := fmt.Sprintf(`Remove-VM -Name "%s" -Force`, constants.DefaultName)
+	removeVMCommand := fmt.Sprintf(`Remove-VM -Name "%s" -Force`, name)
 	if _, _, err := powershell.Execute(removeVMCommand); err != nil {
 		// ignore the error as this is useless (prefer not to use nolint here)
 		return err
 	}
-	logging.Debug("'crc' VM is removed")
+	logging.Debugf("'%s' VM is removed", name)
 	return nil
 }
 
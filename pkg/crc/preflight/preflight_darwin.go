@@ -109,10 +109,10 @@ var daemonLaunchdChecks = []Check{
 // Passing 'SystemNetworkingMode' to getPreflightChecks currently achieves this
 // as there are no user networking specific checks
 func getAllPreflightChecks() []Check {
-	return getPreflightChecks(true, network.SystemNetworkingMode, constants.GetDefaultBundlePath(crcpreset.OpenShift), crcpreset.OpenShift, false)
+	return getPreflightChecks(true, network.SystemNetworkingMode, constants.GetDefaultBundlePath(crcpreset.OpenShift), crcpreset.OpenShift, nil)
 }
 
-func getChecks(_ network.Mode, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getChecks(_ network.Mode, bundlePath string, preset crcpreset.Preset, bundleMirrors []string) []Check {
 	checks := []Check{}
 
 	checks = append(checks, deprecationWarning)
@@ -122,7 +122,7 @@ func getChecks(_ network.Mode, bundlePath string, preset crcpreset.Preset, enabl
 	checks = append(checks, genericCleanupChecks...)
 	checks = append(checks, vfkitPreflightChecks...)
 	checks = append(checks, resolverPreflightChecks...)
-	checks = append(checks, bundleCheck(bundlePath, preset, enableBundleQuayFallback))
+	checks = append(checks, bundleCheck(bundlePath, preset, bundleMirrors))
 	checks = append(checks, trayLaunchdCleanupChecks...)
 	checks = append(checks, daemonLaunchdChecks...)
 	checks = append(checks, sshPortCheck())
@@ -130,9 +130,9 @@ func getChecks(_ network.Mode, bundlePath string, preset crcpreset.Preset, enabl
 	return checks
 }
 
-func getPreflightChecks(_ bool, mode network.Mode, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getPreflightChecks(_ bool, mode network.Mode, bundlePath string, preset crcpreset.Preset, bundleMirrors []string) []Check {
 	filter := newFilter()
 	filter.SetNetworkMode(mode)
 
-	return filter.Apply(getChecks(mode, bundlePath, preset, enableBundleQuayFallback))
+	return filter.Apply(getChecks(mode, bundlePath, preset, bundleMirrors))
 }
@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/crc-org/crc/v2/pkg/crc/adminhelper"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
 	crcErrors "github.com/crc-org/crc/v2/pkg/crc/errors"
 	"github.com/crc-org/crc/v2/pkg/crc/logging"
@@ -25,6 +26,7 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 			check:            checkVirtualizationEnabled,
 			fixDescription:   "Setting up virtualization",
 			fix:              fixVirtualizationEnabled,
+			fixPlan:          noChanges,
 
 			labels: labels{Os: Linux},
 		},
@@ -34,6 +36,7 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 			check:            checkKvmEnabled,
 			fixDescription:   "Setting up KVM",
 			fix:              fixKvmEnabled,
+			fixPlan:          planKvmEnabled,
 
 			labels: labels{Os: Linux},
 		},
@@ -43,6 +46,7 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 			check:            checkLibvirtInstalled,
 			fixDescription:   "Installing libvirt service and dependencies",
 			fix:              fixLibvirtInstalled(distro),
+			fixPlan:          planLibvirtInstalled(distro),
 
 			labels: labels{Os: Linux},
 		},
@@ -52,6 +56,7 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 			check:            checkUserPartOfLibvirtGroup,
 			fixDescription:   "Adding user to libvirt group",
 			fix:              fixUserPartOfLibvirtGroup,
+			fixPlan:          planUserPartOfLibvirtGroup,
 
 			labels: labels{Os: Linux},
 		},
@@ -70,6 +75,7 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 			check:            checkLibvirtServiceRunning,
 			fixDescription:   "Starting libvirt service",
 			fix:              fixLibvirtServiceRunning,
+			fixPlan:          planLibvirtServiceRunning,
 
 			labels: labels{Os: Linux},
 		},
@@ -88,12 +94,14 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 			check:            checkMachineDriverLibvirtInstalled,
 			fixDescription:   "Installing crc-driver-libvirt",
 			fix:              fixMachineDriverLibvirtInstalled,
+			fixPlan:          planMachineDriverLibvirtInstalled,
 
 			labels: labels{Os: Linux},
 		},
 		{
 			cleanupDescription: "Removing crc libvirt storage pool",
 			cleanup:            removeLibvirtStoragePool,
+			cleanupPlan:        planRemoveLibvirtStoragePool,
 			flags:              CleanUpOnly,
 
 			labels: labels{Os: Linux},
@@ -101,6 +109,7 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 		{
 			cleanupDescription: "Removing crc's virtual machine",
 			cleanup:            removeCrcVM,
+			cleanupPlan:        planRemoveCrcVM,
 			flags:              CleanUpOnly,
 
 			labels: labels{Os: Linux},
@@ -111,8 +120,10 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 			check:              checkDaemonSystemdService,
 			fixDescription:     "Setting up crc daemon systemd service",
 			fix:                fixDaemonSystemdService,
+			fixPlan:            planDaemonSystemdService,
 			cleanupDescription: "Removing crc daemon systemd service",
 			cleanup:            removeDaemonSystemdService,
+			cleanupPlan:        planRemoveDaemonSystemdService,
 			flags:              SetupOnly,
 
 			labels: labels{Os: Linux, SystemdUser: Supported},
@@ -123,8 +134,10 @@ func libvirtPreflightChecks(distro *linux.OsRelease) []Check {
 			check:              checkDaemonSystemdSockets,
 			fixDescription:     "Setting up crc daemon systemd socket units",
 			fix:                fixDaemonSystemdSockets,
+			fixPlan:            planDaemonSystemdSockets,
 			cleanupDescription: "Removing crc daemon systemd socket units",
 			cleanup:            removeDaemonSystemdSockets,
+			cleanupPlan:        planRemoveDaemonSystemdSockets,
 
 			labels: labels{Os: Linux, SystemdUser: Supported},
 		},
@@ -145,8 +158,10 @@ var libvirtNetworkPreflightChecks = []Check{
 		check:              checkLibvirtCrcNetworkAvailable,
 		fixDescription:     "Setting up libvirt 'crc' network",
 		fix:                fixLibvirtCrcNetworkAvailable,
+		fixPlan:            planLibvirtCrcNetworkAvailable,
 		cleanupDescription: "Removing 'crc' network from libvirt",
 		cleanup:            removeLibvirtCrcNetwork,
+		cleanupPlan:        planRemoveLibvirtCrcNetwork,
 
 		labels: labels{Os: Linux, NetworkMode: System},
 	},
@@ -156,6 +171,7 @@ var libvirtNetworkPreflightChecks = []Check{
 		check:            checkLibvirtCrcNetworkActive,
 		fixDescription:   "Starting libvirt 'crc' network",
 		fix:              fixLibvirtCrcNetworkActive,
+		fixPlan:          planLibvirtCrcNetworkActive,
 
 		labels: labels{Os: Linux, NetworkMode: System},
 	},
@@ -167,12 +183,23 @@ var vsockPreflightCheck = Check{
 	check:              checkVsock,
 	fixDescription:     "Setting up vsock support",
 	fix:                fixVsock,
+	fixPlan:            planVsock,
 	cleanupDescription: "Removing vsock configuration",
 	cleanup:            removeVsockCrcSettings,
+	cleanupPlan:        planRemoveVsockCrcSettings,
 
 	labels: labels{Os: Linux, NetworkMode: User},
 }
 
+var trustedCACleanupCheck = Check{
+	cleanupDescription: "Removing the cluster CAs from the host trust store",
+	cleanup:            adminhelper.RemoveFromTrustStore,
+	cleanupPlan:        planRemoveFromTrustStore,
+	flags:              CleanUpOnly,
+
+	labels: labels{Os: Linux},
+}
+
 var wsl2PreflightCheck = Check{
 	configKeySuffix:  "check-wsl2",
 	checkDescription: "Checking if running inside WSL2",
@@ -306,6 +333,19 @@ const (
 	Unsupported
 )
 
+func init() {
+	labelNames[Distro] = "distro"
+	labelNames[DNS] = "dns"
+	labelNames[SystemdUser] = "systemd-user"
+
+	labelValues[UbuntuLike] = "ubuntu-like"
+	labelValues[Other] = "other"
+	labelValues[Dnsmasq] = "dnsmasq"
+	labelValues[SystemdResolved] = "systemd-resolved"
+	labelValues[Supported] = "supported"
+	labelValues[Unsupported] = "unsupported"
+}
+
 func (filter preflightFilter) SetSystemdResolved(usingSystemdResolved bool) {
 	if usingSystemdResolved {
 		filter[DNS] = SystemdResolved
@@ -342,32 +382,33 @@ func getAllPreflightChecks() []Check {
 	filter.SetDistro(distro())
 	filter.SetSystemdUser(distro())
 
-	return filter.Apply(getChecks(distro(), constants.GetDefaultBundlePath(crcpreset.OpenShift), crcpreset.OpenShift, false))
+	return filter.Apply(getChecks(distro(), constants.GetDefaultBundlePath(crcpreset.OpenShift), crcpreset.OpenShift, nil))
 }
 
-func getPreflightChecks(_ bool, networkMode network.Mode, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getPreflightChecks(_ bool, networkMode network.Mode, bundlePath string, preset crcpreset.Preset, bundleMirrors []string) []Check {
 	usingSystemdResolved := checkSystemdResolvedIsRunning()
 
-	return getPreflightChecksForDistro(distro(), networkMode, usingSystemdResolved == nil, bundlePath, preset, enableBundleQuayFallback)
+	return getPreflightChecksForDistro(distro(), networkMode, usingSystemdResolved == nil, bundlePath, preset, bundleMirrors)
 }
 
-func getPreflightChecksForDistro(distro *linux.OsRelease, networkMode network.Mode, usingSystemdResolved bool, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getPreflightChecksForDistro(distro *linux.OsRelease, networkMode network.Mode, usingSystemdResolved bool, bundlePath string, preset crcpreset.Preset, bundleMirrors []string) []Check {
 	filter := newFilter()
 	filter.SetDistro(distro)
 	filter.SetSystemdUser(distro)
 	filter.SetNetworkMode(networkMode)
 	filter.SetSystemdResolved(usingSystemdResolved)
 
-	return filter.Apply(getChecks(distro, bundlePath, preset, enableBundleQuayFallback))
+	return filter.Apply(getChecks(distro, bundlePath, preset, bundleMirrors))
 }
 
-func getChecks(distro *linux.OsRelease, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getChecks(distro *linux.OsRelease, bundlePath string, preset crcpreset.Preset, bundleMirrors []string) []Check {
 	var checks []Check
 	checks = append(checks, nonWinPreflightChecks...)
 	checks = append(checks, wsl2PreflightCheck)
 	checks = append(checks, genericPreflightChecks(preset)...)
 	checks = append(checks, memoryCheck(preset))
 	checks = append(checks, genericCleanupChecks...)
+	checks = append(checks, trustedCACleanupCheck)
 	checks = append(checks, libvirtPreflightChecks(distro)...)
 	checks = append(checks, ubuntuPreflightChecks...)
 	checks = append(checks, nmPreflightChecks...)
@@ -375,7 +416,7 @@ func getChecks(distro *linux.OsRelease, bundlePath string, preset crcpreset.Pres
 	checks = append(checks, dnsmasqPreflightChecks...)
 	checks = append(checks, libvirtNetworkPreflightChecks...)
 	checks = append(checks, vsockPreflightCheck)
-	checks = append(checks, bundleCheck(bundlePath, preset, enableBundleQuayFallback))
+	checks = append(checks, bundleCheck(bundlePath, preset, bundleMirrors))
 
 	return checks
 }
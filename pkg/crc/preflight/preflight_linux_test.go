@@ -5,6 +5,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/crc-org/crc/v2/pkg/crc/adminhelper"
 	"github.com/crc-org/crc/v2/pkg/crc/cluster"
 	"github.com/crc-org/crc/v2/pkg/crc/config"
 	"github.com/crc-org/crc/v2/pkg/crc/constants"
@@ -76,6 +77,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -116,6 +118,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -155,6 +158,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -188,6 +192,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -228,6 +233,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -267,6 +273,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -300,6 +307,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -340,6 +348,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -379,6 +388,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -412,6 +422,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -453,6 +464,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -493,6 +505,7 @@ var checkListForDistros = []checkListForDistro{
 			{cleanup: removeHostsFileEntry},
 			{cleanup: removeCRCHostEntriesFromKnownHosts},
 			{cleanup: removeCrcManPages},
+			{cleanup: adminhelper.RemoveFromTrustStore},
 			{check: checkVirtualizationEnabled},
 			{check: checkKvmEnabled},
 			{check: checkLibvirtInstalled},
@@ -521,7 +534,7 @@ func assertFuncEqual(t *testing.T, func1 interface{}, func2 interface{}) {
 }
 
 func assertExpectedPreflights(t *testing.T, distro *crcos.OsRelease, networkMode network.Mode, systemdResolved bool) {
-	preflights := getPreflightChecksForDistro(distro, networkMode, systemdResolved, constants.GetDefaultBundlePath(preset.OpenShift), preset.OpenShift, false)
+	preflights := getPreflightChecksForDistro(distro, networkMode, systemdResolved, constants.GetDefaultBundlePath(preset.OpenShift), preset.OpenShift, nil)
 	var expected checkListForDistro
 	for _, expected = range checkListForDistros {
 		if expected.distro == distro && expected.networkMode == networkMode && expected.systemdResolved == systemdResolved {
@@ -565,3 +578,16 @@ func TestCountPreflights(t *testing.T) {
 	assertExpectedPreflights(t, &ubuntu, network.SystemNetworkingMode, false)
 	assertExpectedPreflights(t, &ubuntu, network.UserNetworkingMode, false)
 }
+
+func TestPreflightsDescribeTheirChanges(t *testing.T) {
+	for _, distro := range []*crcos.OsRelease{&fedora, &ubuntu} {
+		for _, check := range getChecks(distro, constants.GetDefaultBundlePath(preset.OpenShift), preset.OpenShift, nil) {
+			if check.fixAvailable() {
+				assert.NotNil(t, check.fixPlan, "missing fixPlan for '%s'", check.fixDescription)
+			}
+			if check.cleanup != nil {
+				assert.NotNil(t, check.cleanupPlan, "missing cleanupPlan for '%s'", check.cleanupDescription)
+			}
+		}
+	}
+}
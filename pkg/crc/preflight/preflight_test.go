@@ -49,6 +49,38 @@ func TestFixPreflightCheckOnly(t *testing.T) {
 	assert.False(t, calls.fixed)
 }
 
+func TestCheckPreflightResults(t *testing.T) {
+	check, calls := sampleCheck(errors.New("check failed"), nil)
+	cfg := config.New(config.NewEmptyInMemoryStorage(), config.NewEmptyInMemorySecretStorage())
+	doRegisterSettings(cfg, []Check{*check})
+
+	passing, _ := sampleCheck(nil, nil)
+	passing.configKeySuffix = "passing"
+	passing.labels = labels{Os: Linux, NetworkMode: User}
+	checks := []Check{*check, *passing}
+
+	assert.Equal(t, []CheckResult{
+		{ID: "sample", Description: "Sample check", Result: CheckFailed, Error: "check failed", FixAvailable: true},
+		{ID: "passing", Description: "Sample check", Labels: map[string]string{"os": "linux", "network-mode": "user"}, Result: CheckPassed, FixAvailable: true},
+	}, withoutDuration(doCheckPreflightChecks(cfg, checks)))
+	assert.True(t, calls.checked)
+	assert.False(t, calls.fixed)
+
+	_, err := cfg.Set("skip-sample", true)
+	assert.NoError(t, err)
+	check.flags = NoFix
+	assert.Equal(t, []CheckResult{
+		{ID: "sample", Description: "Sample check", Result: CheckSkipped, SkippedByConfig: true},
+	}, withoutDuration(doCheckPreflightChecks(cfg, []Check{*check})))
+}
+
+func withoutDuration(results []CheckResult) []CheckResult {
+	for i := range results {
+		results[i].DurationMs = 0
+	}
+	return results
+}
+
 func sampleCheck(checkErr, fixErr error) (*Check, *status) {
 	status := &status{}
 	return &Check{
@@ -20,8 +20,10 @@ var ubuntuPreflightChecks = []Check{
 		check:              checkAppArmorExceptionIsPresent(os.ReadFile),
 		fixDescription:     "Updating AppArmor configuration",
 		fix:                addAppArmorExceptionForQcowDisks(os.ReadFile, crcos.WriteToFileAsRoot),
+		fixPlan:            planAppArmorException,
 		cleanupDescription: "Cleaning up AppArmor configuration",
 		cleanup:            removeAppArmorExceptionForQcowDisks(os.ReadFile, crcos.WriteToFileAsRoot),
+		cleanupPlan:        planAppArmorException,
 
 		labels: labels{Os: Linux, Distro: UbuntuLike},
 	},
@@ -212,17 +212,17 @@ func checkVsock() error {
 // Passing 'UserNetworkingMode' to getPreflightChecks currently achieves this
 // as there are no system networking specific checks
 func getAllPreflightChecks() []Check {
-	return getPreflightChecks(true, network.UserNetworkingMode, constants.GetDefaultBundlePath(crcpreset.OpenShift), crcpreset.OpenShift, false)
+	return getPreflightChecks(true, network.UserNetworkingMode, constants.GetDefaultBundlePath(crcpreset.OpenShift), crcpreset.OpenShift, nil)
 }
 
-func getChecks(bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getChecks(bundlePath string, preset crcpreset.Preset, bundleMirrors []string) []Check {
 	checks := []Check{}
 	checks = append(checks, memoryCheck(preset))
 	checks = append(checks, hypervPreflightChecks...)
 	checks = append(checks, crcUsersGroupExistsCheck)
 	checks = append(checks, userPartOfCrcUsersAndHypervAdminsGroupCheck)
 	checks = append(checks, vsockChecks...)
-	checks = append(checks, bundleCheck(bundlePath, preset, enableBundleQuayFallback))
+	checks = append(checks, bundleCheck(bundlePath, preset, bundleMirrors))
 	checks = append(checks, genericCleanupChecks...)
 	checks = append(checks, cleanupCheckRemoveCrcVM)
 	checks = append(checks, daemonTaskChecks...)
@@ -231,9 +231,9 @@ func getChecks(bundlePath string, preset crcpreset.Preset, enableBundleQuayFallb
 	return checks
 }
 
-func getPreflightChecks(_ bool, networkMode network.Mode, bundlePath string, preset crcpreset.Preset, enableBundleQuayFallback bool) []Check {
+func getPreflightChecks(_ bool, networkMode network.Mode, bundlePath string, preset crcpreset.Preset, bundleMirrors []string) []Check {
 	filter := newFilter()
 	filter.SetNetworkMode(networkMode)
 
-	return filter.Apply(getChecks(bundlePath, preset, enableBundleQuayFallback))
+	return filter.Apply(getChecks(bundlePath, preset, bundleMirrors))
 }
@@ -0,0 +1,133 @@
+package provision
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the version of the provisioning file format
+const Version = "v1"
+
+const (
+	// HostScript is a script run on the host, with KUBECONFIG pointing to
+	// the kubeconfig of the instance
+	HostScript = "host"
+	// VMScript is a script copied to the instance virtual machine and run
+	// there as the 'core' user
+	VMScript = "vm"
+
+	defaultOperatorNamespace = "openshift-operators"
+	defaultCatalogSource     = "redhat-operators"
+	defaultCatalogNamespace  = "openshift-marketplace"
+	defaultOperatorTimeout   = 10 * time.Minute
+)
+
+// Config describes the provisioning done after the cluster is started:
+//
+//	version: v1
+//	manifests:
+//	- manifests/
+//	operators:
+//	- name: openshift-pipelines-operator-rh
+//	  channel: latest
+//	scripts:
+//	- path: scripts/sample-apps.sh
+//	  location: host
+//
+// The manifests are applied first, then the operators are subscribed, and
+// the scripts are run last. Relative paths are resolved against the
+// directory of the provisioning file.
+type Config struct {
+	Version   string     `yaml:"version"`
+	Manifests []string   `yaml:"manifests"`
+	Operators []Operator `yaml:"operators"`
+	Scripts   []Script   `yaml:"scripts"`
+}
+
+// Operator is an OLM operator subscription
+type Operator struct {
+	Name            string        `yaml:"name"`
+	Namespace       string        `yaml:"namespace"`
+	Channel         string        `yaml:"channel"`
+	Source          string        `yaml:"source"`
+	SourceNamespace string        `yaml:"sourceNamespace"`
+	Timeout         time.Duration `yaml:"timeout"`
+}
+
+type Script struct {
+	Path     string `yaml:"path"`
+	Location string `yaml:"location"`
+}
+
+// Load reads and validates the provisioning file stored in 'path'
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config, err := parse(data, filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid provisioning file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+func parse(data []byte, baseDir string) (*Config, error) {
+	var config Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, err
+	}
+	if config.Version != Version {
+		return nil, fmt.Errorf("unsupported version '%s', expected '%s'", config.Version, Version)
+	}
+	for i, manifest := range config.Manifests {
+		config.Manifests[i] = resolvePath(baseDir, manifest)
+	}
+	for i := range config.Operators {
+		operator := &config.Operators[i]
+		if operator.Name == "" {
+			return nil, fmt.Errorf("operator %d has no name", i+1)
+		}
+		if operator.Namespace == "" {
+			operator.Namespace = defaultOperatorNamespace
+		}
+		if operator.Source == "" {
+			operator.Source = defaultCatalogSource
+		}
+		if operator.SourceNamespace == "" {
+			operator.SourceNamespace = defaultCatalogNamespace
+		}
+		if operator.Timeout == 0 {
+			operator.Timeout = defaultOperatorTimeout
+		}
+	}
+	for i := range config.Scripts {
+		script := &config.Scripts[i]
+		if script.Path == "" {
+			return nil, fmt.Errorf("script %d has no path", i+1)
+		}
+		script.Path = resolvePath(baseDir, script.Path)
+		switch script.Location {
+		case "":
+			script.Location = HostScript
+		case HostScript, VMScript:
+		default:
+			return nil, fmt.Errorf("invalid location '%s' for script %s, must be '%s' or '%s'", script.Location, script.Path, HostScript, VMScript)
+		}
+	}
+	return &config, nil
+}
+
+func resolvePath(baseDir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(baseDir, path)
+}
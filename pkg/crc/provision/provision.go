@@ -0,0 +1,259 @@
+package provision
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	crcerrors "github.com/crc-org/crc/v2/pkg/crc/errors"
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/machine/progress"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	crcos "github.com/crc-org/crc/v2/pkg/os"
+)
+
+const vmProvisionDir = "/tmp/crc-provision"
+
+// VMRunner runs commands and creates files in the instance virtual machine
+type VMRunner interface {
+	crcos.CommandRunner
+	CopyData(data []byte, destFilename string, mode os.FileMode) error
+}
+
+// Provisioner applies a provisioning Config to a running cluster
+type Provisioner struct {
+	OCConfig       oc.Config
+	VMRunner       VMRunner
+	KubeconfigPath string
+
+	// runHostScript is overridden in tests
+	runHostScript func(ctx context.Context, path, kubeconfigPath string) (string, error)
+}
+
+func NewProvisioner(ocConfig oc.Config, vmRunner VMRunner, kubeconfigPath string) *Provisioner {
+	return &Provisioner{
+		OCConfig:       ocConfig,
+		VMRunner:       vmRunner,
+		KubeconfigPath: kubeconfigPath,
+		runHostScript:  runHostScript,
+	}
+}
+
+// Provision applies the manifests, subscribes the operators and runs the
+// scripts of 'config'. It stops at the first failure.
+func (p *Provisioner) Provision(ctx context.Context, config *Config) error {
+	manifests, err := manifestFiles(config.Manifests)
+	if err != nil {
+		return err
+	}
+	if _, _, err := p.VMRunner.Run("mkdir", "-p", vmProvisionDir); err != nil {
+		return err
+	}
+	defer func() {
+		if _, _, err := p.VMRunner.Run("rm", "-rf", vmProvisionDir); err != nil {
+			logging.Debugf("Failed to remove %s: %v", vmProvisionDir, err)
+		}
+	}()
+
+	// each manifest, operator and script is a step of the provisioning
+	// phase of the start progress
+	steps, total := 0, len(manifests)+len(config.Operators)+len(config.Scripts)
+	startStep := func(format string, args ...interface{}) {
+		steps++
+		message := fmt.Sprintf("Provisioning: %s [%d/%d]", fmt.Sprintf(format, args...), steps, total)
+		logging.Info(message)
+		progress.Update(ctx, float64(steps-1)/float64(total), message)
+	}
+
+	for i, manifest := range manifests {
+		startStep("applying manifest %s", manifest)
+		if err := p.applyManifest(i, manifest); err != nil {
+			return err
+		}
+	}
+	for _, operator := range config.Operators {
+		startStep("installing operator %s", operator.Name)
+		if err := p.installOperator(ctx, operator); err != nil {
+			return err
+		}
+	}
+	for _, script := range config.Scripts {
+		startStep("running %s script %s", script.Location, script.Path)
+		if err := p.runScript(ctx, script); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// manifestFiles returns the manifests to apply. The YAML and JSON files of
+// a directory are applied in lexical order.
+func manifestFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		var dirFiles []string
+		for _, entry := range entries {
+			switch filepath.Ext(entry.Name()) {
+			case ".yaml", ".yml", ".json":
+				if !entry.IsDir() {
+					dirFiles = append(dirFiles, filepath.Join(path, entry.Name()))
+				}
+			}
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+	return files, nil
+}
+
+func (p *Provisioner) applyManifest(index int, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	// manifests from different directories may have the same name
+	return p.apply(fmt.Sprintf("%03d-%s", index, filepath.Base(path)), data)
+}
+
+func (p *Provisioner) apply(name string, data []byte) error {
+	vmPath := fmt.Sprintf("%s/%s", vmProvisionDir, name)
+	if err := p.VMRunner.CopyData(data, vmPath, 0600); err != nil {
+		return err
+	}
+	if _, stderr, err := p.OCConfig.RunOcCommand("apply", "-f", vmPath); err != nil {
+		return fmt.Errorf("Failed to apply %s: %v: %s", name, err, stderr)
+	}
+	return nil
+}
+
+const operatorGroupTemplate = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: %[1]s
+---
+apiVersion: operators.coreos.com/v1
+kind: OperatorGroup
+metadata:
+  name: %[1]s
+  namespace: %[1]s
+spec:
+  targetNamespaces:
+  - %[1]s
+`
+
+const subscriptionTemplate = `apiVersion: operators.coreos.com/v1alpha1
+kind: Subscription
+metadata:
+  name: %s
+  namespace: %s
+spec:
+  name: %s
+  source: %s
+  sourceNamespace: %s
+  installPlanApproval: Automatic
+`
+
+func (p *Provisioner) installOperator(ctx context.Context, operator Operator) error {
+	if operator.Namespace != defaultOperatorNamespace {
+		// openshift-operators already has a global operator group
+		operatorGroup := fmt.Sprintf(operatorGroupTemplate, operator.Namespace)
+		if err := p.apply(fmt.Sprintf("operatorgroup-%s.yaml", operator.Namespace), []byte(operatorGroup)); err != nil {
+			return err
+		}
+	}
+	subscription := fmt.Sprintf(subscriptionTemplate, operator.Name, operator.Namespace,
+		operator.Name, operator.Source, operator.SourceNamespace)
+	if operator.Channel != "" {
+		// without a channel, the default channel of the package is used
+		subscription += fmt.Sprintf("  channel: %s\n", operator.Channel)
+	}
+	if err := p.apply(fmt.Sprintf("subscription-%s.yaml", operator.Name), []byte(subscription)); err != nil {
+		return err
+	}
+	return p.waitForCSV(ctx, operator)
+}
+
+// waitForCSV waits until the ClusterServiceVersion installed by the
+// subscription of 'operator' has succeeded
+func (p *Provisioner) waitForCSV(ctx context.Context, operator Operator) error {
+	var csv string
+	waitForInstalledCSV := func() error {
+		stdout, stderr, err := p.OCConfig.RunOcCommand("get", "subscription", operator.Name, "-n", operator.Namespace,
+			"-o", "jsonpath={.status.installedCSV}")
+		if err != nil {
+			return &crcerrors.RetriableError{Err: fmt.Errorf("%v: %s", err, stderr)}
+		}
+		csv = strings.TrimSpace(stdout)
+		if csv == "" {
+			return &crcerrors.RetriableError{Err: fmt.Errorf("operator %s is not installed yet", operator.Name)}
+		}
+		stdout, stderr, err = p.OCConfig.RunOcCommand("get", "csv", csv, "-n", operator.Namespace,
+			"-o", "jsonpath={.status.phase}")
+		if err != nil {
+			return &crcerrors.RetriableError{Err: fmt.Errorf("%v: %s", err, stderr)}
+		}
+		switch phase := strings.TrimSpace(stdout); phase {
+		case "Succeeded":
+			return nil
+		case "Failed":
+			return fmt.Errorf("installation of %s failed", csv)
+		default:
+			return &crcerrors.RetriableError{Err: fmt.Errorf("%s is in phase '%s'", csv, phase)}
+		}
+	}
+	if err := crcerrors.Retry(ctx, operator.Timeout, waitForInstalledCSV, 5*time.Second); err != nil {
+		return fmt.Errorf("Operator %s is not ready: %w", operator.Name, err)
+	}
+	logging.Infof("Provisioning: operator %s installed (%s)", operator.Name, csv)
+	return nil
+}
+
+func (p *Provisioner) runScript(ctx context.Context, script Script) error {
+	if script.Location == HostScript {
+		output, err := p.runHostScript(ctx, script.Path, p.KubeconfigPath)
+		logging.Debugf("Output of %s:\n%s", script.Path, output)
+		if err != nil {
+			return fmt.Errorf("Script %s failed: %v", script.Path, err)
+		}
+		return nil
+	}
+
+	data, err := os.ReadFile(script.Path)
+	if err != nil {
+		return err
+	}
+	vmPath := fmt.Sprintf("%s/%s", vmProvisionDir, filepath.Base(script.Path))
+	if err := p.VMRunner.CopyData(data, vmPath, 0700); err != nil {
+		return err
+	}
+	stdout, stderr, err := p.VMRunner.Run(vmPath)
+	logging.Debugf("Output of %s:\n%s", script.Path, stdout)
+	if err != nil {
+		return fmt.Errorf("Script %s failed: %v: %s", script.Path, err, stderr)
+	}
+	return nil
+}
+
+func runHostScript(ctx context.Context, path, kubeconfigPath string) (string, error) {
+	cmd := exec.CommandContext(ctx, path) // #nosec G204
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
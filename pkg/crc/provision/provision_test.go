@@ -0,0 +1,214 @@
+package provision
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/machine/progress"
+	"github.com/crc-org/crc/v2/pkg/crc/oc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRunner struct {
+	commands []string
+	files    map[string]string
+	outputs  map[string]string
+}
+
+func newFakeRunner() *fakeRunner {
+	return &fakeRunner{
+		files:   map[string]string{},
+		outputs: map[string]string{},
+	}
+}
+
+func (r *fakeRunner) Run(command string, args ...string) (string, string, error) {
+	cmdline := strings.Join(append([]string{command}, args...), " ")
+	r.commands = append(r.commands, cmdline)
+	for prefix, output := range r.outputs {
+		if strings.HasPrefix(cmdline, prefix) {
+			return output, "", nil
+		}
+	}
+	return "", "", nil
+}
+
+func (r *fakeRunner) RunPrivate(command string, args ...string) (string, string, error) {
+	return r.Run(command, args...)
+}
+
+func (r *fakeRunner) RunPrivileged(_ string, cmdAndArgs ...string) (string, string, error) {
+	return r.Run(cmdAndArgs[0], cmdAndArgs[1:]...)
+}
+
+func (r *fakeRunner) CopyData(data []byte, destFilename string, _ os.FileMode) error {
+	r.files[destFilename] = string(data)
+	return nil
+}
+
+func TestParse(t *testing.T) {
+	config, err := parse([]byte(`version: v1
+manifests:
+- manifests
+- /abs/app.yaml
+operators:
+- name: openshift-pipelines-operator-rh
+  channel: latest
+- name: my-operator
+  namespace: my-namespace
+  timeout: 2m
+scripts:
+- path: host.sh
+- path: vm.sh
+  location: vm
+`), "/base")
+	require.NoError(t, err)
+	assert.Equal(t, &Config{
+		Version:   Version,
+		Manifests: []string{filepath.Join("/base", "manifests"), "/abs/app.yaml"},
+		Operators: []Operator{
+			{
+				Name:            "openshift-pipelines-operator-rh",
+				Namespace:       "openshift-operators",
+				Channel:         "latest",
+				Source:          "redhat-operators",
+				SourceNamespace: "openshift-marketplace",
+				Timeout:         10 * time.Minute,
+			},
+			{
+				Name:            "my-operator",
+				Namespace:       "my-namespace",
+				Source:          "redhat-operators",
+				SourceNamespace: "openshift-marketplace",
+				Timeout:         2 * time.Minute,
+			},
+		},
+		Scripts: []Script{
+			{Path: filepath.Join("/base", "host.sh"), Location: HostScript},
+			{Path: filepath.Join("/base", "vm.sh"), Location: VMScript},
+		},
+	}, config)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := parse([]byte("manifests: []\n"), "/base")
+	assert.EqualError(t, err, "unsupported version '', expected 'v1'")
+	_, err = parse([]byte("version: v1\nunknown: true\n"), "/base")
+	assert.Error(t, err)
+	_, err = parse([]byte("version: v1\noperators:\n- channel: latest\n"), "/base")
+	assert.EqualError(t, err, "operator 1 has no name")
+	_, err = parse([]byte("version: v1\nscripts:\n- path: a.sh\n  location: nowhere\n"), "/base")
+	assert.EqualError(t, err, fmt.Sprintf("invalid location 'nowhere' for script %s, must be 'host' or 'vm'", filepath.Join("/base", "a.sh")))
+}
+
+func TestProvision(t *testing.T) {
+	dir := t.TempDir()
+	manifestsDir := filepath.Join(dir, "manifests")
+	require.NoError(t, os.Mkdir(manifestsDir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(manifestsDir, "b.yaml"), []byte("kind: B"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(manifestsDir, "a.json"), []byte("{\"kind\": \"A\"}"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(manifestsDir, "README.md"), []byte("ignored"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vm.sh"), []byte("#!/bin/sh\n"), 0600))
+
+	runner := newFakeRunner()
+	runner.outputs["timeout 30s oc get subscription my-operator"] = "my-operator.v1.0.0"
+	runner.outputs["timeout 30s oc get csv my-operator.v1.0.0"] = "Succeeded"
+	var hostScripts []string
+	provisioner := &Provisioner{
+		OCConfig: oc.Config{
+			Runner:           runner,
+			OcExecutablePath: "oc",
+			Timeout:          "30s",
+		},
+		VMRunner:       runner,
+		KubeconfigPath: "/kubeconfig",
+		runHostScript: func(_ context.Context, path, kubeconfigPath string) (string, error) {
+			hostScripts = append(hostScripts, fmt.Sprintf("%s %s", path, kubeconfigPath))
+			return "", nil
+		},
+	}
+
+	var events []progress.Event
+	ctx := progress.NewContext(context.Background(), func(event progress.Event) {
+		events = append(events, event)
+	})
+	progress.StartPhase(ctx, progress.PhaseProvisioning, "Provisioning the cluster")
+	err := provisioner.Provision(ctx, &Config{
+		Version:   Version,
+		Manifests: []string{manifestsDir},
+		Operators: []Operator{
+			{
+				Name:            "my-operator",
+				Namespace:       "my-namespace",
+				Source:          "redhat-operators",
+				SourceNamespace: "openshift-marketplace",
+				Timeout:         time.Minute,
+			},
+		},
+		Scripts: []Script{
+			{Path: filepath.Join(dir, "host.sh"), Location: HostScript},
+			{Path: filepath.Join(dir, "vm.sh"), Location: VMScript},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"mkdir -p /tmp/crc-provision",
+		"timeout 30s oc apply -f /tmp/crc-provision/000-a.json",
+		"timeout 30s oc apply -f /tmp/crc-provision/001-b.yaml",
+		"timeout 30s oc apply -f /tmp/crc-provision/operatorgroup-my-namespace.yaml",
+		"timeout 30s oc apply -f /tmp/crc-provision/subscription-my-operator.yaml",
+		"timeout 30s oc get subscription my-operator -n my-namespace -o jsonpath={.status.installedCSV}",
+		"timeout 30s oc get csv my-operator.v1.0.0 -n my-namespace -o jsonpath={.status.phase}",
+		"/tmp/crc-provision/vm.sh",
+		"rm -rf /tmp/crc-provision",
+	}, runner.commands)
+	assert.Equal(t, "kind: B", runner.files["/tmp/crc-provision/001-b.yaml"])
+	assert.Contains(t, runner.files["/tmp/crc-provision/subscription-my-operator.yaml"], "  sourceNamespace: openshift-marketplace\n")
+	assert.NotContains(t, runner.files["/tmp/crc-provision/subscription-my-operator.yaml"], "channel")
+	assert.Equal(t, []string{filepath.Join(dir, "host.sh") + " /kubeconfig"}, hostScripts)
+
+	var steps []string
+	for _, event := range events {
+		if event.Phase == progress.PhaseProvisioning && event.Status == progress.StatusRunning {
+			steps = append(steps, event.Message)
+		}
+	}
+	assert.Equal(t, []string{
+		fmt.Sprintf("Provisioning: applying manifest %s [1/5]", filepath.Join(manifestsDir, "a.json")),
+		fmt.Sprintf("Provisioning: applying manifest %s [2/5]", filepath.Join(manifestsDir, "b.yaml")),
+		"Provisioning: installing operator my-operator [3/5]",
+		fmt.Sprintf("Provisioning: running host script %s [4/5]", filepath.Join(dir, "host.sh")),
+		fmt.Sprintf("Provisioning: running vm script %s [5/5]", filepath.Join(dir, "vm.sh")),
+	}, steps)
+}
+
+func TestProvisionFailedOperator(t *testing.T) {
+	runner := newFakeRunner()
+	runner.outputs["timeout 30s oc get subscription"] = "my-operator.v1.0.0"
+	runner.outputs["timeout 30s oc get csv"] = "Failed"
+	provisioner := NewProvisioner(oc.Config{
+		Runner:           runner,
+		OcExecutablePath: "oc",
+		Timeout:          "30s",
+	}, runner, "/kubeconfig")
+
+	err := provisioner.Provision(context.Background(), &Config{
+		Version: Version,
+		Operators: []Operator{
+			{
+				Name:      "my-operator",
+				Namespace: defaultOperatorNamespace,
+				Timeout:   time.Minute,
+			},
+		},
+	})
+	assert.EqualError(t, err, "Operator my-operator is not ready: installation of my-operator.v1.0.0 failed")
+	assert.Equal(t, "rm -rf /tmp/crc-provision", runner.commands[len(runner.commands)-1])
+}
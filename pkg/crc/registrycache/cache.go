@@ -0,0 +1,334 @@
+// Package registrycache implements the pull-through registry cache run by
+// the daemon. When it is enabled, the cluster pulls the images of the cached
+// registries through it. The layers and manifests are stored on the host by
+// digest, they are kept when the instances are deleted.
+package registrycache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/opencontainers/go-digest"
+	"go.podman.io/common/pkg/strongunits"
+	"go.podman.io/image/v5/docker/reference"
+)
+
+// ErrDisabled is returned when the cache is used while the 'registry-cache'
+// setting is false
+var ErrDisabled = errors.New("the registry cache is disabled")
+
+// Options configures the cache, the functions are called for each request
+// so that the configuration changes are applied without restarting the
+// daemon
+type Options struct {
+	// Dir is the directory storing the cached content
+	Dir string
+	// Enabled returns false when the cache must not serve any request
+	Enabled func() bool
+	// Registries returns the registries whose images are cached
+	Registries func() []string
+	// MaxSize returns the size limit of the cache, the least recently
+	// used content is removed when it is exceeded
+	MaxSize func() strongunits.B
+	// PullSecret returns the credentials of the upstream registries, in
+	// the format of the docker config.json file
+	PullSecret func() (string, error)
+}
+
+// Cache is a pull-through cache of the registries returned by
+// Options.Registries, it implements the pull part of the registry HTTP API
+type Cache struct {
+	options  Options
+	upstream *upstream
+
+	// lock serializes the additions to the cache and the removals, the
+	// reads do not need it since the files are renamed into place
+	lock   sync.Mutex
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// Stats describes the content of the cache. Hits and Misses count the
+// layers and manifests requested by digest since the daemon started.
+type Stats struct {
+	Blobs     int
+	Manifests int
+	Size      strongunits.B
+	MaxSize   strongunits.B
+	Hits      int64
+	Misses    int64
+}
+
+// PruneResult describes the content removed by Prune
+type PruneResult struct {
+	Removed int
+	Freed   strongunits.B
+}
+
+type entry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+func New(options Options) *Cache {
+	return &Cache{
+		options:  options,
+		upstream: newUpstream(options.PullSecret),
+	}
+}
+
+// Enabled returns true when the cache serves the requests of the cluster
+func (c *Cache) Enabled() bool {
+	return c.options.Enabled()
+}
+
+// Registries returns the registries whose images are cached
+func (c *Cache) Registries() []string {
+	return c.options.Registries()
+}
+
+func (c *Cache) Stats() (*Stats, error) {
+	stats := &Stats{
+		MaxSize: c.options.MaxSize(),
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+	}
+	blobs, err := c.entries(c.blobsDir())
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := c.entries(c.manifestsDir())
+	if err != nil {
+		return nil, err
+	}
+	stats.Blobs = len(blobs)
+	stats.Manifests = len(manifests)
+	for _, entry := range append(blobs, manifests...) {
+		stats.Size += strongunits.B(entry.size)
+	}
+	return stats, nil
+}
+
+// Prune removes the layers and manifests which were not used for
+// 'unusedFor', all of them when it is 0
+func (c *Cache) Prune(unusedFor time.Duration) (*PruneResult, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entries, err := c.allEntries()
+	if err != nil {
+		return nil, err
+	}
+	result := &PruneResult{}
+	limit := time.Now().Add(-unusedFor)
+	for _, entry := range entries {
+		if unusedFor != 0 && entry.modTime.After(limit) {
+			continue
+		}
+		if err := os.Remove(entry.path); err != nil {
+			return result, err
+		}
+		result.Removed++
+		result.Freed += strongunits.B(entry.size)
+	}
+	return result, c.removeDanglingTags()
+}
+
+// evict removes the least recently used layers and manifests until the
+// size of the cache is below its limit. The lock must be held.
+func (c *Cache) evict() error {
+	entries, err := c.allEntries()
+	if err != nil {
+		return err
+	}
+	var size int64
+	for _, entry := range entries {
+		size += entry.size
+	}
+	maxSize := int64(c.options.MaxSize())
+	if size <= maxSize {
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+	for _, entry := range entries {
+		if size <= maxSize {
+			break
+		}
+		logging.Debugf("Removing %s from the registry cache", filepath.Base(entry.path))
+		if err := os.Remove(entry.path); err != nil {
+			return err
+		}
+		size -= entry.size
+	}
+	return c.removeDanglingTags()
+}
+
+func (c *Cache) allEntries() ([]entry, error) {
+	blobs, err := c.entries(c.blobsDir())
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := c.entries(c.manifestsDir())
+	if err != nil {
+		return nil, err
+	}
+	return append(blobs, manifests...), nil
+}
+
+func (c *Cache) entries(dir string) ([]entry, error) {
+	var entries []entry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	return entries, err
+}
+
+// removeDanglingTags removes the tags whose manifest is not in the cache
+// anymore. The lock must be held.
+func (c *Cache) removeDanglingTags() error {
+	return filepath.WalkDir(c.tagsDir(), func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		dgst, err := readTag(path)
+		if err != nil {
+			return os.Remove(path)
+		}
+		if _, err := os.Stat(c.manifestPath(dgst)); errors.Is(err, fs.ErrNotExist) {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
+
+// store moves the downloaded file 'tmpPath' to 'path' and removes the least
+// recently used content when the cache is too large
+func (c *Cache) store(tmpPath, path string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	return c.evict()
+}
+
+func (c *Cache) storeManifest(data []byte, dgst digest.Digest) error {
+	file, err := c.createTemp()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return c.store(file.Name(), c.manifestPath(dgst))
+}
+
+// storeTag records that the tag 'tag' of 'name' points to the manifest
+// 'dgst', it is used when the upstream registry cannot be reached
+func (c *Cache) storeTag(name reference.Named, tag string, dgst digest.Digest) error {
+	path := c.tagPath(name, tag)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(dgst.String()), 0600)
+}
+
+func (c *Cache) taggedManifest(name reference.Named, tag string) (digest.Digest, error) {
+	return readTag(c.tagPath(name, tag))
+}
+
+func readTag(path string) (digest.Digest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return digest.Parse(strings.TrimSpace(string(data)))
+}
+
+func (c *Cache) createTemp() (*os.File, error) {
+	dir := filepath.Join(c.options.Dir, "tmp")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(dir, "download-*")
+}
+
+func (c *Cache) blobsDir() string {
+	return filepath.Join(c.options.Dir, "blobs")
+}
+
+func (c *Cache) manifestsDir() string {
+	return filepath.Join(c.options.Dir, "manifests")
+}
+
+func (c *Cache) tagsDir() string {
+	return filepath.Join(c.options.Dir, "tags")
+}
+
+func (c *Cache) blobPath(dgst digest.Digest) string {
+	return filepath.Join(c.blobsDir(), dgst.Algorithm().String(), dgst.Encoded())
+}
+
+func (c *Cache) manifestPath(dgst digest.Digest) string {
+	return filepath.Join(c.manifestsDir(), dgst.Algorithm().String(), dgst.Encoded())
+}
+
+func (c *Cache) tagPath(name reference.Named, tag string) string {
+	// ':' cannot be used in the file names on Windows
+	registry := strings.ReplaceAll(reference.Domain(name), ":", "_")
+	return filepath.Join(c.tagsDir(), registry, filepath.FromSlash(reference.Path(name)), tag)
+}
+
+// ParseRegistries parses a comma-separated list of registry hostnames, with
+// an optional port, as stored in the 'registry-cache-registries' setting
+func ParseRegistries(value string) ([]string, error) {
+	var registries []string
+	for _, registry := range strings.Split(value, ",") {
+		if registry = strings.TrimSpace(registry); registry == "" {
+			continue
+		}
+		name, err := reference.ParseNamed(registry + "/image")
+		if err != nil || reference.Domain(name) != registry {
+			return nil, fmt.Errorf("'%s' is not a registry hostname", registry)
+		}
+		if !slices.Contains(registries, registry) {
+			registries = append(registries, registry)
+		}
+	}
+	return registries, nil
+}
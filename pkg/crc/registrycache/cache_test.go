@@ -0,0 +1,207 @@
+package registrycache
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.podman.io/common/pkg/strongunits"
+)
+
+const testManifest = `{"schemaVersion":2,"mediaType":"application/vnd.oci.image.manifest.v1+json","config":{},"layers":[]}`
+
+type fakeRegistry struct {
+	*httptest.Server
+	blobs     map[digest.Digest]string
+	requests  atomic.Int64
+	available atomic.Bool
+}
+
+// newFakeRegistry serves the testManifest manifest with the 'latest' tag and
+// 'blobs', it requires a token obtained with the user:password credentials
+func newFakeRegistry(t *testing.T, blobs ...string) *fakeRegistry {
+	registry := &fakeRegistry{blobs: map[digest.Digest]string{}}
+	for _, blob := range blobs {
+		registry.blobs[digest.FromString(blob)] = blob
+	}
+	registry.available.Store(true)
+	registry.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !registry.available.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/token" {
+			if username, password, _ := r.BasicAuth(); username != "user" || password != "password" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "repository:crcont/app:pull", r.URL.Query().Get("scope"))
+			_, _ = w.Write([]byte(`{"token":"secret"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, registry.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		registry.requests.Add(1)
+		switch {
+		case r.URL.Path == "/v2/crcont/app/manifests/latest":
+			w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+			_, _ = w.Write([]byte(testManifest))
+		case strings.HasPrefix(r.URL.Path, "/v2/crcont/app/blobs/"):
+			blob, ok := registry.blobs[digest.Digest(strings.TrimPrefix(r.URL.Path, "/v2/crcont/app/blobs/"))]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(blob))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(registry.Close)
+	return registry
+}
+
+func (registry *fakeRegistry) host() string {
+	return strings.TrimPrefix(registry.URL, "http://")
+}
+
+func newTestCache(t *testing.T, registry *fakeRegistry, maxSize strongunits.B) (*Cache, *httptest.Server) {
+	pullSecret := fmt.Sprintf(`{"auths":{"%s":{"auth":"%s"}}}`, registry.host(), base64.StdEncoding.EncodeToString([]byte("user:password")))
+	cache := New(Options{
+		Dir:        t.TempDir(),
+		Enabled:    func() bool { return true },
+		Registries: func() []string { return []string{registry.host()} },
+		MaxSize:    func() strongunits.B { return maxSize },
+		PullSecret: func() (string, error) { return pullSecret, nil },
+	})
+	cache.upstream.scheme = "http"
+	server := httptest.NewServer(cache)
+	t.Cleanup(server.Close)
+	return cache, server
+}
+
+func get(t *testing.T, url string) (int, string) {
+	resp, err := http.Get(url) // #nosec G107
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+// waitForBlob waits until 'blob' is stored, which happens after it is sent
+func waitForBlob(t *testing.T, cache *Cache, blob string) {
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(cache.blobPath(digest.FromString(blob)))
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCacheServesBlobsFromDisk(t *testing.T) {
+	registry := newFakeRegistry(t, "layer")
+	cache, server := newTestCache(t, registry, strongunits.MiB(1).ToBytes())
+	blobURL := fmt.Sprintf("%s/v2/%s/crcont/app/blobs/%s", server.URL, registry.host(), digest.FromString("layer"))
+
+	status, _ := get(t, server.URL+"/v2/")
+	assert.Equal(t, http.StatusOK, status)
+
+	status, body := get(t, blobURL)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "layer", body)
+	waitForBlob(t, cache, "layer")
+	status, body = get(t, blobURL)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "layer", body)
+	assert.Equal(t, int64(1), registry.requests.Load())
+
+	stats, err := cache.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, &Stats{
+		Blobs:   1,
+		Size:    strongunits.B(len("layer")),
+		MaxSize: strongunits.MiB(1).ToBytes(),
+		Hits:    1,
+		Misses:  1,
+	}, stats)
+}
+
+func TestCacheServesTaggedManifestWhenRegistryIsUnavailable(t *testing.T) {
+	registry := newFakeRegistry(t)
+	_, server := newTestCache(t, registry, strongunits.MiB(1).ToBytes())
+	manifestURL := fmt.Sprintf("%s/v2/%s/crcont/app/manifests/latest", server.URL, registry.host())
+
+	status, body := get(t, manifestURL)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, testManifest, body)
+
+	registry.available.Store(false)
+	resp, err := http.Get(manifestURL) // #nosec G107
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/vnd.oci.image.manifest.v1+json", resp.Header.Get("Content-Type"))
+	assert.Equal(t, digest.FromString(testManifest).String(), resp.Header.Get("Docker-Content-Digest"))
+}
+
+func TestCacheRejectsOtherRegistries(t *testing.T) {
+	registry := newFakeRegistry(t, "layer")
+	_, server := newTestCache(t, registry, strongunits.MiB(1).ToBytes())
+
+	status, body := get(t, fmt.Sprintf("%s/v2/example.com/crcont/app/blobs/%s", server.URL, digest.FromString("layer")))
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body, "example.com is not cached")
+	assert.Equal(t, int64(0), registry.requests.Load())
+}
+
+func TestCacheRemovesLeastRecentlyUsedBlobs(t *testing.T) {
+	registry := newFakeRegistry(t, "first layer", "second layer")
+	cache, server := newTestCache(t, registry, strongunits.B(len("first layer")+len("second layer")-1))
+
+	for _, blob := range []string{"first layer", "second layer"} {
+		status, _ := get(t, fmt.Sprintf("%s/v2/%s/crcont/app/blobs/%s", server.URL, registry.host(), digest.FromString(blob)))
+		assert.Equal(t, http.StatusOK, status)
+		waitForBlob(t, cache, blob)
+		// the eviction uses the modification times of the files
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.NoFileExists(t, cache.blobPath(digest.FromString("first layer")))
+	assert.FileExists(t, cache.blobPath(digest.FromString("second layer")))
+}
+
+func TestPrune(t *testing.T) {
+	registry := newFakeRegistry(t, "layer")
+	cache, server := newTestCache(t, registry, strongunits.MiB(1).ToBytes())
+	status, _ := get(t, fmt.Sprintf("%s/v2/%s/crcont/app/manifests/latest", server.URL, registry.host()))
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = get(t, fmt.Sprintf("%s/v2/%s/crcont/app/blobs/%s", server.URL, registry.host(), digest.FromString("layer")))
+	assert.Equal(t, http.StatusOK, status)
+	waitForBlob(t, cache, "layer")
+
+	result, err := cache.Prune(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, &PruneResult{}, result)
+
+	result, err = cache.Prune(0)
+	require.NoError(t, err)
+	assert.Equal(t, &PruneResult{Removed: 2, Freed: strongunits.B(len("layer") + len(testManifest))}, result)
+	stats, err := cache.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Blobs+stats.Manifests)
+	tags, err := cache.entries(cache.tagsDir())
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+}
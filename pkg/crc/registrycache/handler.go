@@ -0,0 +1,285 @@
+package registrycache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/opencontainers/go-digest"
+	"go.podman.io/image/v5/docker/reference"
+	"go.podman.io/image/v5/manifest"
+)
+
+// maxManifestSize is the size limit of the manifests fetched from the
+// upstream registries
+const maxManifestSize = 4 * 1024 * 1024
+
+var tagRegexp = regexp.MustCompile(`^[\w][\w.-]{0,127}$`)
+
+type registryError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ServeHTTP implements the pull part of the registry HTTP API: the
+// manifests and blobs of the cached registries are served from the cache
+// or fetched from the upstream registry. The repository names start with
+// the upstream registry, like /v2/quay.io/crcont/openshift-bundle/...
+func (c *Cache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Docker-Distribution-API-Version", "registry/2.0")
+	if !c.Enabled() {
+		writeError(w, http.StatusServiceUnavailable, "UNAVAILABLE", ErrDisabled.Error())
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		writeError(w, http.StatusMethodNotAllowed, "UNSUPPORTED", "the registry cache only supports pulls")
+		return
+	}
+	if r.URL.Path == "/v2" || r.URL.Path == "/v2/" {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+		return
+	}
+	repository, kind, ref, ok := parsePath(r.URL.Path)
+	if !ok {
+		writeError(w, http.StatusNotFound, "NAME_UNKNOWN", fmt.Sprintf("unknown path %s", r.URL.Path))
+		return
+	}
+	name, err := reference.ParseNamed(repository)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "NAME_INVALID", err.Error())
+		return
+	}
+	if !slices.Contains(c.Registries(), reference.Domain(name)) {
+		writeError(w, http.StatusNotFound, "NAME_UNKNOWN", fmt.Sprintf("%s is not cached", reference.Domain(name)))
+		return
+	}
+	if kind == "blobs" {
+		c.serveBlob(w, r, name, ref)
+		return
+	}
+	c.serveManifest(w, r, name, ref)
+}
+
+// parsePath splits /v2/<name>/manifests/<reference> and
+// /v2/<name>/blobs/<digest>
+func parsePath(path string) (string, string, string, bool) {
+	path, ok := strings.CutPrefix(path, "/v2/")
+	if !ok {
+		return "", "", "", false
+	}
+	for _, kind := range []string{"manifests", "blobs"} {
+		index := strings.LastIndex(path, "/"+kind+"/")
+		if index <= 0 {
+			continue
+		}
+		ref := path[index+len(kind)+2:]
+		if ref == "" || strings.Contains(ref, "/") {
+			return "", "", "", false
+		}
+		return path[:index], kind, ref, true
+	}
+	return "", "", "", false
+}
+
+func (c *Cache) serveBlob(w http.ResponseWriter, r *http.Request, name reference.Named, ref string) {
+	dgst, err := digest.Parse(ref)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "DIGEST_INVALID", err.Error())
+		return
+	}
+	if c.serveFile(w, r, c.blobPath(dgst), "application/octet-stream", dgst) {
+		c.hits.Add(1)
+		return
+	}
+	c.misses.Add(1)
+
+	resp, err := c.upstream.get(r.Context(), r.Method, name, "blobs", ref, nil)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "UNAVAILABLE", err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || r.Method == http.MethodHead {
+		copyResponse(w, resp)
+		return
+	}
+
+	file, err := c.createTemp()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "UNKNOWN", err.Error())
+		return
+	}
+	defer os.Remove(file.Name())
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Docker-Content-Digest", dgst.String())
+	if resp.ContentLength >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(resp.ContentLength, 10))
+	}
+	w.WriteHeader(http.StatusOK)
+
+	// the blob is written to the cache while it is sent to the cluster
+	verifier := dgst.Verifier()
+	_, err = io.Copy(w, io.TeeReader(resp.Body, io.MultiWriter(file, verifier)))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		logging.Debugf("Cannot download %s from %s: %v", dgst, reference.Domain(name), err)
+		return
+	}
+	if !verifier.Verified() {
+		logging.Warnf("The content of %s from %s does not match its digest", dgst, reference.Domain(name))
+		return
+	}
+	if err := c.store(file.Name(), c.blobPath(dgst)); err != nil {
+		logging.Warnf("Cannot add %s to the registry cache: %v", dgst, err)
+	}
+}
+
+func (c *Cache) serveManifest(w http.ResponseWriter, r *http.Request, name reference.Named, ref string) {
+	requested, err := digest.Parse(ref)
+	isTag := err != nil
+	if isTag && !tagRegexp.MatchString(ref) {
+		writeError(w, http.StatusBadRequest, "TAG_INVALID", fmt.Sprintf("invalid tag %s", ref))
+		return
+	}
+	if !isTag {
+		if data, err := c.readManifest(requested); err == nil {
+			c.hits.Add(1)
+			writeManifest(w, r, data, requested)
+			return
+		}
+		c.misses.Add(1)
+	}
+
+	// the tags can be moved, they are always looked up in the upstream
+	// registry, unless it cannot be reached
+	resp, err := c.upstream.get(r.Context(), http.MethodGet, name, "manifests", ref, r.Header.Values("Accept"))
+	if err != nil || resp.StatusCode >= http.StatusInternalServerError {
+		if isTag {
+			if dgst, tagErr := c.taggedManifest(name, ref); tagErr == nil {
+				if data, readErr := c.readManifest(dgst); readErr == nil {
+					logging.Debugf("Using the cached manifest of %s:%s", name, ref)
+					if resp != nil {
+						resp.Body.Close()
+					}
+					writeManifest(w, r, data, dgst)
+					return
+				}
+			}
+		}
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "UNAVAILABLE", err.Error())
+			return
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		copyResponse(w, resp)
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "UNAVAILABLE", err.Error())
+		return
+	}
+	if len(data) > maxManifestSize {
+		writeError(w, http.StatusBadGateway, "MANIFEST_INVALID", "the manifest is too large")
+		return
+	}
+	dgst := digest.FromBytes(data)
+	if !isTag {
+		if dgst = requested.Algorithm().FromBytes(data); dgst != requested {
+			writeError(w, http.StatusBadGateway, "MANIFEST_INVALID", fmt.Sprintf("the content of %s does not match its digest", requested))
+			return
+		}
+	}
+	if err := c.storeManifest(data, dgst); err != nil {
+		logging.Warnf("Cannot add %s to the registry cache: %v", dgst, err)
+	} else if isTag {
+		if err := c.storeTag(name, ref, dgst); err != nil {
+			logging.Warnf("Cannot add %s:%s to the registry cache: %v", name, ref, err)
+		}
+	}
+	writeManifest(w, r, data, dgst)
+}
+
+func (c *Cache) readManifest(dgst digest.Digest) ([]byte, error) {
+	path := c.manifestPath(dgst)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	touch(path)
+	return data, nil
+}
+
+// serveFile sends the cached file 'path' and returns false when it is not
+// in the cache
+func (c *Cache) serveFile(w http.ResponseWriter, r *http.Request, path, contentType string, dgst digest.Digest) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	touch(path)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Docker-Content-Digest", dgst.String())
+	http.ServeContent(w, r, "", info.ModTime(), file)
+	return true
+}
+
+// touch updates the modification time of the cached file 'path', it is
+// used to remove the least recently used content first
+func touch(path string) {
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		logging.Debugf("Cannot update the modification time of %s: %v", path, err)
+	}
+}
+
+func writeManifest(w http.ResponseWriter, r *http.Request, data []byte, dgst digest.Digest) {
+	w.Header().Set("Content-Type", manifest.GuessMIMEType(data))
+	w.Header().Set("Docker-Content-Digest", dgst.String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+// copyResponse sends the response of the upstream registry, used for its
+// errors
+func copyResponse(w http.ResponseWriter, resp *http.Response) {
+	for _, header := range []string{"Content-Type", "Content-Length", "Docker-Content-Digest"} {
+		if value := resp.Header.Get(header); value != "" {
+			w.Header().Set(header, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil && !errors.Is(err, io.EOF) {
+		logging.Debugf("Cannot send the response of the upstream registry: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string][]registryError{
+		"errors": {{Code: code, Message: message}},
+	})
+}
@@ -0,0 +1,200 @@
+package registrycache
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"go.podman.io/image/v5/docker/reference"
+)
+
+var challengeParamRegexp = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// upstream fetches the content of the registries, it authenticates with the
+// credentials of the pull secret
+type upstream struct {
+	client     *http.Client
+	scheme     string
+	pullSecret func() (string, error)
+
+	// authorizations caches the Authorization header of each registry and
+	// repository
+	lock           sync.Mutex
+	authorizations map[string]string
+}
+
+func newUpstream(pullSecret func() (string, error)) *upstream {
+	return &upstream{
+		client:         &http.Client{},
+		scheme:         "https",
+		pullSecret:     pullSecret,
+		authorizations: map[string]string{},
+	}
+}
+
+// get sends the request to the registry of 'name'. When it requires
+// authentication, a token is requested and the request is sent again.
+func (u *upstream) get(ctx context.Context, method string, name reference.Named, kind, ref string, accept []string) (*http.Response, error) {
+	registry := reference.Domain(name)
+	requestURL := fmt.Sprintf("%s/v2/%s/%s/%s", u.registryURL(registry), reference.Path(name), kind, ref)
+	scope := fmt.Sprintf("repository:%s:pull", reference.Path(name))
+	key := registry + " " + scope
+
+	u.lock.Lock()
+	authorization := u.authorizations[key]
+	u.lock.Unlock()
+	resp, err := u.do(ctx, method, requestURL, accept, authorization)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := resp.Header.Get("WWW-Authenticate")
+	resp.Body.Close()
+
+	authorization, err = u.authorize(ctx, registry, challenge, scope)
+	if err != nil {
+		return nil, err
+	}
+	u.lock.Lock()
+	u.authorizations[key] = authorization
+	u.lock.Unlock()
+	return u.do(ctx, method, requestURL, accept, authorization)
+}
+
+func (u *upstream) do(ctx context.Context, method, requestURL string, accept []string, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range accept {
+		req.Header.Add("Accept", value)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	return u.client.Do(req)
+}
+
+// authorize returns the Authorization header answering the
+// WWW-Authenticate 'challenge' of the registry
+func (u *upstream) authorize(ctx context.Context, registry, challenge, scope string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	username, password, hasCredentials := u.credentials(registry)
+	switch scheme {
+	case "basic":
+		if !hasCredentials {
+			return "", fmt.Errorf("no credentials for %s in the pull secret", registry)
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	case "bearer":
+		tokenURL, err := url.Parse(params["realm"])
+		if err != nil || params["realm"] == "" {
+			return "", fmt.Errorf("invalid authentication realm of %s: '%s'", registry, params["realm"])
+		}
+		query := tokenURL.Query()
+		if service, ok := params["service"]; ok {
+			query.Set("service", service)
+		}
+		if params["scope"] != "" {
+			scope = params["scope"]
+		}
+		query.Set("scope", scope)
+		tokenURL.RawQuery = query.Encode()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenURL.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if hasCredentials {
+			req.SetBasicAuth(username, password)
+		}
+		resp, err := u.client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("cannot get a token for %s: %s", registry, resp.Status)
+		}
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			return "", err
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		return "Bearer " + token.Token, nil
+	default:
+		return "", fmt.Errorf("unsupported authentication scheme of %s: '%s'", registry, challenge)
+	}
+}
+
+// credentials returns the username and password of 'registry' in the pull
+// secret
+func (u *upstream) credentials(registry string) (string, string, bool) {
+	if u.pullSecret == nil {
+		return "", "", false
+	}
+	pullSecret, err := u.pullSecret()
+	if err != nil {
+		return "", "", false
+	}
+	return pullSecretCredentials(pullSecret, registry)
+}
+
+func pullSecretCredentials(pullSecret, registry string) (string, string, bool) {
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal([]byte(pullSecret), &config); err != nil {
+		return "", "", false
+	}
+	hosts := []string{registry}
+	if registry == "docker.io" {
+		hosts = append(hosts, "index.docker.io", "registry-1.docker.io")
+	}
+	for key, auth := range config.Auths {
+		// the keys can be URLs, like https://index.docker.io/v1/
+		host := strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+		host, _, _ = strings.Cut(host, "/")
+		if !slices.Contains(hosts, host) {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			continue
+		}
+		if username, password, ok := strings.Cut(string(decoded), ":"); ok {
+			return username, strings.TrimSpace(password), true
+		}
+	}
+	return "", "", false
+}
+
+func (u *upstream) registryURL(registry string) string {
+	if registry == "docker.io" {
+		registry = "registry-1.docker.io"
+	}
+	return fmt.Sprintf("%s://%s", u.scheme, registry)
+}
+
+// parseChallenge parses the WWW-Authenticate header, like
+// Bearer realm="https://quay.io/v2/auth",service="quay.io"
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for _, match := range challengeParamRegexp.FindAllStringSubmatch(rest, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	return strings.ToLower(scheme), params
+}
@@ -36,3 +36,22 @@ func TestGetApplicableHostnames(t *testing.T) {
 		"default-route-openshift-image-registry.apps.crc.testing",
 	}, hostnames)
 }
+
+func TestCreateDNSConfigFileWithCustomAppsDomain(t *testing.T) {
+	values := dnsmasqConfFileValues{
+		BaseDomain:  "testing",
+		ClusterName: "crc",
+		Hostname:    "crc",
+		IP:          "192.168.130.11",
+		AppsDomain:  "apps-crc.testing",
+		InternalIP:  "192.168.126.11",
+	}
+	dnsConfig, err := createDNSConfigFile(values, dnsmasqConfTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, dnsConfig, "address=/apps-crc.testing/192.168.130.11\naddress=/api.crc.testing/192.168.130.11\n")
+
+	values.CustomAppsDomain = "apps.mycompany.test"
+	dnsConfig, err = createDNSConfigFile(values, dnsmasqConfTemplate)
+	assert.NoError(t, err)
+	assert.Contains(t, dnsConfig, "address=/apps-crc.testing/192.168.130.11\naddress=/apps.mycompany.test/192.168.130.11\naddress=/api.crc.testing/192.168.130.11\n")
+}
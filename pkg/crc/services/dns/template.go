@@ -14,6 +14,9 @@ log-queries
 local=/{{ .ClusterName}}.{{ .BaseDomain }}/
 domain={{ .ClusterName}}.{{ .BaseDomain }}
 address=/{{ .AppsDomain }}/{{ .IP }}
+{{- if .CustomAppsDomain }}
+address=/{{ .CustomAppsDomain }}/{{ .IP }}
+{{- end }}
 address=/api.{{ .ClusterName}}.{{ .BaseDomain }}/{{ .IP }}
 address=/api-int.{{ .ClusterName}}.{{ .BaseDomain }}/{{ .IP }}
 address=/{{ .Hostname }}.{{ .ClusterName}}.{{ .BaseDomain }}/{{ .InternalIP }}
@@ -28,6 +31,8 @@ type dnsmasqConfFileValues struct {
 	IP          string
 	AppsDomain  string
 	InternalIP  string
+
+	CustomAppsDomain string
 }
 
 func createDnsmasqDNSConfig(serviceConfig services.ServicePostStartConfig) error {
@@ -40,6 +45,8 @@ func createDnsmasqDNSConfig(serviceConfig services.ServicePostStartConfig) error
 		ClusterName: serviceConfig.BundleMetadata.ClusterInfo.ClusterName,
 		IP:          serviceConfig.IP,
 		InternalIP:  serviceConfig.BundleMetadata.Nodes[0].InternalIP,
+
+		CustomAppsDomain: serviceConfig.AppsDomain,
 	}
 
 	dnsConfig, err := createDNSConfigFile(dnsmasqConfFileValues, dnsmasqConfTemplate)
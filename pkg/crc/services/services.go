@@ -13,4 +13,7 @@ type ServicePostStartConfig struct {
 	IP              string
 	NetworkMode     network.Mode
 	ModifyHostsFile bool
+	// Custom domain served by the cluster ingress, in addition to the
+	// apps domain of the bundle
+	AppsDomain string
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"strconv"
@@ -15,9 +16,32 @@ import (
 
 type Client interface {
 	Run(command string) ([]byte, []byte, error)
+	Exec(command string, streams Streams, terminal *Terminal) error
 	Close()
 }
 
+// Streams are connected to the standard input and outputs of the commands
+// run by Exec, they can be nil
+type Streams struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+type TerminalSize struct {
+	Width  int
+	Height int
+}
+
+// Terminal is the pseudo-terminal allocated for the commands run by Exec
+type Terminal struct {
+	// Term is the value of the TERM environment variable
+	Term string
+	Size TerminalSize
+	// Resize receives the new sizes of the local terminal, it can be nil
+	Resize <-chan TerminalSize
+}
+
 type NativeClient struct {
 	User     string
 	Hostname string
@@ -115,6 +139,71 @@ func (client *NativeClient) Run(command string) ([]byte, []byte, error) {
 	return stdout.Bytes(), stderr.Bytes(), err
 }
 
+// Exec runs 'command' with the given streams, or a login shell when it is
+// empty. A pseudo-terminal is allocated when 'terminal' is not nil. The
+// exit status of a failed command can be retrieved with ExitStatus.
+func (client *NativeClient) Exec(command string, streams Streams, terminal *Terminal) error {
+	session, err := client.session()
+	if err != nil {
+		if client.conn != nil {
+			log.Debugf("Failed to create new ssh session: %s", err)
+			client.conn.Close()
+			client.conn = nil
+		}
+		return err
+	}
+	defer session.Close()
+
+	session.Stdin = streams.Stdin
+	session.Stdout = streams.Stdout
+	session.Stderr = streams.Stderr
+
+	if terminal != nil {
+		modes := ssh.TerminalModes{
+			ssh.ECHO:          1,
+			ssh.TTY_OP_ISPEED: 14400,
+			ssh.TTY_OP_OSPEED: 14400,
+		}
+		if err := session.RequestPty(terminal.Term, terminal.Size.Height, terminal.Size.Width, modes); err != nil {
+			return fmt.Errorf("Failed to allocate a pseudo-terminal: %w", err)
+		}
+		if terminal.Resize != nil {
+			done := make(chan struct{})
+			defer close(done)
+			go func() {
+				for {
+					select {
+					case size := <-terminal.Resize:
+						if err := session.WindowChange(size.Height, size.Width); err != nil {
+							log.Debugf("Failed to resize the pseudo-terminal: %v", err)
+						}
+					case <-done:
+						return
+					}
+				}
+			}()
+		}
+	}
+
+	if command == "" {
+		if err := session.Shell(); err != nil {
+			return err
+		}
+		return session.Wait()
+	}
+	return session.Run(command)
+}
+
+// ExitStatus returns the exit status of the remote command which made Exec
+// or Run fail
+func ExitStatus(err error) (int, bool) {
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus(), true
+	}
+	return 0, false
+}
+
 func (client *NativeClient) Close() {
 	if client.conn == nil {
 		return
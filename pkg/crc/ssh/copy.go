@@ -0,0 +1,288 @@
+package ssh
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+)
+
+// CopyOptions are the options of CopyToVM and CopyFromVM
+type CopyOptions struct {
+	// Privileged uses root access in the VM to read or write the files
+	Privileged bool
+	// Progress is called with the size of each chunk of file content
+	// which was copied, it can be nil
+	Progress func(n int64)
+}
+
+func (options CopyOptions) command(args ...string) string {
+	if options.Privileged {
+		args = append([]string{"sudo"}, args...)
+	}
+	return QuoteCommand(args)
+}
+
+// CopyToVM copies the file or the directory 'src' of the host to 'dest' in
+// the VM, or into 'dest' when it is an existing directory. The files are
+// streamed as a tar archive over the SSH session, with their permissions
+// and modification times.
+func (runner *Runner) CopyToVM(src, dest string, options CopyOptions) error {
+	if _, err := os.Lstat(src); err != nil {
+		return err
+	}
+	destDir, name := path.Dir(dest), path.Base(dest)
+	isDir, err := runner.isDir(dest, options)
+	if err != nil {
+		return err
+	}
+	if isDir {
+		destDir, name = dest, filepath.Base(src)
+	}
+	logging.Debugf("Copying %s to %s in the CRC VM", src, path.Join(destDir, name))
+
+	pr, pw := io.Pipe()
+	writeErr := make(chan error, 1)
+	go func() {
+		err := writeTar(pw, src, name, options.Progress)
+		pw.CloseWithError(err)
+		writeErr <- err
+	}()
+
+	var stderr bytes.Buffer
+	err = runner.client.Exec(options.command("tar", "-x", "-p", "--no-same-owner", "-f", "-", "-C", destDir), Streams{Stdin: pr, Stderr: &stderr}, nil)
+	// unblocks writeTar when tar failed before reading the whole archive
+	pr.Close()
+	if err := <-writeErr; err != nil && err != io.ErrClosedPipe {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("Failed to write %s in the CRC VM: %s: %w", dest, strings.TrimSpace(stderr.String()), err)
+	}
+	return nil
+}
+
+// CopyFromVM copies the file or the directory 'src' of the VM to 'dest' on
+// the host, or into 'dest' when it is an existing directory. The files are
+// streamed as a tar archive over the SSH session, with their permissions
+// and modification times.
+func (runner *Runner) CopyFromVM(src, dest string, options CopyOptions) error {
+	src = path.Clean(src)
+	srcDir, name := path.Dir(src), path.Base(src)
+	target := dest
+	if info, err := os.Stat(dest); err == nil && info.IsDir() {
+		target = filepath.Join(dest, name)
+	}
+	logging.Debugf("Copying %s from the CRC VM to %s", src, target)
+
+	pr, pw := io.Pipe()
+	var stderr bytes.Buffer
+	execErr := make(chan error, 1)
+	go func() {
+		err := runner.client.Exec(options.command("tar", "-c", "-f", "-", "-C", srcDir, "--", name), Streams{Stdout: pw, Stderr: &stderr}, nil)
+		pw.CloseWithError(err)
+		execErr <- err
+	}()
+
+	readErr := readTar(pr, name, target, options.Progress)
+	// tar blocks until all its output is read, including the padding after
+	// the end of the archive or what follows an extraction error
+	_, _ = io.Copy(io.Discard, pr)
+	if err := <-execErr; err != nil {
+		return fmt.Errorf("Failed to read %s in the CRC VM: %s: %w", src, strings.TrimSpace(stderr.String()), err)
+	}
+	return readErr
+}
+
+// SizeInVM returns the disk usage in bytes of the file or directory 'file'
+// in the VM
+func (runner *Runner) SizeInVM(file string, options CopyOptions) (int64, error) {
+	var stdout, stderr bytes.Buffer
+	if err := runner.client.Exec(options.command("du", "-s", "-b", "--", file), Streams{Stdout: &stdout, Stderr: &stderr}, nil); err != nil {
+		return 0, fmt.Errorf("Failed to get the size of %s: %s: %w", file, strings.TrimSpace(stderr.String()), err)
+	}
+	fields := strings.Fields(stdout.String())
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("Failed to get the size of %s: unexpected output %q", file, stdout.String())
+	}
+	return strconv.ParseInt(fields[0], 10, 64)
+}
+
+func (runner *Runner) isDir(file string, options CopyOptions) (bool, error) {
+	err := runner.client.Exec(options.command("test", "-d", file), Streams{}, nil)
+	if status, ok := ExitStatus(err); ok && status == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// writeTar writes a tar archive of 'src', with 'name' as the name of its
+// top-level entry
+func writeTar(w io.Writer, src, name string, progress func(int64)) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(src, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(file); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = path.Join(name, filepath.ToSlash(rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		// the files are owned by the user extracting the archive
+		header.Uid, header.Gid, header.Uname, header.Gname = 0, 0, "", ""
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, &progressReader{reader: f, progress: progress})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+// readTar extracts the tar archive read from 'r' to 'target', which
+// replaces 'name', the top-level entry of the archive
+func readTar(r io.Reader, name, target string, progress func(int64)) error {
+	type dirMode struct {
+		path string
+		mode os.FileMode
+	}
+	var dirs []dirMode
+	// the symlinks extracted from the archive, the other entries must not
+	// go through them since they may point outside of 'target'
+	links := map[string]bool{}
+
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		entry := strings.TrimSuffix(header.Name, "/")
+		if entry != name && !strings.HasPrefix(entry, name+"/") {
+			return fmt.Errorf("Unexpected file %s in the archive", header.Name)
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(entry, name), "/")
+		if rel != "" && !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return fmt.Errorf("Invalid file name %s in the archive", header.Name)
+		}
+		if link := archiveLink(target, rel, links); link != "" {
+			return fmt.Errorf("Invalid file name %s in the archive, %s is a symlink", header.Name, link)
+		}
+		file := filepath.Join(target, filepath.FromSlash(rel))
+		mode := header.FileInfo().Mode().Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			// the directory is writable until all its files are extracted
+			if err := os.MkdirAll(file, 0o700); err != nil {
+				return err
+			}
+			dirs = append(dirs, dirMode{path: file, mode: mode})
+		case tar.TypeReg:
+			if err := extractFile(tr, file, mode, progress); err != nil {
+				return err
+			}
+			if err := os.Chtimes(file, header.ModTime, header.ModTime); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if err := os.Symlink(header.Linkname, file); err != nil {
+				return err
+			}
+			links[file] = true
+		default:
+			logging.Debugf("Skipping %s, its file type is not supported", header.Name)
+		}
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Chmod(dirs[i].path, dirs[i].mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archiveLink returns the symlink of 'links' which is a component of the
+// path 'rel' under 'target', if any
+func archiveLink(target, rel string, links map[string]bool) string {
+	if rel == "" {
+		return ""
+	}
+	file := target
+	for _, component := range strings.Split(rel, "/") {
+		file = filepath.Join(file, component)
+		if links[file] {
+			return file
+		}
+	}
+	return ""
+}
+
+func extractFile(r io.Reader, file string, mode os.FileMode, progress func(int64)) error {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, &progressReader{reader: r, progress: progress}); err != nil {
+		return err
+	}
+	// the mode of OpenFile is not used for existing files, and is masked
+	// by the umask
+	if err := f.Chmod(mode); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+type progressReader struct {
+	reader   io.Reader
+	progress func(int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 && r.progress != nil {
+		r.progress(int64(n))
+	}
+	return n, err
+}
@@ -0,0 +1,159 @@
+package ssh
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createLocalShellRunner returns a runner whose commands are run on the
+// host, like they would in the VM
+func createLocalShellRunner(t *testing.T) *Runner {
+	if runtime.GOOS != "linux" {
+		t.Skip("the copy commands need GNU tar and du")
+	}
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+	clientKeyFile := filepath.Join(t.TempDir(), "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	runner, err := CreateRunner(ipFor(addr), portFor(addr), clientKeyFile)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	createSSHServer(ctx, t, listener, clientKey, func(command string, stdin []byte) (byte, string) {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Stdin = bytes.NewReader(stdin)
+		out, err := cmd.Output()
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return byte(exitErr.ExitCode()), string(out)
+		}
+		require.NoError(t, err)
+		return 0, string(out)
+	})
+	t.Cleanup(func() {
+		runner.Close()
+		cancel()
+		listener.Close()
+	})
+	return runner
+}
+
+func TestCopyToAndFromVM(t *testing.T) {
+	runner := createLocalShellRunner(t)
+
+	src := filepath.Join(t.TempDir(), "src")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "a.txt"), []byte("hello"), 0o640))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "sub", "b.sh"), []byte("#!/bin/sh\n"), 0o755))
+	require.NoError(t, os.Symlink("a.txt", filepath.Join(src, "link")))
+
+	var copied int64
+	options := CopyOptions{Progress: func(n int64) { copied += n }}
+
+	vmDir := t.TempDir()
+	require.NoError(t, runner.CopyToVM(src, vmDir, options))
+	assertCopied(t, filepath.Join(vmDir, "src"))
+	assert.Equal(t, int64(len("hello")+len("#!/bin/sh\n")), copied)
+
+	require.NoError(t, runner.CopyToVM(filepath.Join(src, "a.txt"), filepath.Join(vmDir, "renamed.txt"), CopyOptions{}))
+	content, err := os.ReadFile(filepath.Join(vmDir, "renamed.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+
+	size, err := runner.SizeInVM(filepath.Join(vmDir, "renamed.txt"), CopyOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len("hello")), size)
+
+	hostDir := t.TempDir()
+	require.NoError(t, runner.CopyFromVM(filepath.Join(vmDir, "src"), hostDir, CopyOptions{}))
+	assertCopied(t, filepath.Join(hostDir, "src"))
+
+	require.NoError(t, runner.CopyFromVM(filepath.Join(vmDir, "src"), filepath.Join(hostDir, "dest"), CopyOptions{}))
+	assertCopied(t, filepath.Join(hostDir, "dest"))
+
+	assert.Error(t, runner.CopyFromVM(filepath.Join(vmDir, "missing"), hostDir, CopyOptions{}))
+	assert.Error(t, runner.CopyToVM(filepath.Join(src, "missing"), vmDir, CopyOptions{}))
+}
+
+func assertCopied(t *testing.T, dir string) {
+	info, err := os.Stat(dir)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o750), info.Mode().Perm())
+
+	content, err := os.ReadFile(filepath.Join(dir, "a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+	info, err = os.Stat(filepath.Join(dir, "a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+
+	info, err = os.Stat(filepath.Join(dir, "sub", "b.sh"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+
+	link, err := os.Readlink(filepath.Join(dir, "link"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a.txt", link)
+}
+
+func TestReadTarRejectsPathsThroughItsSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symlinks needs privileges on Windows")
+	}
+	tests := []struct {
+		name    string
+		headers []*tar.Header
+	}{
+		{"file", []*tar.Header{
+			{Name: "src/", Typeflag: tar.TypeDir, Mode: 0o755},
+			{Name: "src/link", Typeflag: tar.TypeSymlink, Linkname: "OUTSIDE"},
+			{Name: "src/link/evil", Typeflag: tar.TypeReg, Mode: 0o644},
+		}},
+		{"directory", []*tar.Header{
+			{Name: "src/", Typeflag: tar.TypeDir, Mode: 0o755},
+			{Name: "src/link", Typeflag: tar.TypeSymlink, Linkname: "OUTSIDE"},
+			{Name: "src/link/", Typeflag: tar.TypeDir, Mode: 0o755},
+		}},
+		{"replaced symlink", []*tar.Header{
+			{Name: "src/", Typeflag: tar.TypeDir, Mode: 0o755},
+			{Name: "src/link", Typeflag: tar.TypeSymlink, Linkname: "OUTSIDE/evil"},
+			{Name: "src/link", Typeflag: tar.TypeReg, Mode: 0o644},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			outside := t.TempDir()
+			var archive bytes.Buffer
+			tw := tar.NewWriter(&archive)
+			for _, header := range test.headers {
+				header.Linkname = strings.Replace(header.Linkname, "OUTSIDE", outside, 1)
+				require.NoError(t, tw.WriteHeader(header))
+			}
+			require.NoError(t, tw.Close())
+
+			target := filepath.Join(t.TempDir(), "dst")
+			err := readTar(&archive, "src", target, func(int64) {})
+			assert.ErrorContains(t, err, "is a symlink")
+			entries, err := os.ReadDir(outside)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
@@ -1,9 +1,10 @@
 package ssh
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -51,17 +52,48 @@ func (runner *Runner) RunPrivileged(reason string, cmdAndArgs ...string) (string
 	return runner.runSSHCommand(commandline, false)
 }
 
+// Exec runs 'command' in the VM with the given streams, or a login shell when
+// it is empty. A pseudo-terminal is allocated when 'terminal' is not nil.
+func (runner *Runner) Exec(command string, streams Streams, terminal *Terminal) error {
+	logging.Debugf("Running SSH command: %s", command)
+	return runner.client.Exec(command, streams, terminal)
+}
+
+// QuoteCommand returns a shell command line running 'args' without any
+// interpretation of their content by the shell
+func QuoteCommand(args []string) string {
+	quoted := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" && strings.Trim(arg, safeShellChars) == "" {
+			quoted = append(quoted, arg)
+			continue
+		}
+		quoted = append(quoted, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(quoted, " ")
+}
+
+const safeShellChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=:,@%+"
+
 func (runner *Runner) copyDataFull(data []byte, destFilename string, mode os.FileMode, privileged bool) error {
+	return runner.copyStream(bytes.NewReader(data), destFilename, mode, privileged)
+}
+
+// copyStream writes the content read from 'src' to 'destFilename' in the VM
+// without buffering it
+func (runner *Runner) copyStream(src io.Reader, destFilename string, mode os.FileMode, privileged bool) error {
 	var sudo string
 	if privileged {
 		sudo = "sudo "
 	}
 	logging.Debugf("Creating %s with permissions 0%o in the CRC VM", destFilename, mode)
-	base64Data := base64.StdEncoding.EncodeToString(data)
-	command := fmt.Sprintf("%sinstall -m 0%o /dev/null %s && cat <<EOF | base64 --decode | %stee %s\n%s\nEOF", sudo, mode, destFilename, sudo, destFilename, base64Data)
-	_, _, err := runner.RunPrivate(command)
-
-	return err
+	dest := QuoteCommand([]string{destFilename})
+	command := fmt.Sprintf("%sinstall -m 0%o /dev/null %s && %stee %s >/dev/null", sudo, mode, dest, sudo, dest)
+	var stderr bytes.Buffer
+	if err := runner.client.Exec(command, Streams{Stdin: src, Stderr: &stderr}, nil); err != nil {
+		return fmt.Errorf("Failed to create %s: %s: %w", destFilename, strings.TrimSpace(stderr.String()), err)
+	}
+	return nil
 }
 
 func (runner *Runner) CopyDataPrivileged(data []byte, destFilename string, mode os.FileMode) error {
@@ -73,24 +105,26 @@ func (runner *Runner) CopyData(data []byte, destFilename string, mode os.FileMod
 }
 
 func (runner *Runner) CopyFileFromVM(srcFilename string, destFilename string, mode os.FileMode) error {
-	command := fmt.Sprintf("sudo base64 %s", srcFilename)
-	stdout, stderr, err := runner.RunPrivate(command)
-	if err != nil {
-		return fmt.Errorf("Failed to get file content %s : %w", stderr, err)
-	}
-	rawDecodedText, err := base64.StdEncoding.DecodeString(stdout)
+	logging.Debugf("Copying %s from the CRC VM to %s", srcFilename, destFilename)
+	f, err := os.OpenFile(destFilename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(destFilename, rawDecodedText, mode)
+	defer f.Close()
+	var stderr bytes.Buffer
+	if err := runner.client.Exec(QuoteCommand([]string{"sudo", "cat", srcFilename}), Streams{Stdout: f, Stderr: &stderr}, nil); err != nil {
+		return fmt.Errorf("Failed to get file content %s : %w", strings.TrimSpace(stderr.String()), err)
+	}
+	return f.Close()
 }
 
 func (runner *Runner) CopyFile(srcFilename string, destFilename string, mode os.FileMode) error {
-	data, err := os.ReadFile(srcFilename)
+	f, err := os.Open(srcFilename)
 	if err != nil {
 		return err
 	}
-	return runner.CopyDataPrivileged(data, destFilename, mode)
+	defer f.Close()
+	return runner.copyStream(f, destFilename, mode, true)
 }
 
 func (runner *Runner) runSSHCommand(command string, runPrivate bool) (string, string, error) {
@@ -8,7 +8,9 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
@@ -51,6 +53,40 @@ func TestRunner(t *testing.T) {
 	assert.Equal(t, 1, *totalConn)
 }
 
+func TestRunnerExec(t *testing.T) {
+	dir := t.TempDir()
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	require.NoError(t, err)
+
+	clientKeyFile := filepath.Join(dir, "private.key")
+	writePrivateKey(t, clientKeyFile, clientKey)
+
+	cancel, runner, _ := createListenerAndSSHServer(t, clientKey, clientKeyFile)
+	defer cancel()
+	defer runner.Close()
+
+	var stdout bytes.Buffer
+	assert.NoError(t, runner.Exec("echo hello", Streams{Stdout: &stdout}, nil))
+	assert.Equal(t, "hello", stdout.String())
+
+	stdout.Reset()
+	err = runner.Exec("false", Streams{Stdout: &stdout}, nil)
+	assert.Error(t, err)
+	status, ok := ExitStatus(err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, status)
+	assert.Equal(t, `unexpected command: "false"`, stdout.String())
+
+	_, ok = ExitStatus(errors.New("connection refused"))
+	assert.False(t, ok)
+}
+
+func TestQuoteCommand(t *testing.T) {
+	assert.Equal(t, "ls -l /home/core", QuoteCommand([]string{"ls", "-l", "/home/core"}))
+	assert.Equal(t, `echo 'hello world' '' '$HOME' 'it'\''s'`, QuoteCommand([]string{"echo", "hello world", "", "$HOME", "it's"}))
+}
+
 func createListenerAndSSHServer(t *testing.T, clientKey *ecdsa.PrivateKey, clientKeyFile string) (context.CancelFunc, *Runner, *int) {
 	listener, err := net.Listen("tcp", "127.0.0.1:")
 	require.NoError(t, err)
@@ -59,15 +95,15 @@ func createListenerAndSSHServer(t *testing.T, clientKey *ecdsa.PrivateKey, clien
 	require.NoError(t, err)
 
 	ctx, cancel := context.WithCancel(context.Background())
-	totalConn := createSSHServer(ctx, t, listener, clientKey, func(input string) (byte, string) {
+	totalConn := createSSHServer(ctx, t, listener, clientKey, func(input string, stdin []byte) (byte, string) {
 		escaped := fmt.Sprintf("%q", input)
 		if escaped == `"echo hello"` {
 			return 0, "hello"
 		}
-		if escaped == `"sudo install -m 0644 /dev/null /hello && cat <<EOF | base64 --decode | sudo tee /hello\naGVsbG8gd29ybGQ=\nEOF"` {
+		if escaped == `"sudo install -m 0644 /dev/null /hello && sudo tee /hello >/dev/null"` && string(stdin) == "hello world" {
 			return 0, ""
 		}
-		if escaped == `"install -m 0644 /dev/null /home/core/hello && cat <<EOF | base64 --decode | tee /home/core/hello\naGVsbG8gd29ybGQ=\nEOF"` {
+		if escaped == `"install -m 0644 /dev/null /home/core/hello && tee /home/core/hello >/dev/null"` && string(stdin) == "hello world" {
 			return 0, ""
 		}
 		return 1, fmt.Sprintf("unexpected command: %q", input)
@@ -75,7 +111,7 @@ func createListenerAndSSHServer(t *testing.T, clientKey *ecdsa.PrivateKey, clien
 	return cancel, runner, totalConn
 }
 
-func createSSHServer(ctx context.Context, t *testing.T, listener net.Listener, clientKey *ecdsa.PrivateKey, fun func(string) (byte, string)) *int {
+func createSSHServer(ctx context.Context, t *testing.T, listener net.Listener, clientKey *ecdsa.PrivateKey, fun func(string, []byte) (byte, string)) *int {
 	totalConn := 0
 	config := &ssh.ServerConfig{
 		PublicKeyCallback: func(c ssh.ConnMetadata, pubKey ssh.PublicKey) (*ssh.Permissions, error) {
@@ -136,7 +172,9 @@ func createSSHServer(ctx context.Context, t *testing.T, listener net.Listener, c
 							logrus.Debugf("received command: %s", command)
 							_ = req.Reply(req.Type == "exec", nil)
 
-							ret, out := fun(command)
+							// the client closes stdin after sending it
+							stdin, _ := io.ReadAll(channel)
+							ret, out := fun(command, stdin)
 							_, _ = channel.Write([]byte(out))
 							_, _ = channel.SendRequest("exit-status", false, []byte{0, 0, 0, ret})
 							_ = channel.Close()
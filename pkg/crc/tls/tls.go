@@ -100,7 +100,7 @@ func SignedCertificate(
 	csr *x509.CertificateRequest,
 	key *rsa.PrivateKey,
 	caCert *x509.Certificate,
-	caKey *rsa.PrivateKey,
+	caKey crypto.Signer,
 ) (*x509.Certificate, error) {
 	serial, err := rand.Int(rand.Reader, new(big.Int).SetInt64(math.MaxInt64))
 	if err != nil {
@@ -158,7 +158,7 @@ func generateSubjectKeyID(pub crypto.PublicKey) ([]byte, error) {
 }
 
 // GenerateSignedCertificate generate a key and cert defined by CertCfg and signed by CA.
-func GenerateSignedCertificate(caKey *rsa.PrivateKey, caCert *x509.Certificate,
+func GenerateSignedCertificate(caKey crypto.Signer, caCert *x509.Certificate,
 	cfg *CertCfg) (*rsa.PrivateKey, *x509.Certificate, error) {
 
 	// create a private key
@@ -215,6 +215,37 @@ func GetSelfSignedCA() (*rsa.PrivateKey, *x509.Certificate, error) {
 	return GenerateSelfSignedCertificate(rootCAConf)
 }
 
+// GetSelfSignedIngressCA generates the CA signing the default certificate
+// of the cluster ingress when it serves a custom apps domain and no custom
+// CA is configured
+func GetSelfSignedIngressCA() (*rsa.PrivateKey, *x509.Certificate, error) {
+	ingressCAConf := &CertCfg{
+		Subject:   pkix.Name{CommonName: "crc-ingress-signer", OrganizationalUnit: []string{"crc"}},
+		KeyUsages: x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		Validity:  ValidityTenYears,
+		IsCA:      true,
+	}
+	return GenerateSelfSignedCertificate(ingressCAConf)
+}
+
+// GenerateServingCertificate generates a serving certificate for 'dnsNames'
+// signed by the CA, and returns its key and cert as pem. Its validity stays
+// below the 825 days accepted by macOS for TLS servers.
+func GenerateServingCertificate(caKey crypto.Signer, caCert *x509.Certificate, dnsNames ...string) ([]byte, []byte, error) {
+	servingConf := &CertCfg{
+		Subject:      pkix.Name{CommonName: dnsNames[0], OrganizationalUnit: []string{"crc"}},
+		DNSNames:     dnsNames,
+		KeyUsages:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		Validity:     ValidityOneYear * 2,
+	}
+	key, crt, err := GenerateSignedCertificate(caKey, caCert, servingConf)
+	if err != nil {
+		return nil, nil, err
+	}
+	return PrivateKeyToPem(key), CertToPem(crt), nil
+}
+
 func GenerateClientCertificate(rootCAKey *rsa.PrivateKey, rootCACert *x509.Certificate) ([]byte, []byte, error) {
 	adminUserConf := &CertCfg{
 		Subject:      pkix.Name{CommonName: "system:admin", OrganizationalUnit: []string{"system:masters"}},
@@ -252,6 +283,57 @@ func CertToPem(cert *x509.Certificate) []byte {
 	return certInPem
 }
 
+// PemToCert parses the first certificate of the pem data 'certPEM'
+func PemToCert(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode certificate PEM")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// PemToPrivateKey is the reverse of PrivateKeyToPem
+func PemToPrivateKey(keyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil || block.Type != "RSA PRIVATE KEY" {
+		return nil, errors.New("failed to decode private key PEM")
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// PemToSigner parses a PKCS#1 RSA, SEC 1 EC or PKCS#8 private key, as
+// written by openssl and most CAs
+func PemToSigner(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode private key PEM")
+	}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.Errorf("unsupported private key type %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, errors.Errorf("unsupported private key PEM type %s", block.Type)
+	}
+}
+
+// KeyMatchesCertificate tells if 'key' is the private key of 'cert'
+func KeyMatchesCertificate(key crypto.Signer, cert *x509.Certificate) bool {
+	publicKey, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && publicKey.Equal(cert.PublicKey)
+}
+
 // VerifyCertificateAgainstRootCA  takes caPEM and certificatePEM as string
 // to validate if given certificate is signed by given ca.
 func VerifyCertificateAgainstRootCA(ca, certificate string) (bool, error) {
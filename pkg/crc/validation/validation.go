@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/url"
 	"os"
+	"regexp"
 	"strings"
 
 	"go.podman.io/common/pkg/strongunits"
@@ -171,6 +172,40 @@ func ValidateIPAddress(ipAddress string) error {
 	return nil
 }
 
+var instanceNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,30}[a-z0-9])?$`)
+
+// ValidateInstanceName checks if the provided name can be used for a CRC instance.
+// The name is used for the VM name and in file paths, so it is restricted to
+// lowercase alphanumeric characters and '-'
+func ValidateInstanceName(name string) error {
+	if !instanceNameRegexp.MatchString(name) {
+		return fmt.Errorf("'%s' is not a valid instance name, it must be at most 32 lowercase alphanumeric characters or '-', and must start and end with an alphanumeric character", name)
+	}
+	return nil
+}
+
+var volumeNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,18}[a-z0-9])?$`)
+
+// ValidateVolumeName checks if the provided name can be used for a volume.
+// The name is the serial number of the disk in the VM, which is limited to
+// 20 characters, and is part of the name of its PersistentVolume
+func ValidateVolumeName(name string) error {
+	if !volumeNameRegexp.MatchString(name) {
+		return fmt.Errorf("'%s' is not a valid volume name, it must be at most 20 lowercase alphanumeric characters or '-', and must start and end with an alphanumeric character", name)
+	}
+	return nil
+}
+
+var snapshotNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][-_.a-zA-Z0-9]{0,62}$`)
+
+// ValidateSnapshotName checks if the provided name can be used for a snapshot of a CRC instance
+func ValidateSnapshotName(name string) error {
+	if !snapshotNameRegexp.MatchString(name) {
+		return fmt.Errorf("'%s' is not a valid snapshot name, it must be at most 63 alphanumeric characters, '-', '_' or '.', and must start with an alphanumeric character", name)
+	}
+	return nil
+}
+
 func ValidateURL(uri string) error {
 	u, err := url.ParseRequestURI(uri)
 	if err != nil {
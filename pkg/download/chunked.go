@@ -0,0 +1,468 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crc-org/crc/v2/pkg/crc/logging"
+	"github.com/crc-org/crc/v2/pkg/crc/version"
+	"github.com/pkg/errors"
+)
+
+const (
+	// parallelChunks is the number of ranges of a file which are
+	// downloaded in parallel
+	parallelChunks = 4
+	// chunkRetries is the number of times the download of a range is
+	// retried after a network error before giving up
+	chunkRetries   = 3
+	copyBufferSize = 1024 * 1024
+)
+
+// minSizeForChunkedDownload is the size above which files are downloaded in
+// parallel chunks when the server supports range requests. Smaller files
+// are downloaded in a single stream.
+var minSizeForChunkedDownload int64 = 100_000_000
+
+var errRangesNotSupported = errors.New("range requests are not supported")
+
+type chunk struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+	// Done is the number of bytes of the chunk already written
+	Done int64 `json:"done"`
+}
+
+func (c *chunk) complete() bool {
+	return c.Start+c.Done >= c.End
+}
+
+// chunkedState is saved next to the partial download, so that an
+// interrupted download can be resumed. The sha256 of the beginning of the
+// file which is already written is saved too, so that it does not need to
+// be computed again on resume.
+type chunkedState struct {
+	URI          string  `json:"uri"`
+	Size         int64   `json:"size"`
+	ETag         string  `json:"etag,omitempty"`
+	LastModified string  `json:"lastModified,omitempty"`
+	Chunks       []chunk `json:"chunks"`
+	Hashed       int64   `json:"hashed"`
+	HashState    []byte  `json:"hashState,omitempty"`
+}
+
+type remoteInfo struct {
+	size         int64
+	etag         string
+	lastModified string
+}
+
+type chunkedDownload struct {
+	client      *http.Client
+	uri         string
+	destination string
+	sha256sum   []byte
+
+	lock   sync.Mutex
+	state  chunkedState
+	file   *os.File
+	hasher hash.Hash
+}
+
+func partPath(destination string) string {
+	return destination + ".part"
+}
+
+func statePath(destination string) string {
+	return destination + ".part.json"
+}
+
+// downloadChunked downloads 'uri' using parallel range requests. The
+// progress is saved regularly, and a download interrupted by an error or
+// by the cancellation of 'ctx' is resumed by the next call. It returns
+// errRangesNotSupported when the server does not allow this, or when the
+// file is too small to benefit from it.
+func downloadChunked(ctx context.Context, client *http.Client, uri, destination string, sha256sum []byte) (string, error) {
+	info, err := probe(ctx, client, uri)
+	if err != nil {
+		return "", err
+	}
+	if info.size < minSizeForChunkedDownload {
+		return "", errRangesNotSupported
+	}
+	if stat, err := os.Stat(destination); err == nil && stat.IsDir() {
+		u, err := url.Parse(uri)
+		if err != nil {
+			return "", err
+		}
+		destination = filepath.Join(destination, path.Base(u.Path))
+	}
+
+	d := &chunkedDownload{
+		client:      client,
+		uri:         uri,
+		destination: destination,
+		sha256sum:   sha256sum,
+	}
+	if d.alreadyDownloaded(info) {
+		logging.Debugf("%s is already downloaded", destination)
+		return destination, nil
+	}
+	if err := d.prepare(info); err != nil {
+		return "", err
+	}
+	defer d.file.Close()
+
+	if err := d.run(ctx); err != nil {
+		if err := d.saveState(); err != nil {
+			logging.Debugf("Cannot save the state of the download of %s: %v", uri, err)
+		}
+		return "", err
+	}
+	return destination, d.finish()
+}
+
+// probe checks if 'uri' can be downloaded with range requests, and returns
+// its size and validators
+func probe(ctx context.Context, client *http.Client, uri string) (*remoteInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", version.UserAgent())
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ContentLength <= 0 || resp.Header.Get("Accept-Ranges") != "bytes" {
+		return nil, errRangesNotSupported
+	}
+	return &remoteInfo{
+		size:         resp.ContentLength,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
+
+func (d *chunkedDownload) alreadyDownloaded(info *remoteInfo) bool {
+	stat, err := os.Stat(d.destination)
+	if err != nil || stat.Size() != info.size {
+		return false
+	}
+	if d.sha256sum == nil {
+		return true
+	}
+	f, err := os.Open(d.destination)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return false
+	}
+	return bytes.Equal(h.Sum(nil), d.sha256sum)
+}
+
+// prepare loads the state of a previous download of the same file, or
+// starts a new download
+func (d *chunkedDownload) prepare(info *remoteInfo) error {
+	d.hasher = sha256.New()
+	if d.loadState(info) {
+		logging.Infof("Resuming the download of %s", filepath.Base(d.destination))
+	} else {
+		d.state = newChunkedState(d.uri, info)
+		_ = os.Remove(partPath(d.destination))
+	}
+	if err := os.MkdirAll(filepath.Dir(d.destination), 0750); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(partPath(d.destination), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	if err := file.Truncate(d.state.Size); err != nil {
+		file.Close()
+		return err
+	}
+	d.file = file
+	return nil
+}
+
+func newChunkedState(uri string, info *remoteInfo) chunkedState {
+	state := chunkedState{
+		URI:          uri,
+		Size:         info.size,
+		ETag:         info.etag,
+		LastModified: info.lastModified,
+	}
+	chunkSize := info.size / parallelChunks
+	for i := int64(0); i < parallelChunks; i++ {
+		end := (i + 1) * chunkSize
+		if i == parallelChunks-1 {
+			end = info.size
+		}
+		state.Chunks = append(state.Chunks, chunk{Start: i * chunkSize, End: end})
+	}
+	return state
+}
+
+// loadState returns true if the saved state is for the same version of
+// the same file
+func (d *chunkedDownload) loadState(info *remoteInfo) bool {
+	data, err := os.ReadFile(statePath(d.destination))
+	if err != nil {
+		return false
+	}
+	var state chunkedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		logging.Debugf("Ignoring invalid download state %s: %v", statePath(d.destination), err)
+		return false
+	}
+	if state.URI != d.uri || state.Size != info.size || state.ETag != info.etag || state.LastModified != info.lastModified {
+		logging.Debugf("%s changed since the previous download, restarting it", d.uri)
+		return false
+	}
+	if _, err := os.Stat(partPath(d.destination)); err != nil {
+		return false
+	}
+	if len(state.HashState) > 0 {
+		if err := d.hasher.(encoding.BinaryUnmarshaler).UnmarshalBinary(state.HashState); err != nil {
+			logging.Debugf("Ignoring invalid hash state: %v", err)
+			return false
+		}
+	} else {
+		state.Hashed = 0
+	}
+	d.state = state
+	return true
+}
+
+func (d *chunkedDownload) saveState() error {
+	d.lock.Lock()
+	data, err := json.Marshal(d.state)
+	d.lock.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(statePath(d.destination), data, 0600)
+}
+
+func (d *chunkedDownload) run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(d.state.Chunks))
+	for i := range d.state.Chunks {
+		if d.state.Chunks[i].complete() {
+			continue
+		}
+		wg.Add(1)
+		go func(c *chunk) {
+			defer wg.Done()
+			if err := d.downloadChunkWithRetries(ctx, c); err != nil {
+				errs <- err
+				cancel()
+			}
+		}(&d.state.Chunks[i])
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	bar := startProgressBar(d.state.Size)
+	if bar != nil {
+		defer bar.Finish()
+	}
+	progress := progressFromContext(ctx)
+	report := func() {
+		current := d.completed()
+		if bar != nil {
+			bar.SetCurrent(current)
+		}
+		progress(current, d.state.Size)
+	}
+	defer report()
+
+	t := time.NewTicker(500 * time.Millisecond)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+			report()
+			// the beginning of the file which is written is hashed while the
+			// rest is downloaded, so that the verification does not start
+			// from scratch after a resume
+			if err := d.hashCompletedPrefix(); err != nil {
+				return err
+			}
+			if err := d.saveState(); err != nil {
+				logging.Debugf("Cannot save the state of the download of %s: %v", d.uri, err)
+			}
+		case <-done:
+			select {
+			case err := <-errs:
+				return err
+			default:
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+func (d *chunkedDownload) downloadChunkWithRetries(ctx context.Context, c *chunk) error {
+	var err error
+	for attempt := 0; attempt <= chunkRetries; attempt++ {
+		if attempt > 0 {
+			logging.Debugf("Retrying the download of %s from offset %d: %v", d.uri, c.Start+d.chunkDone(c), err)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+		if err = d.downloadChunk(ctx, c); err == nil || ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (d *chunkedDownload) chunkDone(c *chunk) int64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return c.Done
+}
+
+func (d *chunkedDownload) downloadChunk(ctx context.Context, c *chunk) error {
+	offset := c.Start + d.chunkDone(c)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", version.UserAgent())
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, c.End-1))
+	// weak validators cannot be used in If-Range
+	if d.state.ETag != "" && !strings.HasPrefix(d.state.ETag, "W/") {
+		req.Header.Set("If-Range", d.state.ETag)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("unexpected HTTP status for range %d-%d of %s: %s", offset, c.End-1, d.uri, resp.Status)
+	}
+
+	buf := make([]byte, copyBufferSize)
+	for offset < c.End {
+		n, err := resp.Body.Read(buf[:min(int64(len(buf)), c.End-offset)])
+		if n > 0 {
+			if _, err := d.file.WriteAt(buf[:n], offset); err != nil {
+				return err
+			}
+			offset += int64(n)
+			d.lock.Lock()
+			c.Done = offset - c.Start
+			d.lock.Unlock()
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if offset < c.End {
+		return fmt.Errorf("range %d-%d of %s ended unexpectedly", c.Start, c.End-1, d.uri)
+	}
+	return nil
+}
+
+func (d *chunkedDownload) completed() int64 {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	var completed int64
+	for _, c := range d.state.Chunks {
+		completed += c.Done
+	}
+	return completed
+}
+
+// hashCompletedPrefix feeds the hasher with the bytes which are written at
+// the beginning of the file and were not hashed yet
+func (d *chunkedDownload) hashCompletedPrefix() error {
+	d.lock.Lock()
+	prefix := d.state.Size
+	for _, c := range d.state.Chunks {
+		if !c.complete() {
+			prefix = c.Start + c.Done
+			break
+		}
+	}
+	hashed := d.state.Hashed
+	d.lock.Unlock()
+
+	if prefix <= hashed {
+		return nil
+	}
+	if _, err := io.Copy(d.hasher, io.NewSectionReader(d.file, hashed, prefix-hashed)); err != nil {
+		return err
+	}
+	hashState, err := d.hasher.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return err
+	}
+	d.lock.Lock()
+	d.state.Hashed = prefix
+	d.state.HashState = hashState
+	d.lock.Unlock()
+	return nil
+}
+
+// finish verifies the sha256sum of the downloaded file and moves it to its
+// destination
+func (d *chunkedDownload) finish() error {
+	if err := d.hashCompletedPrefix(); err != nil {
+		return err
+	}
+	if err := d.file.Close(); err != nil {
+		return err
+	}
+	if d.sha256sum != nil {
+		if sum := d.hasher.Sum(nil); !bytes.Equal(sum, d.sha256sum) {
+			d.removePartial()
+			return fmt.Errorf("checksum mismatch for %s: got %s instead of %s", filepath.Base(d.destination), hex.EncodeToString(sum), hex.EncodeToString(d.sha256sum))
+		}
+	}
+	if err := os.Rename(partPath(d.destination), d.destination); err != nil {
+		return err
+	}
+	_ = os.Remove(statePath(d.destination))
+	return nil
+}
+
+func (d *chunkedDownload) removePartial() {
+	_ = os.Remove(partPath(d.destination))
+	_ = os.Remove(statePath(d.destination))
+}
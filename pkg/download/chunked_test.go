@@ -0,0 +1,191 @@
+package download
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testContent() []byte {
+	return bytes.Repeat([]byte("0123456789abcdef"), 64*1024)
+}
+
+type testServer struct {
+	*httptest.Server
+	served       atomic.Int64
+	rangeSupport bool
+}
+
+func newTestServer(t *testing.T, content []byte, rangeSupport bool) *testServer {
+	server := &testServer{rangeSupport: rangeSupport}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !server.rangeSupport {
+			w.Header().Set("Content-Type", "application/octet-stream")
+			if r.Method == http.MethodGet {
+				n, _ := w.Write(content)
+				server.served.Add(int64(n))
+			}
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(&countingWriter{ResponseWriter: w, served: &server.served}, r, "bundle", time.Time{}, bytes.NewReader(content))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	served *atomic.Int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.served.Add(int64(n))
+	return n, err
+}
+
+func withMinSizeForChunkedDownload(t *testing.T, size int64) {
+	previous := minSizeForChunkedDownload
+	minSizeForChunkedDownload = size
+	t.Cleanup(func() {
+		minSizeForChunkedDownload = previous
+	})
+}
+
+func checksum(content []byte) []byte {
+	sum := sha256.Sum256(content)
+	return sum[:]
+}
+
+func TestChunkedDownload(t *testing.T) {
+	withMinSizeForChunkedDownload(t, 1)
+	content := testContent()
+	server := newTestServer(t, content, true)
+	destination := filepath.Join(t.TempDir(), "bundle.crcbundle")
+
+	filename, err := downloadChunked(context.Background(), http.DefaultClient, server.URL+"/bundle.crcbundle", destination, checksum(content))
+	require.NoError(t, err)
+	assert.Equal(t, destination, filename)
+
+	downloaded, err := os.ReadFile(destination)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+	assert.NoFileExists(t, partPath(destination))
+	assert.NoFileExists(t, statePath(destination))
+	assert.Equal(t, int64(len(content)), server.served.Load())
+
+	// the file is not downloaded again
+	_, err = downloadChunked(context.Background(), http.DefaultClient, server.URL+"/bundle.crcbundle", destination, checksum(content))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), server.served.Load())
+}
+
+func TestChunkedDownloadToDirectory(t *testing.T) {
+	withMinSizeForChunkedDownload(t, 1)
+	content := testContent()
+	server := newTestServer(t, content, true)
+	dir := t.TempDir()
+
+	filename, err := downloadChunked(context.Background(), http.DefaultClient, server.URL+"/bundles/bundle.crcbundle", dir, nil)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "bundle.crcbundle"), filename)
+}
+
+func TestChunkedDownloadResume(t *testing.T) {
+	withMinSizeForChunkedDownload(t, 1)
+	content := testContent()
+	server := newTestServer(t, content, true)
+	uri := server.URL + "/bundle.crcbundle"
+	destination := filepath.Join(t.TempDir(), "bundle.crcbundle")
+
+	// simulate a download interrupted after half of the first chunk and all
+	// of the third chunk were written
+	state := newChunkedState(uri, &remoteInfo{size: int64(len(content)), etag: `"v1"`})
+	part := make([]byte, len(content))
+	first, third := &state.Chunks[0], &state.Chunks[2]
+	first.Done = (first.End - first.Start) / 2
+	copy(part[first.Start:first.Start+first.Done], content[first.Start:])
+	third.Done = third.End - third.Start
+	copy(part[third.Start:third.End], content[third.Start:])
+	require.NoError(t, os.WriteFile(partPath(destination), part, 0600))
+	data, err := json.Marshal(state)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(statePath(destination), data, 0600))
+
+	_, err = downloadChunked(context.Background(), http.DefaultClient, uri, destination, checksum(content))
+	require.NoError(t, err)
+
+	downloaded, err := os.ReadFile(destination)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+	assert.Equal(t, int64(len(content))-first.Done-third.Done, server.served.Load())
+}
+
+func TestChunkedDownloadRestartsWhenFileChanged(t *testing.T) {
+	withMinSizeForChunkedDownload(t, 1)
+	content := testContent()
+	server := newTestServer(t, content, true)
+	uri := server.URL + "/bundle.crcbundle"
+	destination := filepath.Join(t.TempDir(), "bundle.crcbundle")
+
+	state := newChunkedState(uri, &remoteInfo{size: int64(len(content)), etag: `"v0"`})
+	state.Chunks[0].Done = 100
+	require.NoError(t, os.WriteFile(partPath(destination), make([]byte, len(content)), 0600))
+	data, err := json.Marshal(state)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(statePath(destination), data, 0600))
+
+	_, err = downloadChunked(context.Background(), http.DefaultClient, uri, destination, checksum(content))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(content)), server.served.Load())
+}
+
+func TestChunkedDownloadChecksumMismatch(t *testing.T) {
+	withMinSizeForChunkedDownload(t, 1)
+	content := testContent()
+	server := newTestServer(t, content, true)
+	destination := filepath.Join(t.TempDir(), "bundle.crcbundle")
+
+	_, err := downloadChunked(context.Background(), http.DefaultClient, server.URL+"/bundle.crcbundle", destination, checksum([]byte("other")))
+	assert.ErrorContains(t, err, "checksum mismatch for bundle.crcbundle")
+	assert.NoFileExists(t, destination)
+	assert.NoFileExists(t, partPath(destination))
+	assert.NoFileExists(t, statePath(destination))
+}
+
+func TestChunkedDownloadNotSupported(t *testing.T) {
+	withMinSizeForChunkedDownload(t, 1)
+	content := testContent()
+	server := newTestServer(t, content, false)
+	destination := filepath.Join(t.TempDir(), "bundle.crcbundle")
+
+	_, err := downloadChunked(context.Background(), http.DefaultClient, server.URL+"/bundle.crcbundle", destination, nil)
+	assert.ErrorIs(t, err, errRangesNotSupported)
+
+	// Download falls back to a single stream
+	filename, err := Download(context.Background(), server.URL+"/bundle.crcbundle", destination, 0600, checksum(content))
+	require.NoError(t, err)
+	downloaded, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+}
+
+func TestChunkedDownloadTooSmall(t *testing.T) {
+	content := testContent()
+	server := newTestServer(t, content, true)
+
+	_, err := downloadChunked(context.Background(), http.DefaultClient, server.URL+"/bundle.crcbundle", filepath.Join(t.TempDir(), "bundle.crcbundle"), nil)
+	assert.ErrorIs(t, err, errRangesNotSupported)
+}
@@ -22,10 +22,47 @@ import (
 	grab "github.com/sebrandon1/grab/lib"
 )
 
+// ProgressFunc is called periodically during a download with the number of
+// bytes received so far and the size of the file
+type ProgressFunc func(current, total int64)
+
+type progressKey struct{}
+
+// WithProgress returns a context which makes Download report its progress
+// to 'progress'
+func WithProgress(ctx context.Context, progress ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, progress)
+}
+
+func progressFromContext(ctx context.Context) ProgressFunc {
+	if progress, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		return progress
+	}
+	return func(_, _ int64) {}
+}
+
+// startProgressBar returns nil when the progress must not be displayed
+func startProgressBar(total int64) *pb.ProgressBar {
+	if !terminal.IsShowTerminalOutput() {
+		return nil
+	}
+	bar := pb.Start64(total)
+	bar.Set(pb.Bytes, true)
+	// This is the same as the 'Default' template https://github.com/cheggaaa/pb/blob/224e0746e1e7b9c5309d6e2637264bfeb746d043/v3/preset.go#L8-L10
+	// except that the 'per second' suffix is changed to '/s' (by default it is ' p/s' which is unexpected)
+	progressBarTemplate := `{{with string . "prefix"}}{{.}} {{end}}{{counters . }} {{bar . }} {{percent . }} {{speed . "%s/s" "??/s"}}{{with string . "suffix"}} {{.}}{{end}}`
+	bar.SetTemplateString(progressBarTemplate)
+	return bar
+}
+
 func doRequest(client *grab.Client, req *grab.Request) (string, error) {
 	const minSizeForProgressBar = 100_000_000
 
 	resp := client.Do(req)
+	progress := progressFromContext(req.Context())
+	defer func() {
+		progress(resp.BytesComplete(), resp.Size())
+	}()
 	if resp.Size() < minSizeForProgressBar {
 		<-resp.Done
 		return resp.Filename, resp.Err()
@@ -33,14 +70,8 @@ func doRequest(client *grab.Client, req *grab.Request) (string, error) {
 
 	t := time.NewTicker(500 * time.Millisecond)
 	defer t.Stop()
-	var bar *pb.ProgressBar
-	if terminal.IsShowTerminalOutput() {
-		bar = pb.Start64(resp.Size())
-		bar.Set(pb.Bytes, true)
-		// This is the same as the 'Default' template https://github.com/cheggaaa/pb/blob/224e0746e1e7b9c5309d6e2637264bfeb746d043/v3/preset.go#L8-L10
-		// except that the 'per second' suffix is changed to '/s' (by default it is ' p/s' which is unexpected)
-		progressBarTemplate := `{{with string . "prefix"}}{{.}} {{end}}{{counters . }} {{bar . }} {{percent . }} {{speed . "%s/s" "??/s"}}{{with string . "suffix"}} {{.}}{{end}}`
-		bar.SetTemplateString(progressBarTemplate)
+	bar := startProgressBar(resp.Size())
+	if bar != nil {
 		defer bar.Finish()
 	}
 
@@ -48,9 +79,10 @@ loop:
 	for {
 		select {
 		case <-t.C:
-			if terminal.IsShowTerminalOutput() {
+			if bar != nil {
 				bar.SetCurrent(resp.BytesComplete())
 			}
+			progress(resp.BytesComplete(), resp.Size())
 		case <-resp.Done:
 			break loop
 		}
@@ -64,24 +96,18 @@ loop:
 func Download(ctx context.Context, uri, destination string, mode os.FileMode, sha256sum []byte) (string, error) {
 	logging.Debugf("Downloading %s to %s", uri, destination)
 
-	client := grab.NewClient()
-	client.UserAgent = version.UserAgent()
-	client.HTTPClient = &http.Client{Transport: httpproxy.HTTPTransport()}
-	req, err := grab.NewRequest(destination, uri)
-	if err != nil {
-		return "", errors.Wrapf(err, "unable to get request from %s", uri)
-	}
-
 	if ctx == nil {
 		panic("ctx is nil, this should not happen")
 	}
-	req = req.WithContext(ctx)
 
-	if sha256sum != nil {
-		req.SetChecksum(sha256.New(), sha256sum, true)
+	httpClient := &http.Client{Transport: httpproxy.HTTPTransport()}
+	// large files are downloaded in parallel chunks which can be resumed,
+	// when the server supports it
+	filename, err := downloadChunked(ctx, httpClient, uri, destination, sha256sum)
+	if errors.Is(err, errRangesNotSupported) {
+		logging.Debugf("Cannot download %s in chunks, using a single stream", uri)
+		filename, err = downloadSingleStream(ctx, httpClient, uri, destination, sha256sum)
 	}
-
-	filename, err := doRequest(client, req)
 	if err != nil {
 		return "", err
 	}
@@ -95,6 +121,23 @@ func Download(ctx context.Context, uri, destination string, mode os.FileMode, sh
 	return filename, nil
 }
 
+func downloadSingleStream(ctx context.Context, httpClient *http.Client, uri, destination string, sha256sum []byte) (string, error) {
+	client := grab.NewClient()
+	client.UserAgent = version.UserAgent()
+	client.HTTPClient = httpClient
+	req, err := grab.NewRequest(destination, uri)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to get request from %s", uri)
+	}
+	req = req.WithContext(ctx)
+
+	if sha256sum != nil {
+		req.SetChecksum(sha256.New(), sha256sum, true)
+	}
+
+	return doRequest(client, req)
+}
+
 // InMemory takes a URL and returns a ReadCloser object to the downloaded file
 // or the file itself if the URL is a file:// URL. In case of failure it returns
 // the respective error.
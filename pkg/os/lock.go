@@ -0,0 +1,27 @@
+package os
+
+import (
+	"os"
+)
+
+// LockFile takes an exclusive lock on 'path', waiting until other
+// processes release it. The lock is held on a separate 'path'.lock file, so
+// that 'path' can be read and replaced while it is held. The returned
+// function releases the lock.
+func LockFile(path string) (func() error, error) {
+	file, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return func() error {
+		if err := unlockFile(file); err != nil {
+			_ = file.Close()
+			return err
+		}
+		return file.Close()
+	}, nil
+}
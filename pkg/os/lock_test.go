@@ -0,0 +1,36 @@
+package os
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "forwards.json")
+	unlock, err := LockFile(path)
+	require.NoError(t, err)
+
+	locked := make(chan struct{})
+	go func() {
+		unlockAgain, err := LockFile(path)
+		assert.NoError(t, err)
+		close(locked)
+		assert.NoError(t, unlockAgain())
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("the lock was taken twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+	require.NoError(t, unlock())
+	select {
+	case <-locked:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the lock was not released")
+	}
+}
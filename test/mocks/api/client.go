@@ -4,7 +4,20 @@ package mocks
 
 import (
 	client "github.com/crc-org/crc/v2/pkg/crc/api/client"
+
+	config "github.com/crc-org/crc/v2/pkg/crc/machine/config"
+
+	io "io"
+
 	mock "github.com/stretchr/testify/mock"
+
+	network "github.com/crc-org/crc/v2/pkg/crc/network"
+
+	registrycache "github.com/crc-org/crc/v2/pkg/crc/registrycache"
+
+	strongunits "go.podman.io/common/pkg/strongunits"
+
+	types "github.com/crc-org/crc/v2/pkg/crc/machine/types"
 )
 
 // Client is an autogenerated mock type for the Client type
@@ -12,6 +25,132 @@ type Client struct {
 	mock.Mock
 }
 
+// AddDNSRecord provides a mock function with given fields: record
+func (_m *Client) AddDNSRecord(record network.DNSRecord) (network.DNSRecord, error) {
+	ret := _m.Called(record)
+
+	var r0 network.DNSRecord
+	if rf, ok := ret.Get(0).(func(network.DNSRecord) network.DNSRecord); ok {
+		r0 = rf(record)
+	} else {
+		r0 = ret.Get(0).(network.DNSRecord)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(network.DNSRecord) error); ok {
+		r1 = rf(record)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AddSharedDir provides a mock function with given fields: dir
+func (_m *Client) AddSharedDir(dir config.SharedDir) (client.SharedDirResult, error) {
+	ret := _m.Called(dir)
+
+	var r0 client.SharedDirResult
+	if rf, ok := ret.Get(0).(func(config.SharedDir) client.SharedDirResult); ok {
+		r0 = rf(dir)
+	} else {
+		r0 = ret.Get(0).(client.SharedDirResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(config.SharedDir) error); ok {
+		r1 = rf(dir)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AttachVolume provides a mock function with given fields: name
+func (_m *Client) AttachVolume(name string) (types.VolumeInfo, error) {
+	ret := _m.Called(name)
+
+	var r0 types.VolumeInfo
+	if rf, ok := ret.Get(0).(func(string) types.VolumeInfo); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(types.VolumeInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CancelOperation provides a mock function with given fields: id
+func (_m *Client) CancelOperation(id string) (client.OperationResult, error) {
+	ret := _m.Called(id)
+
+	var r0 client.OperationResult
+	if rf, ok := ret.Get(0).(func(string) client.OperationResult); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(client.OperationResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSnapshot provides a mock function with given fields: name
+func (_m *Client) CreateSnapshot(name string) (types.SnapshotInfo, error) {
+	ret := _m.Called(name)
+
+	var r0 types.SnapshotInfo
+	if rf, ok := ret.Get(0).(func(string) types.SnapshotInfo); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(types.SnapshotInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateVolume provides a mock function with given fields: name, size
+func (_m *Client) CreateVolume(name string, size strongunits.GiB) (types.VolumeInfo, error) {
+	ret := _m.Called(name, size)
+
+	var r0 types.VolumeInfo
+	if rf, ok := ret.Get(0).(func(string, strongunits.GiB) types.VolumeInfo); ok {
+		r0 = rf(name, size)
+	} else {
+		r0 = ret.Get(0).(types.VolumeInfo)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string, strongunits.GiB) error); ok {
+		r1 = rf(name, size)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Delete provides a mock function with given fields:
 func (_m *Client) Delete() error {
 	ret := _m.Called()
@@ -26,6 +165,125 @@ func (_m *Client) Delete() error {
 	return r0
 }
 
+// DeleteSnapshot provides a mock function with given fields: name
+func (_m *Client) DeleteSnapshot(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteVolume provides a mock function with given fields: name
+func (_m *Client) DeleteVolume(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Diagnose provides a mock function with given fields: w
+func (_m *Client) Diagnose(w io.Writer) error {
+	ret := _m.Called(w)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(io.Writer) error); ok {
+		r0 = rf(w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetachVolume provides a mock function with given fields: name
+func (_m *Client) DetachVolume(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DownloadBundle provides a mock function with given fields: bundle
+func (_m *Client) DownloadBundle(bundle string) (client.BundleDownloadResult, error) {
+	ret := _m.Called(bundle)
+
+	var r0 client.BundleDownloadResult
+	if rf, ok := ret.Get(0).(func(string) client.BundleDownloadResult); ok {
+		r0 = rf(bundle)
+	} else {
+		r0 = ret.Get(0).(client.BundleDownloadResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(bundle)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Exec provides a mock function with given fields: req, stdout, stderr
+func (_m *Client) Exec(req client.ExecRequest, stdout io.Writer, stderr io.Writer) (int, error) {
+	ret := _m.Called(req, stdout, stderr)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(client.ExecRequest, io.Writer, io.Writer) int); ok {
+		r0 = rf(req, stdout, stderr)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(client.ExecRequest, io.Writer, io.Writer) error); ok {
+		r1 = rf(req, stdout, stderr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ExposePort provides a mock function with given fields: forward
+func (_m *Client) ExposePort(forward types.PortForward) (types.PortForward, error) {
+	ret := _m.Called(forward)
+
+	var r0 types.PortForward
+	if rf, ok := ret.Get(0).(func(types.PortForward) types.PortForward); ok {
+		r0 = rf(forward)
+	} else {
+		r0 = ret.Get(0).(types.PortForward)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(types.PortForward) error); ok {
+		r1 = rf(forward)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetConfig provides a mock function with given fields: configs
 func (_m *Client) GetConfig(configs []string) (client.GetConfigResult, error) {
 	ret := _m.Called(configs)
@@ -47,6 +305,27 @@ func (_m *Client) GetConfig(configs []string) (client.GetConfigResult, error) {
 	return r0, r1
 }
 
+// Instances provides a mock function with given fields:
+func (_m *Client) Instances() (client.InstancesResult, error) {
+	ret := _m.Called()
+
+	var r0 client.InstancesResult
+	if rf, ok := ret.Get(0).(func() client.InstancesResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.InstancesResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsPullSecretDefined provides a mock function with given fields:
 func (_m *Client) IsPullSecretDefined() (bool, error) {
 	ret := _m.Called()
@@ -68,6 +347,314 @@ func (_m *Client) IsPullSecretDefined() (bool, error) {
 	return r0, r1
 }
 
+// ListDNSRecords provides a mock function with given fields:
+func (_m *Client) ListDNSRecords() (client.DNSRecordsResult, error) {
+	ret := _m.Called()
+
+	var r0 client.DNSRecordsResult
+	if rf, ok := ret.Get(0).(func() client.DNSRecordsResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.DNSRecordsResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPorts provides a mock function with given fields:
+func (_m *Client) ListPorts() (client.PortsResult, error) {
+	ret := _m.Called()
+
+	var r0 client.PortsResult
+	if rf, ok := ret.Get(0).(func() client.PortsResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.PortsResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSharedDirs provides a mock function with given fields:
+func (_m *Client) ListSharedDirs() (client.SharedDirsResult, error) {
+	ret := _m.Called()
+
+	var r0 client.SharedDirsResult
+	if rf, ok := ret.Get(0).(func() client.SharedDirsResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.SharedDirsResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSnapshots provides a mock function with given fields:
+func (_m *Client) ListSnapshots() (client.SnapshotsResult, error) {
+	ret := _m.Called()
+
+	var r0 client.SnapshotsResult
+	if rf, ok := ret.Get(0).(func() client.SnapshotsResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.SnapshotsResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListVolumes provides a mock function with given fields:
+func (_m *Client) ListVolumes() (client.VolumesResult, error) {
+	ret := _m.Called()
+
+	var r0 client.VolumesResult
+	if rf, ok := ret.Get(0).(func() client.VolumesResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.VolumesResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logs provides a mock function with given fields:
+func (_m *Client) Logs() (client.LogsResult, error) {
+	ret := _m.Called()
+
+	var r0 client.LogsResult
+	if rf, ok := ret.Get(0).(func() client.LogsResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.LogsResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Operation provides a mock function with given fields: id
+func (_m *Client) Operation(id string) (client.OperationResult, error) {
+	ret := _m.Called(id)
+
+	var r0 client.OperationResult
+	if rf, ok := ret.Get(0).(func(string) client.OperationResult); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(client.OperationResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Operations provides a mock function with given fields:
+func (_m *Client) Operations() (client.OperationsResult, error) {
+	ret := _m.Called()
+
+	var r0 client.OperationsResult
+	if rf, ok := ret.Get(0).(func() client.OperationsResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.OperationsResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Preflight provides a mock function with given fields:
+func (_m *Client) Preflight() (client.PreflightResult, error) {
+	ret := _m.Called()
+
+	var r0 client.PreflightResult
+	if rf, ok := ret.Get(0).(func() client.PreflightResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.PreflightResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneRegistryCache provides a mock function with given fields: unusedFor
+func (_m *Client) PruneRegistryCache(unusedFor string) (registrycache.PruneResult, error) {
+	ret := _m.Called(unusedFor)
+
+	var r0 registrycache.PruneResult
+	if rf, ok := ret.Get(0).(func(string) registrycache.PruneResult); ok {
+		r0 = rf(unusedFor)
+	} else {
+		r0 = ret.Get(0).(registrycache.PruneResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(unusedFor)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegistryCache provides a mock function with given fields:
+func (_m *Client) RegistryCache() (client.RegistryCacheResult, error) {
+	ret := _m.Called()
+
+	var r0 client.RegistryCacheResult
+	if rf, ok := ret.Get(0).(func() client.RegistryCacheResult); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.RegistryCacheResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveDNSRecord provides a mock function with given fields: hostname
+func (_m *Client) RemoveDNSRecord(hostname string) (client.RemoveDNSRecordResult, error) {
+	ret := _m.Called(hostname)
+
+	var r0 client.RemoveDNSRecordResult
+	if rf, ok := ret.Get(0).(func(string) client.RemoveDNSRecordResult); ok {
+		r0 = rf(hostname)
+	} else {
+		r0 = ret.Get(0).(client.RemoveDNSRecordResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(hostname)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSharedDir provides a mock function with given fields: target
+func (_m *Client) RemoveSharedDir(target string) (client.SharedDirResult, error) {
+	ret := _m.Called(target)
+
+	var r0 client.SharedDirResult
+	if rf, ok := ret.Get(0).(func(string) client.SharedDirResult); ok {
+		r0 = rf(target)
+	} else {
+		r0 = ret.Get(0).(client.SharedDirResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(target)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Resize provides a mock function with given fields: req
+func (_m *Client) Resize(req client.ResizeRequest) (client.ResizeResult, error) {
+	ret := _m.Called(req)
+
+	var r0 client.ResizeResult
+	if rf, ok := ret.Get(0).(func(client.ResizeRequest) client.ResizeResult); ok {
+		r0 = rf(req)
+	} else {
+		r0 = ret.Get(0).(client.ResizeResult)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(client.ResizeRequest) error); ok {
+		r1 = rf(req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreSnapshot provides a mock function with given fields: name
+func (_m *Client) RestoreSnapshot(name string) error {
+	ret := _m.Called(name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SetConfig provides a mock function with given fields: configs
 func (_m *Client) SetConfig(configs client.SetConfigRequest) (client.SetOrUnsetConfigResult, error) {
 	ret := _m.Called(configs)
@@ -173,6 +760,20 @@ func (_m *Client) Telemetry(action string) error {
 	return r0
 }
 
+// UnexposePort provides a mock function with given fields: protocol, local
+func (_m *Client) UnexposePort(protocol string, local string) error {
+	ret := _m.Called(protocol, local)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(protocol, local)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnsetConfig provides a mock function with given fields: configs
 func (_m *Client) UnsetConfig(configs []string) (client.SetOrUnsetConfigResult, error) {
 	ret := _m.Called(configs)